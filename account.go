@@ -0,0 +1,327 @@
+package payqr
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"sync"
+)
+
+// AccountValidator validates an account number. Errors should wrap
+// ErrInvalidAccount, as those of the built-in validators do.
+type AccountValidator func(account string) error
+
+type accountKey struct {
+	countryCode string
+	paymentType PaymentType
+}
+
+var (
+	accountValidatorsMu sync.RWMutex
+	accountValidators   = map[accountKey]AccountValidator{
+		{"SE", PaymentTypeBG}:   validateBankgiro,
+		{"SE", PaymentTypePG}:   validatePlusgiro,
+		{"SE", PaymentTypeBBAN}: validateSwedishBBAN,
+		{"NO", PaymentTypeBBAN}: validateNorwegianBBAN,
+		{"DK", PaymentTypeBBAN}: validateDanishBBAN,
+		{"", PaymentTypeIBAN}:   validateIBAN,
+		{"FI", PaymentTypeIBAN}: validateFinnishIBAN,
+	}
+)
+
+// RegisterAccountValidator registers a validator for accounts of the payment
+// type in the country, given as ISO 3166-1 alpha-2. An empty country code
+// registers a validator used for all countries without a specific one.
+// Registering replaces any existing validator for the same key.
+func RegisterAccountValidator(countryCode string, paymentType PaymentType, validator AccountValidator) {
+	accountValidatorsMu.Lock()
+	defer accountValidatorsMu.Unlock()
+
+	accountValidators[accountKey{strings.ToUpper(countryCode), paymentType}] = validator
+}
+
+// ValidateAccount validates the account with the validator registered for the
+// country and payment type, falling back to the one registered for all
+// countries. Accounts without any registered validator are accepted.
+func ValidateAccount(countryCode string, paymentType PaymentType, account string) error {
+	accountValidatorsMu.RLock()
+	validator, ok := accountValidators[accountKey{strings.ToUpper(countryCode), paymentType}]
+	if !ok {
+		validator, ok = accountValidators[accountKey{"", paymentType}]
+	}
+	accountValidatorsMu.RUnlock()
+
+	if !ok {
+		return nil
+	}
+
+	return validator(account)
+}
+
+// accountCountry returns the country of the account of the payment. IBANs
+// carry their own country, other accounts are assumed to be Swedish unless a
+// country code is set.
+func (d *Payment) accountCountry() string {
+	if d.PaymentType == PaymentTypeIBAN && len(d.AccountNumber) >= 2 {
+		return strings.ToUpper(d.AccountNumber[:2])
+	}
+	if d.CountryCode != "" {
+		return d.CountryCode
+	}
+
+	return "SE"
+}
+
+// Validate checks that the payment has the required fields, that the fields
+// fit the specification and are consistent with the type and payment type,
+// and that the account and reference are valid. Errors are ValidationErrors
+// wrapping one of the Err errors of the package, such as ErrInvalidIBAN.
+func (d *Payment) Validate() error {
+	if !d.HasRequiredFields() {
+		return invalid("", ErrMissingFields, "payment is missing required fields")
+	}
+	if d.AccountNumber == "" {
+		return invalid("acc", ErrMissingAccount, "payment is missing the account number")
+	}
+	if err := d.ValidateFields(); err != nil {
+		return err
+	}
+	if err := d.validateExtraFields(); err != nil {
+		return err
+	}
+	if math.IsNaN(d.DueAmount) || math.IsInf(d.DueAmount, 0) {
+		return invalid("due", ErrInvalidAmount, "due amount %v is not a number", d.DueAmount)
+	}
+	if err := d.validateVAT(); err != nil {
+		return err
+	}
+	if err := d.validateCredit(); err != nil {
+		return err
+	}
+	if err := d.validatePaymentType(); err != nil {
+		return err
+	}
+	if err := ValidateAccount(d.accountCountry(), d.PaymentType, d.AccountNumber); err != nil {
+		var v *ValidationError
+		if errors.As(err, &v) {
+			return err
+		}
+		return &ValidationError{Field: "acc", Reason: err.Error(), Err: err}
+	}
+
+	return d.ValidateReference()
+}
+
+// bbanClearing are the countries of which bank account numbers start with
+// the clearing or registration number of the bank, so that BBAN payments
+// need no bank code.
+var bbanClearing = map[string]bool{"SE": true, "NO": true, "DK": true}
+
+// validatePaymentType checks the fields required and disallowed by the
+// payment type: IBAN payments need the BIC of the bank, a country code and
+// a currency, BBAN payments the clearing number of the bank, and Bankgiro
+// and Plusgiro payments are in SEK without a bank code.
+func (d *Payment) validatePaymentType() error {
+	switch d.PaymentType {
+	case PaymentTypeIBAN:
+		if d.BankCode == "" {
+			return invalid("bc", ErrInvalidField, "%s payment requires a bank code (BIC)", d.PaymentType)
+		}
+		if err := validateBIC(d.BankCode); err != nil {
+			return err
+		}
+		if d.CountryCode == "" {
+			return invalid("cc", ErrInvalidField, "%s payment requires a country code", d.PaymentType)
+		}
+		if d.Currency == "" {
+			return invalid("cur", ErrInvalidField, "%s payment requires a currency", d.PaymentType)
+		}
+	case PaymentTypeBBAN:
+		if d.BankCode == "" && !bbanClearing[d.accountCountry()] {
+			return invalid("bc", ErrInvalidField, "%s payment in %s requires a bank code with the clearing number", d.PaymentType, d.accountCountry())
+		}
+	case PaymentTypeBG, PaymentTypePG:
+		if d.BankCode != "" {
+			return invalid("bc", ErrInvalidField, "%s payment must not have a bank code, got %q", d.PaymentType, d.BankCode)
+		}
+		if d.Currency != "" && !strings.EqualFold(d.Currency, "SEK") {
+			return invalid("cur", ErrInvalidField, "%s payment must be in SEK, got currency %q", d.PaymentType, d.Currency)
+		}
+	default:
+		return invalid("pt", ErrInvalidField, "unknown payment type %q", d.PaymentType)
+	}
+	if d.CountryCode != "" && !isLetters(d.CountryCode, 2) {
+		return invalid("cc", ErrInvalidField, "country code %q must be two letters", d.CountryCode)
+	}
+	if d.Currency != "" && !isLetters(d.Currency, 3) {
+		return invalid("cur", ErrInvalidField, "currency %q must be a three letter ISO 4217 code", d.Currency)
+	}
+
+	return nil
+}
+
+// validateBIC validates the format of a BIC: four letters of the bank, two
+// of the country, two characters of the location and optionally three of
+// the branch.
+func validateBIC(bic string) error {
+	if len(bic) != 8 && len(bic) != 11 {
+		return invalid("bc", ErrInvalidField, "bank code %q must be a BIC of 8 or 11 characters", bic)
+	}
+	for i, c := range strings.ToUpper(bic) {
+		letter := c >= 'A' && c <= 'Z'
+		if !letter && (i < 6 || c < '0' || c > '9') {
+			return invalid("bc", ErrInvalidField, "bank code %q is not a valid BIC", bic)
+		}
+	}
+
+	return nil
+}
+
+// isLetters reports whether the code is n letters, as country and currency
+// codes are.
+func isLetters(code string, n int) bool {
+	if len(code) != n {
+		return false
+	}
+	for _, c := range strings.ToUpper(code) {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// stripAccount removes spaces and dashes used for formatting.
+func stripAccount(account string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(account)
+}
+
+func validateDigits(name, account string, min, max int) (string, error) {
+	digits := stripAccount(account)
+	if err := digitsOnly(digits); err != nil {
+		return "", invalid("acc", ErrInvalidAccount, "%s account %q: %v", name, account, err)
+	}
+	if len(digits) < min || len(digits) > max {
+		return "", invalid("acc", ErrInvalidAccount, "%s account %q must be %d to %d digits", name, account, min, max)
+	}
+
+	return digits, nil
+}
+
+func validateMod10(name, account, digits string) error {
+	if mod10(digits[:len(digits)-1]) != digits[len(digits)-1:] {
+		return invalid("acc", ErrInvalidAccount, "%s account %q has an invalid check digit", name, account)
+	}
+
+	return nil
+}
+
+func validateBankgiro(account string) error {
+	digits, err := validateDigits("bankgiro", account, 7, 8)
+	if err != nil {
+		return err
+	}
+
+	return validateMod10("bankgiro", account, digits)
+}
+
+func validatePlusgiro(account string) error {
+	digits, err := validateDigits("plusgiro", account, 2, 8)
+	if err != nil {
+		return err
+	}
+
+	return validateMod10("plusgiro", account, digits)
+}
+
+// validateSwedishBBAN validates the format of a clearing number followed by
+// an account number. The check digit rules differ per bank and are not
+// validated.
+func validateSwedishBBAN(account string) error {
+	_, err := validateDigits("swedish bank", account, 9, 16)
+	return err
+}
+
+// validateNorwegianBBAN validates an 11 digit Norwegian account number with
+// its mod 11 check digit.
+func validateNorwegianBBAN(account string) error {
+	digits, err := validateDigits("norwegian bank", strings.ReplaceAll(account, ".", ""), 11, 11)
+	if err != nil {
+		return err
+	}
+
+	weights := []int{5, 4, 3, 2, 7, 6, 5, 4, 3, 2}
+	sum := 0
+	for i, w := range weights {
+		sum += int(digits[i]-'0') * w
+	}
+
+	if check := (11 - sum%11) % 11; check == 10 || int(digits[10]-'0') != check {
+		return invalid("acc", ErrInvalidAccount, "norwegian bank account %q has an invalid check digit", account)
+	}
+
+	return nil
+}
+
+// validateDanishBBAN validates a 4 digit registration number followed by an
+// account number of up to 10 digits.
+func validateDanishBBAN(account string) error {
+	_, err := validateDigits("danish bank", account, 5, 14)
+	return err
+}
+
+// ibanLengths is the length of IBANs per country.
+var ibanLengths = map[string]int{
+	"AT": 20, "BE": 16, "CH": 21, "DE": 22, "DK": 18, "EE": 20, "ES": 24,
+	"FI": 18, "FO": 18, "FR": 27, "GB": 22, "GL": 18, "IE": 22, "IS": 26,
+	"IT": 27, "LI": 21, "LT": 20, "LU": 20, "LV": 21, "NL": 18, "NO": 15, "PL": 28,
+	"PT": 25, "SE": 24,
+}
+
+// validateIBAN validates the length and mod 97 checksum of an IBAN.
+func validateIBAN(account string) error {
+	iban := strings.ToUpper(strings.ReplaceAll(account, " ", ""))
+	if len(iban) < 15 || len(iban) > 34 {
+		return invalid("acc", ErrInvalidIBAN, "iban %q has invalid length %d", account, len(iban))
+	}
+	if want, ok := ibanLengths[iban[:2]]; ok && len(iban) != want {
+		return invalid("acc", ErrInvalidIBAN, "iban %q must be %d characters for %s", account, want, iban[:2])
+	}
+
+	var digits strings.Builder
+	for _, r := range iban[4:] + iban[:4] {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r >= 'A' && r <= 'Z':
+			fmt.Fprintf(&digits, "%d", r-'A'+10)
+		default:
+			return invalid("acc", ErrInvalidIBAN, "iban %q contains invalid character %q", account, r)
+		}
+	}
+
+	n, _ := new(big.Int).SetString(digits.String(), 10)
+	if new(big.Int).Mod(n, big.NewInt(97)).Int64() != 1 {
+		return invalid("acc", ErrInvalidIBAN, "iban %q has an invalid checksum", account)
+	}
+
+	return nil
+}
+
+// validateFinnishIBAN validates a Finnish IBAN, which also carries a Luhn
+// check digit in the domestic account number.
+func validateFinnishIBAN(account string) error {
+	if err := validateIBAN(account); err != nil {
+		return err
+	}
+
+	bban := strings.ReplaceAll(account, " ", "")[4:]
+	if err := validateMod10("finnish", account, bban); err != nil {
+		return err
+	}
+
+	return nil
+}
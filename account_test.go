@@ -0,0 +1,260 @@
+package payqr
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAccount(t *testing.T) {
+	tests := []struct {
+		name        string
+		countryCode string
+		paymentType PaymentType
+		account     string
+		wantErr     bool
+	}{
+		{name: "Bankgiro", countryCode: "SE", paymentType: PaymentTypeBG, account: "5536-7742"},
+		{name: "Bankgiro with bad check digit", countryCode: "SE", paymentType: PaymentTypeBG, account: "5536-7743", wantErr: true},
+		{name: "Bankgiro too short", countryCode: "SE", paymentType: PaymentTypeBG, account: "123-4", wantErr: true},
+		{name: "Plusgiro", countryCode: "SE", paymentType: PaymentTypePG, account: "90 01 22-3"},
+		{name: "IBAN", countryCode: "DK", paymentType: PaymentTypeIBAN, account: "DK4830004073013895"},
+		{name: "IBAN with bad checksum", countryCode: "DK", paymentType: PaymentTypeIBAN, account: "DK4830004073013896", wantErr: true},
+		{name: "IBAN with bad length", countryCode: "DK", paymentType: PaymentTypeIBAN, account: "DK48300040730138951", wantErr: true},
+		{name: "Finnish IBAN", countryCode: "FI", paymentType: PaymentTypeIBAN, account: "FI21 1234 5600 0007 85"},
+		{name: "Norwegian account", countryCode: "NO", paymentType: PaymentTypeBBAN, account: "8601.11.17947"},
+		{name: "Norwegian account with bad check digit", countryCode: "NO", paymentType: PaymentTypeBBAN, account: "86011117948", wantErr: true},
+		{name: "Danish account", countryCode: "DK", paymentType: PaymentTypeBBAN, account: "3000-4073013895"},
+		{name: "Unknown country", countryCode: "US", paymentType: PaymentTypeBBAN, account: "anything"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateAccount(test.countryCode, test.paymentType, test.account)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRegisterAccountValidator(t *testing.T) {
+	errInvalid := errors.New("invalid")
+	RegisterAccountValidator("us", PaymentTypeBBAN, func(string) error { return errInvalid })
+	defer func() {
+		accountValidatorsMu.Lock()
+		delete(accountValidators, accountKey{"US", PaymentTypeBBAN})
+		accountValidatorsMu.Unlock()
+	}()
+
+	assert.Equal(t, errInvalid, ValidateAccount("US", PaymentTypeBBAN, "123"))
+}
+
+func TestPaymentValidate(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+
+	assert.NoError(t, New("5536-7742", "Test AB", "1234", "1001", 50, due).Validate())
+	assert.Error(t, New("5536-7743", "Test AB", "1234", "1001", 50, due).Validate())
+	assert.NoError(t, New("DK4830004073013895", "Test company AB", "555555-5555", "934000000000159", 10.75, due, WithPaymentType(PaymentTypeIBAN), WithCountryCode("SE"), WithBankCode("DABADKKK"), WithCurrency("DKK")).Validate())
+	assert.Error(t, New("5536-7742", "", "1234", "1001", 50, due).Validate())
+}
+
+func TestPaymentTypeFields(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name    string
+		have    *Payment
+		wantErr string
+	}{
+		{
+			name: "IBAN",
+			have: New("DE89370400440532013000", "Test GmbH", "1234", "1001", 50, due, WithPaymentType(PaymentTypeIBAN), WithBankCode("cobadeffxxx"), WithCountryCode("DE"), WithCurrency("EUR")),
+		},
+		{
+			name:    "IBAN without bank code",
+			have:    New("DE89370400440532013000", "Test GmbH", "1234", "1001", 50, due, WithPaymentType(PaymentTypeIBAN), WithCountryCode("DE")),
+			wantErr: "IBAN payment requires a bank code (BIC)",
+		},
+		{
+			name:    "IBAN with invalid BIC",
+			have:    New("DE89370400440532013000", "Test GmbH", "1234", "1001", 50, due, WithPaymentType(PaymentTypeIBAN), WithBankCode("COBA1EFF"), WithCountryCode("DE")),
+			wantErr: `bank code "COBA1EFF" is not a valid BIC`,
+		},
+		{
+			name:    "IBAN without country code",
+			have:    New("DE89370400440532013000", "Test GmbH", "1234", "1001", 50, due, WithPaymentType(PaymentTypeIBAN), WithBankCode("COBADEFF")),
+			wantErr: "IBAN payment requires a country code",
+		},
+		{
+			name:    "IBAN without currency",
+			have:    New("DE89370400440532013000", "Test GmbH", "1234", "1001", 50, due, WithPaymentType(PaymentTypeIBAN), WithBankCode("COBADEFF"), WithCountryCode("DE")),
+			wantErr: "IBAN payment requires a currency",
+		},
+		{
+			name: "Swedish BBAN with clearing number",
+			have: New("8327-9 123456789-0", "Test AB", "1234", "1001", 50, due, WithPaymentType(PaymentTypeBBAN)),
+		},
+		{
+			name:    "BBAN without clearing number",
+			have:    New("12345678", "Test Ltd", "1234", "1001", 50, due, WithPaymentType(PaymentTypeBBAN), WithCountryCode("GB")),
+			wantErr: "BBAN payment in GB requires a bank code with the clearing number",
+		},
+		{
+			name: "BBAN with bank code",
+			have: New("12345678", "Test Ltd", "1234", "1001", 50, due, WithPaymentType(PaymentTypeBBAN), WithCountryCode("GB"), WithBankCode("400515")),
+		},
+		{
+			name:    "Bankgiro with BIC",
+			have:    New("5402-9681", "Test AB", "1234", "1001", 50, due, WithBankCode("ESSESESS")),
+			wantErr: `BG payment must not have a bank code, got "ESSESESS"`,
+		},
+		{
+			name: "Bankgiro in SEK",
+			have: New("5402-9681", "Test AB", "1234", "1001", 50, due, WithCurrency("sek")),
+		},
+		{
+			name:    "Bankgiro in EUR",
+			have:    New("5402-9681", "Test AB", "1234", "1001", 50, due, WithCurrency("EUR")),
+			wantErr: `BG payment must be in SEK, got currency "EUR"`,
+		},
+		{
+			name:    "Plusgiro with BIC",
+			have:    New("90 01 22-3", "Test AB", "1234", "1001", 50, due, WithPaymentType(PaymentTypePG), WithBankCode("NDEASESS")),
+			wantErr: `PG payment must not have a bank code, got "NDEASESS"`,
+		},
+		{
+			name:    "Unknown payment type",
+			have:    New("5402-9681", "Test AB", "1234", "1001", 50, due, WithPaymentType("SWIFT")),
+			wantErr: `unknown payment type "SWIFT"`,
+		},
+		{
+			name:    "Invalid country code",
+			have:    New("5402-9681", "Test AB", "1234", "1001", 50, due, WithCountryCode("SWE")),
+			wantErr: `country code "SWE" must be two letters`,
+		},
+		{
+			name:    "Invalid currency",
+			have:    New("12345678", "Test Ltd", "1234", "1001", 50, due, WithPaymentType(PaymentTypeBBAN), WithCountryCode("GB"), WithBankCode("400515"), WithCurrency("£")),
+			wantErr: `currency "£" must be a three letter ISO 4217 code`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.have.Validate()
+			if test.wantErr != "" {
+				assert.EqualError(t, err, test.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCreditInvoice(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+
+	d := New("5402-9681", "Test AB", "1234", "1002", 250, due, WithCreditInvoice("1001"))
+	assert.Equal(t, CreditInvoiceType, d.Type)
+	assert.Equal(t, "1001", d.CreditInvoiceReference)
+	assert.Equal(t, -250.0, d.DueAmount)
+	assert.NoError(t, d.Validate())
+
+	tests := []struct {
+		name    string
+		have    *Payment
+		wantErr string
+	}{
+		{
+			name: "Nothing credited",
+			have: New("5402-9681", "Test AB", "1234", "1002", 0, due, WithCreditInvoice("1001")),
+		},
+		{
+			name:    "Without credited invoice",
+			have:    New("5402-9681", "Test AB", "1234", "1002", 250, due, WithCreditInvoice("")),
+			wantErr: "credit invoice requires the reference of the credited invoice",
+		},
+		{
+			name:    "Positive amount",
+			have:    New("5402-9681", "Test AB", "1234", "1002", 250, due, WithCreditInvoice("1001"), func(p *Payment) { p.DueAmount = 250 }),
+			wantErr: "credit invoice amount 250.00 must be negative",
+		},
+		{
+			name:    "Negative invoice",
+			have:    New("5402-9681", "Test AB", "1234", "1001", -250, due),
+			wantErr: "negative amount -250.00 is only allowed on credit invoices",
+		},
+		{
+			name:    "Invoice with credited invoice",
+			have:    New("5402-9681", "Test AB", "1234", "1001", 250, due, func(p *Payment) { p.CreditInvoiceReference = "1000" }),
+			wantErr: `credited invoice reference "1000" is only allowed on credit invoices`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.have.Validate()
+			if test.wantErr != "" {
+				assert.EqualError(t, err, test.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestNewValidated(t *testing.T) {
+	due := time.Now().AddDate(0, 0, 30)
+
+	tests := []struct {
+		name    string
+		due     time.Time
+		amount  float64
+		options []Option
+		wantErr string
+	}{
+		{
+			name:   "Valid",
+			due:    due,
+			amount: 50,
+		},
+		{
+			name:    "Invalid option",
+			due:     due,
+			amount:  50,
+			options: []Option{WithCurrency("EUR")},
+			wantErr: `BG payment must be in SEK, got currency "EUR"`,
+		},
+		{
+			name:    "Amount not a number",
+			due:     due,
+			amount:  math.NaN(),
+			wantErr: "due amount NaN is not a number",
+		},
+		{
+			name:    "Due before creation",
+			due:     time.Now().AddDate(0, 0, -30),
+			amount:  50,
+			wantErr: "due date " + time.Now().AddDate(0, 0, -30).Format("20060102") + " is before the creation date " + time.Now().Format("20060102"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d, err := NewValidated("5402-9681", "Test AB", "5555555555", "52250047157", test.amount, test.due, test.options...)
+			if test.wantErr != "" {
+				assert.EqualError(t, err, test.wantErr)
+				assert.Nil(t, d)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "52250047157", d.Reference)
+		})
+	}
+}
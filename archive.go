@@ -0,0 +1,165 @@
+//go:build !payqr_payloadonly
+
+package payqr
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// ArchiveType is the container format written by WriteArchive.
+type ArchiveType string
+
+const (
+	ArchiveZIP ArchiveType = "zip"
+	ArchiveTar ArchiveType = "tar"
+)
+
+// ArchiveOption is a modifier for WriteArchive.
+type ArchiveOption func(*archiveConfig)
+
+type archiveConfig struct {
+	typ    ArchiveType
+	name   string
+	format Format
+	size   int
+}
+
+// WithArchiveType sets the container format. Default is ArchiveZIP.
+func WithArchiveType(typ ArchiveType) ArchiveOption {
+	return func(c *archiveConfig) {
+		c.typ = typ
+	}
+}
+
+// WithEntryName sets the text/template the entry names are executed from
+// with each payment, such as "invoices/{{.Reference}}". The extension of the
+// image format is appended. Default is "{{.Reference}}".
+func WithEntryName(tmpl string) ArchiveOption {
+	return func(c *archiveConfig) {
+		c.name = tmpl
+	}
+}
+
+// WithArchiveImage sets the format and size in pixels of the images. Default
+// is PNG of 512 pixels.
+func WithArchiveImage(format Format, size int) ArchiveOption {
+	return func(c *archiveConfig) {
+		c.format, c.size = format, size
+	}
+}
+
+// archiveWriter adds entries to an archive.
+type archiveWriter interface {
+	add(name string, b []byte, modified time.Time) error
+	Close() error
+}
+
+type zipWriter struct{ *zip.Writer }
+
+func (w zipWriter) add(name string, b []byte, modified time.Time) error {
+	// The images are already compressed, except SVG.
+	method := zip.Store
+	if strings.HasSuffix(name, FormatSVG.Extension()) {
+		method = zip.Deflate
+	}
+
+	f, err := w.CreateHeader(&zip.FileHeader{Name: name, Method: method, Modified: modified})
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(b)
+
+	return err
+}
+
+type tarWriter struct{ *tar.Writer }
+
+func (w tarWriter) add(name string, b []byte, modified time.Time) error {
+	hdr := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(b)), ModTime: modified, Typeflag: tar.TypeReg}
+	if err := w.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := w.Write(b)
+
+	return err
+}
+
+// entryName executes the name template for the payment. Names must be
+// relative and may not leave the archive.
+func entryName(tmpl *template.Template, format Format, d *Payment) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, d); err != nil {
+		return "", err
+	}
+
+	name := path.Clean(buf.String())
+	if buf.Len() == 0 || name == "." || path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+		return "", fmt.Errorf("invalid entry name %q", buf.String())
+	}
+
+	return format.Filename(name), nil
+}
+
+// WriteArchive writes the QR codes of the payments to w as a ZIP or tar
+// archive, one image per payment. Each image is rendered and written before
+// the next, so the images are never all held in memory.
+//
+// An error is returned for the first payment whose code can not be rendered
+// or whose entry name is invalid or taken, with what was written so far left
+// in w. The writer is not closed.
+func WriteArchive(w io.Writer, payments []*Payment, options ...ArchiveOption) error {
+	cfg := &archiveConfig{typ: ArchiveZIP, name: "{{.Reference}}", format: FormatPNG, size: 512}
+	for _, opt := range options {
+		opt(cfg)
+	}
+
+	tmpl, err := template.New("name").Parse(cfg.name)
+	if err != nil {
+		return fmt.Errorf("archive: invalid entry name template: %w", err)
+	}
+
+	var aw archiveWriter
+	switch cfg.typ {
+	case ArchiveZIP:
+		aw = zipWriter{zip.NewWriter(w)}
+	case ArchiveTar:
+		aw = tarWriter{tar.NewWriter(w)}
+	default:
+		return fmt.Errorf("archive: unknown archive type %q", cfg.typ)
+	}
+
+	modified := time.Now()
+	names := map[string]int{}
+	for i, d := range payments {
+		name, err := entryName(tmpl, cfg.format, d)
+		if err != nil {
+			return fmt.Errorf("archive: payment %d: %w", i+1, err)
+		}
+		if prev, ok := names[name]; ok {
+			return fmt.Errorf("archive: payment %d: entry %s is taken by payment %d", i+1, name, prev)
+		}
+		names[name] = i + 1
+
+		q, err := d.QR()
+		if err != nil {
+			return fmt.Errorf("archive: payment %d: %w", i+1, err)
+		}
+		b, err := Render(q, cfg.format, cfg.size)
+		if err != nil {
+			return fmt.Errorf("archive: payment %d: %w", i+1, err)
+		}
+		if err := aw.add(name, b, modified); err != nil {
+			return err
+		}
+	}
+
+	return aw.Close()
+}
@@ -0,0 +1,113 @@
+//go:build !payqr_payloadonly
+
+package payqr
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func archivePayments() []*Payment {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+
+	return []*Payment{
+		New("5402-9681", "Test AB", "5555555555", "52250047157", 1250, due),
+		New("5050-1055", "Test AB", "5555555555", "1001", 50, due),
+	}
+}
+
+func TestWriteArchiveZIP(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteArchive(&buf, archivePayments(), WithEntryName("invoices/{{.Reference}}")))
+
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	require.Len(t, r.File, 2)
+	assert.Equal(t, "invoices/52250047157.png", r.File[0].Name)
+	assert.Equal(t, "invoices/1001.png", r.File[1].Name)
+
+	f, err := r.File[0].Open()
+	require.NoError(t, err)
+	defer f.Close()
+	b, err := io.ReadAll(f)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(b, []byte("\x89PNG")))
+}
+
+func TestWriteArchiveTar(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteArchive(&buf, archivePayments(), WithArchiveType(ArchiveTar), WithArchiveImage(FormatSVG, 256)))
+
+	r := tar.NewReader(&buf)
+	var names []string
+	for {
+		hdr, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, hdr.Name)
+
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Contains(t, string(b), "<svg")
+	}
+	assert.Equal(t, []string{"52250047157.svg", "1001.svg"}, names)
+}
+
+func TestWriteArchiveError(t *testing.T) {
+	tests := []struct {
+		name     string
+		payments []*Payment
+		options  []ArchiveOption
+	}{
+		{
+			name:     "Duplicate names",
+			payments: archivePayments(),
+			options:  []ArchiveOption{WithEntryName("{{.AccountName}}")},
+		},
+		{
+			name:     "Name outside of the archive",
+			payments: archivePayments(),
+			options:  []ArchiveOption{WithEntryName("../{{.Reference}}")},
+		},
+		{
+			name:     "Empty name",
+			payments: archivePayments(),
+			options:  []ArchiveOption{WithEntryName("{{.CreditInvoiceReference}}")},
+		},
+		{
+			name:     "Invalid template",
+			payments: archivePayments(),
+			options:  []ArchiveOption{WithEntryName("{{.Reference")},
+		},
+		{
+			name:     "Unknown field",
+			payments: archivePayments(),
+			options:  []ArchiveOption{WithEntryName("{{.Invoice}}")},
+		},
+		{
+			name:     "Unknown archive type",
+			payments: archivePayments(),
+			options:  []ArchiveOption{WithArchiveType("rar")},
+		},
+		{
+			name:     "Format not rendered",
+			payments: archivePayments(),
+			options:  []ArchiveOption{WithArchiveImage(FormatPDF, 512)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, WriteArchive(io.Discard, tt.payments, tt.options...))
+		})
+	}
+}
@@ -0,0 +1,82 @@
+// Package bancontact encodes Belgian payments as the EPC QR codes scanned by
+// the Bancontact Payconiq app and Belgian banking apps, with the Belgian
+// structured communication ("gestructureerde mededeling") as reference.
+package bancontact
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/antonlindstrom/payqr/epc"
+)
+
+// StructuredCommunication returns the 10 digit base with the mod 97 check
+// digits appended, formatted as +++123/4567/89002+++.
+func StructuredCommunication(base string) (string, error) {
+	if len(base) != 10 || !isDigits(base) {
+		return "", fmt.Errorf("bancontact: base must be 10 digits, got %q", base)
+	}
+
+	return format(base + checkDigits(base)), nil
+}
+
+// ParseStructuredCommunication returns the 12 digits of a structured
+// communication, with or without the +++ or *** delimiters and slashes, and
+// checks the check digits.
+func ParseStructuredCommunication(s string) (string, error) {
+	digits := strings.NewReplacer("+", "", "*", "", "/", "", " ", "").Replace(s)
+	if len(digits) != 12 || !isDigits(digits) {
+		return "", fmt.Errorf("bancontact: %q is not a structured communication", s)
+	}
+	if want := checkDigits(digits[:10]); digits[10:] != want {
+		return "", fmt.Errorf("bancontact: %q has check digits %s, expected %s", s, digits[10:], want)
+	}
+
+	return digits, nil
+}
+
+// Encode returns the EPC QR payload of the payment to a Belgian IBAN. A
+// reference that is a structured communication is normalized to the
+// +++123/4567/89002+++ format which Belgian apps fill in as structured
+// communication, other references are passed on as is.
+func Encode(p *payqr.Payment, options ...epc.Option) (string, error) {
+	if !strings.HasPrefix(strings.ToUpper(p.AccountNumber), "BE") {
+		return "", fmt.Errorf("bancontact: account must be a Belgian IBAN")
+	}
+
+	q := *p
+	if digits, err := ParseStructuredCommunication(p.Reference); err == nil {
+		q.Reference = format(digits)
+	} else if strings.HasPrefix(p.Reference, "+++") || strings.HasPrefix(p.Reference, "***") {
+		return "", err
+	}
+
+	return epc.Encode(&q, options...)
+}
+
+// checkDigits returns the base mod 97, where 0 is written as 97.
+func checkDigits(base string) string {
+	n, _ := strconv.ParseUint(base, 10, 64)
+	check := n % 97
+	if check == 0 {
+		check = 97
+	}
+
+	return fmt.Sprintf("%02d", check)
+}
+
+func format(digits string) string {
+	return "+++" + digits[:3] + "/" + digits[3:7] + "/" + digits[7:] + "+++"
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,76 @@
+package bancontact
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStructuredCommunication(t *testing.T) {
+	got, err := StructuredCommunication("0100000067")
+	require.NoError(t, err)
+	assert.Equal(t, "+++010/0000/06751+++", got)
+
+	got, err = StructuredCommunication("0000000097")
+	require.NoError(t, err)
+	assert.Equal(t, "+++000/0000/09797+++", got)
+
+	_, err = StructuredCommunication("123")
+	assert.Error(t, err)
+
+	digits, err := ParseStructuredCommunication("***010/0000/06751***")
+	require.NoError(t, err)
+	assert.Equal(t, "010000006751", digits)
+
+	_, err = ParseStructuredCommunication("+++010/0000/06752+++")
+	assert.Error(t, err)
+}
+
+func TestEncode(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		have    *payqr.Payment
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Structured communication",
+			have: payqr.New("BE71096123456769", "Test BV", "", "010000006751", 25, due, payqr.WithPaymentType(payqr.PaymentTypeIBAN)),
+			want: "BCD\n002\n1\nSCT\n\nTest BV\nBE71096123456769\nEUR25.00\n\n\n+++010/0000/06751+++",
+		},
+		{
+			name: "Free text",
+			have: payqr.New("BE71096123456769", "Test BV", "", "Invoice 12", 25, due, payqr.WithPaymentType(payqr.PaymentTypeIBAN)),
+			want: "BCD\n002\n1\nSCT\n\nTest BV\nBE71096123456769\nEUR25.00\n\n\nInvoice 12",
+		},
+		{
+			name:    "Bad structured communication",
+			have:    payqr.New("BE71096123456769", "Test BV", "", "+++010/0000/06752+++", 25, due, payqr.WithPaymentType(payqr.PaymentTypeIBAN)),
+			wantErr: true,
+		},
+		{
+			name:    "Dutch IBAN",
+			have:    payqr.New("NL91ABNA0417164300", "Test BV", "", "Invoice 12", 25, due, payqr.WithPaymentType(payqr.PaymentTypeIBAN)),
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Encode(test.have)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+			assert.False(t, strings.HasSuffix(got, "\n"))
+		})
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !payqr_payloadonly
+
+package bancontact
+
+import (
+	"github.com/antonlindstrom/payqr"
+	"github.com/antonlindstrom/payqr/epc"
+	"github.com/skip2/go-qrcode"
+)
+
+// QR returns the QR code of the payment, see Encode.
+func QR(p *payqr.Payment, options ...epc.Option) (*qrcode.QRCode, error) {
+	payload, err := Encode(p, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return qrcode.New(payload, qrcode.Medium)
+}
@@ -0,0 +1,91 @@
+package payqr
+
+import "time"
+
+// swedishHolidays are the Swedish weekdays without banking, other than
+// Midsummer Eve, as months and days of the year or as days after Easter
+// Sunday when the month is zero. Midsummer Day and All Saints' Day are
+// always Saturdays.
+var swedishHolidays = []struct {
+	month time.Month
+	day   int
+}{
+	{time.January, 1},   // New Year's Day
+	{time.January, 6},   // Epiphany
+	{0, -2},             // Good Friday
+	{0, 1},              // Easter Monday
+	{time.May, 1},       // May Day
+	{0, 39},             // Ascension Day
+	{time.June, 6},      // National Day
+	{time.December, 24}, // Christmas Eve
+	{time.December, 25}, // Christmas Day
+	{time.December, 26}, // Boxing Day
+	{time.December, 31}, // New Year's Eve
+}
+
+// easter returns the date of Easter Sunday of the year, by the anonymous
+// Gregorian algorithm.
+func easter(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// IsBankDay reports whether the date of t is a banking day in Sweden, that
+// is a weekday other than a bank holiday.
+func IsBankDay(t time.Time) bool {
+	if wd := t.Weekday(); wd == time.Saturday || wd == time.Sunday {
+		return false
+	}
+
+	year, month, day := t.Date()
+	if month == time.June && day >= 19 && day <= 25 && t.Weekday() == time.Friday {
+		return false // Midsummer Eve
+	}
+
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	for _, h := range swedishHolidays {
+		var holiday time.Time
+		if h.month == 0 {
+			holiday = easter(year).AddDate(0, 0, h.day)
+		} else {
+			holiday = time.Date(year, h.month, h.day, 0, 0, 0, 0, time.UTC)
+		}
+		if date.Equal(holiday) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NextBankDay returns t if it is a banking day in Sweden, or else the first
+// banking day after it, so that payments due on weekends and bank holidays
+// are due when they can be paid. See IsBankDay.
+func NextBankDay(t time.Time) time.Time {
+	for !IsBankDay(t) {
+		t = t.AddDate(0, 0, 1)
+	}
+
+	return t
+}
+
+// WithDueDateAdjustedToBankDay moves the due date forward to the next
+// banking day if it is on a weekend or bank holiday, see NextBankDay.
+func WithDueDateAdjustedToBankDay() Option {
+	return func(p *Payment) {
+		if due, err := time.Parse("20060102", p.DueDate); err == nil {
+			p.DueDate = NextBankDay(due).Format("20060102")
+		}
+	}
+}
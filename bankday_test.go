@@ -0,0 +1,53 @@
+package payqr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextBankDay(t *testing.T) {
+	tests := []struct {
+		name string
+		have time.Time
+		want time.Time
+	}{
+		{name: "Weekday", have: date(2024, time.March, 12), want: date(2024, time.March, 12)},
+		{name: "Saturday", have: date(2024, time.March, 16), want: date(2024, time.March, 18)},
+		{name: "Sunday", have: date(2024, time.March, 17), want: date(2024, time.March, 18)},
+		{name: "Good Friday", have: date(2024, time.March, 29), want: date(2024, time.April, 2)},
+		{name: "Easter Monday", have: date(2025, time.April, 21), want: date(2025, time.April, 22)},
+		{name: "Ascension Day", have: date(2024, time.May, 9), want: date(2024, time.May, 10)},
+		{name: "National Day", have: date(2024, time.June, 6), want: date(2024, time.June, 7)},
+		{name: "Midsummer Eve", have: date(2024, time.June, 21), want: date(2024, time.June, 24)},
+		{name: "Friday before Midsummer week", have: date(2024, time.June, 14), want: date(2024, time.June, 14)},
+		{name: "Christmas", have: date(2024, time.December, 24), want: date(2024, time.December, 27)},
+		{name: "New Year", have: date(2024, time.December, 31), want: date(2025, time.January, 2)},
+		{name: "Epiphany", have: date(2025, time.January, 6), want: date(2025, time.January, 7)},
+		{name: "May Day", have: date(2025, time.May, 1), want: date(2025, time.May, 2)},
+		{name: "Keeps time of day", have: time.Date(2024, time.March, 16, 13, 30, 0, 0, time.Local), want: time.Date(2024, time.March, 18, 13, 30, 0, 0, time.Local)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, NextBankDay(test.have))
+		})
+	}
+}
+
+func TestEaster(t *testing.T) {
+	assert.Equal(t, date(2000, time.April, 23), easter(2000))
+	assert.Equal(t, date(2019, time.April, 21), easter(2019))
+	assert.Equal(t, date(2024, time.March, 31), easter(2024))
+	assert.Equal(t, date(2038, time.April, 25), easter(2038))
+}
+
+func TestWithDueDateAdjustedToBankDay(t *testing.T) {
+	d := New("5402-9681", "Test AB", "1234", "1001", 50, date(2024, time.December, 24), WithDueDateAdjustedToBankDay())
+	assert.Equal(t, "20241227", d.DueDate)
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
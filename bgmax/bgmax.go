@@ -0,0 +1,241 @@
+// Package bgmax parses Bankgirot BgMax files of incoming payments and
+// reconciles the received payments with the payments they were invoiced by.
+//
+// A BgMax file has records of 80 characters, the first two of which is the
+// transaction code of the record. Each deposit to a bank account is a
+// section, opened by a 05 record and closed by a 15 record, with the
+// payments and their information records in between.
+package bgmax
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// File is a parsed BgMax file.
+type File struct {
+	Version  string
+	Created  time.Time
+	Test     bool
+	Sections []*Section
+}
+
+// Section is a deposit of payments to the payee.
+type Section struct {
+	Bankgiro     string
+	Plusgiro     string
+	Currency     string
+	BankAccount  string
+	Date         time.Time
+	SerialNumber string
+	Amount       float64
+	DepositType  string
+	Payments     []*Payment
+}
+
+// Payment is a received payment or deduction, with its information records.
+type Payment struct {
+	PayerBankgiro string
+	Reference     string
+	Amount        float64
+	ReferenceCode int // 2 for a correct OCR reference.
+	Channel       int
+	SerialNumber  string
+	Deduction     bool // Amount is deducted from the deposit.
+
+	// References are the extra references, of a payment for several
+	// invoices, with the amount of each.
+	References []Reference
+
+	Information        []string
+	Name               string
+	Address            string
+	PostalCode         string
+	Town               string
+	Country            string
+	CountryCode        string
+	OrganizationNumber string
+}
+
+// Reference is an extra reference of a payment. Amount is negative for
+// references of credited amounts.
+type Reference struct {
+	Reference string
+	Amount    float64
+}
+
+// record is a record of a BgMax file, encoded as ISO 8859-1. Positions are 1
+// based as in the specification of the format.
+type record string
+
+// field returns the text at the positions, decoded to UTF-8.
+func (r record) field(from, to int) string {
+	if from > len(r) {
+		return ""
+	}
+	if to > len(r) {
+		to = len(r)
+	}
+
+	b := []byte(r[from-1 : to])
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+
+	return strings.TrimSpace(string(runes))
+}
+
+// number returns the numeric field without leading zeros, or an empty
+// string if it is all zeros.
+func (r record) number(from, to int) string {
+	return strings.TrimLeft(r.field(from, to), "0")
+}
+
+func (r record) amount(from, to int) (float64, error) {
+	s := r.field(from, to)
+	öre, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+
+	return float64(öre) / 100, nil
+}
+
+func (r record) payment(deduction bool) (*Payment, error) {
+	amount, err := r.amount(38, 55)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Payment{
+		PayerBankgiro: r.number(3, 12),
+		Reference:     r.field(13, 37),
+		Amount:        amount,
+		SerialNumber:  r.field(58, 69),
+		Deduction:     deduction,
+	}
+	p.ReferenceCode, _ = strconv.Atoi(r.field(56, 56))
+	p.Channel, _ = strconv.Atoi(r.field(57, 57))
+
+	return p, nil
+}
+
+// Parse parses a BgMax file, encoded as ISO 8859-1 as delivered by
+// Bankgirot. The number of records is checked against the end record.
+func Parse(r io.Reader) (*File, error) {
+	f := &File{}
+
+	var (
+		section *Section
+		payment *Payment
+		counts  [4]int // Payments, deductions, extra references and deposits.
+		ended   bool
+	)
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		rec := record(strings.TrimRight(scanner.Text(), "\r"))
+		if strings.TrimSpace(string(rec)) == "" {
+			continue
+		}
+		if ended {
+			return nil, fmt.Errorf("bgmax: line %d: record after end record", line)
+		}
+
+		code := rec.field(1, 2)
+		if line == 1 && code != "01" {
+			return nil, errors.New("bgmax: file does not start with a start record")
+		}
+
+		// Payment records and their information must be in a section.
+		needPayment := strings.HasPrefix(code, "2") && code != "20" && code != "21"
+		if (strings.HasPrefix(code, "2") || code == "15") && section == nil {
+			return nil, fmt.Errorf("bgmax: line %d: record %s outside of a section", line, code)
+		}
+		if needPayment && payment == nil {
+			return nil, fmt.Errorf("bgmax: line %d: record %s without a payment", line, code)
+		}
+
+		var err error
+		switch code {
+		case "01":
+			if rec.field(3, 22) != "BGMAX" {
+				return nil, errors.New("bgmax: not a BgMax file")
+			}
+			f.Version = rec.field(23, 24)
+			f.Created, _ = time.ParseInLocation("20060102150405", rec.field(25, 38), time.Local)
+			f.Test = rec.field(45, 45) == "T"
+		case "05":
+			section = &Section{Bankgiro: rec.number(3, 12), Plusgiro: rec.number(13, 22), Currency: rec.field(23, 25)}
+			payment = nil
+			f.Sections = append(f.Sections, section)
+		case "20", "21":
+			if payment, err = rec.payment(code == "21"); err == nil {
+				section.Payments = append(section.Payments, payment)
+				if payment.Deduction {
+					counts[1]++
+				} else {
+					counts[0]++
+				}
+			}
+		case "22", "23":
+			var amount float64
+			if amount, err = rec.amount(38, 55); err == nil {
+				if code == "23" {
+					amount = -amount
+				}
+				payment.References = append(payment.References, Reference{Reference: rec.field(13, 37), Amount: amount})
+				counts[2]++
+			}
+		case "25":
+			payment.Information = append(payment.Information, rec.field(3, 52))
+		case "26":
+			payment.Name = strings.TrimSpace(rec.field(3, 37) + " " + rec.field(38, 72))
+		case "27":
+			payment.Address, payment.PostalCode = rec.field(3, 37), rec.field(38, 46)
+		case "28":
+			payment.Town, payment.Country, payment.CountryCode = rec.field(3, 37), rec.field(38, 72), rec.field(73, 74)
+		case "29":
+			payment.OrganizationNumber = rec.number(3, 14)
+		case "15":
+			section.BankAccount = rec.number(3, 37)
+			section.SerialNumber = rec.field(46, 50)
+			section.Currency = rec.field(69, 71)
+			section.DepositType = rec.field(80, 80)
+			if section.Date, err = time.Parse("20060102", rec.field(38, 45)); err == nil {
+				section.Amount, err = rec.amount(51, 68)
+			}
+			if n, _ := strconv.Atoi(rec.field(72, 79)); err == nil && n != len(section.Payments) {
+				err = fmt.Errorf("deposit has %d payments, expected %d", len(section.Payments), n)
+			}
+			section, payment = nil, nil
+			counts[3]++
+		case "70":
+			for i, want := range []string{rec.field(3, 10), rec.field(11, 18), rec.field(19, 26), rec.field(27, 34)} {
+				if n, _ := strconv.Atoi(want); n != counts[i] {
+					err = fmt.Errorf("end record counts do not match the file")
+				}
+			}
+			ended = true
+		default:
+			err = fmt.Errorf("unknown transaction code %q", code)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("bgmax: line %d: %w", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !ended {
+		return nil, errors.New("bgmax: file has no end record")
+	}
+
+	return f, nil
+}
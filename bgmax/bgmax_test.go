@@ -0,0 +1,116 @@
+package bgmax
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/antonlindstrom/payqr/reconcile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFile(t *testing.T) *File {
+	f, err := os.Open("testdata/bgmax.txt")
+	require.NoError(t, err)
+	defer f.Close()
+
+	file, err := Parse(f)
+	require.NoError(t, err)
+
+	return file
+}
+
+func TestParse(t *testing.T) {
+	f := testFile(t)
+
+	assert.Equal(t, "01", f.Version)
+	assert.False(t, f.Test)
+	assert.Equal(t, time.Date(2022, time.August, 8, 17, 30, 35, 0, time.Local), f.Created)
+	require.Len(t, f.Sections, 1)
+
+	s := f.Sections[0]
+	assert.Equal(t, "54029681", s.Bankgiro)
+	assert.Equal(t, "SEK", s.Currency)
+	assert.Equal(t, "1234567890", s.BankAccount)
+	assert.Equal(t, time.Date(2022, time.August, 8, 0, 0, 0, 0, time.UTC), s.Date)
+	assert.Equal(t, 1500.0, s.Amount)
+	require.Len(t, s.Payments, 3)
+
+	assert.Equal(t, &Payment{
+		Reference:          "52250047157",
+		Amount:             1250,
+		ReferenceCode:      2,
+		Channel:            1,
+		SerialNumber:       "000120000018",
+		Name:               "Kund AB",
+		Address:            "Lillgatan 2",
+		PostalCode:         "22222",
+		Town:               "Göteborg",
+		CountryCode:        "SE",
+		OrganizationNumber: "5555555555",
+	}, s.Payments[0])
+
+	assert.Equal(t, "9912346", s.Payments[1].PayerBankgiro)
+	assert.Equal(t, []Reference{{Reference: "1234567897", Amount: 200}, {Reference: "99999999999", Amount: 100}}, s.Payments[1].References)
+	assert.Equal(t, []string{"Two invoices"}, s.Payments[1].Information)
+	assert.True(t, s.Payments[2].Deduction)
+}
+
+func TestParseErrors(t *testing.T) {
+	b, err := os.ReadFile("testdata/bgmax.txt")
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\r\n")
+
+	tests := []struct {
+		name string
+		have []string
+	}{
+		{name: "Empty", have: nil},
+		{name: "No start record", have: lines[1:]},
+		{name: "No end record", have: lines[:len(lines)-1]},
+		{name: "Wrong counts", have: append(append([]string{}, lines[:2]...), append(lines[7:], "")...)},
+		{name: "Payment outside section", have: []string{lines[0], lines[2]}},
+		{name: "Information without payment", have: []string{lines[0], lines[1], lines[3]}},
+		{name: "Unknown record", have: []string{lines[0], "99"}},
+		{name: "Not BgMax", have: []string{"01OTHER"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(strings.Join(test.have, "\n")))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+	invoices := []*payqr.Payment{
+		payqr.New("5402-9681", "Test AB", "", "52250047157", 1250, due),
+		payqr.New("5402-9681", "Test AB", "", "1234567897", 150, due),
+		payqr.New("5402-9681", "Test AB", "", "4455667781", 100, due),
+		payqr.New("9912-3467", "Other AB", "", "99999999999", 100, due),
+	}
+
+	res := Reconcile(invoices, testFile(t))
+	require.Len(t, res.Matches, 4)
+
+	// The invoice is paid in full but has a deduction of 50.
+	assert.Equal(t, reconcile.StatusPartial, res.Matches[0].Status)
+	assert.Equal(t, 1200.0, res.Matches[0].Received)
+	assert.Len(t, res.Matches[0].Entries, 2)
+
+	assert.Equal(t, reconcile.StatusOverpaid, res.Matches[1].Status)
+	assert.Equal(t, 200.0, res.Matches[1].Received)
+
+	assert.Equal(t, reconcile.StatusUnpaid, res.Matches[2].Status)
+
+	// The reference is paid to another Bankgiro number.
+	assert.Equal(t, reconcile.StatusUnpaid, res.Matches[3].Status)
+	require.Len(t, res.Unmatched, 1)
+	assert.Equal(t, "99999999999", res.Unmatched[0].Reference)
+	assert.Equal(t, 100.0, res.Unmatched[0].Amount)
+}
@@ -0,0 +1,49 @@
+package bgmax
+
+import (
+	"github.com/antonlindstrom/payqr"
+	"github.com/antonlindstrom/payqr/reconcile"
+)
+
+// Entries returns the amounts received in the file by reference, with the
+// *Payment as source. Payments with extra references give one entry per
+// reference.
+func (f *File) Entries() []reconcile.Entry {
+	var entries []reconcile.Entry
+	for _, s := range f.Sections {
+		for _, p := range s.Payments {
+			sign := 1.0
+			if p.Deduction {
+				sign = -1
+			}
+
+			refs := p.References
+			if len(refs) == 0 {
+				refs = []Reference{{Reference: p.Reference, Amount: p.Amount}}
+			}
+			for _, ref := range refs {
+				entries = append(entries, reconcile.Entry{
+					Account:   s.Bankgiro,
+					Date:      s.Date,
+					Reference: ref.Reference,
+					Amount:    sign * ref.Amount,
+					Currency:  s.Currency,
+					Source:    p,
+				})
+			}
+		}
+	}
+
+	return entries
+}
+
+// Reconcile matches the entries of the files to the invoiced payments by
+// reference and payee Bankgiro number, see reconcile.Reconcile.
+func Reconcile(invoices []*payqr.Payment, files ...*File) *reconcile.Result {
+	var entries []reconcile.Entry
+	for _, f := range files {
+		entries = append(entries, f.Entries()...)
+	}
+
+	return reconcile.Reconcile(invoices, entries)
+}
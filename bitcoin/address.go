@@ -0,0 +1,174 @@
+package bitcoin
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Network is a Bitcoin network.
+type Network string
+
+const (
+	Mainnet Network = "mainnet"
+	Testnet Network = "testnet"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// ValidateAddress checks the checksum of a Base58Check (legacy and P2SH) or
+// Bech32/Bech32m (SegWit and Taproot) address and returns its network.
+func ValidateAddress(address string) (Network, error) {
+	lower := strings.ToLower(address)
+	switch {
+	case strings.HasPrefix(lower, "bc1"):
+		return Mainnet, validateSegwit("bc", address)
+	case strings.HasPrefix(lower, "tb1"):
+		return Testnet, validateSegwit("tb", address)
+	}
+
+	version, err := decodeBase58Check(address)
+	if err != nil {
+		return "", err
+	}
+
+	switch version {
+	case 0x00, 0x05:
+		return Mainnet, nil
+	case 0x6f, 0xc4:
+		return Testnet, nil
+	}
+
+	return "", fmt.Errorf("bitcoin: unknown address version %#x", version)
+}
+
+// decodeBase58Check returns the version byte of a Base58Check address with a
+// 20 byte hash.
+func decodeBase58Check(address string) (byte, error) {
+	n := new(big.Int)
+	for _, r := range address {
+		i := strings.IndexRune(base58Alphabet, r)
+		if i < 0 {
+			return 0, fmt.Errorf("bitcoin: invalid character %q in address", r)
+		}
+		n.Mul(n, big.NewInt(58))
+		n.Add(n, big.NewInt(int64(i)))
+	}
+
+	// Leading ones are leading zero bytes.
+	zeros := len(address) - len(strings.TrimLeft(address, "1"))
+	b := append(make([]byte, zeros), n.Bytes()...)
+	if len(b) != 25 {
+		return 0, fmt.Errorf("bitcoin: address %s has invalid length", address)
+	}
+
+	first := sha256.Sum256(b[:21])
+	second := sha256.Sum256(first[:])
+	if !bytes.Equal(second[:4], b[21:]) {
+		return 0, fmt.Errorf("bitcoin: address %s has invalid checksum", address)
+	}
+
+	return b[0], nil
+}
+
+// validateSegwit validates a SegWit address as specified in BIP-173 and
+// BIP-350.
+func validateSegwit(hrp, address string) error {
+	if address != strings.ToLower(address) && address != strings.ToUpper(address) {
+		return fmt.Errorf("bitcoin: address %s has mixed case", address)
+	}
+	address = strings.ToLower(address)
+	if len(address) > 90 {
+		return fmt.Errorf("bitcoin: address %s is too long", address)
+	}
+
+	data := make([]byte, 0, len(address)-len(hrp)-1)
+	for _, r := range address[len(hrp)+1:] {
+		i := strings.IndexRune(bech32Charset, r)
+		if i < 0 {
+			return fmt.Errorf("bitcoin: invalid character %q in address", r)
+		}
+		data = append(data, byte(i))
+	}
+	if len(data) < 7 {
+		return fmt.Errorf("bitcoin: address %s is too short", address)
+	}
+
+	version := data[0]
+	want := uint32(1)
+	if version > 0 {
+		want = 0x2bc830a3
+	}
+	if bech32Polymod(append(hrpExpand(hrp), data...)) != want {
+		return fmt.Errorf("bitcoin: address %s has invalid checksum", address)
+	}
+
+	program, err := convertBits(data[1:len(data)-6], 5, 8)
+	if err != nil {
+		return fmt.Errorf("bitcoin: address %s: %w", address, err)
+	}
+	switch {
+	case version > 16:
+		return fmt.Errorf("bitcoin: invalid witness version %d", version)
+	case len(program) < 2 || len(program) > 40:
+		return fmt.Errorf("bitcoin: invalid witness program length %d", len(program))
+	case version == 0 && len(program) != 20 && len(program) != 32:
+		return fmt.Errorf("bitcoin: invalid witness program length %d for version 0", len(program))
+	}
+
+	return nil
+}
+
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+
+	return chk
+}
+
+func hrpExpand(hrp string) []byte {
+	b := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		b = append(b, hrp[i]>>5)
+	}
+	b = append(b, 0)
+	for i := 0; i < len(hrp); i++ {
+		b = append(b, hrp[i]&31)
+	}
+
+	return b
+}
+
+// convertBits regroups the data from groups of from bits to groups of to
+// bits, without padding.
+func convertBits(data []byte, from, to uint) ([]byte, error) {
+	var acc, bits uint
+	var out []byte
+	for _, v := range data {
+		acc = acc<<from | uint(v)
+		bits += from
+		for bits >= to {
+			bits -= to
+			out = append(out, byte(acc>>bits&(1<<to-1)))
+		}
+	}
+	if bits >= from || acc<<(to-bits)&(1<<to-1) != 0 {
+		return nil, fmt.Errorf("invalid padding")
+	}
+
+	return out, nil
+}
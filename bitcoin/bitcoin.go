@@ -0,0 +1,56 @@
+// Package bitcoin encodes payments as BIP-21 bitcoin: URIs, for merchants
+// that accept bitcoin alongside bank payments.
+package bitcoin
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/antonlindstrom/payqr"
+)
+
+// Currency is the currency of the payment amount.
+const Currency = "BTC"
+
+// URI returns the BIP-21 URI of the payment. The account number is the
+// address, the account name is the label and the reference is the message.
+// The amount is in bitcoin, a zero amount lets the payer enter it.
+func URI(p *payqr.Payment) (string, error) {
+	if p.Currency != "" && !strings.EqualFold(p.Currency, Currency) {
+		return "", fmt.Errorf("bitcoin: currency must be %s, got %s", Currency, p.Currency)
+	}
+	if _, err := ValidateAddress(p.AccountNumber); err != nil {
+		return "", err
+	}
+	if p.DueAmount < 0 || p.DueAmount > 21e6 {
+		return "", fmt.Errorf("bitcoin: amount %v is out of range", p.DueAmount)
+	}
+
+	var params []string
+	if p.DueAmount > 0 {
+		// Amounts are rounded to whole satoshis.
+		amount := strconv.FormatFloat(math.Round(p.DueAmount*1e8)/1e8, 'f', -1, 64)
+		params = append(params, "amount="+amount)
+	}
+	if p.AccountName != "" {
+		params = append(params, "label="+escape(p.AccountName))
+	}
+	if p.Reference != "" {
+		params = append(params, "message="+escape(p.Reference))
+	}
+
+	uri := "bitcoin:" + p.AccountNumber
+	if len(params) > 0 {
+		uri += "?" + strings.Join(params, "&")
+	}
+
+	return uri, nil
+}
+
+// escape escapes a query value with spaces as %20 rather than "+".
+func escape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
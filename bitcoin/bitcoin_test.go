@@ -0,0 +1,93 @@
+package bitcoin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		have    string
+		want    Network
+		wantErr bool
+	}{
+		{name: "P2PKH", have: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", want: Mainnet},
+		{name: "P2SH", have: "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", want: Mainnet},
+		{name: "Testnet P2PKH", have: "mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn", want: Testnet},
+		{name: "SegWit", have: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", want: Mainnet},
+		{name: "SegWit upper case", have: "BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", want: Mainnet},
+		{name: "Taproot", have: "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", want: Mainnet},
+		{name: "Testnet SegWit", have: "tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7", want: Testnet},
+		{name: "Bad checksum", have: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN3", wantErr: true},
+		{name: "Bad character", have: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN0", wantErr: true},
+		{name: "Bad bech32 checksum", have: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5", wantErr: true},
+		{name: "Version 0 with bech32m", have: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kemeawh", wantErr: true},
+		{name: "Mixed case", have: "bc1qW508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ValidateAddress(test.have)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestURI(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		have    *payqr.Payment
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Full",
+			have: payqr.New("1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", "Luke-Jr", "", "Donation for project xyz", 20.3, due, payqr.WithCurrency("BTC")),
+			want: "bitcoin:1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2?amount=20.3&label=Luke-Jr&message=Donation%20for%20project%20xyz",
+		},
+		{
+			name: "Address only",
+			have: payqr.New("bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", "", "", "", 0, due),
+			want: "bitcoin:bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+		},
+		{
+			name: "Satoshi rounding",
+			have: payqr.New("bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", "", "", "", 0.000000014, due),
+			want: "bitcoin:bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4?amount=0.00000001",
+		},
+		{
+			name:    "Invalid address",
+			have:    payqr.New("5402-9681", "Test AB", "", "", 1, due),
+			wantErr: true,
+		},
+		{
+			name:    "SEK",
+			have:    payqr.New("bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", "", "", "", 1, due, payqr.WithCurrency("SEK")),
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := URI(test.have)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !payqr_payloadonly
+
+package bitcoin
+
+import (
+	"github.com/antonlindstrom/payqr"
+	"github.com/skip2/go-qrcode"
+)
+
+// QR returns a QR code of the BIP-21 URI of the payment.
+func QR(p *payqr.Payment) (*qrcode.QRCode, error) {
+	uri, err := URI(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return qrcode.New(uri, qrcode.Medium)
+}
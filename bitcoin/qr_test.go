@@ -0,0 +1,26 @@
+//go:build !payqr_payloadonly
+
+package bitcoin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQR(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+	p := payqr.New("1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", "Luke-Jr", "", "Donation for project xyz", 20.3, due, payqr.WithCurrency("BTC"))
+
+	q, err := QR(p)
+	require.NoError(t, err)
+	want, err := URI(p)
+	require.NoError(t, err)
+	assert.Equal(t, want, q.Content)
+
+	_, err = QR(payqr.New("1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", "Luke-Jr", "", "", 20.3, due, payqr.WithCurrency("SEK")))
+	assert.Error(t, err)
+}
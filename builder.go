@@ -0,0 +1,90 @@
+package payqr
+
+import "time"
+
+// Builder builds a payment with chained setters, which reads better than New
+// when mapping many optional fields, e.g. from an ERP:
+//
+//	p, err := NewBuilder().
+//		Account(PaymentTypeBG, "5536-7742").
+//		Payee("Test AB", "555555-5555").
+//		Reference("1001").
+//		Due(NewMoney(5000, "SEK"), due).
+//		Build()
+//
+// Setters for optional fields are applied in order, as options to New.
+type Builder struct {
+	accountNumber string
+	accountName   string
+	companyID     string
+	reference     string
+	dueDate       time.Time
+	options       []Option
+}
+
+// NewBuilder returns a builder of a payment with the defaults of New.
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// Account sets the payment type and account number.
+func (b *Builder) Account(typ PaymentType, number string) *Builder {
+	b.accountNumber = number
+	return b.With(WithPaymentType(typ))
+}
+
+// Payee sets the name and organization number of the payee.
+func (b *Builder) Payee(name, companyID string) *Builder {
+	b.accountName, b.companyID = name, companyID
+	return b
+}
+
+// Reference sets the reference of the invoice, such as an OCR number.
+func (b *Builder) Reference(reference string) *Builder {
+	b.reference = reference
+	return b
+}
+
+// Due sets the due amount, with its currency, and the due date.
+func (b *Builder) Due(amount Money, date time.Time) *Builder {
+	b.dueDate = date
+	return b.With(WithDue(amount))
+}
+
+// CreationDate sets the creation date, default is today.
+func (b *Builder) CreationDate(t time.Time) *Builder {
+	return b.With(WithCreationDate(t))
+}
+
+// CreditInvoice makes the payment a credit invoice of the invoice with the
+// reference, see WithCreditInvoice. It must be set after the due amount.
+func (b *Builder) CreditInvoice(invoiceReference string) *Builder {
+	return b.With(WithCreditInvoice(invoiceReference))
+}
+
+// Currency sets the currency.
+func (b *Builder) Currency(currency string) *Builder {
+	return b.With(WithCurrency(currency))
+}
+
+// Address sets the address of the payee.
+func (b *Builder) Address(address string) *Builder {
+	return b.With(WithAddress(address))
+}
+
+// Bank sets the bank code and the country code of the account.
+func (b *Builder) Bank(bankCode, countryCode string) *Builder {
+	return b.With(WithBankCode(bankCode), WithCountryCode(countryCode))
+}
+
+// With applies the options, for the fields without a setter.
+func (b *Builder) With(options ...Option) *Builder {
+	b.options = append(b.options, options...)
+	return b
+}
+
+// Build returns the payment, or an error if it does not validate, as
+// NewValidated.
+func (b *Builder) Build() (*Payment, error) {
+	return NewValidated(b.accountNumber, b.accountName, b.companyID, b.reference, 0, b.dueDate, b.options...)
+}
@@ -0,0 +1,46 @@
+package payqr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder(t *testing.T) {
+	created := time.Now()
+	due := created.AddDate(0, 0, 30)
+
+	d, err := NewBuilder().
+		Account(PaymentTypeIBAN, "DE89370400440532013000").
+		Payee("Test GmbH", "1234").
+		Reference("1001").
+		Due(NewMoney(1075, "EUR"), due).
+		CreationDate(created).
+		Bank("COBADEFF", "DE").
+		Address("Hauptstraße 1").
+		Build()
+	require.NoError(t, err)
+
+	want := New("DE89370400440532013000", "Test GmbH", "1234", "1001", 10.75, due, WithCreationDate(created),
+		WithPaymentType(PaymentTypeIBAN), WithCurrency("EUR"), WithBankCode("COBADEFF"), WithCountryCode("DE"), WithAddress("Hauptstraße 1"))
+	assert.Equal(t, want, d)
+
+	d, err = NewBuilder().
+		Account(PaymentTypeBG, "5402-9681").
+		Payee("Test AB", "5555555555").
+		Reference("1002").
+		Due(NewMoney(25000, ""), due).
+		CreditInvoice("1001").
+		Build()
+	require.NoError(t, err)
+	assert.Equal(t, CreditInvoiceType, d.Type)
+	assert.Equal(t, -250.0, d.DueAmount)
+
+	_, err = NewBuilder().Account(PaymentTypeBG, "5402-9681").Reference("1001").Due(NewMoney(100, "SEK"), due).Build()
+	assert.EqualError(t, err, "payment is missing required fields")
+
+	_, err = NewBuilder().Account(PaymentTypeBG, "5402-9681").Payee("Test AB", "5555555555").Reference("1001").Due(NewMoney(100, "EUR"), due).Build()
+	assert.EqualError(t, err, `BG payment must be in SEK, got currency "EUR"`)
+}
@@ -0,0 +1,38 @@
+// Package cache keeps rendered QR codes, so that repeated renders of the
+// same payment, such as an invoice page reloaded many times, return the
+// stored image instead of encoding it again.
+//
+// Caches implement the Cache interface. LRU is an in-memory cache with a
+// capacity and expiry, other implementations can share images between
+// instances of a service.
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+)
+
+// Cache stores values by key. Implementations must be safe for concurrent
+// use.
+type Cache interface {
+	// Get returns the value of the key, or false if it is not cached or has
+	// expired.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Set stores the value of the key for the ttl, or for the default of
+	// the cache if zero.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// Key returns the key of the image of the payload in the format and size.
+// Codes are rendered deterministically, so the key identifies the image.
+func Key(payload string, format payqr.Format, size int) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d\x00%s", format, size, payload)))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,17 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKey(t *testing.T) {
+	key := Key("payload", payqr.FormatPNG, 512)
+	assert.Len(t, key, 64)
+	assert.Equal(t, key, Key("payload", payqr.FormatPNG, 512))
+	assert.NotEqual(t, key, Key("payload", payqr.FormatSVG, 512))
+	assert.NotEqual(t, key, Key("payload", payqr.FormatPNG, 256))
+	assert.NotEqual(t, key, Key("other payload", payqr.FormatPNG, 512))
+}
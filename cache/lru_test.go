@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRU(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRU(2, 0)
+
+	require.NoError(t, c.Set(ctx, "a", []byte("1"), 0))
+	require.NoError(t, c.Set(ctx, "b", []byte("2"), 0))
+
+	// Using a makes b the least recently used.
+	v, ok, err := c.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), v)
+
+	require.NoError(t, c.Set(ctx, "c", []byte("3"), 0))
+	assert.Equal(t, 2, c.Len())
+	_, ok, _ = c.Get(ctx, "b")
+	assert.False(t, ok, "b is evicted")
+	_, ok, _ = c.Get(ctx, "a")
+	assert.True(t, ok)
+
+	require.NoError(t, c.Set(ctx, "a", []byte("4"), 0))
+	v, _, _ = c.Get(ctx, "a")
+	assert.Equal(t, []byte("4"), v, "values are replaced")
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestLRUExpiry(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2022, 7, 7, 12, 0, 0, 0, time.UTC)
+	c := NewLRU(10, time.Minute)
+	c.now = func() time.Time { return now }
+
+	require.NoError(t, c.Set(ctx, "default", []byte("1"), 0))
+	require.NoError(t, c.Set(ctx, "long", []byte("2"), time.Hour))
+
+	now = now.Add(59 * time.Second)
+	_, ok, _ := c.Get(ctx, "default")
+	assert.True(t, ok)
+
+	now = now.Add(time.Second)
+	_, ok, _ = c.Get(ctx, "default")
+	assert.False(t, ok, "expired after the default ttl")
+	assert.Equal(t, 1, c.Len(), "expired values are removed")
+
+	_, ok, _ = c.Get(ctx, "long")
+	assert.True(t, ok, "kept for its own ttl")
+}
@@ -0,0 +1,314 @@
+// Package redis is a cache backed by Redis, so that the instances of a
+// horizontally scaled service share rendered codes and other values, such as
+// Swish payment request tokens. It speaks the Redis protocol itself and only
+// needs the GET and SET commands.
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/antonlindstrom/payqr/cache"
+)
+
+var _ cache.Cache = (*Cache)(nil)
+
+// Option is a modifier for the Cache.
+type Option func(*Cache)
+
+// WithPrefix sets the prefix of the keys in Redis. Default is "payqr:".
+func WithPrefix(prefix string) Option {
+	return func(c *Cache) {
+		c.prefix = prefix
+	}
+}
+
+// WithTTL sets the expiry of values set without a ttl. Default is to keep
+// them until Redis evicts them.
+func WithTTL(ttl time.Duration) Option {
+	return func(c *Cache) {
+		c.ttl = ttl
+	}
+}
+
+// WithAuth authenticates connections, with an empty username for the
+// password of the default user.
+func WithAuth(username, password string) Option {
+	return func(c *Cache) {
+		c.username, c.password = username, password
+	}
+}
+
+// WithDB selects the database of the connections. Default is 0.
+func WithDB(db int) Option {
+	return func(c *Cache) {
+		c.db = db
+	}
+}
+
+// WithDialer sets the function connecting to Redis, e.g. to connect over
+// TLS. Default is a net.Dialer.
+func WithDialer(dial func(ctx context.Context, network, addr string) (net.Conn, error)) Option {
+	return func(c *Cache) {
+		c.dial = dial
+	}
+}
+
+// WithMaxIdle sets the number of idle connections kept open. Default is 8.
+func WithMaxIdle(n int) Option {
+	return func(c *Cache) {
+		c.maxIdle = n
+	}
+}
+
+// Cache is a cache.Cache storing values in Redis. It is safe for concurrent
+// use, each call uses a connection of its own.
+type Cache struct {
+	addr     string
+	prefix   string
+	ttl      time.Duration
+	username string
+	password string
+	db       int
+	dial     func(ctx context.Context, network, addr string) (net.Conn, error)
+	maxIdle  int
+
+	mu   sync.Mutex
+	idle []*conn
+}
+
+// New returns a cache of the Redis server at the address, as host:port.
+// Connections are opened when needed.
+func New(addr string, options ...Option) *Cache {
+	c := &Cache{
+		addr:    addr,
+		prefix:  "payqr:",
+		dial:    (&net.Dialer{Timeout: 5 * time.Second}).DialContext,
+		maxIdle: 8,
+	}
+
+	for _, opt := range options {
+		opt(c)
+	}
+
+	return c
+}
+
+// Error is an error reply of Redis.
+type Error struct {
+	Message string
+}
+
+func (e *Error) Error() string {
+	return "redis: " + e.Message
+}
+
+// Get returns the value of the key, or false if it is not set or has
+// expired.
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	var (
+		value []byte
+		ok    bool
+	)
+	err := c.do(ctx, func(cn *conn) error {
+		var err error
+		value, ok, err = cn.command("GET", c.prefix+key)
+		return err
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return value, ok, nil
+}
+
+// Set stores the value of the key for the ttl, or for the default of the
+// cache if zero.
+func (c *Cache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl == 0 {
+		ttl = c.ttl
+	}
+
+	args := []string{"SET", c.prefix + key, string(value)}
+	if ttl > 0 {
+		ms := ttl.Milliseconds()
+		if ms == 0 {
+			ms = 1
+		}
+		args = append(args, "PX", strconv.FormatInt(ms, 10))
+	}
+
+	return c.do(ctx, func(cn *conn) error {
+		_, _, err := cn.command(args...)
+		return err
+	})
+}
+
+// Close closes the idle connections.
+func (c *Cache) Close() error {
+	c.mu.Lock()
+	idle := c.idle
+	c.idle = nil
+	c.mu.Unlock()
+
+	var err error
+	for _, cn := range idle {
+		if cerr := cn.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+
+	return err
+}
+
+// do runs f on a connection, with the deadline of the context. The
+// connection is reused unless it failed; error replies leave it usable.
+func (c *Cache) do(ctx context.Context, f func(cn *conn) error) error {
+	cn, err := c.get(ctx)
+	if err != nil {
+		return err
+	}
+
+	deadline, _ := ctx.Deadline()
+	if err := cn.SetDeadline(deadline); err != nil {
+		cn.Close()
+		return wrap(err)
+	}
+
+	err = f(cn)
+	var replyErr *Error
+	if err != nil && !errors.As(err, &replyErr) {
+		cn.Close()
+		return wrap(err)
+	}
+	c.put(cn)
+
+	return err
+}
+
+// wrap marks errors other than error replies, which are marked already, as
+// errors of the package.
+func wrap(err error) error {
+	var replyErr *Error
+	if errors.As(err, &replyErr) {
+		return err
+	}
+
+	return fmt.Errorf("redis: %w", err)
+}
+
+// get returns an idle connection, or a new one.
+func (c *Cache) get(ctx context.Context) (*conn, error) {
+	c.mu.Lock()
+	if n := len(c.idle); n > 0 {
+		cn := c.idle[n-1]
+		c.idle = c.idle[:n-1]
+		c.mu.Unlock()
+		return cn, nil
+	}
+	c.mu.Unlock()
+
+	nc, err := c.dial(ctx, "tcp", c.addr)
+	if err != nil {
+		return nil, wrap(err)
+	}
+	cn := &conn{Conn: nc, r: bufio.NewReader(nc), w: bufio.NewWriter(nc)}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = nc.SetDeadline(deadline)
+	}
+	if c.password != "" {
+		args := []string{"AUTH", c.password}
+		if c.username != "" {
+			args = []string{"AUTH", c.username, c.password}
+		}
+		if _, _, err := cn.command(args...); err != nil {
+			cn.Close()
+			return nil, wrap(err)
+		}
+	}
+	if c.db != 0 {
+		if _, _, err := cn.command("SELECT", strconv.Itoa(c.db)); err != nil {
+			cn.Close()
+			return nil, wrap(err)
+		}
+	}
+
+	return cn, nil
+}
+
+// put keeps the connection for reuse, or closes it if enough are idle.
+func (c *Cache) put(cn *conn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.idle) >= c.maxIdle {
+		cn.Close()
+		return
+	}
+	c.idle = append(c.idle, cn)
+}
+
+// conn is a connection to Redis.
+type conn struct {
+	net.Conn
+	r *bufio.Reader
+	w *bufio.Writer
+}
+
+// command sends the command and reads its reply. The value is false for nil
+// replies.
+func (cn *conn) command(args ...string) ([]byte, bool, error) {
+	fmt.Fprintf(cn.w, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(cn.w, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if err := cn.w.Flush(); err != nil {
+		return nil, false, err
+	}
+
+	return cn.reply()
+}
+
+// reply reads a reply of a simple string, error, integer or bulk string.
+func (cn *conn) reply() ([]byte, bool, error) {
+	line, err := cn.r.ReadString('\n')
+	if err != nil {
+		return nil, false, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, false, fmt.Errorf("invalid reply %q", line)
+	}
+	kind, text := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+', ':':
+		return []byte(text), true, nil
+	case '-':
+		return nil, false, &Error{Message: text}
+	case '$':
+		n, err := strconv.Atoi(text)
+		if err != nil {
+			return nil, false, fmt.Errorf("invalid bulk string length %q", text)
+		}
+		if n < 0 {
+			return nil, false, nil
+		}
+
+		b := make([]byte, n+2)
+		if _, err := io.ReadFull(cn.r, b); err != nil {
+			return nil, false, err
+		}
+
+		return b[:n], true, nil
+	}
+
+	return nil, false, fmt.Errorf("unsupported reply %q", line)
+}
@@ -0,0 +1,205 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// server is a Redis server knowing the commands used by the cache.
+type server struct {
+	addr     string
+	password string
+
+	mu       sync.Mutex
+	values   map[string]string
+	commands []string
+	conns    int
+}
+
+func newServer(t *testing.T, password string) *server {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { lis.Close() })
+
+	s := &server{addr: lis.Addr().String(), password: password, values: map[string]string{}}
+	go func() {
+		for {
+			c, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.conns++
+			s.mu.Unlock()
+			go s.serve(c)
+		}
+	}()
+
+	return s
+}
+
+// log returns the commands received.
+func (s *server) log() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.commands...)
+}
+
+func (s *server) serve(c net.Conn) {
+	defer c.Close()
+
+	r := bufio.NewReader(c)
+	authenticated := s.password == ""
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.commands = append(s.commands, strings.Join(args, " "))
+		var reply string
+		switch {
+		case args[0] == "AUTH" && args[len(args)-1] == s.password:
+			authenticated, reply = true, "+OK\r\n"
+		case args[0] == "AUTH":
+			reply = "-WRONGPASS invalid password\r\n"
+		case !authenticated:
+			reply = "-NOAUTH Authentication required.\r\n"
+		case args[0] == "SELECT":
+			reply = "+OK\r\n"
+		case args[0] == "GET":
+			if v, ok := s.values[args[1]]; ok {
+				reply = fmt.Sprintf("$%d\r\n%s\r\n", len(v), v)
+			} else {
+				reply = "$-1\r\n"
+			}
+		case args[0] == "SET":
+			s.values[args[1]] = args[2]
+			reply = "+OK\r\n"
+		default:
+			reply = "-ERR unknown command\r\n"
+		}
+		s.mu.Unlock()
+
+		if _, err := io.WriteString(c, reply); err != nil {
+			return
+		}
+	}
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, n)
+	for i := range args {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+		if err != nil {
+			return nil, err
+		}
+		b := make([]byte, size+2)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		args[i] = string(b[:size])
+	}
+
+	return args, nil
+}
+
+func TestCache(t *testing.T) {
+	ctx := context.Background()
+	s := newServer(t, "")
+	c := New(s.addr, WithTTL(time.Hour))
+	defer c.Close()
+
+	_, ok, err := c.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	value := []byte("\x89PNG\r\n\x1a\n binary \x00 value")
+	require.NoError(t, c.Set(ctx, "key", value, 0))
+	require.NoError(t, c.Set(ctx, "token", []byte("abc"), time.Minute))
+
+	got, ok, err := c.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, value, got)
+
+	assert.Equal(t, []string{
+		"GET payqr:key",
+		"SET payqr:key " + string(value) + " PX 3600000",
+		"SET payqr:token abc PX 60000",
+		"GET payqr:key",
+	}, s.log())
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	assert.Equal(t, 1, s.conns, "the connection is reused")
+}
+
+func TestCacheOptions(t *testing.T) {
+	ctx := context.Background()
+	s := newServer(t, "secret")
+
+	c := New(s.addr, WithAuth("", "secret"), WithDB(2), WithPrefix("shop:"))
+	defer c.Close()
+	require.NoError(t, c.Set(ctx, "key", []byte("value"), 0))
+	assert.Equal(t, []string{"AUTH secret", "SELECT 2", "SET shop:key value"}, s.log())
+
+	c = New(s.addr, WithAuth("payqr", "wrong"))
+	defer c.Close()
+	_, _, err := c.Get(ctx, "key")
+	require.Error(t, err)
+	assert.IsType(t, &Error{}, err)
+	assert.Equal(t, "redis: WRONGPASS invalid password", err.Error())
+}
+
+func TestCacheErrorReply(t *testing.T) {
+	ctx := context.Background()
+	s := newServer(t, "secret")
+	c := New(s.addr)
+	defer c.Close()
+
+	_, _, err := c.Get(ctx, "key")
+	assert.Equal(t, "redis: NOAUTH Authentication required.", err.Error())
+	_, _, err = c.Get(ctx, "key")
+	assert.Error(t, err)
+	assert.Len(t, s.log(), 2)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	assert.Equal(t, 1, s.conns, "error replies keep the connection")
+}
+
+func TestCacheUnavailable(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := lis.Addr().String()
+	lis.Close()
+
+	_, _, err = New(addr).Get(context.Background(), "key")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "redis: ")
+}
@@ -0,0 +1,28 @@
+//go:build !payqr_payloadonly
+
+package cache
+
+import (
+	"context"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/skip2/go-qrcode"
+)
+
+// Render returns the image of the code in the format and size from the
+// cache, or renders it with payqr.Render and stores it. Errors of the cache
+// are not returned, a failing cache only costs rendering the image.
+func Render(ctx context.Context, c Cache, q *qrcode.QRCode, format payqr.Format, size int) ([]byte, error) {
+	key := Key(q.Content, format, size)
+	if b, ok, err := c.Get(ctx, key); err == nil && ok {
+		return b, nil
+	}
+
+	b, err := payqr.Render(q, format, size)
+	if err != nil {
+		return nil, err
+	}
+	_ = c.Set(ctx, key, b, 0)
+
+	return b, nil
+}
@@ -0,0 +1,74 @@
+//go:build !payqr_payloadonly
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/skip2/go-qrcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingCache counts how often values are stored in the underlying cache.
+type countingCache struct {
+	Cache
+	sets int
+	err  error
+}
+
+func (c *countingCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	if c.err != nil {
+		return nil, false, c.err
+	}
+
+	return c.Cache.Get(ctx, key)
+}
+
+func (c *countingCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.sets++
+	if c.err != nil {
+		return c.err
+	}
+
+	return c.Cache.Set(ctx, key, value, ttl)
+}
+
+func TestRender(t *testing.T) {
+	ctx := context.Background()
+	c := &countingCache{Cache: NewLRU(10, time.Hour)}
+
+	q, err := qrcode.New("C1231111111;50.00;My message;6", qrcode.High)
+	require.NoError(t, err)
+	want, err := q.PNG(256)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		b, err := Render(ctx, c, q, payqr.FormatPNG, 256)
+		require.NoError(t, err)
+		assert.Equal(t, want, b)
+	}
+	assert.Equal(t, 1, c.sets, "rendered once")
+
+	_, err = Render(ctx, c, q, payqr.FormatSVG, 256)
+	require.NoError(t, err)
+	assert.Equal(t, 2, c.sets)
+
+	_, err = Render(ctx, c, q, payqr.FormatPDF, 256)
+	assert.Error(t, err)
+}
+
+func TestRenderFailingCache(t *testing.T) {
+	c := &countingCache{Cache: NewLRU(10, 0), err: errors.New("unavailable")}
+
+	q, err := qrcode.New("C1231111111;50.00;My message;6", qrcode.High)
+	require.NoError(t, err)
+
+	b, err := Render(context.Background(), c, q, payqr.FormatPNG, 256)
+	require.NoError(t, err)
+	assert.NotEmpty(t, b)
+}
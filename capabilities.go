@@ -0,0 +1,61 @@
+package payqr
+
+import (
+	"sort"
+	"sync"
+)
+
+// Capability is a feature that may or may not be built in. Heavy features
+// live in their own packages and register their capability when imported,
+// the same way image formats are registered with the image package.
+type Capability string
+
+const (
+	CapabilityPNG      Capability = "png"
+	CapabilitySwish    Capability = "swish"
+	CapabilitySwishAPI Capability = "swishapi"
+	CapabilityPDF      Capability = "pdf"
+	CapabilityWallet   Capability = "wallet"
+	CapabilityTIFF     Capability = "tiff"
+)
+
+var (
+	capabilitiesMu sync.RWMutex
+	capabilities   = map[Capability]bool{
+		CapabilitySwish: true,
+	}
+)
+
+// RegisterCapability registers that the capability is supported by the
+// current build. It is meant to be called from the init function of the
+// package implementing the feature.
+func RegisterCapability(c Capability) {
+	capabilitiesMu.Lock()
+	defer capabilitiesMu.Unlock()
+
+	capabilities[c] = true
+}
+
+// HasCapability reports whether the capability is supported by the current
+// build.
+func HasCapability(c Capability) bool {
+	capabilitiesMu.RLock()
+	defer capabilitiesMu.RUnlock()
+
+	return capabilities[c]
+}
+
+// Capabilities returns the capabilities supported by the current build,
+// sorted by name.
+func Capabilities() []Capability {
+	capabilitiesMu.RLock()
+	defer capabilitiesMu.RUnlock()
+
+	list := make([]Capability, 0, len(capabilities))
+	for c := range capabilities {
+		list = append(list, c)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+
+	return list
+}
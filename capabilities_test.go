@@ -0,0 +1,19 @@
+package payqr
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCapabilities(t *testing.T) {
+	assert.True(t, HasCapability(CapabilitySwish))
+	assert.False(t, HasCapability("test"))
+
+	RegisterCapability("test")
+	assert.True(t, HasCapability("test"))
+	assert.Contains(t, Capabilities(), Capability("test"))
+	list := Capabilities()
+	assert.True(t, sort.SliceIsSorted(list, func(i, j int) bool { return list[i] < list[j] }))
+}
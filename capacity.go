@@ -0,0 +1,116 @@
+//go:build !payqr_payloadonly
+
+package payqr
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// maxPayloadBytes are the most bytes in byte mode that fit in a QR code, of
+// version 40, by recovery level.
+var maxPayloadBytes = map[qrcode.RecoveryLevel]int{
+	qrcode.Low:     2953,
+	qrcode.Medium:  2331,
+	qrcode.High:    1663,
+	qrcode.Highest: 1273,
+}
+
+var levelNames = map[qrcode.RecoveryLevel]string{
+	qrcode.Low:     "low",
+	qrcode.Medium:  "medium",
+	qrcode.High:    "high",
+	qrcode.Highest: "highest",
+}
+
+// CapacityError is returned when a payload does not fit in a QR code of the
+// recovery level.
+type CapacityError struct {
+	Bytes int                  // Length of the payload.
+	Max   int                  // Most bytes that fit at the recovery level.
+	Level qrcode.RecoveryLevel // Recovery level of the QR code.
+
+	// Drop are the optional fields of the payment that can be left out of
+	// the payload, by the bytes they save, most first.
+	Drop []DroppableField
+}
+
+// DroppableField is an optional field of a payload and the bytes leaving it
+// out saves.
+type DroppableField struct {
+	Key   string
+	Bytes int
+}
+
+func (e *CapacityError) Error() string {
+	msg := fmt.Sprintf("payload is %d bytes, the maximum with %s error correction is %d bytes", e.Bytes, levelNames[e.Level], e.Max)
+	if len(e.Drop) == 0 {
+		return msg
+	}
+
+	drop := make([]string, len(e.Drop))
+	for i, f := range e.Drop {
+		drop[i] = fmt.Sprintf("%s (%d bytes)", f.Key, f.Bytes)
+	}
+
+	return msg + ", drop the optional fields " + strings.Join(drop, ", ")
+}
+
+// droppableFields clear the optional fields that are not needed to make the
+// payment, as Payment.minimal.
+var droppableFields = []struct {
+	key   string
+	clear func(d *Payment)
+}{
+	{"adr", func(d *Payment) { d.Address = "" }},
+	{"vat", func(d *Payment) { d.VAT = 0 }},
+	{"vh", func(d *Payment) { d.HighVAT = 0 }},
+	{"vm", func(d *Payment) { d.MediumVAT = 0 }},
+	{"vl", func(d *Payment) { d.LowVAT = 0 }},
+}
+
+// newQR returns the QR code of the payload, with a CapacityError if it does
+// not fit. The fields to drop are given from d, which may be nil if the
+// payload is not of a payment.
+func newQR(payload string, level qrcode.RecoveryLevel, d *Payment) (*qrcode.QRCode, error) {
+	q, err := qrcode.New(payload, level)
+	// go-qrcode does not export its errors.
+	if err == nil || err.Error() != "content too long to encode" {
+		return q, err
+	}
+
+	e := &CapacityError{Bytes: len(payload), Max: maxPayloadBytes[level], Level: level}
+	if d != nil {
+		e.Drop = d.droppable()
+	}
+
+	return nil, e
+}
+
+// droppable returns the optional fields set in the payment and the bytes
+// of the payload they take, most first.
+func (d *Payment) droppable() []DroppableField {
+	full, err := d.AppendPayload(nil)
+	if err != nil {
+		return nil
+	}
+
+	var fields []DroppableField
+	for _, f := range droppableFields {
+		p := *d
+		f.clear(&p)
+		b, err := p.AppendPayload(nil)
+		if err != nil || len(b) == len(full) {
+			continue
+		}
+		fields = append(fields, DroppableField{Key: f.key, Bytes: len(full) - len(b)})
+	}
+	sort.SliceStable(fields, func(i, j int) bool {
+		return fields[i].Bytes > fields[j].Bytes
+	})
+
+	return fields
+}
@@ -0,0 +1,40 @@
+//go:build !payqr_payloadonly
+
+package payqr
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/skip2/go-qrcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapacityError(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+	d := New("5402-9681", "Test AB", "5555555555", "52250047157", 1250, due, WithCreationDate(due), WithAddress(strings.Repeat("a", 1700)))
+	d.VAT = 250
+
+	_, err := d.QR()
+	require.Error(t, err)
+	e, ok := err.(*CapacityError)
+	require.True(t, ok, "got %T", err)
+	assert.Equal(t, 1864, e.Bytes)
+	assert.Equal(t, 1663, e.Max)
+	assert.Equal(t, qrcode.High, e.Level)
+	assert.Equal(t, []DroppableField{{Key: "adr", Bytes: 1709}, {Key: "vat", Bytes: 10}}, e.Drop)
+	assert.EqualError(t, err, "payload is 1864 bytes, the maximum with high error correction is 1663 bytes, drop the optional fields adr (1709 bytes), vat (10 bytes)")
+
+	_, _, err = NewRemediator(Variant{Level: qrcode.Low, Minimal: true}).Remediate("invoice", d)
+	assert.NoError(t, err)
+
+	_, err = NewSwish("1231111111", 50, strings.Repeat("a", 1700)).QR()
+	assert.Error(t, err)
+
+	_, err = newQR(strings.Repeat("a", 3000), qrcode.Low, nil)
+	assert.EqualError(t, err, "payload is 3000 bytes, the maximum with low error correction is 2953 bytes")
+	_, err = newQR(strings.Repeat("a", 2953), qrcode.Low, nil)
+	assert.NoError(t, err)
+}
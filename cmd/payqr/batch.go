@@ -0,0 +1,197 @@
+//go:build !payqr_payloadonly
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/antonlindstrom/payqr"
+)
+
+// batchReport is the result of a batch.
+type batchReport struct {
+	Written int        `json:"written"`
+	Failed  int        `json:"failed"`
+	Errors  []rowError `json:"errors"`
+}
+
+// job is a payment to write to a file.
+type job struct {
+	input   string
+	payment *payqr.Payment
+	output  string
+}
+
+// outputName executes the name template for the payment and returns the
+// path of the file in dir. Names may not leave dir.
+func outputName(tmpl *template.Template, dir string, p *payqr.Payment) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, p); err != nil {
+		return "", err
+	}
+
+	name := filepath.Clean(buf.String())
+	if name == "." || filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid file name %q", buf.String())
+	}
+
+	return filepath.Join(dir, name), nil
+}
+
+// writeImage writes the QR code of the payment to the file in the format.
+func writeImage(j job, format payqr.Format, size int) error {
+	q, err := j.payment.QR()
+	if err != nil {
+		return err
+	}
+
+	b, err := payqr.Render(q, format, size)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(j.output), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(j.output, b, 0o644)
+}
+
+func batch(e *env, args []string) int {
+	fs := flag.NewFlagSet("batch", flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(e.stderr, "Usage: payqr batch [flags] file...")
+		fmt.Fprintln(e.stderr)
+		fmt.Fprintln(e.stderr, "Writes the QR code of each payment of JSON, JSON Lines or CSV files, - for")
+		fmt.Fprintln(e.stderr, "stdin, to a file named by a template executed with the payment, such as")
+		fmt.Fprintln(e.stderr, "{{.Reference}}.png. Prints a JSON report of the files written and the")
+		fmt.Fprintln(e.stderr, "payments that failed, and exits with 1 if any failed.")
+		fmt.Fprintln(e.stderr)
+		fs.PrintDefaults()
+	}
+	var (
+		input       = fs.String("input", "", "input format, json, jsonl or csv, default is by file extension")
+		comma       = fs.String("comma", ",", "field delimiter of CSV files")
+		dir         = fs.String("out-dir", ".", "directory to write the files to")
+		name        = fs.String("name", "{{.Reference}}.png", "template of the file names")
+		format      = fs.String("format", "", "output format, png or svg, default is by the extension of -name")
+		size        = fs.Int("size", 512, "image size in pixels")
+		concurrency = fs.Int("concurrency", runtime.NumCPU(), "number of files written at the same time")
+	)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+	if *concurrency < 1 {
+		fmt.Fprintln(e.stderr, "payqr batch: concurrency must be at least 1")
+		return exitUsage
+	}
+
+	tmpl, err := template.New("name").Option("missingkey=error").Parse(*name)
+	if err != nil {
+		fmt.Fprintf(e.stderr, "payqr batch: invalid name template: %v\n", err)
+		return exitUsage
+	}
+	f, err := outputFormat(*format, strings.TrimSpace(*name))
+	if err != nil {
+		fmt.Fprintf(e.stderr, "payqr batch: %v\n", err)
+		return exitUsage
+	}
+
+	mapping := payqr.DefaultColumnMap()
+	if mapping.Comma, _ = utf8.DecodeRuneInString(*comma); utf8.RuneCountInString(*comma) != 1 {
+		fmt.Fprintf(e.stderr, "payqr batch: invalid delimiter %q\n", *comma)
+		return exitUsage
+	}
+
+	r := batchReport{Errors: []rowError{}}
+	fail := func(re rowError) {
+		r.Failed++
+		r.Errors = append(r.Errors, re)
+	}
+
+	var jobs []job
+	outputs := map[string]bool{}
+	for _, file := range fs.Args() {
+		in, err := inputFormat(*input, file)
+		if err != nil {
+			fmt.Fprintf(e.stderr, "payqr batch: %s: %v\n", file, err)
+			return exitUsage
+		}
+
+		payments, invalid, err := readPayments(e, file, in, mapping)
+		if err != nil {
+			fmt.Fprintf(e.stderr, "payqr batch: %s: %v\n", file, err)
+			return exitUsage
+		}
+		for _, re := range invalid {
+			fail(re)
+		}
+
+		for _, p := range payments {
+			out, err := outputName(tmpl, *dir, p)
+			switch {
+			case err != nil:
+				fail(rowError{File: file, Error: err.Error()})
+			case outputs[out]:
+				fail(rowError{File: file, Output: out, Error: "file name is used by an earlier payment"})
+			default:
+				outputs[out] = true
+				jobs = append(jobs, job{input: file, payment: p, output: out})
+			}
+		}
+	}
+
+	// Files are written by a pool of workers, the report lists errors in
+	// the order of the payments.
+	errs := make([]error, len(jobs))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < *concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				errs[i] = writeImage(jobs[i], f, *size)
+			}
+		}()
+	}
+	for i := range jobs {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			fail(rowError{File: jobs[i].input, Output: jobs[i].output, Error: err.Error()})
+			continue
+		}
+		r.Written++
+	}
+
+	enc := json.NewEncoder(e.stdout)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(r)
+	fmt.Fprintf(e.stderr, "payqr batch: wrote %d files, %d failed\n", r.Written, r.Failed)
+
+	if r.Failed > 0 {
+		return exitFailure
+	}
+
+	return exitOK
+}
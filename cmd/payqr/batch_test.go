@@ -0,0 +1,77 @@
+//go:build !payqr_payloadonly
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatch(t *testing.T) {
+	csv := writeFile(t, "payments.csv", "account,name,company_id,reference,amount,due_date\n"+
+		"5402-9681,Test AB,5555555555,52250047157,1250,2022-08-06\n"+
+		"5402-9681,Test AB,5555555555,1001,100,2022-08-06\n"+
+		"5402-9680,Test AB,5555555555,1002,100,2022-08-06\n"+
+		"5402-9681,Test AB,5555555555,1001,200,2022-08-06\n")
+	dir := t.TempDir()
+
+	code, stdout, stderr := testRun("", "batch", "--out-dir", dir, "--name", "{{.CompanyID}}/{{.Reference}}.svg", "--concurrency", "2", csv)
+	assert.Equal(t, exitFailure, code)
+	assert.Contains(t, stderr, "wrote 2 files, 2 failed")
+
+	var got batchReport
+	require.NoError(t, json.Unmarshal([]byte(stdout), &got))
+	assert.Equal(t, 2, got.Written)
+	assert.Equal(t, 2, got.Failed)
+	require.Len(t, got.Errors, 2)
+	assert.Equal(t, 4, got.Errors[0].Row)
+	assert.Equal(t, filepath.Join(dir, "5555555555", "1001.svg"), got.Errors[1].Output)
+
+	for _, name := range []string{"52250047157.svg", "1001.svg"} {
+		b, err := os.ReadFile(filepath.Join(dir, "5555555555", name))
+		require.NoError(t, err)
+		assert.Contains(t, string(b), "<svg")
+	}
+}
+
+func TestBatchJSONL(t *testing.T) {
+	dir := t.TempDir()
+	stdin := `{"account_number":"5402-9681","account_name":"Test AB","company_id":"5555555555","reference":"1001","due_amount":1,"due_date":"2022-08-06"}` + "\n"
+
+	code, _, stderr := testRun(stdin, "batch", "--input", "jsonl", "--out-dir", dir, "-")
+	require.Equal(t, exitOK, code, stderr)
+
+	b, err := os.ReadFile(filepath.Join(dir, "1001.png"))
+	require.NoError(t, err)
+	assert.Equal(t, "\x89PNG", string(b[:4]))
+}
+
+func TestBatchError(t *testing.T) {
+	jsonl := writeFile(t, "payments.jsonl", `{"account_number":"5402-9681","account_name":"Test AB","company_id":"5555555555","reference":"../x","due_amount":1,"due_date":"2022-08-06"}`+"\n")
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		wantErr  string
+	}{
+		{name: "No files", wantCode: exitUsage},
+		{name: "Invalid template", args: []string{"--name", "{{.Reference", jsonl}, wantCode: exitUsage, wantErr: "invalid name template"},
+		{name: "Unknown field", args: []string{"--name", "{{.Bogus}}.png", "--out-dir", t.TempDir(), jsonl}, wantCode: exitFailure},
+		{name: "Outside directory", args: []string{"--name", "{{.Reference}}/../../x.png", "--out-dir", t.TempDir(), jsonl}, wantCode: exitFailure},
+		{name: "Concurrency", args: []string{"--concurrency", "0", jsonl}, wantCode: exitUsage, wantErr: "concurrency"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			code, _, stderr := testRun("", append([]string{"batch"}, test.args...)...)
+			assert.Equal(t, test.wantCode, code)
+			assert.Contains(t, stderr, test.wantErr)
+		})
+	}
+}
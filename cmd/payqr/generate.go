@@ -0,0 +1,118 @@
+//go:build !payqr_payloadonly
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+)
+
+// outputFormat returns the format given by name, or else by the extension of
+// the output file, or PNG.
+func outputFormat(name, out string) (payqr.Format, error) {
+	if name != "" {
+		return payqr.ParseFormat(name)
+	}
+	if ext := filepath.Ext(out); ext != "" {
+		return payqr.ParseFormat(ext)
+	}
+
+	return payqr.FormatPNG, nil
+}
+
+// writeOutput writes b to the file, or to stdout if it is "-".
+func writeOutput(e *env, out string, b []byte) error {
+	if out == "-" {
+		_, err := e.stdout.Write(b)
+		return err
+	}
+
+	return os.WriteFile(out, b, 0o644)
+}
+
+func generate(e *env, args []string) int {
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(e.stderr, "Usage: payqr generate [flags] [< payment.json]")
+		fmt.Fprintln(e.stderr)
+		fmt.Fprintln(e.stderr, "Writes the QR code of the payment given by flags, or read as JSON from")
+		fmt.Fprintln(e.stderr, "stdin if no account is given.")
+		fmt.Fprintln(e.stderr)
+		fs.PrintDefaults()
+	}
+
+	var (
+		account   = fs.String("account", "", "account number of the payee")
+		name      = fs.String("name", "", "name of the payee")
+		companyID = fs.String("company-id", "", "organization number of the payee")
+		reference = fs.String("reference", "", "reference, such as an OCR number")
+		amount    = fs.Float64("amount", 0, "amount to pay")
+		due       = fs.String("due", "", "due date as yyyy-mm-dd, default is today")
+		typ       = fs.String("type", string(payqr.PaymentTypeBG), "payment type: BG, PG, BBAN or IBAN")
+		currency  = fs.String("currency", "", "currency, default is SEK")
+		bankCode  = fs.String("bank-code", "", "BIC of IBAN payments, or clearing number of foreign BBAN payments")
+		country   = fs.String("country-code", "", "country of the account as ISO 3166-1 alpha-2, required for IBAN")
+		out       = fs.String("out", "-", "output file, - for stdout")
+		format    = fs.String("format", "", "output format, png or svg, default is by the extension of -out or png")
+		size      = fs.Int("size", 512, "image size in pixels")
+	)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(e.stderr, "payqr generate: unexpected arguments %q\n", fs.Args())
+		return exitUsage
+	}
+
+	f, err := outputFormat(*format, *out)
+	if err != nil {
+		fmt.Fprintf(e.stderr, "payqr generate: %v\n", err)
+		return exitUsage
+	}
+
+	var p *payqr.Payment
+	if *account == "" {
+		if p, err = readPayment(e.stdin); err != nil {
+			fmt.Fprintf(e.stderr, "payqr generate: %v\n", err)
+			return exitFailure
+		}
+	} else {
+		date := time.Now()
+		if *due != "" {
+			if date, err = time.Parse("2006-01-02", *due); err != nil {
+				fmt.Fprintf(e.stderr, "payqr generate: invalid due date %q\n", *due)
+				return exitUsage
+			}
+		}
+
+		p = payqr.New(*account, *name, *companyID, *reference, *amount, date,
+			payqr.WithPaymentType(payqr.PaymentType(*typ)), payqr.WithCurrency(*currency),
+			payqr.WithBankCode(*bankCode), payqr.WithCountryCode(strings.ToUpper(*country)))
+	}
+
+	if err := p.Validate(); err != nil {
+		fmt.Fprintf(e.stderr, "payqr generate: invalid payment: %v\n", err)
+		return exitFailure
+	}
+
+	q, err := p.QR()
+	if err == nil {
+		var b []byte
+		if b, err = payqr.Render(q, f, *size); err == nil {
+			err = writeOutput(e, *out, b)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(e.stderr, "payqr generate: %v\n", err)
+		return exitFailure
+	}
+
+	return exitOK
+}
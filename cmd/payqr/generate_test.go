@@ -0,0 +1,84 @@
+//go:build !payqr_payloadonly
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	flags := []string{"--account", "5402-9681", "--name", "Test AB", "--company-id", "5555555555",
+		"--reference", "52250047157", "--amount", "1250", "--due", "2022-08-06"}
+
+	t.Run("PNG to stdout", func(t *testing.T) {
+		code, stdout, stderr := testRun("", append([]string{"generate"}, flags...)...)
+		require.Equal(t, exitOK, code, stderr)
+		assert.True(t, strings.HasPrefix(stdout, "\x89PNG"))
+	})
+
+	t.Run("SVG by extension", func(t *testing.T) {
+		out := filepath.Join(t.TempDir(), "invoice.svg")
+		code, _, stderr := testRun("", append([]string{"generate", "--out", out}, flags...)...)
+		require.Equal(t, exitOK, code, stderr)
+
+		b, err := os.ReadFile(out)
+		require.NoError(t, err)
+		assert.Contains(t, string(b), "<svg")
+	})
+
+	t.Run("JSON on stdin", func(t *testing.T) {
+		json := `{
+			"account_number": "5402-9681",
+			"account_name": "Test AB",
+			"company_id": "5555555555",
+			"reference": "52250047157",
+			"due_amount": 1250,
+			"due_date": "2022-08-06"
+		}`
+		code, stdout, stderr := testRun(json, "generate", "--format", "svg")
+		require.Equal(t, exitOK, code, stderr)
+		assert.Contains(t, stdout, "<svg")
+	})
+}
+
+func TestGenerateError(t *testing.T) {
+	tests := []struct {
+		name     string
+		stdin    string
+		args     []string
+		wantCode int
+		wantErr  string
+	}{
+		{name: "Unknown flag", args: []string{"--bogus"}, wantCode: exitUsage},
+		{name: "Unknown format", args: []string{"--account", "5402-9681", "--format", "bmp"}, wantCode: exitUsage, wantErr: "unknown format"},
+		{name: "Invalid due date", args: []string{"--account", "5402-9681", "--due", "6/8"}, wantCode: exitUsage, wantErr: "invalid due date"},
+		{
+			name:     "Invalid account",
+			args:     []string{"--account", "5402-9680", "--name", "Test AB", "--company-id", "5555555555", "--amount", "1"},
+			wantCode: exitFailure,
+			wantErr:  "invalid payment",
+		},
+		{
+			name:     "IBAN without bank code",
+			args:     []string{"--account", "DE89370400440532013000", "--name", "Test GmbH", "--company-id", "1234", "--amount", "1", "--type", "IBAN", "--country-code", "de"},
+			wantCode: exitFailure,
+			wantErr:  "requires a bank code",
+		},
+		{name: "Invalid JSON", stdin: "{", wantCode: exitFailure, wantErr: "invalid payment JSON"},
+		{name: "Unsupported format", args: []string{"--account", "5402-9681", "--name", "Test AB", "--company-id", "5555555555", "--amount", "1", "--format", "pdf"}, wantCode: exitFailure, wantErr: "not supported"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			code, _, stderr := testRun(test.stdin, append([]string{"generate"}, test.args...)...)
+			assert.Equal(t, test.wantCode, code)
+			assert.Contains(t, stderr, test.wantErr)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+//go:build !payqr_payloadonly
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/antonlindstrom/payqr"
+)
+
+// Input formats of payment files.
+const (
+	inputJSON  = "json"
+	inputJSONL = "jsonl"
+	inputCSV   = "csv"
+)
+
+// inputFormat returns the format given by name, or else by the extension of
+// the file.
+func inputFormat(name, file string) (string, error) {
+	if name == "" {
+		name = strings.TrimPrefix(filepath.Ext(file), ".")
+	}
+
+	switch strings.ToLower(name) {
+	case inputJSON:
+		return inputJSON, nil
+	case inputJSONL, "ndjson":
+		return inputJSONL, nil
+	case inputCSV:
+		return inputCSV, nil
+	}
+
+	return "", fmt.Errorf("unknown input format %q, use json, jsonl or csv", name)
+}
+
+// decodePayment decodes a payment as JSON, with the long field names of JSON
+// Lines, such as {"account_number": "5402-9681", ...}. The JSON may span
+// several lines.
+func decodePayment(b []byte) (*payqr.Payment, error) {
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, b); err != nil {
+		return nil, fmt.Errorf("invalid payment JSON: %w", err)
+	}
+
+	return payqr.NewJSONLDecoder(&compact).Decode()
+}
+
+// readPayment reads one payment as JSON.
+func readPayment(r io.Reader) (*payqr.Payment, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodePayment(b)
+}
+
+// rowError is an invalid payment of an input file. Row is the line of CSV
+// and JSON Lines files and the position in JSON arrays, counted from 1.
+type rowError struct {
+	File   string `json:"file"`
+	Row    int    `json:"row,omitempty"`
+	Output string `json:"output,omitempty"`
+	Error  string `json:"error"`
+}
+
+// readPayments reads the payments of the file, or of stdin if it is "-", in
+// the format. It returns the valid payments and the errors of the invalid
+// ones. An error is returned if the file can not be read at all.
+func readPayments(e *env, file, format string, mapping payqr.ColumnMap) ([]*payqr.Payment, []rowError, error) {
+	r := e.stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var (
+		payments []*payqr.Payment
+		invalid  []rowError
+	)
+	add := func(row int, p *payqr.Payment, err error) {
+		if err == nil {
+			err = p.Validate()
+		}
+		if err != nil {
+			invalid = append(invalid, rowError{File: file, Row: row, Error: err.Error()})
+			return
+		}
+		payments = append(payments, p)
+	}
+
+	switch format {
+	case inputCSV:
+		var err error
+		payments, err = payqr.LoadCSV(r, mapping)
+		var csvErr *payqr.CSVError
+		if errors.As(err, &csvErr) {
+			for _, row := range csvErr.Rows {
+				invalid = append(invalid, rowError{File: file, Row: row.Row, Error: row.Err.Error()})
+			}
+		} else if err != nil {
+			return nil, nil, err
+		}
+	case inputJSONL:
+		dec := payqr.NewJSONLDecoder(r)
+		for {
+			p, err := dec.Decode()
+			if err == io.EOF {
+				break
+			}
+			if dec.Err() != nil {
+				return nil, nil, err
+			}
+			add(dec.Line(), p, err)
+		}
+	case inputJSON:
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		if b = bytes.TrimSpace(b); !bytes.HasPrefix(b, []byte("[")) {
+			p, err := decodePayment(b)
+			add(0, p, err)
+			break
+		}
+
+		var items []json.RawMessage
+		if err := json.Unmarshal(b, &items); err != nil {
+			return nil, nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		for i, item := range items {
+			p, err := decodePayment(item)
+			add(i+1, p, err)
+		}
+	}
+
+	return payments, invalid, nil
+}
@@ -0,0 +1,138 @@
+//go:build !payqr_payloadonly
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/antonlindstrom/payqr/payqrhttp"
+)
+
+// inspection is the result of inspecting a payload.
+type inspection struct {
+	Kind     string   `json:"kind"`
+	Fields   []field  `json:"fields"`
+	Problems []string `json:"problems"`
+}
+
+type field struct {
+	Label    string `json:"label"`
+	Value    string `json:"value"`
+	Editable bool   `json:"editable,omitempty"`
+}
+
+// validatePayload returns the validation problems of the payload.
+func validatePayload(payload string) []string {
+	if strings.HasPrefix(payload, "C") {
+		s, err := payqr.ParseSwish(payload)
+		if err == nil {
+			err = s.Validate()
+		}
+		if err != nil {
+			return []string{err.Error()}
+		}
+		return nil
+	}
+
+	var problems []string
+
+	// Unknown fields are ignored by apps, but often are typos of the
+	// producing system.
+	p, err := payqr.ParsePayload(payload)
+	if err != nil {
+		problems = append(problems, err.Error())
+		p = &payqr.Payment{}
+		_ = json.Unmarshal([]byte(payload), p)
+	}
+	if err := p.Validate(); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	return problems
+}
+
+func inspect(e *env, args []string) int {
+	fs := flag.NewFlagSet("inspect", flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(e.stderr, "Usage: payqr inspect [flags] [payload]")
+		fmt.Fprintln(e.stderr)
+		fmt.Fprintln(e.stderr, "Prints the fields of a QR payload, read from stdin if not given, and")
+		fmt.Fprintln(e.stderr, "its validation problems. Exits with 1 if the payload is invalid.")
+		fmt.Fprintln(e.stderr)
+		fs.PrintDefaults()
+	}
+	asJSON := fs.Bool("json", false, "print as JSON")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() > 1 {
+		fmt.Fprintln(e.stderr, "payqr inspect: give one payload, quoted")
+		return exitUsage
+	}
+
+	payload := fs.Arg(0)
+	if fs.NArg() == 0 {
+		b, err := io.ReadAll(e.stdin)
+		if err != nil {
+			fmt.Fprintf(e.stderr, "payqr inspect: %v\n", err)
+			return exitFailure
+		}
+		payload = string(b)
+	}
+	payload = strings.TrimSpace(payload)
+
+	preview, err := payqrhttp.ParsePreview(payload)
+	if err != nil {
+		fmt.Fprintf(e.stderr, "payqr inspect: %v\n", err)
+		return exitFailure
+	}
+
+	result := inspection{Kind: preview.Kind, Problems: validatePayload(payload)}
+	for _, f := range preview.Fields {
+		result.Fields = append(result.Fields, field{Label: f.Label, Value: f.Value, Editable: f.Editable && preview.Kind == "Swish"})
+	}
+	if result.Problems == nil {
+		result.Problems = []string{}
+	}
+
+	if *asJSON {
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(result)
+		_, _ = e.stdout.Write(buf.Bytes())
+	} else {
+		tw := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintf(tw, "Kind\t%s\n", result.Kind)
+		for _, f := range result.Fields {
+			editable := ""
+			if f.Editable {
+				editable = "\t(editable)"
+			}
+			fmt.Fprintf(tw, "%s\t%s%s\n", f.Label, f.Value, editable)
+		}
+		tw.Flush()
+
+		if len(result.Problems) > 0 {
+			fmt.Fprintln(e.stdout)
+			fmt.Fprintln(e.stdout, "Problems:")
+			for _, p := range result.Problems {
+				fmt.Fprintf(e.stdout, "  - %s\n", p)
+			}
+		}
+	}
+
+	if len(result.Problems) > 0 {
+		return exitFailure
+	}
+
+	return exitOK
+}
@@ -0,0 +1,62 @@
+//go:build !payqr_payloadonly
+
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspect(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+	b, err := json.Marshal(payqr.New("5402-9681", "Test AB", "5555555555", "52250047157", 1250, due))
+	require.NoError(t, err)
+	invoice := string(b)
+
+	t.Run("Table", func(t *testing.T) {
+		code, stdout, stderr := testRun("", "inspect", invoice)
+		require.Equal(t, exitOK, code, stderr)
+		assert.Contains(t, stdout, "Kind          Invoice\n")
+		assert.Contains(t, stdout, "Amount        1250.00 SEK\n")
+		assert.NotContains(t, stdout, "Problems")
+	})
+
+	t.Run("JSON from stdin", func(t *testing.T) {
+		code, stdout, stderr := testRun(invoice+"\n", "inspect", "--json")
+		require.Equal(t, exitOK, code, stderr)
+
+		var got inspection
+		require.NoError(t, json.Unmarshal([]byte(stdout), &got))
+		assert.Equal(t, "Invoice", got.Kind)
+		assert.Contains(t, got.Fields, field{Label: "Reference", Value: "52250047157"})
+		assert.Empty(t, got.Problems)
+	})
+
+	t.Run("Swish", func(t *testing.T) {
+		code, stdout, stderr := testRun("", "inspect", payqr.NewSwish("1231111111", 50, "Order 1", payqr.WithEditableFields(payqr.SwishMessageEditable)).Encode())
+		require.Equal(t, exitOK, code, stderr)
+		assert.Contains(t, stdout, "Kind       Swish\n")
+		assert.Contains(t, stdout, "Message    Order 1  (editable)\n")
+	})
+
+	t.Run("Problems", func(t *testing.T) {
+		code, stdout, _ := testRun("", "inspect", "--json", `{"uqr":1,"tp":1,"nme":"Test AB","cid":"5555555555","acc":"5402-9680","pt":"BG","ddt":"20220806","due":1,"amount":1}`)
+		assert.Equal(t, exitFailure, code)
+
+		var got inspection
+		require.NoError(t, json.Unmarshal([]byte(stdout), &got))
+		require.Len(t, got.Problems, 2)
+		assert.Contains(t, got.Problems[0], `unknown field "amount"`)
+	})
+
+	t.Run("Unknown payload", func(t *testing.T) {
+		code, _, stderr := testRun("", "inspect", "hello")
+		assert.Equal(t, exitFailure, code)
+		assert.Contains(t, stderr, "unknown payload format")
+	})
+}
@@ -0,0 +1,91 @@
+//go:build !payqr_payloadonly
+
+// Command payqr creates and checks payment QR codes from the command line.
+//
+//	payqr generate --account 5402-9681 --name "Test AB" --company-id 5555555555 \
+//		--reference 52250047157 --amount 1250 --due 2022-08-06 --out invoice.png
+//
+// Run payqr help for the list of commands.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// Exit codes of the commands.
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+// env is the environment a command runs in.
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// command is a subcommand, run with the arguments following its name. It
+// returns the exit code.
+type command struct {
+	summary string
+	run     func(e *env, args []string) int
+}
+
+var commands = map[string]command{
+	"batch":    {summary: "write the QR codes of payment files to templated file names", run: batch},
+	"generate": {summary: "write the QR code of a payment as PNG or SVG", run: generate},
+	"inspect":  {summary: "print the fields and problems of a QR payload", run: inspect},
+	"serve":    {summary: "serve QR codes over HTTP", run: serve},
+	"validate": {summary: "validate payment files, for use in CI", run: validate},
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: payqr <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-10s %s\n", name, commands[name].summary)
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run payqr <command> -h for the flags of a command.")
+}
+
+// run runs the command named by the first argument and returns the exit
+// code.
+func run(e *env, args []string) int {
+	if len(args) == 0 {
+		usage(e.stderr)
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		usage(e.stdout)
+		return exitOK
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(e.stderr, "payqr: unknown command %q\n\n", args[0])
+		usage(e.stderr)
+		return exitUsage
+	}
+
+	return cmd.run(e, args[1:])
+}
+
+func main() {
+	os.Exit(run(&env{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}, os.Args[1:]))
+}
@@ -0,0 +1,43 @@
+//go:build !payqr_payloadonly
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testRun runs the command with the input on stdin and returns the exit code
+// and output.
+func testRun(stdin string, args ...string) (code int, stdout, stderr string) {
+	var out, errOut bytes.Buffer
+	code = run(&env{stdin: strings.NewReader(stdin), stdout: &out, stderr: &errOut}, args)
+
+	return code, out.String(), errOut.String()
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		wantOut  string
+		wantErr  string
+	}{
+		{name: "No command", wantCode: exitUsage, wantErr: "Usage: payqr"},
+		{name: "Help", args: []string{"help"}, wantCode: exitOK, wantOut: "generate"},
+		{name: "Unknown command", args: []string{"frobnicate"}, wantCode: exitUsage, wantErr: `unknown command "frobnicate"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			code, stdout, stderr := testRun("", test.args...)
+			assert.Equal(t, test.wantCode, code)
+			assert.Contains(t, stdout, test.wantOut)
+			assert.Contains(t, stderr, test.wantErr)
+		})
+	}
+}
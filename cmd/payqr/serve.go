@@ -0,0 +1,204 @@
+//go:build !payqr_payloadonly
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/antonlindstrom/payqr/payqrhttp"
+)
+
+// serveConfig is the configuration of the QR service, read from a JSON
+// config file and overridden by flags.
+type serveConfig struct {
+	Listen string `json:"listen"`
+
+	// AllowedPayees are the accounts codes may be generated for. Any
+	// account is allowed if empty.
+	AllowedPayees []string `json:"allowed_payees"`
+
+	// Formats are the image formats served, PNG and SVG by default.
+	Formats []string `json:"formats"`
+
+	// MaxSize is the largest image size in pixels.
+	MaxSize int `json:"max_size"`
+
+	// CacheControl is the Cache-Control header of served codes, none if
+	// empty.
+	CacheControl string `json:"cache_control"`
+
+	// SigningKey makes the service serve only URLs signed with it. It has
+	// no flag, to keep it out of process listings.
+	SigningKey string `json:"signing_key"`
+}
+
+func defaultServeConfig() serveConfig {
+	return serveConfig{
+		Listen:  "localhost:8080",
+		Formats: []string{string(payqr.FormatPNG), string(payqr.FormatSVG)},
+		MaxSize: 2048,
+	}
+}
+
+// loadServeConfig reads the config file over the defaults.
+func loadServeConfig(file string) (serveConfig, error) {
+	cfg := defaultServeConfig()
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return cfg, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: %w", file, err)
+	}
+
+	return cfg, nil
+}
+
+// splitList splits a comma separated list, dropping empty items.
+func splitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
+
+// newHandler returns the QR handler of the configuration.
+func newHandler(cfg serveConfig) (*payqrhttp.Handler, error) {
+	var formats []payqr.Format
+	for _, name := range cfg.Formats {
+		f, err := payqr.ParseFormat(name)
+		if err != nil {
+			return nil, err
+		}
+		if f != payqr.FormatPNG && f != payqr.FormatSVG {
+			return nil, fmt.Errorf("format %s can not be served", f)
+		}
+		formats = append(formats, f)
+	}
+	if len(formats) == 0 {
+		return nil, errors.New("no formats to serve")
+	}
+
+	options := []payqrhttp.HandlerOption{payqrhttp.WithFormats(formats...), payqrhttp.WithMaxSize(cfg.MaxSize)}
+	if cfg.CacheControl != "" {
+		options = append(options, payqrhttp.WithCacheControl(cfg.CacheControl))
+	}
+	if cfg.SigningKey != "" {
+		options = append(options, payqrhttp.WithSigningKey([]byte(cfg.SigningKey)))
+	}
+	if len(cfg.AllowedPayees) > 0 {
+		options = append(options, payqrhttp.WithAllowedPayees(cfg.AllowedPayees...))
+	}
+
+	return payqrhttp.NewHandler(options...), nil
+}
+
+// newServeMux returns the routes of the service.
+func newServeMux(s http.Handler) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("/qr", s)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+
+	return mux
+}
+
+func serve(e *env, args []string) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(e.stderr, "Usage: payqr serve [flags]")
+		fmt.Fprintln(e.stderr)
+		fmt.Fprintln(e.stderr, "Serves QR codes of payments given by query parameters or a JSON body at")
+		fmt.Fprintln(e.stderr, "/qr, such as /qr?account=5402-9681&name=Test+AB&company_id=5555555555&amount=100.")
+		fmt.Fprintln(e.stderr, "Flags override the config file, a JSON object with the keys listen,")
+		fmt.Fprintln(e.stderr, "allowed_payees, formats, max_size, cache_control and signing_key.")
+		fmt.Fprintln(e.stderr)
+		fs.PrintDefaults()
+	}
+	defaults := defaultServeConfig()
+	var (
+		config  = fs.String("config", "", "config file")
+		listen  = fs.String("listen", defaults.Listen, "address to listen on")
+		allow   = fs.String("allow", "", "comma separated accounts codes may be generated for, default is any")
+		formats = fs.String("formats", strings.Join(defaults.Formats, ","), "comma separated formats to serve")
+		maxSize = fs.Int("max-size", defaults.MaxSize, "largest image size in pixels")
+		cache   = fs.String("cache-control", "", "Cache-Control header of served codes, such as \"public, max-age=86400\"")
+	)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	cfg := defaults
+	if *config != "" {
+		var err error
+		if cfg, err = loadServeConfig(*config); err != nil {
+			fmt.Fprintf(e.stderr, "payqr serve: %v\n", err)
+			return exitUsage
+		}
+	}
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "listen":
+			cfg.Listen = *listen
+		case "allow":
+			cfg.AllowedPayees = splitList(*allow)
+		case "formats":
+			cfg.Formats = splitList(*formats)
+		case "max-size":
+			cfg.MaxSize = *maxSize
+		case "cache-control":
+			cfg.CacheControl = *cache
+		}
+	})
+
+	h, err := newHandler(cfg)
+	if err != nil {
+		fmt.Fprintf(e.stderr, "payqr serve: %v\n", err)
+		return exitUsage
+	}
+
+	srv := &http.Server{
+		Addr:              cfg.Listen,
+		Handler:           newServeMux(h),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdown)
+	}()
+
+	fmt.Fprintf(e.stderr, "payqr serve: listening on %s\n", cfg.Listen)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(e.stderr, "payqr serve: %v\n", err)
+		return exitFailure
+	}
+
+	return exitOK
+}
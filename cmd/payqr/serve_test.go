@@ -0,0 +1,88 @@
+//go:build !payqr_payloadonly
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeMux(t *testing.T) {
+	h, err := newHandler(serveConfig{AllowedPayees: []string{"5402 9681"}, Formats: []string{"png"}, MaxSize: 1024})
+	require.NoError(t, err)
+	srv := httptest.NewServer(newServeMux(h))
+	defer srv.Close()
+
+	query := func(extra ...string) string {
+		q := url.Values{
+			"account":    {"5402-9681"},
+			"name":       {"Test AB"},
+			"company_id": {"5555555555"},
+			"reference":  {"52250047157"},
+			"amount":     {"1250"},
+			"due":        {"2022-08-06"},
+		}
+		for i := 0; i < len(extra); i += 2 {
+			q.Set(extra[i], extra[i+1])
+		}
+		return q.Encode()
+	}
+
+	tests := []struct {
+		name            string
+		path            string
+		wantStatus      int
+		wantContentType string
+	}{
+		{name: "PNG", path: "/qr?" + query(), wantStatus: http.StatusOK, wantContentType: "image/png"},
+		{name: "Format not served", path: "/qr?" + query("format", "svg"), wantStatus: http.StatusBadRequest},
+		{name: "Too large", path: "/qr?" + query("size", "4096"), wantStatus: http.StatusBadRequest},
+		{name: "Invalid payment", path: "/qr?" + query("account", "5402-9680"), wantStatus: http.StatusBadRequest},
+		{name: "Payee not allowed", path: "/qr?" + query("account", "5050-1055"), wantStatus: http.StatusForbidden},
+		{name: "Health", path: "/healthz", wantStatus: http.StatusOK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp, err := http.Get(srv.URL + test.path)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, test.wantStatus, resp.StatusCode)
+			if test.wantContentType != "" {
+				assert.Equal(t, test.wantContentType, resp.Header.Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestLoadServeConfig(t *testing.T) {
+	cfg, err := loadServeConfig(writeFile(t, "serve.json", `{"listen": ":9000", "allowed_payees": ["5402-9681"], "cache_control": "max-age=60"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "max-age=60", cfg.CacheControl)
+	assert.Equal(t, ":9000", cfg.Listen)
+	assert.Equal(t, []string{"5402-9681"}, cfg.AllowedPayees)
+	assert.Equal(t, []string{"png", "svg"}, cfg.Formats, "defaults are kept")
+
+	_, err = loadServeConfig(writeFile(t, "serve.json", `{"listen": ":9000", "payees": []}`))
+	assert.Error(t, err)
+
+	cfg, err = loadServeConfig(writeFile(t, "serve.json", `{"signing_key": "secret"}`))
+	require.NoError(t, err)
+	h, err := newHandler(cfg)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/qr?account=5402-9681&name=Test+AB&company_id=5555555555&amount=100", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code, "unsigned URL")
+}
+
+func TestServeError(t *testing.T) {
+	code, _, stderr := testRun("", "serve", "--formats", "pdf")
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, "can not be served")
+}
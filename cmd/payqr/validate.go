@@ -0,0 +1,77 @@
+//go:build !payqr_payloadonly
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/antonlindstrom/payqr"
+)
+
+// report is the result of validating payment files.
+type report struct {
+	Valid   int        `json:"valid"`
+	Invalid int        `json:"invalid"`
+	Errors  []rowError `json:"errors"`
+}
+
+func validate(e *env, args []string) int {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(e.stderr, "Usage: payqr validate [flags] file...")
+		fmt.Fprintln(e.stderr)
+		fmt.Fprintln(e.stderr, "Validates the payments of JSON, JSON Lines or CSV files, - for stdin, and")
+		fmt.Fprintln(e.stderr, "prints a JSON report of the invalid ones. Exits with 1 if any payment is")
+		fmt.Fprintln(e.stderr, "invalid and 2 if a file can not be read.")
+		fmt.Fprintln(e.stderr)
+		fs.PrintDefaults()
+	}
+	format := fs.String("input", "", "input format, json, jsonl or csv, default is by file extension")
+	comma := fs.String("comma", ",", "field delimiter of CSV files")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+
+	mapping := payqr.DefaultColumnMap()
+	if mapping.Comma, _ = utf8.DecodeRuneInString(*comma); utf8.RuneCountInString(*comma) != 1 {
+		fmt.Fprintf(e.stderr, "payqr validate: invalid delimiter %q\n", *comma)
+		return exitUsage
+	}
+
+	r := report{Errors: []rowError{}}
+	for _, file := range fs.Args() {
+		f, err := inputFormat(*format, file)
+		if err != nil {
+			fmt.Fprintf(e.stderr, "payqr validate: %s: %v\n", file, err)
+			return exitUsage
+		}
+
+		payments, invalid, err := readPayments(e, file, f, mapping)
+		if err != nil {
+			fmt.Fprintf(e.stderr, "payqr validate: %s: %v\n", file, err)
+			return exitUsage
+		}
+
+		r.Valid += len(payments)
+		r.Invalid += len(invalid)
+		r.Errors = append(r.Errors, invalid...)
+	}
+
+	enc := json.NewEncoder(e.stdout)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(r)
+
+	if r.Invalid > 0 {
+		return exitFailure
+	}
+
+	return exitOK
+}
@@ -0,0 +1,82 @@
+//go:build !payqr_payloadonly
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFile writes the content to the file in a temporary directory and
+// returns its path.
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	return path
+}
+
+func TestValidate(t *testing.T) {
+	csv := writeFile(t, "payments.csv", "account,name,company_id,reference,amount,due_date\n"+
+		"5402-9681,Test AB,5555555555,52250047157,1250,2022-08-06\n"+
+		"5402-9680,Test AB,5555555555,52250047157,1250,2022-08-06\n")
+	jsonl := writeFile(t, "payments.jsonl", `{"account_number":"5402-9681","account_name":"Test AB","company_id":"5555555555","due_amount":1,"due_date":"2022-08-06"}`+"\n\n"+
+		`{"account_number":"5402-9681","account_name":"Test AB","due_amount":1,"due_date":"2022-08-06"}`+"\n")
+	array := writeFile(t, "payments.json", `[
+		{"account_number": "5402-9681", "account_name": "Test AB", "company_id": "5555555555", "due_amount": 1, "due_date": "2022-08-06"},
+		{"account_number": "5402-9681", "account_name": "Test AB", "company_id": "5555555555", "due_amount": "1"}
+	]`)
+	valid := writeFile(t, "payment.json", `{"account_number": "5402-9681", "account_name": "Test AB", "company_id": "5555555555", "due_amount": 1, "due_date": "2022-08-06"}`)
+
+	t.Run("Valid", func(t *testing.T) {
+		code, stdout, stderr := testRun("", "validate", valid)
+		require.Equal(t, exitOK, code, stderr)
+		assert.JSONEq(t, `{"valid":1,"invalid":0,"errors":[]}`, stdout)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		code, stdout, _ := testRun("", "validate", csv, jsonl, array)
+		assert.Equal(t, exitFailure, code)
+
+		var got report
+		require.NoError(t, json.Unmarshal([]byte(stdout), &got))
+		assert.Equal(t, 3, got.Valid)
+		assert.Equal(t, 3, got.Invalid)
+		require.Len(t, got.Errors, 3)
+		assert.Equal(t, rowError{File: csv, Row: 3, Error: `bankgiro account "5402-9680" has an invalid check digit`}, got.Errors[0])
+		assert.Equal(t, jsonl, got.Errors[1].File)
+		assert.Equal(t, 3, got.Errors[1].Row)
+		assert.Equal(t, 2, got.Errors[2].Row)
+	})
+
+	t.Run("Stdin", func(t *testing.T) {
+		code, stdout, stderr := testRun("account;name;company_id;amount;due_date\n5402-9681;Test AB;5555555555;1,50;2022-08-06\n", "validate", "--input", "csv", "--comma", ";", "-")
+		require.Equal(t, exitOK, code, stderr)
+		assert.JSONEq(t, `{"valid":1,"invalid":0,"errors":[]}`, stdout)
+	})
+
+	t.Run("Unreadable", func(t *testing.T) {
+		code, _, stderr := testRun("", "validate", filepath.Join(t.TempDir(), "missing.csv"))
+		assert.Equal(t, exitUsage, code)
+		assert.Contains(t, stderr, "missing.csv")
+	})
+
+	t.Run("Line too long", func(t *testing.T) {
+		long := writeFile(t, "long.jsonl", `{"reference":"`+strings.Repeat("1", 1<<20)+`"}`+"\n")
+		code, _, stderr := testRun("", "validate", long)
+		assert.Equal(t, exitUsage, code)
+		assert.Contains(t, stderr, "token too long")
+	})
+
+	t.Run("Unknown format", func(t *testing.T) {
+		code, _, stderr := testRun("", "validate", "payments.xml")
+		assert.Equal(t, exitUsage, code)
+		assert.Contains(t, stderr, "unknown input format")
+	})
+}
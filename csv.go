@@ -0,0 +1,215 @@
+package payqr
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ColumnMap maps the fields of a payment to the header names of CSV columns.
+// Fields mapped to an empty name are not read. Header names are matched
+// ignoring case and surrounding space.
+type ColumnMap struct {
+	AccountNumber string
+	AccountName   string
+	CompanyID     string
+	Reference     string
+	Amount        string
+	DueDate       string
+	Currency      string
+	PaymentType   string
+	BankCode      string
+	CountryCode   string
+	Address       string
+
+	// DateLayout is the layout of due dates as for time.Parse, default is
+	// 2006-01-02. Dates as 20060102 are accepted as well.
+	DateLayout string
+
+	// Comma is the field delimiter, default is a comma. Spreadsheets with a
+	// decimal comma often export with a semicolon.
+	Comma rune
+}
+
+// DefaultColumnMap returns the column map of CSV files with the headers
+// account, name, company_id, reference, amount, due_date, currency,
+// payment_type, bank_code, country_code and address.
+func DefaultColumnMap() ColumnMap {
+	return ColumnMap{
+		AccountNumber: "account",
+		AccountName:   "name",
+		CompanyID:     "company_id",
+		Reference:     "reference",
+		Amount:        "amount",
+		DueDate:       "due_date",
+		Currency:      "currency",
+		PaymentType:   "payment_type",
+		BankCode:      "bank_code",
+		CountryCode:   "country_code",
+		Address:       "address",
+	}
+}
+
+// RowError is an invalid row of a CSV file.
+type RowError struct {
+	Row int // Line of the row, the header is line 1.
+	Err error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("csv: row %d: %v", e.Row, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// CSVError is returned by LoadCSV when rows are invalid.
+type CSVError struct {
+	Rows []*RowError
+}
+
+func (e *CSVError) Error() string {
+	if len(e.Rows) == 1 {
+		return e.Rows[0].Error()
+	}
+
+	return fmt.Sprintf("%v (and %d more invalid rows)", e.Rows[0], len(e.Rows)-1)
+}
+
+// LoadCSV reads a payment from each row of a CSV file with a header row. The
+// account, name, amount and due date columns are required, other columns are
+// optional and may be left out of the file.
+//
+// Each payment is validated. If rows are invalid the payments of the valid
+// rows are returned with a *CSVError listing the invalid ones, so that a
+// batch can go ahead with the valid rows or report all errors at once.
+func LoadCSV(r io.Reader, mapping ColumnMap) ([]*Payment, error) {
+	cr := csv.NewReader(r)
+	if mapping.Comma != 0 {
+		cr.Comma = mapping.Comma
+	}
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, errors.New("csv: no header row")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("csv: %w", err)
+	}
+
+	index := map[string]int{}
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range []string{mapping.AccountNumber, mapping.AccountName, mapping.Amount, mapping.DueDate} {
+		if _, ok := index[strings.ToLower(strings.TrimSpace(name))]; !ok || name == "" {
+			return nil, fmt.Errorf("csv: no column %q for a required field", name)
+		}
+	}
+
+	layout := mapping.DateLayout
+	if layout == "" {
+		layout = "2006-01-02"
+	}
+
+	var (
+		payments []*Payment
+		invalid  []*RowError
+	)
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		line, _ := cr.FieldPos(0)
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, fmt.Errorf("csv: %w", err)
+			}
+			invalid = append(invalid, &RowError{Row: parseErr.StartLine, Err: parseErr.Err})
+			continue
+		}
+
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		// field returns the value of the column, or an empty string for
+		// unmapped columns and columns missing from the row.
+		field := func(name string) string {
+			i, ok := index[strings.ToLower(strings.TrimSpace(name))]
+			if name == "" || !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		p, err := csvPayment(field(mapping.AccountNumber), field(mapping.AccountName), field(mapping.Amount), field(mapping.DueDate), layout)
+		if err == nil {
+			p.CompanyID = field(mapping.CompanyID)
+			p.Reference = field(mapping.Reference)
+			p.Currency = strings.ToUpper(field(mapping.Currency))
+			if typ := strings.ToUpper(field(mapping.PaymentType)); typ != "" {
+				p.PaymentType = PaymentType(typ)
+			}
+			p.BankCode = field(mapping.BankCode)
+			p.CountryCode = strings.ToUpper(field(mapping.CountryCode))
+			p.Address = field(mapping.Address)
+
+			err = p.Validate()
+		}
+		if err != nil {
+			invalid = append(invalid, &RowError{Row: line, Err: err})
+			continue
+		}
+
+		payments = append(payments, p)
+	}
+
+	if len(invalid) > 0 {
+		return payments, &CSVError{Rows: invalid}
+	}
+
+	return payments, nil
+}
+
+// csvPayment returns a payment of the required fields of a row.
+func csvPayment(account, name, amount, date, layout string) (*Payment, error) {
+	due, err := time.Parse(layout, date)
+	if err != nil {
+		if due, err = time.Parse("20060102", date); err != nil {
+			return nil, fmt.Errorf("invalid due date %q", date)
+		}
+	}
+
+	f, err := parseAmount(amount)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(account, name, "", "", f, due), nil
+}
+
+// parseAmount parses an amount as exported by spreadsheets, with a decimal
+// point or comma and spaces as thousands separators.
+func parseAmount(s string) (float64, error) {
+	clean := strings.NewReplacer(" ", "", " ", "", " ", "").Replace(s)
+	if !strings.Contains(clean, ".") {
+		clean = strings.Replace(clean, ",", ".", 1)
+	}
+
+	f, err := strconv.ParseFloat(clean, 64)
+	if err != nil || f < 0 {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+
+	return f, nil
+}
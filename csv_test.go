@@ -0,0 +1,92 @@
+package payqr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadCSV(t *testing.T) {
+	have := "\ufeffAccount,Name,Company_ID,Reference,Amount,Due_Date,Payment_Type\n" +
+		"5402-9681,Test AB,5555555555,52250047157,1250.00,2022-08-06,bg\n" +
+		"\n" +
+		"90 01 22-3,Föreningen,8020000000,4455,\"1 000,50\",20220831,PG\n"
+
+	payments, err := LoadCSV(strings.NewReader(have), DefaultColumnMap())
+	require.NoError(t, err)
+	require.Len(t, payments, 2)
+
+	assert.Equal(t, "5402-9681", payments[0].AccountNumber)
+	assert.Equal(t, "Test AB", payments[0].AccountName)
+	assert.Equal(t, "5555555555", payments[0].CompanyID)
+	assert.Equal(t, "52250047157", payments[0].Reference)
+	assert.Equal(t, 1250.0, payments[0].DueAmount)
+	assert.Equal(t, "20220806", payments[0].DueDate)
+	assert.Equal(t, PaymentTypeBG, payments[0].PaymentType)
+
+	assert.Equal(t, PaymentTypePG, payments[1].PaymentType)
+	assert.Equal(t, 1000.5, payments[1].DueAmount)
+	assert.Equal(t, "20220831", payments[1].DueDate)
+}
+
+func TestLoadCSVMapping(t *testing.T) {
+	have := "Bankgiro;Mottagare;Orgnr;OCR;Belopp;Förfallodag\n" +
+		"5402-9681;Test AB;5555555555;52250047157;1250,00;06/08/2022\n"
+
+	payments, err := LoadCSV(strings.NewReader(have), ColumnMap{
+		AccountNumber: "Bankgiro",
+		AccountName:   "Mottagare",
+		CompanyID:     "Orgnr",
+		Reference:     "OCR",
+		Amount:        "Belopp",
+		DueDate:       "Förfallodag",
+		DateLayout:    "02/01/2006",
+		Comma:         ';',
+	})
+	require.NoError(t, err)
+	require.Len(t, payments, 1)
+	assert.Equal(t, 1250.0, payments[0].DueAmount)
+	assert.Equal(t, "20220806", payments[0].DueDate)
+}
+
+func TestLoadCSVRowErrors(t *testing.T) {
+	have := "account,name,company_id,amount,due_date\n" +
+		"5402-9681,Test AB,5555555555,1250,2022-08-06\n" +
+		"5402-9681,Test AB,5555555555,many,2022-08-06\n" +
+		"5402-9681,Test AB,5555555555,100,tomorrow\n" +
+		"5402-9680,Test AB,5555555555,100,2022-08-06\n" +
+		"5402-9681,Test AB,5555555555,50,2022-08-06\n"
+
+	payments, err := LoadCSV(strings.NewReader(have), DefaultColumnMap())
+	require.Error(t, err)
+	assert.Len(t, payments, 2, "valid rows are returned")
+
+	var csvErr *CSVError
+	require.True(t, errors.As(err, &csvErr))
+	require.Len(t, csvErr.Rows, 3)
+	for i, want := range []int{3, 4, 5} {
+		assert.Equal(t, want, csvErr.Rows[i].Row)
+	}
+	assert.Contains(t, err.Error(), "row 3")
+	assert.Contains(t, err.Error(), "2 more")
+}
+
+func TestLoadCSVError(t *testing.T) {
+	tests := []struct {
+		name string
+		have string
+	}{
+		{name: "Empty", have: ""},
+		{name: "Missing column", have: "account,name,due_date\n5402-9681,Test AB,2022-08-06\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := LoadCSV(strings.NewReader(test.have), DefaultColumnMap())
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package payqr
+
+import (
+	"math"
+	"strings"
+)
+
+// minorUnits are the decimals of the ISO 4217 currencies that do not have
+// two.
+var minorUnits = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0,
+	"KRW": 0, "PYG": 0, "RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0,
+	"XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"CLF": 4, "UYW": 4,
+}
+
+// MinorUnits returns the number of decimals of amounts in the currency, as
+// given by ISO 4217, e.g. 0 for JPY. Most currencies, and payments without
+// a currency which are in SEK, have two.
+func MinorUnits(currency string) int {
+	if n, ok := minorUnits[strings.ToUpper(currency)]; ok {
+		return n
+	}
+
+	return 2
+}
+
+// RoundAmount rounds the amount to the minor units of the currency, halves
+// away from zero, as the due amount of a payment is encoded in its payload.
+func RoundAmount(amount float64, currency string) float64 {
+	// The division gives the number closest to the rounded amount, which is
+	// encoded without the drift of float arithmetic, such as
+	// 0.30000000000000004 for 0.1 + 0.2.
+	if amount == math.Trunc(amount) {
+		return amount
+	}
+	scale := math.Pow10(MinorUnits(currency))
+
+	return math.Round(amount*scale) / scale
+}
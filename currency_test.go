@@ -0,0 +1,59 @@
+package payqr
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundAmount(t *testing.T) {
+	tests := []struct {
+		name     string
+		currency string
+		have     float64
+		want     float64
+	}{
+		{name: "Default currency", have: 1250.456, want: 1250.46},
+		{name: "SEK", currency: "SEK", have: 0.1 + 0.2, want: 0.3},
+		{name: "Without minor units", currency: "jpy", have: 1250.5, want: 1251},
+		{name: "Three decimals", currency: "KWD", have: 1.23456, want: 1.235},
+		{name: "Four decimals", currency: "CLF", have: 1.23456, want: 1.2346},
+		{name: "Negative", currency: "EUR", have: -5.555555, want: -5.56},
+		{name: "Below the minor unit", currency: "EUR", have: 1e-7, want: 0},
+		{name: "Large", currency: "EUR", have: 1e21, want: 1e21},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, RoundAmount(test.have, test.currency))
+		})
+	}
+
+	assert.True(t, math.IsNaN(RoundAmount(math.NaN(), "SEK")))
+	assert.True(t, math.IsInf(RoundAmount(math.Inf(1), "SEK"), 1))
+}
+
+func TestMinorUnits(t *testing.T) {
+	assert.Equal(t, 2, MinorUnits(""))
+	assert.Equal(t, 2, MinorUnits("SEK"))
+	assert.Equal(t, 0, MinorUnits("JPY"))
+	assert.Equal(t, 0, MinorUnits("isk"))
+	assert.Equal(t, 3, MinorUnits("BHD"))
+}
+
+func TestPayloadRoundsAmount(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+	d := New("5402-9681", "Test AB", "5555555555", "52250047157", 0.1+0.2, due, WithCreationDate(due))
+
+	payload, err := d.Payload()
+	require.NoError(t, err)
+	assert.Equal(t, `{"uqr":1,"tp":1,"nme":"Test AB","cid":"5555555555","iref":"52250047157","idt":"20220806","ddt":"20220806","due":0.3,"pt":"BG","acc":"5402-9681"}`, payload)
+
+	d.Currency, d.DueAmount = "JPY", 1250.5
+	payload, err = d.Payload()
+	require.NoError(t, err)
+	assert.Contains(t, payload, `"due":1251,`)
+}
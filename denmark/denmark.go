@@ -0,0 +1,140 @@
+// Package denmark encodes Danish FI payment lines (FIK, "indbetalingskort"),
+// printed on invoices and scanned or typed into Danish bank apps.
+package denmark
+
+import (
+	"fmt"
+	"image"
+	"strings"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/antonlindstrom/payqr/internal/code128"
+)
+
+// Card is the card type (kortart) of a payment line.
+type Card string
+
+const (
+	// Card04 is a giro card with a 16 digit payment ID checked with mod 11
+	// (GIK).
+	Card04 Card = "04"
+	// Card71 is an FI card with a 15 digit payment ID checked with mod 10.
+	Card71 Card = "71"
+	// Card73 is an FI card without payment ID, the payer writes a message.
+	Card73 Card = "73"
+	// Card75 is an FI card with a 16 digit payment ID checked with mod 10,
+	// where the payer can also write a message.
+	Card75 Card = "75"
+)
+
+// Line is a Danish payment line.
+type Line struct {
+	Card      Card
+	PaymentID string
+	Creditor  string
+}
+
+// Validate checks the payment ID against the card type and that the creditor
+// number has 8 digits, or 7 to 8 digits for giro cards.
+func (l Line) Validate() error {
+	var scheme payqr.ReferenceScheme
+	var length int
+	switch l.Card {
+	case Card04:
+		scheme, length = payqr.ReferenceGIK, 16
+	case Card71:
+		scheme, length = payqr.ReferenceFIK, 15
+	case Card73:
+	case Card75:
+		scheme, length = payqr.ReferenceFIK, 16
+	default:
+		return fmt.Errorf("denmark: unknown card type %q", l.Card)
+	}
+
+	if scheme == payqr.ReferenceFree {
+		if l.PaymentID != "" {
+			return fmt.Errorf("denmark: card type %s has no payment ID", l.Card)
+		}
+	} else {
+		if len(l.PaymentID) != length {
+			return fmt.Errorf("denmark: card type %s needs a %d digit payment ID, got %d", l.Card, length, len(l.PaymentID))
+		}
+		if err := scheme.Validate(l.PaymentID); err != nil {
+			return fmt.Errorf("denmark: %w", err)
+		}
+	}
+
+	minCreditor := 8
+	if l.Card == Card04 {
+		minCreditor = 7
+	}
+	if len(l.Creditor) < minCreditor || len(l.Creditor) > 8 || !isDigits(l.Creditor) {
+		return fmt.Errorf("denmark: invalid creditor number %q", l.Creditor)
+	}
+
+	return nil
+}
+
+// String returns the payment line, such as:
+//
+//	+71<000000001234566+12345678<
+func (l Line) String() string {
+	return fmt.Sprintf("+%s<%s+%s<", l.Card, l.PaymentID, l.Creditor)
+}
+
+// FromPayment returns the payment line of the payment, with the reference as
+// payment ID and the account number as creditor number. The card type is 73
+// without reference, 71 for 15 digit references and 75 for 16 digit ones.
+func FromPayment(p *payqr.Payment) (Line, error) {
+	if p.Currency != "" && !strings.EqualFold(p.Currency, "DKK") {
+		return Line{}, fmt.Errorf("denmark: currency must be DKK, got %s", p.Currency)
+	}
+
+	l := Line{
+		PaymentID: p.Reference,
+		Creditor:  strings.TrimPrefix(strings.ReplaceAll(p.AccountNumber, " ", ""), "+"),
+	}
+	switch {
+	case p.ReferenceScheme() == payqr.ReferenceGIK:
+		l.Card = Card04
+	case p.Reference == "":
+		l.Card = Card73
+	case len(p.Reference) == 16:
+		l.Card = Card75
+	default:
+		l.Card = Card71
+	}
+
+	return l, l.Validate()
+}
+
+// PaymentLine returns the payment line of the payment, see FromPayment.
+func PaymentLine(p *payqr.Payment) (string, error) {
+	l, err := FromPayment(p)
+	if err != nil {
+		return "", err
+	}
+
+	return l.String(), nil
+}
+
+// Barcode renders the payment line as a Code 128 barcode, with each module
+// moduleWidth pixels wide and height pixels high.
+func Barcode(p *payqr.Payment, moduleWidth, height int) (image.Image, error) {
+	line, err := PaymentLine(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return code128.Image(line, moduleWidth, height)
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,77 @@
+package denmark
+
+import (
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaymentLine(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		have    *payqr.Payment
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Card 71",
+			have: payqr.New("12345678", "Test A/S", "", "000000001234566", 100, due, payqr.WithCurrency("DKK")),
+			want: "+71<000000001234566+12345678<",
+		},
+		{
+			name: "Card 73",
+			have: payqr.New("+12345678", "Test A/S", "", "", 100, due),
+			want: "+73<+12345678<",
+		},
+		{
+			name: "Card 75",
+			have: payqr.New("12345678", "Test A/S", "", "0000000012345674", 100, due),
+			want: "+75<0000000012345674+12345678<",
+		},
+		{
+			name: "Card 04",
+			have: payqr.New("1234567", "Test A/S", "", "0000000012345674", 100, due, payqr.WithReferenceScheme(payqr.ReferenceGIK)),
+			want: "+04<0000000012345674+1234567<",
+		},
+		{
+			name:    "Invalid check digit",
+			have:    payqr.New("12345678", "Test A/S", "", "000000001234565", 100, due),
+			wantErr: true,
+		},
+		{
+			name:    "Short creditor",
+			have:    payqr.New("1234567", "Test A/S", "", "000000001234566", 100, due),
+			wantErr: true,
+		},
+		{
+			name:    "EUR",
+			have:    payqr.New("12345678", "Test A/S", "", "000000001234566", 100, due, payqr.WithCurrency("EUR")),
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := PaymentLine(test.have)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+			_, err = Barcode(test.have, 2, 50)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestLineValidate(t *testing.T) {
+	assert.Error(t, Line{Card: Card73, PaymentID: "000000001234566", Creditor: "12345678"}.Validate())
+	assert.Error(t, Line{Card: "01", Creditor: "12345678"}.Validate())
+	assert.NoError(t, Line{Card: Card73, Creditor: "12345678"}.Validate())
+}
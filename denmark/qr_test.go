@@ -0,0 +1,26 @@
+//go:build !payqr_payloadonly
+
+package denmark
+
+import (
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQR(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+	p := payqr.New("12345678", "Test A/S", "", "000000001234566", 100, due, payqr.WithCurrency("DKK"))
+
+	q, err := QR(p)
+	require.NoError(t, err)
+	want, err := PaymentLine(p)
+	require.NoError(t, err)
+	assert.Equal(t, want, q.Content)
+
+	_, err = QR(payqr.New("12345678", "Test A/S", "", "000000001234566", 100, due, payqr.WithCurrency("SEK")))
+	assert.Error(t, err)
+}
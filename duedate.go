@@ -0,0 +1,92 @@
+package payqr
+
+import "time"
+
+// DefaultMaxDueDays is how many days after the creation date the due date of
+// a payment may be by default, see ValidateDueDate.
+const DefaultMaxDueDays = 365
+
+// DueDateOption configures the checks of Payment.ValidateDueDate.
+type DueDateOption func(*dueDateConfig)
+
+type dueDateConfig struct {
+	maxDays int
+	notPast bool
+	now     time.Time
+	warn    func(err error)
+}
+
+// WithMaxDueDays sets how many days after the creation date the due date may
+// be. Zero or less disables the check.
+func WithMaxDueDays(days int) DueDateOption {
+	return func(c *dueDateConfig) {
+		c.maxDays = days
+	}
+}
+
+// WithDueDateNotPast checks that the due date is not before the date of now,
+// such as for payments about to be sent rather than reprinted.
+func WithDueDateNotPast(now time.Time) DueDateOption {
+	return func(c *dueDateConfig) {
+		c.notPast = true
+		c.now = now
+	}
+}
+
+// WithDueDateWarnings reports failed checks to warn instead of returning
+// them as errors, e.g. to log dates that look mis-keyed without rejecting
+// the payment. Dates that cannot be parsed are still errors.
+func WithDueDateWarnings(warn func(err error)) DueDateOption {
+	return func(c *dueDateConfig) {
+		c.warn = warn
+	}
+}
+
+// ValidateDueDate checks that the due date is not before the creation date
+// and at most DefaultMaxDueDays after it, and with WithDueDateNotPast that it
+// is not in the past. Mis-keyed dates are a common defect of invoices, but
+// the checks are not done by Validate as payments are often encoded long
+// after they were created.
+func (d *Payment) ValidateDueDate(options ...DueDateOption) error {
+	c := &dueDateConfig{maxDays: DefaultMaxDueDays}
+	for _, option := range options {
+		option(c)
+	}
+
+	due, err := time.Parse("20060102", d.DueDate)
+	if err != nil {
+		return invalid("ddt", ErrInvalidDate, "invalid due date %q", d.DueDate)
+	}
+
+	var checks []error
+	if d.CreatedDate != "" {
+		created, err := time.Parse("20060102", d.CreatedDate)
+		if err != nil {
+			return invalid("idt", ErrInvalidDate, "invalid creation date %q", d.CreatedDate)
+		}
+		if due.Before(created) {
+			checks = append(checks, invalid("ddt", ErrInvalidDate, "due date %s is before the creation date %s", d.DueDate, d.CreatedDate))
+		}
+		if c.maxDays > 0 && due.After(created.AddDate(0, 0, c.maxDays)) {
+			checks = append(checks, invalid("ddt", ErrInvalidDate, "due date %s is more than %d days after the creation date %s", d.DueDate, c.maxDays, d.CreatedDate))
+		}
+	}
+	if c.notPast {
+		today := time.Date(c.now.Year(), c.now.Month(), c.now.Day(), 0, 0, 0, 0, time.UTC)
+		if due.Before(today) {
+			checks = append(checks, invalid("ddt", ErrInvalidDate, "due date %s is in the past", d.DueDate))
+		}
+	}
+
+	if c.warn == nil {
+		if len(checks) > 0 {
+			return checks[0]
+		}
+		return nil
+	}
+	for _, err := range checks {
+		c.warn(err)
+	}
+
+	return nil
+}
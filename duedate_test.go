@@ -0,0 +1,96 @@
+package payqr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateDueDate(t *testing.T) {
+	created := time.Date(2022, time.August, 1, 0, 0, 0, 0, time.Local)
+	now := time.Date(2022, time.August, 10, 15, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name    string
+		due     time.Time
+		options []DueDateOption
+		wantErr string
+	}{
+		{
+			name: "Due after creation",
+			due:  time.Date(2022, time.August, 31, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name: "Due at creation",
+			due:  created,
+		},
+		{
+			name:    "Due before creation",
+			due:     time.Date(2022, time.July, 31, 0, 0, 0, 0, time.Local),
+			wantErr: "due date 20220731 is before the creation date 20220801",
+		},
+		{
+			name:    "Due far ahead",
+			due:     time.Date(2032, time.August, 1, 0, 0, 0, 0, time.Local),
+			wantErr: "due date 20320801 is more than 365 days after the creation date 20220801",
+		},
+		{
+			name:    "Due after max days",
+			due:     time.Date(2022, time.September, 1, 0, 0, 0, 0, time.Local),
+			options: []DueDateOption{WithMaxDueDays(30)},
+			wantErr: "due date 20220901 is more than 30 days after the creation date 20220801",
+		},
+		{
+			name:    "Max days disabled",
+			due:     time.Date(2032, time.August, 1, 0, 0, 0, 0, time.Local),
+			options: []DueDateOption{WithMaxDueDays(0)},
+		},
+		{
+			name: "Past without check",
+			due:  time.Date(2022, time.August, 9, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name:    "Past",
+			due:     time.Date(2022, time.August, 9, 0, 0, 0, 0, time.Local),
+			options: []DueDateOption{WithDueDateNotPast(now)},
+			wantErr: "due date 20220809 is in the past",
+		},
+		{
+			name:    "Today",
+			due:     time.Date(2022, time.August, 10, 0, 0, 0, 0, time.Local),
+			options: []DueDateOption{WithDueDateNotPast(now)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := New("5402-9681", "Test AB", "1234", "1001", 50, test.due, WithCreationDate(created))
+
+			err := d.ValidateDueDate(test.options...)
+			if test.wantErr != "" {
+				assert.EqualError(t, err, test.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestValidateDueDateWarnings(t *testing.T) {
+	created := time.Date(2022, time.August, 1, 0, 0, 0, 0, time.Local)
+	d := New("5402-9681", "Test AB", "1234", "1001", 50, time.Date(2022, time.July, 1, 0, 0, 0, 0, time.Local), WithCreationDate(created))
+
+	var warnings []string
+	err := d.ValidateDueDate(WithDueDateNotPast(created), WithDueDateWarnings(func(err error) {
+		warnings = append(warnings, err.Error())
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"due date 20220701 is before the creation date 20220801",
+		"due date 20220701 is in the past",
+	}, warnings)
+
+	d.DueDate = "2022-07-01"
+	assert.EqualError(t, d.ValidateDueDate(WithDueDateWarnings(func(error) {})), `invalid due date "2022-07-01"`)
+}
@@ -0,0 +1,106 @@
+// Package email builds MIME bodies for emails with the payment QR code as
+// an inline image. The code is attached to a multipart/related body and
+// referenced by its Content-ID, so that it is shown by email clients that
+// block remote images and data URIs.
+package email
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+)
+
+// QRContentID is the default Content-ID of the QR code image.
+const QRContentID = "qr@payqr"
+
+// Inline is an image attached inline to the body.
+type Inline struct {
+	ContentID   string
+	ContentType string
+	Filename    string
+	Data        []byte
+}
+
+// URL returns the cid URL referring to the image, to be used as the src of an
+// img element.
+func (i Inline) URL() template.URL {
+	return template.URL("cid:" + i.ContentID)
+}
+
+// WriteRelated writes a multipart/related body with the HTML and the inline
+// images, and returns its content type, including the boundary, for the
+// Content-Type header of the message.
+func WriteRelated(w io.Writer, html []byte, images ...Inline) (string, error) {
+	mw := multipart.NewWriter(w)
+
+	part, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/html; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return "", err
+	}
+	qw := quotedprintable.NewWriter(part)
+	if _, err := qw.Write(html); err != nil {
+		return "", err
+	}
+	if err := qw.Close(); err != nil {
+		return "", err
+	}
+
+	for _, img := range images {
+		if img.ContentID == "" {
+			return "", fmt.Errorf("email: inline image %q has no Content-ID", img.Filename)
+		}
+
+		header := textproto.MIMEHeader{
+			"Content-Type":              {img.ContentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-ID":                {"<" + img.ContentID + ">"},
+			"Content-Disposition":       {"inline"},
+		}
+		if img.Filename != "" {
+			header.Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": img.Filename}))
+		}
+
+		part, err := mw.CreatePart(header)
+		if err != nil {
+			return "", err
+		}
+		if err := writeBase64(part, img.Data); err != nil {
+			return "", err
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return "", err
+	}
+
+	return mime.FormatMediaType("multipart/related", map[string]string{
+		"boundary": mw.Boundary(),
+		"type":     "text/html",
+	}), nil
+}
+
+// writeBase64 writes the data base64 encoded in lines of 76 characters, the
+// maximum allowed by RFC 2045.
+func writeBase64(w io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 0 {
+		n := 76
+		if n > len(encoded) {
+			n = len(encoded)
+		}
+		if _, err := io.WriteString(w, encoded[:n]+"\r\n"); err != nil {
+			return err
+		}
+		encoded = encoded[n:]
+	}
+
+	return nil
+}
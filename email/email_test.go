@@ -0,0 +1,54 @@
+package email
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteRelated(t *testing.T) {
+	img := Inline{ContentID: "logo@example.com", ContentType: "image/png", Filename: "logo.png", Data: bytes.Repeat([]byte{1, 2, 3}, 100)}
+
+	var buf bytes.Buffer
+	contentType, err := WriteRelated(&buf, []byte(`<p>Hello <img src="cid:logo@example.com"></p>`), img)
+	require.NoError(t, err)
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/related", mediaType)
+	assert.Equal(t, "text/html", params["type"])
+
+	r := multipart.NewReader(&buf, params["boundary"])
+
+	part, err := r.NextPart()
+	require.NoError(t, err)
+	assert.Equal(t, "text/html; charset=utf-8", part.Header.Get("Content-Type"))
+	html, err := io.ReadAll(part)
+	require.NoError(t, err)
+	assert.Equal(t, `<p>Hello <img src="cid:logo@example.com"></p>`, string(html))
+
+	part, err = r.NextPart()
+	require.NoError(t, err)
+	assert.Equal(t, "<logo@example.com>", part.Header.Get("Content-ID"))
+	assert.Equal(t, `inline; filename=logo.png`, part.Header.Get("Content-Disposition"))
+	encoded, err := io.ReadAll(part)
+	require.NoError(t, err)
+	for _, line := range bytes.Split(bytes.TrimSpace(encoded), []byte("\r\n")) {
+		assert.True(t, len(line) <= 76)
+	}
+	data, err := base64.StdEncoding.DecodeString(string(bytes.ReplaceAll(encoded, []byte("\r\n"), nil)))
+	require.NoError(t, err)
+	assert.Equal(t, img.Data, data)
+
+	_, err = r.NextPart()
+	assert.Equal(t, io.EOF, err)
+
+	_, err = WriteRelated(&buf, nil, Inline{Filename: "x.png"})
+	assert.Error(t, err)
+}
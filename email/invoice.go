@@ -0,0 +1,37 @@
+//go:build !payqr_payloadonly
+
+package email
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/antonlindstrom/payqr/invoice"
+)
+
+// Invoice writes the invoice rendered with invoice.DefaultTemplate as a
+// multipart/related body with the QR code inline, and returns its content
+// type.
+func Invoice(w io.Writer, inv *invoice.Invoice) (string, error) {
+	data, err := invoice.NewTemplateData(inv)
+	if err != nil {
+		return "", err
+	}
+
+	q, err := inv.Payment.QR()
+	if err != nil {
+		return "", err
+	}
+	img, err := QR(q, invoice.QRImageSize, "")
+	if err != nil {
+		return "", err
+	}
+	data.QR = img.URL()
+
+	var html bytes.Buffer
+	if err := invoice.DefaultTemplate.Execute(&html, data); err != nil {
+		return "", err
+	}
+
+	return WriteRelated(w, html.Bytes(), img)
+}
@@ -0,0 +1,49 @@
+//go:build !payqr_payloadonly
+
+package email
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/antonlindstrom/payqr/invoice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvoice(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+	inv := &invoice.Invoice{
+		Number:  "1001",
+		Date:    due.AddDate(0, 0, -30),
+		DueDate: due,
+		Seller:  invoice.Party{Name: "Test AB"},
+		Buyer:   invoice.Party{Name: "Kund AB"},
+		Items:   []invoice.Item{{Description: "Book", Quantity: 1, UnitPrice: 100, VATRate: 6}},
+		Payment: payqr.New("5402-9681", "Test AB", "", "52250047157", 106, due),
+	}
+
+	var buf bytes.Buffer
+	contentType, err := Invoice(&buf, inv)
+	require.NoError(t, err)
+
+	_, params, err := mime.ParseMediaType(contentType)
+	require.NoError(t, err)
+	r := multipart.NewReader(&buf, params["boundary"])
+
+	part, err := r.NextPart()
+	require.NoError(t, err)
+	html, err := io.ReadAll(part)
+	require.NoError(t, err)
+	assert.Contains(t, string(html), `src="cid:qr@payqr"`)
+	assert.NotContains(t, string(html), "data:image/png")
+
+	part, err = r.NextPart()
+	require.NoError(t, err)
+	assert.Equal(t, "<qr@payqr>", part.Header.Get("Content-ID"))
+}
@@ -0,0 +1,19 @@
+//go:build !payqr_payloadonly
+
+package email
+
+import "github.com/skip2/go-qrcode"
+
+// QR returns the QR code as an inline PNG image of size pixels with the
+// Content-ID, or QRContentID if empty.
+func QR(q *qrcode.QRCode, size int, contentID string) (Inline, error) {
+	b, err := q.PNG(size)
+	if err != nil {
+		return Inline{}, err
+	}
+	if contentID == "" {
+		contentID = QRContentID
+	}
+
+	return Inline{ContentID: contentID, ContentType: "image/png", Filename: "qr.png", Data: b}, nil
+}
@@ -0,0 +1,37 @@
+//go:build !payqr_payloadonly
+
+package payqr
+
+import (
+	"io"
+
+	"github.com/antonlindstrom/payqr/internal/pdf"
+)
+
+// EmbedInPDF writes the PDF document from src to dst with the QR code of the
+// payment drawn on the page, numbered from 1. The top left corner of the code
+// is placed x and y millimeters from the top left corner of the page and the
+// code, including its quiet zone, is sizeMM millimeters wide.
+//
+// The code is added as an incremental update, so the original document,
+// including any signatures, is kept intact.
+func (d *Payment) EmbedInPDF(src io.Reader, dst io.Writer, page int, x, y, sizeMM float64) error {
+	q, err := d.QR()
+	if err != nil {
+		return err
+	}
+
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return err
+	}
+
+	return pdf.Overlay(data, dst, page, func(p *pdf.Page) {
+		left, bottom, size := x*pdf.MM, p.Height-(y+sizeMM)*pdf.MM, sizeMM*pdf.MM
+
+		p.Gray(1)
+		p.Rect(left, bottom, size, size, true, 0)
+		p.Gray(0)
+		p.Modules(left, bottom, size, q.Bitmap())
+	})
+}
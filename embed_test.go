@@ -0,0 +1,46 @@
+//go:build !payqr_payloadonly
+
+package payqr
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr/internal/pdf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbedInPDF(t *testing.T) {
+	doc := pdf.New()
+	doc.AddPage(pdf.A4Width, pdf.A4Height).Text(50, 800, pdf.Helvetica, 12, "Invoice 123")
+	src := doc.Bytes()
+
+	p := New("5402-9681", "Test AB", "1234", "", 50, time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name    string
+		page    int
+		wantErr bool
+	}{
+		{name: "First page", page: 1},
+		{name: "Missing page", page: 2, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := p.EmbedInPDF(bytes.NewReader(src), &out, test.page, 160, 232, 30)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, bytes.HasPrefix(out.Bytes(), src))
+
+			_, err = pdf.NewReader(out.Bytes())
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,285 @@
+// Package emvco encodes EMVCo Merchant-Presented Mode (MPM) QR code payloads,
+// the base of many national QR payment schemes such as Pix and PromptPay.
+package emvco
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Root field IDs of the payload.
+const (
+	IDPayloadFormat       = "00"
+	IDPointOfInitiation   = "01"
+	IDMerchantCategory    = "52"
+	IDCurrency            = "53"
+	IDAmount              = "54"
+	IDCountryCode         = "58"
+	IDMerchantName        = "59"
+	IDMerchantCity        = "60"
+	IDPostalCode          = "61"
+	IDAdditionalData      = "62"
+	IDCRC                 = "63"
+	IDMerchantInformation = "64"
+)
+
+// Point of initiation methods.
+const (
+	// Static is used for codes that are shown for more than one payment.
+	Static = "11"
+	// Dynamic is used for codes that are shown for a single payment.
+	Dynamic = "12"
+)
+
+// Field is a data object of the payload, either a value or a template of
+// sub fields.
+type Field struct {
+	ID     string
+	Value  string
+	Fields []Field
+}
+
+// Value returns a field with the value.
+func Value(id, value string) Field {
+	return Field{ID: id, Value: value}
+}
+
+// Template returns a field holding the sub fields.
+func Template(id string, fields ...Field) Field {
+	return Field{ID: id, Fields: fields}
+}
+
+// Encode returns the field in ID, length, value format.
+func (f Field) Encode() (string, error) {
+	if len(f.ID) != 2 || !isDigits(f.ID) {
+		return "", fmt.Errorf("emvco: invalid field ID %q", f.ID)
+	}
+
+	value := f.Value
+	if len(f.Fields) > 0 {
+		var err error
+		if value, err = encodeFields(f.Fields); err != nil {
+			return "", err
+		}
+	}
+	if value == "" {
+		return "", fmt.Errorf("emvco: field %s is empty", f.ID)
+	}
+	if len(value) > 99 {
+		return "", fmt.Errorf("emvco: field %s is %d characters, max is 99", f.ID, len(value))
+	}
+
+	return fmt.Sprintf("%s%02d%s", f.ID, len(value), value), nil
+}
+
+func encodeFields(fields []Field) (string, error) {
+	var b strings.Builder
+	for _, f := range fields {
+		s, err := f.Encode()
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(s)
+	}
+
+	return b.String(), nil
+}
+
+// Payload is a Merchant-Presented Mode payload. Empty fields are left out.
+type Payload struct {
+	// PointOfInitiation is Static or Dynamic.
+	PointOfInitiation string
+	// MerchantAccounts are the merchant account information templates, IDs
+	// 02 to 51, of the schemes the merchant accepts.
+	MerchantAccounts []Field
+	// MerchantCategory is the ISO 18245 merchant category code, "0000" if
+	// not used.
+	MerchantCategory string
+	// Currency is the ISO 4217 numeric currency code, such as "986".
+	Currency string
+	// Amount is the transaction amount, left out to let the payer enter it.
+	Amount string
+	// CountryCode is the ISO 3166-1 alpha-2 country code of the merchant.
+	CountryCode  string
+	MerchantName string
+	MerchantCity string
+	PostalCode   string
+	// AdditionalData are the sub fields of the additional data template,
+	// such as the reference label (05).
+	AdditionalData []Field
+	// Extra are any other fields, such as the merchant information language
+	// template (64) or unreserved templates (80 to 99).
+	Extra []Field
+}
+
+// FormatAmount formats an amount with the decimals of the currency.
+func FormatAmount(amount float64, decimals int) string {
+	return strconv.FormatFloat(amount, 'f', decimals, 64)
+}
+
+// Validate checks that the mandatory fields are set.
+func (p Payload) Validate() error {
+	if len(p.MerchantAccounts) == 0 {
+		return fmt.Errorf("emvco: no merchant account information")
+	}
+	for _, f := range p.MerchantAccounts {
+		if id, _ := strconv.Atoi(f.ID); id < 2 || id > 51 {
+			return fmt.Errorf("emvco: merchant account information ID %s is not between 02 and 51", f.ID)
+		}
+	}
+	if p.PointOfInitiation != "" && p.PointOfInitiation != Static && p.PointOfInitiation != Dynamic {
+		return fmt.Errorf("emvco: invalid point of initiation %q", p.PointOfInitiation)
+	}
+
+	required := []struct{ name, value string }{
+		{"merchant category", p.MerchantCategory},
+		{"currency", p.Currency},
+		{"country code", p.CountryCode},
+		{"merchant name", p.MerchantName},
+		{"merchant city", p.MerchantCity},
+	}
+	for _, r := range required {
+		if r.value == "" {
+			return fmt.Errorf("emvco: %s is required", r.name)
+		}
+	}
+	if len(p.Currency) != 3 || !isDigits(p.Currency) {
+		return fmt.Errorf("emvco: currency must be a 3 digit ISO 4217 code, got %q", p.Currency)
+	}
+	if len(p.Amount) > 13 {
+		return fmt.Errorf("emvco: amount %s is longer than 13 characters", p.Amount)
+	}
+	if len(p.MerchantName) > 25 {
+		return fmt.Errorf("emvco: merchant name is longer than 25 characters")
+	}
+	if len(p.MerchantCity) > 15 {
+		return fmt.Errorf("emvco: merchant city is longer than 15 characters")
+	}
+
+	return nil
+}
+
+// Fields returns the fields of the payload, without CRC, in order of their
+// IDs.
+func (p Payload) Fields() []Field {
+	fields := []Field{Value(IDPayloadFormat, "01")}
+	add := func(id, value string) {
+		if value != "" {
+			fields = append(fields, Value(id, value))
+		}
+	}
+
+	add(IDPointOfInitiation, p.PointOfInitiation)
+	fields = append(fields, p.MerchantAccounts...)
+	add(IDMerchantCategory, p.MerchantCategory)
+	add(IDCurrency, p.Currency)
+	add(IDAmount, p.Amount)
+	add(IDCountryCode, p.CountryCode)
+	add(IDMerchantName, p.MerchantName)
+	add(IDMerchantCity, p.MerchantCity)
+	add(IDPostalCode, p.PostalCode)
+	if len(p.AdditionalData) > 0 {
+		fields = append(fields, Template(IDAdditionalData, p.AdditionalData...))
+	}
+	fields = append(fields, p.Extra...)
+
+	sort.SliceStable(fields, func(i, j int) bool { return fields[i].ID < fields[j].ID })
+
+	return fields
+}
+
+// Encode returns the payload with the CRC appended.
+func (p Payload) Encode() (string, error) {
+	if err := p.Validate(); err != nil {
+		return "", err
+	}
+
+	s, err := encodeFields(p.Fields())
+	if err != nil {
+		return "", err
+	}
+
+	s += IDCRC + "04"
+
+	return fmt.Sprintf("%s%04X", s, CRC16(s)), nil
+}
+
+// CRC16 returns the CRC-16/CCITT-FALSE checksum (polynomial 0x1021, initial
+// value 0xFFFF) of the data.
+func CRC16(data string) uint16 {
+	crc := uint16(0xffff)
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+
+	return crc
+}
+
+// Decode parses the root fields of a payload and verifies the CRC. Templates
+// are returned as values, use DecodeFields to parse them.
+func Decode(payload string) ([]Field, error) {
+	if len(payload) < 8 || payload[len(payload)-8:len(payload)-4] != IDCRC+"04" {
+		return nil, fmt.Errorf("emvco: payload does not end with a CRC")
+	}
+
+	crc, err := strconv.ParseUint(payload[len(payload)-4:], 16, 16)
+	if err != nil {
+		return nil, fmt.Errorf("emvco: invalid CRC %q", payload[len(payload)-4:])
+	}
+	if want := CRC16(payload[:len(payload)-4]); uint16(crc) != want {
+		return nil, fmt.Errorf("emvco: CRC is %04X, expected %04X", crc, want)
+	}
+
+	return DecodeFields(payload)
+}
+
+// DecodeFields parses a sequence of fields in ID, length, value format.
+func DecodeFields(s string) ([]Field, error) {
+	var fields []Field
+	for len(s) > 0 {
+		if len(s) < 4 {
+			return nil, fmt.Errorf("emvco: truncated field %q", s)
+		}
+
+		// The length is two digits, Atoi would accept a sign.
+		n, err := strconv.Atoi(s[2:4])
+		if err != nil || !isDigits(s[2:4]) || len(s) < 4+n {
+			return nil, fmt.Errorf("emvco: invalid length of field %s", s[:2])
+		}
+
+		fields = append(fields, Value(s[:2], s[4:4+n]))
+		s = s[4+n:]
+	}
+
+	return fields, nil
+}
+
+// Lookup returns the value of the field with the ID.
+func Lookup(fields []Field, id string) (string, bool) {
+	for _, f := range fields {
+		if f.ID == id {
+			return f.Value, true
+		}
+	}
+
+	return "", false
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
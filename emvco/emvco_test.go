@@ -0,0 +1,106 @@
+package emvco
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name    string
+		have    Payload
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Pix static",
+			have: Payload{
+				MerchantAccounts: []Field{Template("26", Value("00", "br.gov.bcb.pix"), Value("01", "123e4567-e12b-12d1-a456-426655440000"))},
+				MerchantCategory: "0000",
+				Currency:         "986",
+				CountryCode:      "BR",
+				MerchantName:     "Fulano de Tal",
+				MerchantCity:     "BRASILIA",
+				AdditionalData:   []Field{Value("05", "***")},
+			},
+			want: "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3D",
+		},
+		{
+			name: "Missing merchant account",
+			have: Payload{
+				MerchantCategory: "0000",
+				Currency:         "986",
+				CountryCode:      "BR",
+				MerchantName:     "Fulano de Tal",
+				MerchantCity:     "BRASILIA",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Alpha currency",
+			have: Payload{
+				MerchantAccounts: []Field{Template("26", Value("00", "br.gov.bcb.pix"))},
+				MerchantCategory: "0000",
+				Currency:         "BRL",
+				CountryCode:      "BR",
+				MerchantName:     "Fulano de Tal",
+				MerchantCity:     "BRASILIA",
+			},
+			wantErr: true,
+		},
+		{
+			name: "Empty field",
+			have: Payload{
+				MerchantAccounts: []Field{Template("26", Value("00", ""))},
+				MerchantCategory: "0000",
+				Currency:         "986",
+				CountryCode:      "BR",
+				MerchantName:     "Fulano de Tal",
+				MerchantCity:     "BRASILIA",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.have.Encode()
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	fields, err := Decode("00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3D")
+	require.NoError(t, err)
+
+	name, ok := Lookup(fields, IDMerchantName)
+	assert.True(t, ok)
+	assert.Equal(t, "Fulano de Tal", name)
+
+	account, _ := Lookup(fields, "26")
+	sub, err := DecodeFields(account)
+	require.NoError(t, err)
+	assert.Equal(t, []Field{Value("00", "br.gov.bcb.pix"), Value("01", "123e4567-e12b-12d1-a456-426655440000")}, sub)
+
+	_, err = Decode("00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3E")
+	assert.Error(t, err)
+
+	for _, fields := range []string{"00-1abcd", "00+1abcd", "0002ab0"} {
+		payload := fields + IDCRC + "04"
+		_, err = Decode(payload + fmt.Sprintf("%04X", CRC16(payload)))
+		assert.Error(t, err, fields)
+	}
+}
+
+func TestCRC16(t *testing.T) {
+	assert.Equal(t, uint16(0x29B1), CRC16("123456789"))
+}
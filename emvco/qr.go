@@ -0,0 +1,15 @@
+//go:build !payqr_payloadonly
+
+package emvco
+
+import "github.com/skip2/go-qrcode"
+
+// QR returns a QR code of the payload.
+func (p Payload) QR() (*qrcode.QRCode, error) {
+	s, err := p.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	return qrcode.New(s, qrcode.Medium)
+}
@@ -0,0 +1,146 @@
+//go:build !payqr_payloadonly
+
+package payqr
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"sync"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// Encoder renders the QR codes of payments like Payment.QR and
+// QRCode.PNG, with the same output, but reuses its buffers between calls
+// so that services rendering many codes allocate little besides the QR
+// symbol itself. An Encoder is safe for concurrent use.
+type Encoder struct {
+	states sync.Pool // *encodeState
+	images sync.Pool // *image.Paletted
+	png    png.Encoder
+}
+
+// encodeState are the buffers of one encoding.
+type encodeState struct {
+	payload []byte
+	image   bytes.Buffer
+}
+
+// NewEncoder returns an encoder.
+func NewEncoder() *Encoder {
+	e := &Encoder{}
+	e.png = png.Encoder{CompressionLevel: png.BestCompression, BufferPool: &pngBufferPool{}}
+	e.states.New = func() interface{} {
+		return &encodeState{}
+	}
+
+	return e
+}
+
+// pngBufferPool reuses the buffers of the PNG encoder.
+type pngBufferPool struct {
+	pool sync.Pool
+}
+
+func (p *pngBufferPool) Get() *png.EncoderBuffer {
+	b, _ := p.pool.Get().(*png.EncoderBuffer)
+	return b
+}
+
+func (p *pngBufferPool) Put(b *png.EncoderBuffer) {
+	p.pool.Put(b)
+}
+
+// appendPayload appends the JSON payload of the payment to the state's
+// payload buffer, as encoded by Payment.QR.
+func (s *encodeState) appendPayload(d *Payment) ([]byte, error) {
+	var err error
+	s.payload, err = d.AppendPayload(s.payload[:0])
+
+	return s.payload, err
+}
+
+// AppendPayload appends the JSON payload of the QR code of the payment to
+// dst and returns the extended buffer, as Payment.AppendPayload.
+func (e *Encoder) AppendPayload(dst []byte, d *Payment) ([]byte, error) {
+	return d.AppendPayload(dst)
+}
+
+// EncodeAppend appends the QR code of the payment as a PNG image of the size
+// in pixels to dst and returns the extended buffer.
+func (e *Encoder) EncodeAppend(dst []byte, d *Payment, size int) ([]byte, error) {
+	s := e.states.Get().(*encodeState)
+	defer e.states.Put(s)
+
+	payload, err := s.appendPayload(d)
+	if err != nil {
+		return dst, err
+	}
+	q, err := newQR(string(payload), qrcode.High, d)
+	if err != nil {
+		return dst, err
+	}
+
+	return e.appendPNG(dst, s, q, size)
+}
+
+// AppendPNG appends the QR code as a PNG image of the size in pixels to dst
+// and returns the extended buffer, as QRCode.PNG does.
+func (e *Encoder) AppendPNG(dst []byte, q *qrcode.QRCode, size int) ([]byte, error) {
+	s := e.states.Get().(*encodeState)
+	defer e.states.Put(s)
+
+	return e.appendPNG(dst, s, q, size)
+}
+
+func (e *Encoder) appendPNG(dst []byte, s *encodeState, q *qrcode.QRCode, size int) ([]byte, error) {
+	img := e.image(q, size)
+	defer e.images.Put(img)
+
+	s.image.Reset()
+	if err := e.png.Encode(&s.image, img); err != nil {
+		return dst, err
+	}
+
+	return append(dst, s.image.Bytes()...), nil
+}
+
+// image draws the code in a pooled image, pixel by pixel as QRCode.Image.
+func (e *Encoder) image(q *qrcode.QRCode, size int) *image.Paletted {
+	bitmap := q.Bitmap()
+	modules := len(bitmap)
+	if size < 0 {
+		size = -size * modules
+	}
+	if size < modules {
+		size = modules
+	}
+
+	img, _ := e.images.Get().(*image.Paletted)
+	if img == nil || cap(img.Pix) < size*size {
+		img = image.NewPaletted(image.Rect(0, 0, size, size), color.Palette{q.BackgroundColor, q.ForegroundColor})
+	} else {
+		img.Pix = img.Pix[:size*size]
+		for i := range img.Pix {
+			img.Pix[i] = 0
+		}
+		img.Stride, img.Rect = size, image.Rect(0, 0, size, size)
+		img.Palette[0], img.Palette[1] = q.BackgroundColor, q.ForegroundColor
+	}
+	fg := uint8(img.Palette.Index(q.ForegroundColor))
+
+	modulesPerPixel := float64(modules) / float64(size)
+	for y := 0; y < size; y++ {
+		row := bitmap[int(float64(y)*modulesPerPixel)]
+		pix := img.Pix[y*size : (y+1)*size]
+		for x := range pix {
+			if row[int(float64(x)*modulesPerPixel)] {
+				pix[x] = fg
+			}
+		}
+	}
+
+	return img
+}
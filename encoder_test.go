@@ -0,0 +1,108 @@
+//go:build !payqr_payloadonly
+
+package payqr
+
+import (
+	"encoding/json"
+	"image/color"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/skip2/go-qrcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encoderPayment() *Payment {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+
+	return New("5402-9681", "Test AB & Co", "5555555555", "52250047157", 1250, due)
+}
+
+func TestEncoder(t *testing.T) {
+	e := NewEncoder()
+	d := encoderPayment()
+
+	want, err := json.Marshal(d)
+	require.NoError(t, err)
+	payload, err := e.AppendPayload([]byte("prefix:"), d)
+	require.NoError(t, err)
+	assert.Equal(t, "prefix:"+string(want), string(payload))
+
+	q, err := d.QR()
+	require.NoError(t, err)
+
+	// Sizes alternate to reuse pooled images of other sizes.
+	for _, size := range []int{512, 128, 512, 10, -4} {
+		want, err := q.PNG(size)
+		require.NoError(t, err)
+
+		b, err := e.EncodeAppend(nil, d, size)
+		require.NoError(t, err)
+		assert.Equal(t, want, b, "size %d", size)
+
+		b, err = e.AppendPNG([]byte{1, 2}, q, size)
+		require.NoError(t, err)
+		assert.Equal(t, append([]byte{1, 2}, want...), b, "size %d", size)
+	}
+}
+
+func TestEncoderColors(t *testing.T) {
+	q, err := qrcode.New("C1231111111;50.00;My message;6", qrcode.High)
+	require.NoError(t, err)
+	q.ForegroundColor = color.RGBA{R: 0x1b, G: 0x6d, B: 0x3a, A: 0xff}
+
+	want, err := q.PNG(256)
+	require.NoError(t, err)
+	b, err := NewEncoder().AppendPNG(nil, q, 256)
+	require.NoError(t, err)
+	assert.Equal(t, want, b)
+}
+
+func TestEncoderConcurrent(t *testing.T) {
+	e := NewEncoder()
+	want, err := e.EncodeAppend(nil, encoderPayment(), 256)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				b, err := e.EncodeAppend(nil, encoderPayment(), 256)
+				assert.NoError(t, err)
+				assert.Equal(t, want, b)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkPNG(b *testing.B) {
+	d := encoderPayment()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		q, err := d.QR()
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := q.PNG(512); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncoder(b *testing.B) {
+	d := encoderPayment()
+	e := NewEncoder()
+	var dst []byte
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var err error
+		if dst, err = e.EncodeAppend(dst[:0], d, 512); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// Package epc encodes payments as EPC069-12 QR codes (also known as Girocode
+// or SEPA QR), scannable by banking apps in most of the SEPA area.
+package epc
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/antonlindstrom/payqr"
+)
+
+// Currency is the only currency supported by EPC QR codes.
+const Currency = "EUR"
+
+// MaxPayloadSize is the maximum size of the payload in bytes.
+const MaxPayloadSize = 331
+
+// Option is a modifier for the encoding.
+type Option func(*config)
+
+type config struct {
+	version     int
+	purpose     string
+	information string
+}
+
+// WithVersion sets the version of the format, 1 or 2. Version 1 requires a
+// BIC. Default is 2.
+func WithVersion(version int) Option {
+	return func(c *config) {
+		c.version = version
+	}
+}
+
+// WithPurpose sets the four letter ISO 20022 purpose code, e.g. "GDDS".
+func WithPurpose(purpose string) Option {
+	return func(c *config) {
+		c.purpose = purpose
+	}
+}
+
+// WithInformation sets the beneficiary to originator information shown to the
+// payer.
+func WithInformation(information string) Option {
+	return func(c *config) {
+		c.information = information
+	}
+}
+
+// Encode encodes the payment as an EPC QR payload. The payment must be an
+// IBAN payment in EUR, or without currency. A reference that is a valid
+// ISO 11649 (RF) creditor reference is encoded as structured reference,
+// other references as unstructured remittance information.
+func Encode(p *payqr.Payment, options ...Option) (string, error) {
+	c := &config{version: 2}
+	for _, opt := range options {
+		opt(c)
+	}
+
+	if p.PaymentType != payqr.PaymentTypeIBAN {
+		return "", fmt.Errorf("epc: payment type must be %s, got %s", payqr.PaymentTypeIBAN, p.PaymentType)
+	}
+	if p.Currency != "" && !strings.EqualFold(p.Currency, Currency) {
+		return "", fmt.Errorf("epc: currency must be %s, got %s", Currency, p.Currency)
+	}
+	if c.version != 1 && c.version != 2 {
+		return "", fmt.Errorf("epc: unsupported version %d", c.version)
+	}
+	if c.version == 1 && p.BankCode == "" {
+		return "", fmt.Errorf("epc: version 1 requires a BIC")
+	}
+
+	iban := strings.ToUpper(strings.ReplaceAll(p.AccountNumber, " ", ""))
+	if err := payqr.ValidateAccount(iban[:min(2, len(iban))], payqr.PaymentTypeIBAN, iban); err != nil {
+		return "", fmt.Errorf("epc: %w", err)
+	}
+
+	if p.DueAmount < 0.01 || p.DueAmount > 999999999.99 {
+		return "", fmt.Errorf("epc: amount %.2f must be between 0.01 and 999999999.99", p.DueAmount)
+	}
+
+	var structured, unstructured string
+	if IsCreditorReference(p.Reference) {
+		structured = strings.ReplaceAll(p.Reference, " ", "")
+	} else {
+		unstructured = p.Reference
+	}
+
+	fields := []struct {
+		name  string
+		value string
+		max   int
+	}{
+		{"service tag", "BCD", 3},
+		{"version", fmt.Sprintf("%03d", c.version), 3},
+		{"character set", "1", 1},
+		{"identification", "SCT", 3},
+		{"BIC", p.BankCode, 11},
+		{"name", p.AccountName, 70},
+		{"IBAN", iban, 34},
+		{"amount", fmt.Sprintf("%s%.2f", Currency, p.DueAmount), len(Currency) + 12}, // 12 characters after the currency.
+		{"purpose", c.purpose, 4},
+		{"reference", structured, 35},
+		{"remittance information", unstructured, 140},
+		{"information", c.information, 70},
+	}
+
+	lines := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if utf8.RuneCountInString(f.value) > f.max {
+			return "", fmt.Errorf("epc: %s is longer than %d characters", f.name, f.max)
+		}
+		lines = append(lines, f.value)
+	}
+	if p.AccountName == "" {
+		return "", fmt.Errorf("epc: name is required")
+	}
+
+	// Trailing empty fields are omitted.
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	payload := strings.Join(lines, "\n")
+	if len(payload) > MaxPayloadSize {
+		return "", fmt.Errorf("epc: payload is %d bytes, maximum is %d", len(payload), MaxPayloadSize)
+	}
+
+	return payload, nil
+}
+
+// IsCreditorReference reports whether the reference is a valid ISO 11649
+// (RF) creditor reference.
+func IsCreditorReference(ref string) bool {
+	ref = strings.ToUpper(strings.ReplaceAll(ref, " ", ""))
+	if len(ref) < 5 || len(ref) > 25 || !strings.HasPrefix(ref, "RF") {
+		return false
+	}
+
+	var digits strings.Builder
+	for _, r := range ref[4:] + ref[:4] {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r >= 'A' && r <= 'Z':
+			fmt.Fprintf(&digits, "%d", r-'A'+10)
+		default:
+			return false
+		}
+	}
+
+	n, ok := new(big.Int).SetString(digits.String(), 10)
+
+	return ok && new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
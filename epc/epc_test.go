@@ -0,0 +1,94 @@
+package epc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		have        *payqr.Payment
+		haveOptions []Option
+		want        string
+		wantErr     bool
+	}{
+		{
+			name: "Unstructured remittance",
+			have: payqr.New("DE89 3704 0044 0532 0130 00", "Red Cross", "", "Donation 2022", 12.3, due, payqr.WithPaymentType(payqr.PaymentTypeIBAN), payqr.WithBankCode("COBADEFFXXX")),
+			want: "BCD\n002\n1\nSCT\nCOBADEFFXXX\nRed Cross\nDE89370400440532013000\nEUR12.30\n\n\nDonation 2022",
+		},
+		{
+			name:        "Structured reference without BIC",
+			have:        payqr.New("DE89370400440532013000", "Red Cross", "", "RF18 5390 0754 7034", 100, due, payqr.WithPaymentType(payqr.PaymentTypeIBAN), payqr.WithCurrency("EUR")),
+			haveOptions: []Option{WithPurpose("CHAR")},
+			want:        "BCD\n002\n1\nSCT\n\nRed Cross\nDE89370400440532013000\nEUR100.00\nCHAR\nRF18539007547034",
+		},
+		{
+			name:        "Version 1 without BIC",
+			have:        payqr.New("DE89370400440532013000", "Red Cross", "", "1001", 100, due, payqr.WithPaymentType(payqr.PaymentTypeIBAN)),
+			haveOptions: []Option{WithVersion(1)},
+			wantErr:     true,
+		},
+		{
+			name: "Large amount",
+			have: payqr.New("DE89370400440532013000", "Red Cross", "", "1001", 1500000, due, payqr.WithPaymentType(payqr.PaymentTypeIBAN), payqr.WithBankCode("COBADEFF")),
+			want: "BCD\n002\n1\nSCT\nCOBADEFF\nRed Cross\nDE89370400440532013000\nEUR1500000.00\n\n\n1001",
+		},
+		{
+			name: "Maximum amount",
+			have: payqr.New("DE89370400440532013000", "Red Cross", "", "1001", 999999999.99, due, payqr.WithPaymentType(payqr.PaymentTypeIBAN), payqr.WithBankCode("COBADEFF")),
+			want: "BCD\n002\n1\nSCT\nCOBADEFF\nRed Cross\nDE89370400440532013000\nEUR999999999.99\n\n\n1001",
+		},
+		{
+			name:    "Above maximum amount",
+			have:    payqr.New("DE89370400440532013000", "Red Cross", "", "1001", 1000000000, due, payqr.WithPaymentType(payqr.PaymentTypeIBAN), payqr.WithBankCode("COBADEFF")),
+			wantErr: true,
+		},
+		{
+			name:    "Not IBAN",
+			have:    payqr.New("5536-7742", "Test AB", "1234", "1001", 50, due),
+			wantErr: true,
+		},
+		{
+			name: "Lowercase currency",
+			have: payqr.New("DE89370400440532013000", "Red Cross", "", "Donation 2022", 12.3, due, payqr.WithPaymentType(payqr.PaymentTypeIBAN), payqr.WithCurrency("eur"), payqr.WithBankCode("COBADEFFXXX")),
+			want: "BCD\n002\n1\nSCT\nCOBADEFFXXX\nRed Cross\nDE89370400440532013000\nEUR12.30\n\n\nDonation 2022",
+		},
+		{
+			name:    "Not EUR",
+			have:    payqr.New("DE89370400440532013000", "Red Cross", "", "1001", 100, due, payqr.WithPaymentType(payqr.PaymentTypeIBAN), payqr.WithCurrency("SEK")),
+			wantErr: true,
+		},
+		{
+			name:    "Invalid IBAN",
+			have:    payqr.New("DE89370400440532013001", "Red Cross", "", "1001", 100, due, payqr.WithPaymentType(payqr.PaymentTypeIBAN)),
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Encode(test.have, test.haveOptions...)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestIsCreditorReference(t *testing.T) {
+	assert.True(t, IsCreditorReference("RF18 5390 0754 7034"))
+	assert.True(t, IsCreditorReference("RF18000000000539007547034"))
+	assert.False(t, IsCreditorReference("RF19 5390 0754 7034"))
+	assert.False(t, IsCreditorReference("1001"))
+}
@@ -0,0 +1,26 @@
+//go:build !payqr_payloadonly
+
+package epc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQR(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+	p := payqr.New("DE89370400440532013000", "Red Cross", "", "RF18 5390 0754 7034", 100, due, payqr.WithPaymentType(payqr.PaymentTypeIBAN))
+
+	q, err := QR(p, WithPurpose("CHAR"))
+	require.NoError(t, err)
+	want, err := Encode(p, WithPurpose("CHAR"))
+	require.NoError(t, err)
+	assert.Equal(t, want, q.Content)
+
+	_, err = QR(payqr.New("5536-7742", "Test AB", "1234", "1001", 50, due))
+	assert.Error(t, err)
+}
@@ -0,0 +1,41 @@
+package payqr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors of invalid payments, which the errors returned by Validate and the
+// other checks of payments wrap, so that callers can tell the causes apart
+// with errors.Is. The details are given by the ValidationError.
+var (
+	ErrMissingFields    = errors.New("payment is missing required fields")
+	ErrMissingAccount   = fmt.Errorf("missing account number: %w", ErrMissingFields)
+	ErrInvalidAccount   = errors.New("invalid account number")
+	ErrInvalidIBAN      = fmt.Errorf("invalid IBAN: %w", ErrInvalidAccount)
+	ErrInvalidReference = errors.New("invalid reference")
+	ErrInvalidAmount    = errors.New("invalid amount")
+	ErrInvalidDate      = errors.New("invalid date")
+	ErrInvalidField     = errors.New("invalid field")
+)
+
+// ValidationError is an invalid field of a payment.
+type ValidationError struct {
+	Field  string // Key of the field in the payload, e.g. "acc", or empty.
+	Reason string // What is invalid, the message of the error.
+	Err    error  // Cause, such as ErrInvalidIBAN.
+}
+
+func (e *ValidationError) Error() string {
+	return e.Reason
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// invalid returns a ValidationError of the field caused by err, with the
+// reason formatted by format.
+func invalid(field string, err error, format string, args ...interface{}) *ValidationError {
+	return &ValidationError{Field: field, Reason: fmt.Sprintf(format, args...), Err: err}
+}
@@ -0,0 +1,95 @@
+package payqr
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationError(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+	errCustom := errors.New("custom")
+	RegisterAccountValidator("US", PaymentTypeBBAN, func(string) error { return errCustom })
+	defer func() {
+		accountValidatorsMu.Lock()
+		delete(accountValidators, accountKey{"US", PaymentTypeBBAN})
+		accountValidatorsMu.Unlock()
+	}()
+
+	tests := []struct {
+		name      string
+		have      *Payment
+		wantField string
+		want      []error
+	}{
+		{
+			name:      "Missing account",
+			have:      New("", "Test AB", "5555555555", "52250047157", 50, due),
+			wantField: "acc",
+			want:      []error{ErrMissingAccount, ErrMissingFields},
+		},
+		{
+			name: "Missing fields",
+			have: New("5402-9681", "", "5555555555", "52250047157", 50, due),
+			want: []error{ErrMissingFields},
+		},
+		{
+			name:      "Invalid bankgiro",
+			have:      New("5402-9680", "Test AB", "5555555555", "52250047157", 50, due),
+			wantField: "acc",
+			want:      []error{ErrInvalidAccount},
+		},
+		{
+			name:      "Invalid IBAN",
+			have:      New("DE89370400440532013001", "Test GmbH", "1234", "1001", 50, due, WithPaymentType(PaymentTypeIBAN), WithBankCode("COBADEFF"), WithCountryCode("DE"), WithCurrency("EUR")),
+			wantField: "acc",
+			want:      []error{ErrInvalidIBAN, ErrInvalidAccount},
+		},
+		{
+			name:      "Custom account validator",
+			have:      New("123", "Test Inc", "1234", "1001", 50, due, WithPaymentType(PaymentTypeBBAN), WithCountryCode("US"), WithBankCode("026009593")),
+			wantField: "acc",
+			want:      []error{errCustom},
+		},
+		{
+			name:      "Invalid reference",
+			have:      New("5402-9681", "Test AB", "5555555555", "52250047158", 50, due, WithReferenceScheme(ReferenceOCR)),
+			wantField: "iref",
+			want:      []error{ErrInvalidReference},
+		},
+		{
+			name:      "Negative amount",
+			have:      New("5402-9681", "Test AB", "5555555555", "52250047157", -50, due),
+			wantField: "due",
+			want:      []error{ErrInvalidAmount},
+		},
+		{
+			name:      "Bank code of bankgiro",
+			have:      New("5402-9681", "Test AB", "5555555555", "52250047157", 50, due, WithBankCode("ESSESESS")),
+			wantField: "bc",
+			want:      []error{ErrInvalidField},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.have.Validate()
+			require.Error(t, err)
+
+			var v *ValidationError
+			require.True(t, errors.As(err, &v), "got %T", err)
+			assert.Equal(t, test.wantField, v.Field)
+			assert.Equal(t, err.Error(), v.Reason)
+			for _, want := range test.want {
+				assert.True(t, errors.Is(err, want), "%v is not %v", err, want)
+			}
+		})
+	}
+
+	err := New("5402-9681", "Test AB", "5555555555", "52250047157", 50, due).ValidateDueDate(WithDueDateNotPast(time.Now()))
+	assert.True(t, errors.Is(err, ErrInvalidDate))
+	assert.False(t, errors.Is(err, ErrInvalidField))
+}
@@ -0,0 +1,157 @@
+//go:build !payqr_payloadonly
+
+// Command webui is an example web application built on the public payqr API.
+// It shows a form for an invoice payment, validates the input and renders a
+// live preview of the QR code which can be downloaded as PNG or SVG.
+//
+//	go run ./examples/webui -addr localhost:8080
+package main
+
+import (
+	"flag"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+)
+
+var page = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>payqr</title>
+<style>
+body { font-family: sans-serif; max-width: 40em; margin: 2em auto; padding: 0 1em; }
+label { display: block; margin: .5em 0; }
+input, select { width: 100%; }
+.error { color: #c00; }
+img { width: 100%; max-width: 20em; }
+</style>
+</head>
+<body>
+<h1>Payment QR</h1>
+<form method="get" action="/">
+<label>Account <input name="account" value="{{.Form.account}}"></label>
+<label>Payment type <select name="type">
+{{range .Types}}<option{{if eq . $.Form.type}} selected{{end}}>{{.}}</option>{{end}}
+</select></label>
+<label>Name <input name="name" value="{{.Form.name}}"></label>
+<label>Company ID <input name="cid" value="{{.Form.cid}}"></label>
+<label>Reference <input name="reference" value="{{.Form.reference}}"></label>
+<label>Amount <input name="amount" value="{{.Form.amount}}"></label>
+<label>Due date <input name="due" type="date" value="{{.Form.due}}"></label>
+<button type="submit">Preview</button>
+</form>
+{{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+{{if .Query}}
+<p><img src="/qr?format=png&amp;{{.Query}}" alt="QR code"></p>
+<p><a href="/qr?format=png&amp;download=1&amp;{{.Query}}">Download PNG</a> | <a href="/qr?format=svg&amp;download=1&amp;{{.Query}}">Download SVG</a></p>
+{{end}}
+</body>
+</html>
+`))
+
+// paymentFromForm creates and validates a payment from the form values.
+func paymentFromForm(form url.Values) (*payqr.Payment, error) {
+	amount, err := strconv.ParseFloat(form.Get("amount"), 64)
+	if err != nil {
+		return nil, err
+	}
+
+	due, err := time.Parse("2006-01-02", form.Get("due"))
+	if err != nil {
+		return nil, err
+	}
+
+	p := payqr.New(form.Get("account"), form.Get("name"), form.Get("cid"), form.Get("reference"), amount, due, payqr.WithPaymentType(payqr.PaymentType(form.Get("type"))))
+
+	return p, p.Validate()
+}
+
+func index(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	form := map[string]string{"type": string(payqr.PaymentTypeBG)}
+	for k := range r.URL.Query() {
+		form[k] = r.URL.Query().Get(k)
+	}
+
+	data := struct {
+		Form  map[string]string
+		Types []payqr.PaymentType
+		Error string
+		Query template.URL
+	}{
+		Form:  form,
+		Types: []payqr.PaymentType{payqr.PaymentTypeBG, payqr.PaymentTypePG, payqr.PaymentTypeBBAN, payqr.PaymentTypeIBAN},
+	}
+
+	if len(r.URL.Query()) > 0 {
+		if _, err := paymentFromForm(r.URL.Query()); err != nil {
+			data.Error = err.Error()
+		} else {
+			data.Query = template.URL(r.URL.Query().Encode())
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := page.Execute(w, data); err != nil {
+		log.Print(err)
+	}
+}
+
+func qr(w http.ResponseWriter, r *http.Request) {
+	p, err := paymentFromForm(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	format, err := payqr.ParseFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	q, err := p.QR()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	b, err := payqr.Render(q, format, 512)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	if r.URL.Query().Get("download") != "" {
+		w.Header().Set("Content-Disposition", format.ContentDisposition("payment-"+p.Reference, false))
+	}
+	_, _ = w.Write(b)
+}
+
+func newMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", index)
+	mux.HandleFunc("/qr", qr)
+
+	return mux
+}
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "address to listen on")
+	flag.Parse()
+
+	log.Printf("listening on http://%s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, newMux()))
+}
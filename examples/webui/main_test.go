@@ -0,0 +1,56 @@
+//go:build !payqr_payloadonly
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebUI(t *testing.T) {
+	srv := httptest.NewServer(newMux())
+	defer srv.Close()
+
+	form := url.Values{
+		"account":   {"5536-7742"},
+		"type":      {"BG"},
+		"name":      {"Test AB"},
+		"cid":       {"1234"},
+		"reference": {"1001"},
+		"amount":    {"50"},
+		"due":       {"2022-08-06"},
+	}
+
+	tests := []struct {
+		name            string
+		path            string
+		wantStatus      int
+		wantContentType string
+	}{
+		{name: "Form", path: "/", wantStatus: http.StatusOK, wantContentType: "text/html; charset=utf-8"},
+		{name: "Preview", path: "/?" + form.Encode(), wantStatus: http.StatusOK, wantContentType: "text/html; charset=utf-8"},
+		{name: "PNG", path: "/qr?format=png&" + form.Encode(), wantStatus: http.StatusOK, wantContentType: "image/png"},
+		{name: "SVG", path: "/qr?format=svg&" + form.Encode(), wantStatus: http.StatusOK, wantContentType: "image/svg+xml"},
+		{name: "Unsupported format", path: "/qr?format=eps&" + form.Encode(), wantStatus: http.StatusBadRequest},
+		{name: "Invalid account", path: "/qr?format=png&account=1&" + form.Encode()[len("account=5536-7742&"):], wantStatus: http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp, err := http.Get(srv.URL + test.path)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer resp.Body.Close()
+
+			assert.Equal(t, test.wantStatus, resp.StatusCode)
+			if test.wantContentType != "" {
+				assert.Equal(t, test.wantContentType, resp.Header.Get("Content-Type"))
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package payqr
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// payloadKeys are the keys of the fields of the specification.
+var payloadKeys = map[string]bool{
+	"uqr": true, "tp": true, "nme": true, "cid": true, "iref": true, "cref": true,
+	"cur": true, "vat": true, "vh": true, "vm": true, "vl": true, "idt": true,
+	"ddt": true, "due": true, "pt": true, "acc": true, "bc": true, "cc": true,
+	"adr": true,
+}
+
+var (
+	extraFieldsMu sync.RWMutex
+	extraFields   = map[string]bool{}
+)
+
+// RegisterExtraField allows the key as an extra field of payloads, for bank
+// integrations that need keys beyond the specification. Payments with extra
+// fields that are not registered do not validate.
+func RegisterExtraField(key string) {
+	extraFieldsMu.Lock()
+	defer extraFieldsMu.Unlock()
+
+	extraFields[key] = true
+}
+
+func isExtraField(key string) bool {
+	extraFieldsMu.RLock()
+	defer extraFieldsMu.RUnlock()
+
+	return extraFields[key]
+}
+
+// WithExtraField adds a field with the key and string value to the payload,
+// after the fields of the specification and in the order of the keys. The
+// key must be registered with RegisterExtraField and not be a key of the
+// specification, or neither Validate nor AppendPayload accept the payment.
+func WithExtraField(key, value string) Option {
+	return func(p *Payment) {
+		// The fields are copied, as copies of payments share them.
+		extra := make(map[string]string, len(p.extra)+1)
+		for k, v := range p.extra {
+			extra[k] = v
+		}
+		extra[key] = value
+		p.extra = extra
+	}
+}
+
+// ExtraFields returns a copy of the extra fields of the payment, see
+// WithExtraField.
+func (d *Payment) ExtraFields() map[string]string {
+	extra := make(map[string]string, len(d.extra))
+	for k, v := range d.extra {
+		extra[k] = v
+	}
+
+	return extra
+}
+
+// extraKeys returns the keys of the extra fields, sorted.
+func (d *Payment) extraKeys() []string {
+	keys := make([]string, 0, len(d.extra))
+	for k := range d.extra {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// validateExtraFields checks that the extra fields are registered and not
+// fields of the specification.
+func (d *Payment) validateExtraFields() error {
+	for _, key := range d.extraKeys() {
+		if payloadKeys[strings.ToLower(key)] {
+			return invalid(key, ErrInvalidField, "extra field %q is a field of the specification", key)
+		}
+		if !isExtraField(key) {
+			return invalid(key, ErrInvalidField, "extra field %q is not registered", key)
+		}
+	}
+
+	return nil
+}
+
+// appendExtraFields appends the extra fields of the payment to dst, in the
+// order of the keys, or returns an error if they do not validate, see
+// validateExtraFields.
+func (d *Payment) appendExtraFields(dst []byte) ([]byte, error) {
+	if len(d.extra) == 0 {
+		return dst, nil
+	}
+	if err := d.validateExtraFields(); err != nil {
+		return dst, err
+	}
+	for _, key := range d.extraKeys() {
+		dst = append(appendJSONString(append(dst, ','), key), ':')
+		dst = appendJSONString(dst, d.extra[key])
+	}
+
+	return dst, nil
+}
+
+// decodeExtraFields returns the registered extra fields of the payload. If
+// strict, other keys than those of the specification are an error.
+func decodeExtraFields(b []byte, strict bool) (map[string]string, error) {
+	extraFieldsMu.RLock()
+	registered := len(extraFields) > 0
+	extraFieldsMu.RUnlock()
+	if !strict && !registered {
+		return nil, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var extra map[string]string
+	for _, key := range keys {
+		// encoding/json matches the keys of the fields case-insensitively.
+		if payloadKeys[strings.ToLower(key)] {
+			continue
+		}
+		if !isExtraField(key) {
+			if strict {
+				return nil, fmt.Errorf("json: unknown field %q", key)
+			}
+			continue
+		}
+
+		var value string
+		if err := json.Unmarshal(fields[key], &value); err != nil {
+			return nil, invalid(key, ErrInvalidField, "extra field %q must be a string", key)
+		}
+		if extra == nil {
+			extra = map[string]string{}
+		}
+		extra[key] = value
+	}
+
+	return extra, nil
+}
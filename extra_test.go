@@ -0,0 +1,85 @@
+package payqr
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func registerExtraFields(t *testing.T, keys ...string) {
+	for _, key := range keys {
+		RegisterExtraField(key)
+	}
+	t.Cleanup(func() {
+		extraFieldsMu.Lock()
+		for _, key := range keys {
+			delete(extraFields, key)
+		}
+		extraFieldsMu.Unlock()
+	})
+}
+
+func TestExtraFields(t *testing.T) {
+	registerExtraFields(t, "xbank", "xref")
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+
+	d := New("5402-9681", "Test AB", "5555555555", "52250047157", 50, due, WithCreationDate(due), WithExtraField("xref", "A-1"), WithExtraField("xbank", `"1"`))
+	require.NoError(t, d.Validate())
+
+	payload, err := d.Payload()
+	require.NoError(t, err)
+	assert.Equal(t, `{"uqr":1,"tp":1,"nme":"Test AB","cid":"5555555555","iref":"52250047157","idt":"20220806","ddt":"20220806","due":50,"pt":"BG","acc":"5402-9681","xbank":"\"1\"","xref":"A-1"}`, payload)
+	assert.Equal(t, map[string]string{"xbank": `"1"`, "xref": "A-1"}, d.ExtraFields())
+
+	parsed, err := ParsePayload(payload)
+	require.NoError(t, err)
+	assert.Equal(t, d, parsed)
+
+	var decoded Payment
+	require.NoError(t, json.Unmarshal([]byte(payload), &decoded))
+	assert.True(t, d.Equal(&decoded))
+
+	c := d.Clone()
+	WithExtraField("xref", "A-2")(c)
+	assert.Equal(t, "A-1", d.ExtraFields()["xref"])
+	assert.False(t, d.Equal(c))
+
+	tmpl, err := NewTemplate(d)
+	require.NoError(t, err)
+	p, err := tmpl.Payload("52250047157", 50)
+	require.NoError(t, err)
+	assert.Equal(t, payload, p)
+}
+
+func TestExtraFieldsInvalid(t *testing.T) {
+	registerExtraFields(t, "xref")
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+
+	err := New("5402-9681", "Test AB", "5555555555", "52250047157", 50, due, WithExtraField("xother", "1")).Validate()
+	assert.EqualError(t, err, `extra field "xother" is not registered`)
+	assert.True(t, errors.Is(err, ErrInvalidField))
+
+	err = New("5402-9681", "Test AB", "5555555555", "52250047157", 50, due, WithExtraField("Due", "1")).Validate()
+	assert.EqualError(t, err, `extra field "Due" is a field of the specification`)
+
+	_, err = New("5402-9681", "Test AB", "5555555555", "52250047157", 50, due, WithExtraField("xother", "1")).Payload()
+	assert.EqualError(t, err, `extra field "xother" is not registered`)
+
+	_, err = json.Marshal(New("5402-9681", "Test AB", "5555555555", "52250047157", 50, due, WithExtraField("due", "1")))
+	assert.Error(t, err)
+
+	_, err = ParsePayload(`{"uqr":1,"tp":1,"nme":"Test AB","xref":"A-1","xother":"1"}`)
+	assert.EqualError(t, err, `json: unknown field "xother"`)
+
+	_, err = ParsePayload(`{"uqr":1,"tp":1,"nme":"Test AB","xref":1}`)
+	assert.EqualError(t, err, `extra field "xref" must be a string`)
+
+	var d Payment
+	require.NoError(t, json.Unmarshal([]byte(`{"uqr":1,"NME":"Test AB","xother":"1"}`), &d))
+	assert.Equal(t, "Test AB", d.AccountName)
+	assert.Empty(t, d.ExtraFields())
+}
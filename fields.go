@@ -0,0 +1,121 @@
+package payqr
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// fieldLimits are the maximum lengths in characters of the text fields by
+// the specification. Only the free text fields are truncated by
+// WithFieldTruncation, as a truncated reference or account would pay the
+// wrong invoice.
+var fieldLimits = []struct {
+	key      string
+	max      int
+	freeText bool
+	field    func(d *Payment) *string
+}{
+	{"nme", 70, true, func(d *Payment) *string { return &d.AccountName }},
+	{"cid", 20, false, func(d *Payment) *string { return &d.CompanyID }},
+	{"iref", 25, false, func(d *Payment) *string { return &d.Reference }},
+	{"cref", 25, false, func(d *Payment) *string { return &d.CreditInvoiceReference }},
+	{"acc", 35, false, func(d *Payment) *string { return &d.AccountNumber }},
+	{"bc", 11, false, func(d *Payment) *string { return &d.BankCode }},
+	{"adr", 70, true, func(d *Payment) *string { return &d.Address }},
+}
+
+// FieldOption configures Payment.ValidateFields.
+type FieldOption func(*fieldConfig)
+
+type fieldConfig struct {
+	warn        func(err error)
+	displayable bool
+}
+
+// WithFieldTruncation truncates free text fields longer than allowed, the
+// name and the address, and reports them, and with WithDisplayableCharacters
+// the characters bank apps may not display, to warn instead of returning
+// them as errors.
+func WithFieldTruncation(warn func(err error)) FieldOption {
+	return func(c *fieldConfig) {
+		c.warn = warn
+	}
+}
+
+// WithDisplayableCharacters also checks that the text fields only have
+// characters that common bank apps can display: printable Latin-1
+// characters and line breaks in the address. Payloads are UTF-8, so other
+// characters are valid, but some apps mangle them, see Lint.
+func WithDisplayableCharacters() FieldOption {
+	return func(c *fieldConfig) {
+		c.displayable = true
+	}
+}
+
+// ValidateFields checks the text fields against the maximum lengths of the
+// specification. With WithFieldTruncation the payment is modified to fit.
+func (d *Payment) ValidateFields(options ...FieldOption) error {
+	c := &fieldConfig{}
+	for _, option := range options {
+		option(c)
+	}
+
+	for _, l := range fieldLimits {
+		s := l.field(d)
+		if !utf8.ValidString(*s) {
+			return invalid(l.key, ErrInvalidField, "field %s is not valid UTF-8", l.key)
+		}
+
+		if n := utf8.RuneCountInString(*s); n > l.max {
+			err := invalid(l.key, ErrInvalidField, "field %s is %d characters, the maximum is %d", l.key, n, l.max)
+			if c.warn == nil || !l.freeText {
+				return err
+			}
+			*s = truncateRunes(*s, l.max)
+			c.warn(fmt.Errorf("%w, truncated to %q", err, *s))
+		}
+
+		if !c.displayable {
+			continue
+		}
+		for _, err := range undisplayable(l.key, *s) {
+			if c.warn == nil {
+				return err
+			}
+			c.warn(err)
+		}
+	}
+
+	return nil
+}
+
+// undisplayable returns errors for the characters of the field that bank
+// apps may not display.
+func undisplayable(key, s string) []error {
+	var errs []error
+	for _, r := range s {
+		if isDisplayable(r) || r == '\n' && key == "adr" {
+			continue
+		}
+		errs = append(errs, invalid(key, ErrInvalidField, "field %s has the character %q which bank apps may not display", key, r))
+	}
+
+	return errs
+}
+
+// isDisplayable reports whether r is a printable Latin-1 character.
+func isDisplayable(r rune) bool {
+	return r >= 0x20 && r <= 0x7e || r >= 0xa0 && r <= 0xff
+}
+
+// truncateRunes returns the first n characters of s.
+func truncateRunes(s string, n int) string {
+	for i := range s {
+		if n == 0 {
+			return s[:i]
+		}
+		n--
+	}
+
+	return s
+}
@@ -0,0 +1,91 @@
+package payqr
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateFields(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name    string
+		have    *Payment
+		options []FieldOption
+		wantErr string
+	}{
+		{
+			name:    "Swedish characters",
+			have:    New("5402-9681", "Smörgåsbordet AB", "5555555555", "52250047157", 50, due, WithAddress("Storgatan 1\n111 22 Stockholm")),
+			options: []FieldOption{WithDisplayableCharacters()},
+		},
+		{
+			name: "Characters outside Latin-1",
+			have: New("5402-9681", "Łódź Sp. z o.o. 🐟", "5555555555", "52250047157", 50, due),
+		},
+		{
+			name: "Name at maximum length",
+			have: New("5402-9681", strings.Repeat("å", 70), "5555555555", "52250047157", 50, due),
+		},
+		{
+			name:    "Name too long",
+			have:    New("5402-9681", strings.Repeat("å", 71), "5555555555", "52250047157", 50, due),
+			wantErr: "field nme is 71 characters, the maximum is 70",
+		},
+		{
+			name:    "Reference too long",
+			have:    New("5402-9681", "Test AB", "5555555555", strings.Repeat("1", 26), 50, due),
+			wantErr: "field iref is 26 characters, the maximum is 25",
+		},
+		{
+			name:    "Emoji in name",
+			have:    New("5402-9681", "Test AB 🐟", "5555555555", "52250047157", 50, due),
+			options: []FieldOption{WithDisplayableCharacters()},
+			wantErr: `field nme has the character '🐟' which bank apps may not display`,
+		},
+		{
+			name:    "Line break in name",
+			have:    New("5402-9681", "Test\nAB", "5555555555", "52250047157", 50, due),
+			options: []FieldOption{WithDisplayableCharacters()},
+			wantErr: `field nme has the character '\n' which bank apps may not display`,
+		},
+		{
+			name:    "Invalid UTF-8",
+			have:    New("5402-9681", "Test AB", "5555555555", "52250047157", 50, due, WithAddress("\xff")),
+			wantErr: "field adr is not valid UTF-8",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.have.ValidateFields(test.options...)
+			if test.wantErr != "" {
+				assert.EqualError(t, err, test.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestValidateFieldsTruncation(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+	d := New("5402-9681", strings.Repeat("å", 75), "5555555555", "52250047157", 50, due, WithAddress("Storgatan 1 ✉"))
+
+	var warnings []string
+	warn := WithFieldTruncation(func(err error) {
+		warnings = append(warnings, err.Error())
+	})
+	assert.NoError(t, d.ValidateFields(warn, WithDisplayableCharacters()))
+	assert.Equal(t, strings.Repeat("å", 70), d.AccountName)
+	assert.Equal(t, []string{
+		`field nme is 75 characters, the maximum is 70, truncated to "` + strings.Repeat("å", 70) + `"`,
+		`field adr has the character '✉' which bank apps may not display`,
+	}, warnings)
+
+	d.Reference = strings.Repeat("1", 26)
+	assert.EqualError(t, d.ValidateFields(warn), "field iref is 26 characters, the maximum is 25")
+}
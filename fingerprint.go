@@ -0,0 +1,51 @@
+package payqr
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+// Clone returns a copy of the payment, which can be modified without
+// modifying the payment, e.g. to derive variants of a shared payment.
+func (d *Payment) Clone() *Payment {
+	p := *d
+	return &p
+}
+
+// Equal reports whether the payments have the same content, that is the same
+// payload, so that e.g. amounts are equal when rounded to the minor units of
+// the currency. The reference scheme, which is not encoded, is not compared.
+func (d *Payment) Equal(other *Payment) bool {
+	if d == nil || other == nil {
+		return d == other
+	}
+
+	return bytes.Equal(d.appendContent(nil), other.appendContent(nil))
+}
+
+// Fingerprint returns a hash of the content of the payment, which is the
+// same for payments that are Equal and stable across versions of this
+// package as long as the payload is, e.g. to dedupe payments or as a cache
+// key.
+func (d *Payment) Fingerprint() string {
+	sum := sha256.Sum256(d.appendContent(nil))
+	return hex.EncodeToString(sum[:])
+}
+
+// appendContent appends the payload of the payment to dst, or for amounts
+// that cannot be encoded the payload without the amount and the amount as
+// text.
+func (d *Payment) appendContent(dst []byte) []byte {
+	b, err := d.AppendPayload(dst)
+	if err == nil {
+		return b
+	}
+
+	p := *d
+	p.DueAmount = 0
+	b, _ = p.AppendPayload(dst)
+
+	return strconv.AppendFloat(append(b, 0), d.DueAmount, 'g', -1, 64)
+}
@@ -0,0 +1,57 @@
+package payqr
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClone(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+	d := New("5402-9681", "Test AB", "5555555555", "52250047157", 50, due, WithReferenceScheme(ReferenceOCR))
+
+	c := d.Clone()
+	assert.Equal(t, d, c)
+
+	c.Reference = "1001"
+	assert.Equal(t, "52250047157", d.Reference)
+	assert.Equal(t, ReferenceOCR, c.ReferenceScheme())
+}
+
+func TestEqualAndFingerprint(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+	d := New("5402-9681", "Test AB", "5555555555", "52250047157", 50, due, WithCreationDate(due))
+
+	tests := []struct {
+		name string
+		have func(p *Payment)
+		want bool
+	}{
+		{name: "Same", have: func(p *Payment) {}, want: true},
+		{name: "Same rounded amount", have: func(p *Payment) { p.DueAmount = 50.001 }, want: true},
+		{name: "Other reference scheme", have: func(p *Payment) { p.referenceScheme = ReferenceOCR }, want: true},
+		{name: "Other amount", have: func(p *Payment) { p.DueAmount = 50.01 }},
+		{name: "Other reference", have: func(p *Payment) { p.Reference = "1001" }},
+		{name: "Other address", have: func(p *Payment) { p.Address = "Storgatan 1" }},
+		{name: "Not a number", have: func(p *Payment) { p.DueAmount = math.NaN() }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			other := d.Clone()
+			test.have(other)
+
+			assert.Equal(t, test.want, d.Equal(other))
+			assert.Equal(t, test.want, d.Fingerprint() == other.Fingerprint())
+		})
+	}
+
+	assert.Equal(t, "cb8e7c4ba8ccb85751c8c9a35cf63b8620106ba8e8d00dc7544e4f9e36ea5166", d.Fingerprint())
+	assert.False(t, d.Equal(nil))
+
+	nan, inf := d.Clone(), d.Clone()
+	nan.DueAmount, inf.DueAmount = math.NaN(), math.Inf(1)
+	assert.NotEqual(t, nan.Fingerprint(), inf.Fingerprint())
+}
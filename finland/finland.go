@@ -0,0 +1,112 @@
+// Package finland encodes payments as Finnish bank barcodes (virtual barcode,
+// "virtuaaliviivakoodi") and as the Finnish bank QR code.
+package finland
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"strings"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/antonlindstrom/payqr/epc"
+	"github.com/antonlindstrom/payqr/internal/code128"
+)
+
+// NationalReference returns the base with the check digit of the Finnish
+// national reference number (viitenumero) appended.
+func NationalReference(base string) (string, error) {
+	if len(base) < 3 || len(base) > 19 || !isDigits(base) {
+		return "", fmt.Errorf("finland: reference base %q must be 3 to 19 digits", base)
+	}
+
+	return base + nationalCheckDigit(base), nil
+}
+
+// ValidateNationalReference validates a Finnish national reference number.
+func ValidateNationalReference(ref string) error {
+	ref = strings.ReplaceAll(ref, " ", "")
+	if len(ref) < 4 || len(ref) > 20 || !isDigits(ref) {
+		return fmt.Errorf("finland: reference %q must be 4 to 20 digits", ref)
+	}
+	if nationalCheckDigit(ref[:len(ref)-1]) != ref[len(ref)-1:] {
+		return fmt.Errorf("finland: reference %q has an invalid check digit", ref)
+	}
+
+	return nil
+}
+
+// nationalCheckDigit uses the weights 7, 3, 1 from the right.
+func nationalCheckDigit(base string) string {
+	weights := []int{7, 3, 1}
+	sum := 0
+	for i := 0; i < len(base); i++ {
+		sum += int(base[len(base)-1-i]-'0') * weights[i%3]
+	}
+
+	return string(rune('0' + (10-sum%10)%10))
+}
+
+// VirtualBarcode returns the 54 digit virtual barcode of the payment. Version
+// 5 is used for RF creditor references, version 4 for national references.
+// The payment must be to a Finnish IBAN, in EUR.
+func VirtualBarcode(p *payqr.Payment) (string, error) {
+	iban := strings.ToUpper(strings.ReplaceAll(p.AccountNumber, " ", ""))
+	if !strings.HasPrefix(iban, "FI") {
+		return "", errors.New("finland: account must be a Finnish IBAN")
+	}
+	if err := payqr.ValidateAccount("FI", payqr.PaymentTypeIBAN, iban); err != nil {
+		return "", fmt.Errorf("finland: %w", err)
+	}
+	if p.Currency != "" && !strings.EqualFold(p.Currency, epc.Currency) {
+		return "", fmt.Errorf("finland: currency must be %s, got %s", epc.Currency, p.Currency)
+	}
+
+	// Amounts that do not fit are left for the payer to fill in.
+	cents := int64(p.DueAmount*100 + 0.5)
+	if cents < 0 || cents > 99999999 {
+		cents = 0
+	}
+
+	due := "000000"
+	if len(p.DueDate) == 8 {
+		due = p.DueDate[2:]
+	}
+
+	ref := strings.ToUpper(strings.ReplaceAll(p.Reference, " ", ""))
+	if epc.IsCreditorReference(ref) {
+		rf := ref[4:]
+		if len(rf) > 21 || !isDigits(rf) {
+			return "", fmt.Errorf("finland: creditor reference %q must be numeric", p.Reference)
+		}
+
+		return fmt.Sprintf("5%s%08d%s%021s%s", iban[2:], cents, ref[2:4], rf, due), nil
+	}
+
+	if err := ValidateNationalReference(ref); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("4%s%08d000%020s%s", iban[2:], cents, ref, due), nil
+}
+
+// Barcode renders the virtual barcode as a Code 128 barcode, with each module
+// moduleWidth pixels wide and height pixels high.
+func Barcode(p *payqr.Payment, moduleWidth, height int) (image.Image, error) {
+	code, err := VirtualBarcode(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return code128.Image(code, moduleWidth, height)
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return s != ""
+}
@@ -0,0 +1,65 @@
+package finland
+
+import (
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVirtualBarcode(t *testing.T) {
+	due := time.Date(2010, time.June, 12, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		have    *payqr.Payment
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Version 4",
+			have: payqr.New("FI79 4405 2020 0360 82", "Test Oy", "", "86851 62596 19897", 4883.15, due, payqr.WithPaymentType(payqr.PaymentTypeIBAN)),
+			want: "479440520200360820048831500000000868516259619897100612",
+		},
+		{
+			name: "Version 5",
+			have: payqr.New("FI58 1017 1000 0001 22", "Test Oy", "", "RF05 18412", 482.99, due, payqr.WithPaymentType(payqr.PaymentTypeIBAN)),
+			want: "5" + "5810171000000122" + "00048299" + "05" + "000000000000000018412" + "100612",
+		},
+		{
+			name:    "Not Finnish",
+			have:    payqr.New("DE89370400440532013000", "Test GmbH", "", "1232", 10, due, payqr.WithPaymentType(payqr.PaymentTypeIBAN)),
+			wantErr: true,
+		},
+		{
+			name:    "Invalid reference",
+			have:    payqr.New("FI79 4405 2020 0360 82", "Test Oy", "", "1231", 10, due, payqr.WithPaymentType(payqr.PaymentTypeIBAN)),
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := VirtualBarcode(test.have)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+
+			_, err = Barcode(test.have, 2, 50)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestNationalReference(t *testing.T) {
+	ref, err := NationalReference("123")
+	require.NoError(t, err)
+	assert.Equal(t, "1232", ref)
+	assert.NoError(t, ValidateNationalReference("1232"))
+	assert.Error(t, ValidateNationalReference("1231"))
+}
@@ -0,0 +1,19 @@
+//go:build !payqr_payloadonly
+
+package finland
+
+import (
+	"github.com/antonlindstrom/payqr"
+	"github.com/antonlindstrom/payqr/epc"
+	"github.com/skip2/go-qrcode"
+)
+
+// QR returns the Finnish bank QR code for the payment, which is the EPC QR
+// code. National references are carried as remittance information.
+func QR(p *payqr.Payment) (*qrcode.QRCode, error) {
+	if _, err := VirtualBarcode(p); err != nil {
+		return nil, err
+	}
+
+	return epc.QR(p)
+}
@@ -0,0 +1,27 @@
+//go:build !payqr_payloadonly
+
+package finland
+
+import (
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/antonlindstrom/payqr/epc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQR(t *testing.T) {
+	due := time.Date(2010, time.June, 12, 0, 0, 0, 0, time.UTC)
+	p := payqr.New("FI79 4405 2020 0360 82", "Test Oy", "", "86851 62596 19897", 4883.15, due, payqr.WithPaymentType(payqr.PaymentTypeIBAN))
+
+	q, err := QR(p)
+	require.NoError(t, err)
+	want, err := epc.Encode(p)
+	require.NoError(t, err)
+	assert.Equal(t, want, q.Content)
+
+	_, err = QR(payqr.New("5536-7742", "Test AB", "1234", "1001", 50, due))
+	assert.Error(t, err)
+}
@@ -0,0 +1,70 @@
+package payqr
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// Format is an output format for rendered QR codes.
+type Format string
+
+const (
+	FormatPNG  Format = "png"
+	FormatSVG  Format = "svg"
+	FormatPDF  Format = "pdf"
+	FormatEPS  Format = "eps"
+	FormatZPL  Format = "zpl"
+	FormatTIFF Format = "tiff"
+)
+
+var formatContentTypes = map[Format]string{
+	FormatPNG:  "image/png",
+	FormatSVG:  "image/svg+xml",
+	FormatPDF:  "application/pdf",
+	FormatEPS:  "application/postscript",
+	FormatZPL:  "application/vnd.zebra-zpl",
+	FormatTIFF: "image/tiff",
+}
+
+// ParseFormat returns the format from a name or file extension, e.g. "PNG" or
+// ".png".
+func ParseFormat(name string) (Format, error) {
+	f := Format(strings.ToLower(strings.TrimPrefix(name, ".")))
+	if _, ok := formatContentTypes[f]; !ok {
+		return "", fmt.Errorf("unknown format %q", name)
+	}
+
+	return f, nil
+}
+
+// ContentType returns the MIME type of the format.
+func (f Format) ContentType() string {
+	if ct, ok := formatContentTypes[f]; ok {
+		return ct
+	}
+
+	return "application/octet-stream"
+}
+
+// Extension returns the file extension of the format, including the dot.
+func (f Format) Extension() string {
+	return "." + string(f)
+}
+
+// Filename returns the name with the extension of the format.
+func (f Format) Filename(name string) string {
+	return name + f.Extension()
+}
+
+// ContentDisposition returns a Content-Disposition header value suggesting
+// the name with the extension of the format as filename. If inline is false
+// the file is offered as a download.
+func (f Format) ContentDisposition(name string, inline bool) string {
+	disposition := "attachment"
+	if inline {
+		disposition = "inline"
+	}
+
+	return mime.FormatMediaType(disposition, map[string]string{"filename": f.Filename(name)})
+}
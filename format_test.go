@@ -0,0 +1,36 @@
+package payqr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		have            string
+		wantContentType string
+		wantDisposition string
+	}{
+		{have: "png", wantContentType: "image/png", wantDisposition: `attachment; filename=invoice-1001.png`},
+		{have: ".SVG", wantContentType: "image/svg+xml", wantDisposition: `attachment; filename=invoice-1001.svg`},
+		{have: "pdf", wantContentType: "application/pdf", wantDisposition: `attachment; filename=invoice-1001.pdf`},
+		{have: "eps", wantContentType: "application/postscript", wantDisposition: `attachment; filename=invoice-1001.eps`},
+		{have: "zpl", wantContentType: "application/vnd.zebra-zpl", wantDisposition: `attachment; filename=invoice-1001.zpl`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.have, func(t *testing.T) {
+			f, err := ParseFormat(test.have)
+			require.NoError(t, err)
+			assert.Equal(t, test.wantContentType, f.ContentType())
+			assert.Equal(t, test.wantDisposition, f.ContentDisposition("invoice-1001", false))
+		})
+	}
+
+	_, err := ParseFormat("bmp")
+	assert.Error(t, err)
+
+	assert.Equal(t, `inline; filename="faktura 1.png"`, FormatPNG.ContentDisposition("faktura 1", true))
+}
@@ -0,0 +1,261 @@
+// Package fortnox is a client for the Fortnox API, used to fetch the open
+// invoices of a company and convert them into payments for payment QR codes.
+//
+// Requests are authenticated with an OAuth 2 access token for the company,
+// with at least the invoice and companyinformation scopes.
+package fortnox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+)
+
+// BaseURL is the base URL of version 3 of the Fortnox API.
+const BaseURL = "https://api.fortnox.se/3"
+
+// pageSize is the number of invoices fetched per request, the maximum
+// allowed by the API.
+const pageSize = 500
+
+// Client is a client for the Fortnox API.
+type Client struct {
+	httpClient  *http.Client
+	baseURL     string
+	accessToken string
+}
+
+// Option is a modifier for the Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithBaseURL sets the base URL of the API. Default is BaseURL.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// New creates a client authenticating with the access token.
+func New(accessToken string, options ...Option) *Client {
+	c := &Client{
+		httpClient:  http.DefaultClient,
+		baseURL:     BaseURL,
+		accessToken: accessToken,
+	}
+
+	for _, opt := range options {
+		opt(c)
+	}
+
+	return c
+}
+
+// Error is returned when the Fortnox API responds with an unexpected status.
+type Error struct {
+	StatusCode int
+	Code       int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("fortnox: unexpected status %d", e.StatusCode)
+	}
+
+	return fmt.Sprintf("fortnox: status %d: %s (%d)", e.StatusCode, e.Message, e.Code)
+}
+
+// get fetches the path and decodes the JSON response into v.
+func (c *Client) get(ctx context.Context, path string, query url.Values, v interface{}) error {
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var body struct {
+			ErrorInformation struct {
+				Message string `json:"message"`
+				Code    int    `json:"code"`
+			} `json:"ErrorInformation"`
+		}
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+		_ = json.Unmarshal(b, &body)
+
+		return &Error{StatusCode: resp.StatusCode, Code: body.ErrorInformation.Code, Message: body.ErrorInformation.Message}
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// Company is the company settings of the account, with the accounts invoices
+// are paid to.
+type Company struct {
+	Name               string `json:"Name"`
+	OrganizationNumber string `json:"OrganizationNumber"`
+	Address            string `json:"Address"`
+	ZipCode            string `json:"ZipCode"`
+	City               string `json:"City"`
+	CountryCode        string `json:"CountryCode"`
+	BG                 string `json:"BG"`
+	PG                 string `json:"PG"`
+	IBAN               string `json:"IBAN"`
+	BIC                string `json:"BIC"`
+}
+
+// Company fetches the company settings.
+func (c *Client) Company(ctx context.Context) (*Company, error) {
+	var body struct {
+		CompanySettings Company `json:"CompanySettings"`
+	}
+	if err := c.get(ctx, "/settings/company", nil, &body); err != nil {
+		return nil, err
+	}
+
+	return &body.CompanySettings, nil
+}
+
+// Invoice is a customer invoice, as listed by the API.
+type Invoice struct {
+	DocumentNumber string  `json:"DocumentNumber"`
+	CustomerNumber string  `json:"CustomerNumber"`
+	CustomerName   string  `json:"CustomerName"`
+	InvoiceDate    string  `json:"InvoiceDate"`
+	DueDate        string  `json:"DueDate"`
+	Currency       string  `json:"Currency"`
+	Total          float64 `json:"Total"`
+	Balance        float64 `json:"Balance"`
+	OCR            string  `json:"OCR"`
+	Cancelled      bool    `json:"Cancelled"`
+	Booked         bool    `json:"Booked"`
+}
+
+// OpenInvoices fetches the unpaid invoices, fetching all pages.
+func (c *Client) OpenInvoices(ctx context.Context) ([]Invoice, error) {
+	var invoices []Invoice
+	for page := 1; ; page++ {
+		var body struct {
+			MetaInformation struct {
+				TotalPages int `json:"@TotalPages"`
+			} `json:"MetaInformation"`
+			Invoices []Invoice `json:"Invoices"`
+		}
+		query := url.Values{
+			"filter": {"unpaid"},
+			"limit":  {strconv.Itoa(pageSize)},
+			"page":   {strconv.Itoa(page)},
+		}
+		if err := c.get(ctx, "/invoices", query, &body); err != nil {
+			return nil, err
+		}
+
+		invoices = append(invoices, body.Invoices...)
+		if page >= body.MetaInformation.TotalPages {
+			return invoices, nil
+		}
+	}
+}
+
+// Payment returns the payment of the remaining balance of the invoice to the
+// Bankgiro number of the company, or else its Plusgiro number or IBAN. The
+// OCR number of the invoice is used as reference, or else the invoice number.
+func (inv Invoice) Payment(company *Company) (*payqr.Payment, error) {
+	if inv.Cancelled {
+		return nil, fmt.Errorf("fortnox: invoice %s is cancelled", inv.DocumentNumber)
+	}
+
+	due, err := time.Parse("2006-01-02", inv.DueDate)
+	if err != nil {
+		return nil, fmt.Errorf("fortnox: invoice %s: invalid due date %q", inv.DocumentNumber, inv.DueDate)
+	}
+
+	var options []payqr.Option
+	account := company.BG
+	switch {
+	case company.BG != "":
+		options = append(options, payqr.WithPaymentType(payqr.PaymentTypeBG))
+	case company.PG != "":
+		account = company.PG
+		options = append(options, payqr.WithPaymentType(payqr.PaymentTypePG))
+	case company.IBAN != "":
+		account = strings.ReplaceAll(company.IBAN, " ", "")
+		options = append(options, payqr.WithPaymentType(payqr.PaymentTypeIBAN))
+		if company.BIC != "" {
+			options = append(options, payqr.WithBankCode(company.BIC))
+		}
+	default:
+		return nil, errors.New("fortnox: company has no Bankgiro, Plusgiro or IBAN account")
+	}
+
+	if inv.Currency != "" {
+		options = append(options, payqr.WithCurrency(inv.Currency))
+	}
+	if date, err := time.Parse("2006-01-02", inv.InvoiceDate); err == nil {
+		options = append(options, payqr.WithCreationDate(date))
+	}
+
+	reference := inv.OCR
+	if reference == "" {
+		reference = inv.DocumentNumber
+	}
+
+	return payqr.New(account, company.Name, company.OrganizationNumber, reference, inv.Balance, due, options...), nil
+}
+
+// Payments fetches the company settings and the open invoices and returns a
+// payment for each invoice with a balance left to pay.
+func (c *Client) Payments(ctx context.Context) ([]*payqr.Payment, error) {
+	company, err := c.Company(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	invoices, err := c.OpenInvoices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	payments := make([]*payqr.Payment, 0, len(invoices))
+	for _, inv := range invoices {
+		if inv.Cancelled || inv.Balance <= 0 {
+			continue
+		}
+
+		p, err := inv.Payment(company)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, p)
+	}
+
+	return payments, nil
+}
@@ -0,0 +1,99 @@
+package fortnox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayments(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+		switch r.URL.Path {
+		case "/settings/company":
+			fmt.Fprint(w, `{"CompanySettings":{"Name":"Test AB","OrganizationNumber":"555555-5555","BG":"5402-9681","PG":"90 01 22-3"}}`)
+		case "/invoices":
+			assert.Equal(t, "unpaid", r.URL.Query().Get("filter"))
+			if r.URL.Query().Get("page") == "1" {
+				fmt.Fprint(w, `{"MetaInformation":{"@TotalPages":2,"@CurrentPage":1},"Invoices":[`+
+					`{"DocumentNumber":"1001","CustomerName":"Kund AB","InvoiceDate":"2022-07-07","DueDate":"2022-08-06","Currency":"SEK","Total":1250,"Balance":1250,"OCR":"52250047157"},`+
+					`{"DocumentNumber":"1002","InvoiceDate":"2022-07-07","DueDate":"2022-08-06","Currency":"SEK","Total":100,"Balance":100,"Cancelled":true}]}`)
+				return
+			}
+			fmt.Fprint(w, `{"MetaInformation":{"@TotalPages":2,"@CurrentPage":2},"Invoices":[`+
+				`{"DocumentNumber":"1003","InvoiceDate":"2022-07-08","DueDate":"2022-08-07","Currency":"SEK","Total":500,"Balance":250}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := New("token", WithBaseURL(srv.URL), WithHTTPClient(srv.Client()))
+
+	payments, err := c.Payments(context.Background())
+	require.NoError(t, err)
+
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, []*payqr.Payment{
+		payqr.New("5402-9681", "Test AB", "555555-5555", "52250047157", 1250, due,
+			payqr.WithPaymentType(payqr.PaymentTypeBG), payqr.WithCurrency("SEK"), payqr.WithCreationDate(due.AddDate(0, 0, -30))),
+		payqr.New("5402-9681", "Test AB", "555555-5555", "1003", 250, due.AddDate(0, 0, 1),
+			payqr.WithPaymentType(payqr.PaymentTypeBG), payqr.WithCurrency("SEK"), payqr.WithCreationDate(due.AddDate(0, 0, -29))),
+	}, payments)
+}
+
+func TestInvoicePayment(t *testing.T) {
+	inv := Invoice{DocumentNumber: "1001", DueDate: "2022-08-06", Balance: 200.5, Currency: "EUR", OCR: "1001"}
+
+	tests := []struct {
+		name    string
+		company Company
+		want    payqr.PaymentType
+		account string
+		wantErr bool
+	}{
+		{name: "Plusgiro", company: Company{Name: "Test AB", PG: "90 01 22-3"}, want: payqr.PaymentTypePG, account: "90 01 22-3"},
+		{name: "IBAN", company: Company{Name: "Test AB", IBAN: "SE45 5000 0000 0583 9825 7466", BIC: "ESSESESS"}, want: payqr.PaymentTypeIBAN, account: "SE4550000000058398257466"},
+		{name: "No account", company: Company{Name: "Test AB"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := inv.Payment(&test.company)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, p.PaymentType)
+			assert.Equal(t, test.account, p.AccountNumber)
+			assert.Equal(t, "EUR", p.Currency)
+		})
+	}
+}
+
+func TestError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"ErrorInformation":{"error":1,"message":"Invalid access token.","code":2000311}}`)
+	}))
+	defer srv.Close()
+
+	_, err := New("token", WithBaseURL(srv.URL)).Payments(context.Background())
+	require.Error(t, err)
+
+	var apiErr *Error
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+	assert.Equal(t, 2000311, apiErr.Code)
+	assert.Equal(t, "fortnox: status 401: Invalid access token. (2000311)", err.Error())
+}
@@ -0,0 +1,65 @@
+//go:build !payqr_payloadonly
+
+package payqr
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"time"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// PayloadProvider returns the payload of a frame, e.g. the current payment
+// request token.
+type PayloadProvider func(frame int) (string, error)
+
+// AnimatedGIF renders the payloads as the frames of a looping animated GIF of
+// size pixels, each frame shown for delay. It is useful for short-lived
+// payment request tokens that are rotated every few seconds.
+func AnimatedGIF(payloads []string, size int, delay time.Duration) ([]byte, error) {
+	return AnimatedGIFFromProvider(func(frame int) (string, error) {
+		return payloads[frame], nil
+	}, len(payloads), size, delay)
+}
+
+// AnimatedGIFFromProvider renders frames payloads from the provider as an
+// animated GIF, see AnimatedGIF.
+func AnimatedGIFFromProvider(provider PayloadProvider, frames, size int, delay time.Duration) ([]byte, error) {
+	if frames < 1 {
+		return nil, errors.New("animated gif needs at least one frame")
+	}
+
+	palette := color.Palette{color.White, color.Black}
+	anim := &gif.GIF{}
+
+	for i := 0; i < frames; i++ {
+		payload, err := provider(i)
+		if err != nil {
+			return nil, err
+		}
+
+		q, err := newQR(payload, qrcode.High, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		img := q.Image(size)
+		frame := image.NewPaletted(img.Bounds(), palette)
+		draw.Draw(frame, frame.Rect, img, img.Bounds().Min, draw.Src)
+
+		anim.Image = append(anim.Image, frame)
+		anim.Delay = append(anim.Delay, int(delay/(10*time.Millisecond)))
+	}
+
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, anim); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,31 @@
+//go:build !payqr_payloadonly
+
+package payqr
+
+import (
+	"bytes"
+	"errors"
+	"image/gif"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnimatedGIF(t *testing.T) {
+	b, err := AnimatedGIF([]string{"Dtoken1", "Dtoken2", "Dtoken3"}, 256, 2*time.Second)
+	require.NoError(t, err)
+
+	anim, err := gif.DecodeAll(bytes.NewReader(b))
+	require.NoError(t, err)
+	assert.Len(t, anim.Image, 3)
+	assert.Equal(t, []int{200, 200, 200}, anim.Delay)
+	assert.Equal(t, 256, anim.Config.Width)
+
+	_, err = AnimatedGIF(nil, 256, time.Second)
+	assert.Error(t, err)
+
+	_, err = AnimatedGIFFromProvider(func(int) (string, error) { return "", errors.New("no token") }, 2, 256, time.Second)
+	assert.Error(t, err)
+}
@@ -0,0 +1,103 @@
+// Package ideal builds iDEAL QR links for Dutch payments.
+//
+// iDEAL QR codes are registered with the merchant's acquirer, which issues a
+// base URL for the merchant. The link adds the amount, description and
+// expiry of the payment to it, so one registration can serve all invoices.
+package ideal
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/antonlindstrom/payqr"
+)
+
+// Currency is the currency of iDEAL payments.
+const Currency = "EUR"
+
+// MaxAmount is the largest amount of an iDEAL QR payment.
+const MaxAmount = 50000.00
+
+// MaxDescriptionLength is the maximum length of the description.
+const MaxDescriptionLength = 35
+
+// Option is a modifier for the link.
+type Option func(*config)
+
+type config struct {
+	description string
+	expiry      time.Time
+	changeable  bool
+}
+
+// WithDescription sets the description shown to the payer, default is the
+// reference of the payment.
+func WithDescription(description string) Option {
+	return func(c *config) {
+		c.description = description
+	}
+}
+
+// WithExpiry sets when the link expires, default is the end of the due date
+// of the payment.
+func WithExpiry(t time.Time) Option {
+	return func(c *config) {
+		c.expiry = t
+	}
+}
+
+// WithChangeableAmount lets the payer change the amount.
+func WithChangeableAmount() Option {
+	return func(c *config) {
+		c.changeable = true
+	}
+}
+
+// Link returns the iDEAL QR link of the payment under the base URL issued by
+// the acquirer.
+func Link(baseURL string, p *payqr.Payment, options ...Option) (string, error) {
+	c := &config{description: p.Reference}
+	for _, opt := range options {
+		opt(c)
+	}
+
+	base, err := url.Parse(baseURL)
+	if err != nil || base.Scheme != "https" || base.Host == "" {
+		return "", fmt.Errorf("ideal: %q is not an https URL", baseURL)
+	}
+	if p.Currency != "" && !strings.EqualFold(p.Currency, Currency) {
+		return "", fmt.Errorf("ideal: currency must be %s, got %s", Currency, p.Currency)
+	}
+	if p.DueAmount < 0.01 || p.DueAmount > MaxAmount {
+		return "", fmt.Errorf("ideal: amount %.2f must be between 0.01 and %.2f", p.DueAmount, MaxAmount)
+	}
+	if c.description == "" {
+		return "", fmt.Errorf("ideal: description is required")
+	}
+	if utf8.RuneCountInString(c.description) > MaxDescriptionLength {
+		return "", fmt.Errorf("ideal: description is longer than %d characters", MaxDescriptionLength)
+	}
+
+	if c.expiry.IsZero() {
+		due, err := time.Parse("20060102", p.DueDate)
+		if err != nil {
+			return "", fmt.Errorf("ideal: no expiry and invalid due date %q", p.DueDate)
+		}
+		c.expiry = due.Add(24*time.Hour - time.Second)
+	}
+
+	query := base.Query()
+	query.Set("amount", strconv.FormatFloat(p.DueAmount, 'f', 2, 64))
+	query.Set("description", c.description)
+	query.Set("expiry", c.expiry.UTC().Format(time.RFC3339))
+	if c.changeable {
+		query.Set("amountChangeable", "true")
+	}
+	base.RawQuery = query.Encode()
+
+	return base.String(), nil
+}
@@ -0,0 +1,73 @@
+package ideal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLink(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		base    string
+		have    *payqr.Payment
+		options []Option
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Defaults",
+			base: "https://qr.example.nl/m/123",
+			have: payqr.New("", "Test BV", "", "Factuur 2022-001", 12.5, due, payqr.WithCurrency("EUR")),
+			want: "https://qr.example.nl/m/123?amount=12.50&description=Factuur+2022-001&expiry=2022-08-06T23%3A59%3A59Z",
+		},
+		{
+			name:    "Options",
+			base:    "https://qr.example.nl/m/123?lang=nl",
+			have:    payqr.New("", "Test BV", "", "", 99, due),
+			options: []Option{WithDescription("Donatie"), WithExpiry(due.Add(time.Hour)), WithChangeableAmount()},
+			want:    "https://qr.example.nl/m/123?amount=99.00&amountChangeable=true&description=Donatie&expiry=2022-08-06T01%3A00%3A00Z&lang=nl",
+		},
+		{
+			name:    "HTTP base",
+			base:    "http://qr.example.nl/m/123",
+			have:    payqr.New("", "Test BV", "", "Factuur", 12.5, due),
+			wantErr: true,
+		},
+		{
+			name:    "Missing description",
+			base:    "https://qr.example.nl/m/123",
+			have:    payqr.New("", "Test BV", "", "", 12.5, due),
+			wantErr: true,
+		},
+		{
+			name:    "Amount too large",
+			base:    "https://qr.example.nl/m/123",
+			have:    payqr.New("", "Test BV", "", "Factuur", 50000.01, due),
+			wantErr: true,
+		},
+		{
+			name:    "SEK",
+			base:    "https://qr.example.nl/m/123",
+			have:    payqr.New("", "Test BV", "", "Factuur", 12.5, due, payqr.WithCurrency("SEK")),
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Link(test.base, test.have, test.options...)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !payqr_payloadonly
+
+package ideal
+
+import (
+	"github.com/antonlindstrom/payqr"
+	"github.com/skip2/go-qrcode"
+)
+
+// QR returns a QR code of the iDEAL QR link of the payment.
+func QR(baseURL string, p *payqr.Payment, options ...Option) (*qrcode.QRCode, error) {
+	link, err := Link(baseURL, p, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return qrcode.New(link, qrcode.Medium)
+}
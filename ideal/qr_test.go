@@ -0,0 +1,26 @@
+//go:build !payqr_payloadonly
+
+package ideal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQR(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+	p := payqr.New("", "Test BV", "", "Factuur 2022-001", 12.5, due, payqr.WithCurrency("EUR"))
+
+	q, err := QR("https://qr.example.nl/m/123", p)
+	require.NoError(t, err)
+	want, err := Link("https://qr.example.nl/m/123", p)
+	require.NoError(t, err)
+	assert.Equal(t, want, q.Content)
+
+	_, err = QR("javascript:alert(1)", p)
+	assert.Error(t, err)
+}
@@ -0,0 +1,200 @@
+// Package canvas draws the same layout as PDF or as an image, with
+// coordinates in millimeters from the top left corner.
+package canvas
+
+import (
+	"bytes"
+	"image"
+	"image/draw"
+	"image/png"
+	"math"
+
+	"github.com/antonlindstrom/payqr/internal/pdf"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// Canvas is a surface to draw on. Text is placed by its baseline.
+type Canvas interface {
+	Text(left, top float64, font pdf.Font, size float64, s string)
+	TextRight(right, top float64, font pdf.Font, size float64, s string)
+	Rect(left, top, width, height float64)
+	Modules(left, top, size float64, bitmap [][]bool)
+}
+
+// TextWidth returns the width of the text in millimeters. Images use the
+// same widths, so that layouts fit the same in both.
+func TextWidth(font pdf.Font, size float64, s string) float64 {
+	return pdf.TextWidth(font, size, s) / pdf.MM
+}
+
+// PDF draws on a PDF page, with the origin left and top millimeters from the
+// top left corner of the page.
+type PDF struct {
+	*pdf.Page
+	left, top float64
+}
+
+// NewPDF returns a canvas drawing on the page with the origin at left, top.
+func NewPDF(page *pdf.Page, left, top float64) *PDF {
+	return &PDF{Page: page, left: left, top: top}
+}
+
+func (c *PDF) x(left float64) float64 {
+	return (c.left + left) * pdf.MM
+}
+
+func (c *PDF) y(top float64) float64 {
+	return c.Height - (c.top+top)*pdf.MM
+}
+
+// Text draws the text with its baseline starting at left, top.
+func (c *PDF) Text(left, top float64, font pdf.Font, size float64, s string) {
+	c.Page.Text(c.x(left), c.y(top), font, size, s)
+}
+
+// TextRight draws the text with its baseline ending at right, top.
+func (c *PDF) TextRight(right, top float64, font pdf.Font, size float64, s string) {
+	c.Page.TextRight(c.x(right), c.y(top), font, size, s)
+}
+
+// Rect draws the outline of a rectangle, half a point wide.
+func (c *PDF) Rect(left, top, width, height float64) {
+	c.Page.Rect(c.x(left), c.y(top+height), width*pdf.MM, height*pdf.MM, false, 0.5)
+}
+
+// Modules draws the dark modules of the bitmap as a square of size
+// millimeters.
+func (c *PDF) Modules(left, top, size float64, bitmap [][]bool) {
+	c.Page.Modules(c.x(left), c.y(top+size), size*pdf.MM, bitmap)
+}
+
+// fonts are the Go fonts standing in for the PDF fonts, by pdf.Font.
+var fonts = [...][]byte{goregular.TTF, gobold.TTF, gomono.TTF}
+
+// Image draws on a white grayscale image. The first error loading a font is
+// returned by Err.
+type Image struct {
+	*image.Gray
+	dpi   int
+	faces map[[2]float64]font.Face
+	err   error
+}
+
+// NewImage returns a canvas of the size in millimeters at the resolution in
+// dots per inch.
+func NewImage(width, height float64, dpi int) *Image {
+	c := &Image{
+		Gray:  image.NewGray(image.Rect(0, 0, Pixels(width, dpi), Pixels(height, dpi))),
+		dpi:   dpi,
+		faces: map[[2]float64]font.Face{},
+	}
+	draw.Draw(c.Gray, c.Gray.Rect, image.White, image.Point{}, draw.Src)
+
+	return c
+}
+
+// Err returns the first error drawing on the image.
+func (c *Image) Err() error {
+	return c.err
+}
+
+// PNG encodes the image as PNG.
+func (c *Image) PNG() ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, c.Gray); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (c *Image) px(mm float64) int {
+	return Pixels(mm, c.dpi)
+}
+
+func (c *Image) drawer(f pdf.Font, size float64) *font.Drawer {
+	key := [2]float64{float64(f), size}
+	face, ok := c.faces[key]
+	if !ok {
+		parsed, err := opentype.Parse(fonts[f])
+		if err == nil {
+			face, err = opentype.NewFace(parsed, &opentype.FaceOptions{Size: size, DPI: float64(c.dpi), Hinting: font.HintingFull})
+		}
+		if err != nil {
+			if c.err == nil {
+				c.err = err
+			}
+			return nil
+		}
+		c.faces[key] = face
+	}
+
+	return &font.Drawer{Dst: c.Gray, Src: image.Black, Face: face}
+}
+
+// Text draws the text with its baseline starting at left, top.
+func (c *Image) Text(left, top float64, f pdf.Font, size float64, s string) {
+	if d := c.drawer(f, size); d != nil {
+		d.Dot = fixed.P(c.px(left), c.px(top))
+		d.DrawString(s)
+	}
+}
+
+// TextRight draws the text with its baseline ending at right, top.
+func (c *Image) TextRight(right, top float64, f pdf.Font, size float64, s string) {
+	if d := c.drawer(f, size); d != nil {
+		d.Dot = fixed.Point26_6{X: fixed.I(c.px(right)) - d.MeasureString(s), Y: fixed.I(c.px(top))}
+		d.DrawString(s)
+	}
+}
+
+// Rect draws the outline of a rectangle, half a point wide.
+func (c *Image) Rect(left, top, width, height float64) {
+	w := int(math.Max(1, math.Round(0.5/72*float64(c.dpi))))
+	r := image.Rect(c.px(left), c.px(top), c.px(left+width), c.px(top+height))
+
+	for _, edge := range []image.Rectangle{
+		image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+w),
+		image.Rect(r.Min.X, r.Max.Y-w, r.Max.X, r.Max.Y),
+		image.Rect(r.Min.X, r.Min.Y, r.Min.X+w, r.Max.Y),
+		image.Rect(r.Max.X-w, r.Min.Y, r.Max.X, r.Max.Y),
+	} {
+		draw.Draw(c.Gray, edge, image.Black, image.Point{}, draw.Src)
+	}
+}
+
+// Modules draws the dark modules of the bitmap as a square of size
+// millimeters.
+func (c *Image) Modules(left, top, size float64, bitmap [][]bool) {
+	n := len(bitmap)
+	x0, y0 := c.px(left), c.px(top)
+	px := c.px(size)
+
+	// Module edges are rounded so that all modules are within one pixel of
+	// the same size.
+	edge := func(i int) int {
+		return i * px / n
+	}
+	for row, line := range bitmap {
+		for col, dark := range line {
+			if dark {
+				r := image.Rect(x0+edge(col), y0+edge(row), x0+edge(col+1), y0+edge(row+1))
+				draw.Draw(c.Gray, r, image.Black, image.Point{}, draw.Src)
+			}
+		}
+	}
+}
+
+// Pixels converts millimeters to pixels at the resolution in dots per inch.
+func Pixels(mm float64, dpi int) int {
+	return int(math.Round(mm / 25.4 * float64(dpi)))
+}
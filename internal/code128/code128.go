@@ -0,0 +1,115 @@
+// Package code128 encodes and renders Code 128 barcodes.
+package code128
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+)
+
+// patterns are the bar/space widths of all symbols, the stop symbol has an
+// extra final bar.
+var patterns = [107]string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+const (
+	startB = 104
+	startC = 105
+	stop   = 106
+)
+
+// Encode returns the symbols of the data, including start, checksum and stop
+// symbols. Data of only digits with an even length is encoded with code set
+// C, anything else with code set B.
+func Encode(data string) ([]int, error) {
+	if data == "" {
+		return nil, errors.New("code128: empty data")
+	}
+
+	var symbols []int
+	if len(data)%2 == 0 && isDigits(data) {
+		symbols = append(symbols, startC)
+		for i := 0; i < len(data); i += 2 {
+			symbols = append(symbols, int(data[i]-'0')*10+int(data[i+1]-'0'))
+		}
+	} else {
+		symbols = append(symbols, startB)
+		for i := 0; i < len(data); i++ {
+			if data[i] < 32 || data[i] > 127 {
+				return nil, fmt.Errorf("code128: character %q can not be encoded", data[i])
+			}
+			symbols = append(symbols, int(data[i]-32))
+		}
+	}
+
+	sum := symbols[0]
+	for i, s := range symbols[1:] {
+		sum += s * (i + 1)
+	}
+
+	return append(symbols, sum%103, stop), nil
+}
+
+// Modules returns the barcode as modules, true for bars, including a quiet
+// zone of 10 modules on each side.
+func Modules(data string) ([]bool, error) {
+	symbols, err := Encode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	modules := make([]bool, 10, 10+len(symbols)*11+12)
+	for _, s := range symbols {
+		for i, w := range patterns[s] {
+			for j := 0; j < int(w-'0'); j++ {
+				modules = append(modules, i%2 == 0)
+			}
+		}
+	}
+
+	return append(modules, make([]bool, 10)...), nil
+}
+
+// Image renders the barcode with each module moduleWidth pixels wide and
+// height pixels high.
+func Image(data string, moduleWidth, height int) (image.Image, error) {
+	modules, err := Modules(data)
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewGray(image.Rect(0, 0, len(modules)*moduleWidth, height))
+	for x := 0; x < img.Rect.Dx(); x++ {
+		c := color.Gray{Y: 0xff}
+		if modules[x/moduleWidth] {
+			c = color.Gray{}
+		}
+		for y := 0; y < height; y++ {
+			img.SetGray(x, y, c)
+		}
+	}
+
+	return img, nil
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,42 @@
+package code128
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		have string
+		want []int
+	}{
+		// Checksum: 105 + 12*1 + 34*2 = 185, 185 % 103 = 82.
+		{have: "1234", want: []int{startC, 12, 34, 82, stop}},
+		// Checksum: 104 + 33*1 + 83*2 = 303, 303 % 103 = 97.
+		{have: "As", want: []int{startB, 33, 83, 97, stop}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.have, func(t *testing.T) {
+			got, err := Encode(test.have)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+
+	_, err := Encode("")
+	assert.Error(t, err)
+}
+
+func TestImage(t *testing.T) {
+	modules, err := Modules("1234")
+	require.NoError(t, err)
+	// Quiet zones, four symbols of 11 modules and a stop of 13 modules.
+	assert.Len(t, modules, 10+4*11+13+10)
+
+	img, err := Image("1234", 2, 50)
+	require.NoError(t, err)
+	assert.Equal(t, len(modules)*2, img.Bounds().Dx())
+}
@@ -0,0 +1,49 @@
+package pdf
+
+// Widths of the printable ASCII characters, 32 to 126, in 1/1000 of the font
+// size, from the Adobe font metrics.
+var (
+	helveticaWidths = [95]int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	}
+	helveticaBoldWidths = [95]int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+)
+
+// latinWidths are the widths of accented letters common in invoices, as
+// Helvetica and Helvetica-Bold.
+var latinWidths = map[byte][2]int{
+	0xc4: {667, 722}, 0xc5: {667, 722}, 0xc6: {1000, 1000}, 0xc9: {667, 667}, 0xd6: {778, 778},
+	0xd8: {778, 778}, 0xdc: {722, 722}, 0xdf: {611, 611}, 0xe4: {556, 556}, 0xe5: {556, 556},
+	0xe6: {889, 889}, 0xe9: {556, 556}, 0xf6: {556, 611}, 0xf8: {611, 611}, 0xfc: {556, 611},
+	0x80: {556, 556},
+}
+
+func charWidth(font Font, b byte) int {
+	if font == Courier {
+		return 600
+	}
+
+	if b >= 32 && b <= 126 {
+		if font == HelveticaBold {
+			return helveticaBoldWidths[b-32]
+		}
+		return helveticaWidths[b-32]
+	}
+	if w, ok := latinWidths[b]; ok {
+		return w[font]
+	}
+
+	return 556
+}
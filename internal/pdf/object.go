@@ -0,0 +1,118 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Objects of a parsed document are nil, bool, int64, float64, Name, String,
+// Ref, Array, Dict and *Stream.
+
+// Name is a PDF name, without the slash.
+type Name string
+
+// String is a PDF string.
+type String []byte
+
+// Ref is a reference to an indirect object.
+type Ref struct {
+	Num, Gen int
+}
+
+// Array is a PDF array.
+type Array []interface{}
+
+// Dict is a PDF dictionary.
+type Dict map[Name]interface{}
+
+// Stream is a stream object, Data is the encoded data.
+type Stream struct {
+	Dict Dict
+	Data []byte
+}
+
+// writeObject writes the object in PDF syntax. Dictionary keys are sorted
+// and strings written as hex to keep the output simple and deterministic.
+func writeObject(buf *bytes.Buffer, obj interface{}) {
+	switch v := obj.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case int64:
+		buf.WriteString(strconv.FormatInt(v, 10))
+	case int:
+		buf.WriteString(strconv.Itoa(v))
+	case float64:
+		buf.WriteString(strconv.FormatFloat(v, 'f', -1, 64))
+	case Name:
+		writeName(buf, v)
+	case String:
+		fmt.Fprintf(buf, "<%x>", []byte(v))
+	case Ref:
+		fmt.Fprintf(buf, "%d %d R", v.Num, v.Gen)
+	case Array:
+		buf.WriteByte('[')
+		for i, o := range v {
+			if i > 0 {
+				buf.WriteByte(' ')
+			}
+			writeObject(buf, o)
+		}
+		buf.WriteByte(']')
+	case Dict:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, string(k))
+		}
+		sort.Strings(keys)
+
+		buf.WriteString("<<")
+		for _, k := range keys {
+			buf.WriteByte(' ')
+			writeName(buf, Name(k))
+			buf.WriteByte(' ')
+			writeObject(buf, v[Name(k)])
+		}
+		buf.WriteString(" >>")
+	case *Stream:
+		d := Dict{}
+		for k, o := range v.Dict {
+			d[k] = o
+		}
+		d["Length"] = int64(len(v.Data))
+		writeObject(buf, d)
+		buf.WriteString("\nstream\n")
+		buf.Write(v.Data)
+		buf.WriteString("\nendstream")
+	default:
+		panic(fmt.Sprintf("pdf: can not write %T", obj))
+	}
+}
+
+func writeName(buf *bytes.Buffer, n Name) {
+	buf.WriteByte('/')
+	for i := 0; i < len(n); i++ {
+		c := n[i]
+		if c < '!' || c > '~' || isDelimiter(c) || c == '#' {
+			fmt.Fprintf(buf, "#%02X", c)
+			continue
+		}
+		buf.WriteByte(c)
+	}
+}
+
+func isWhitespace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == 0
+}
+
+func isDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,247 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Overlay writes the document with the content drawn by draw on top of the
+// page, numbered from 1, appended as an incremental update so the original
+// document is kept byte for byte. The page given to draw has the size of the
+// media box of the page and supports graphics but not text.
+func Overlay(data []byte, w io.Writer, page int, draw func(p *Page)) error {
+	r, err := NewReader(data)
+	if err != nil {
+		return err
+	}
+
+	ref, dict, box, err := r.page(page)
+	if err != nil {
+		return err
+	}
+
+	p := &Page{Width: box[2] - box[0], Height: box[3] - box[1]}
+	draw(p)
+
+	contents, err := r.contents(dict)
+	if err != nil {
+		return err
+	}
+
+	size := r.Size()
+	before, after := Ref{Num: size}, Ref{Num: size + 1}
+
+	overlay := fmt.Sprintf("Q\nq\n1 0 0 1 %s %s cm\n%s\nQ\n", num(box[0]), num(box[1]), p.content.Bytes())
+	newPage := Dict{}
+	for k, v := range dict {
+		newPage[k] = v
+	}
+	newPage["Contents"] = append(append(Array{before}, contents...), after)
+
+	var buf bytes.Buffer
+	buf.Write(data)
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+
+	offsets := map[int]int64{}
+	gens := map[int]int{ref.Num: r.Gen(ref.Num)}
+	obj := func(num int, o interface{}) {
+		offsets[num] = int64(buf.Len())
+		fmt.Fprintf(&buf, "%d %d obj\n", num, gens[num])
+		writeObject(&buf, o)
+		buf.WriteString("\nendobj\n")
+	}
+
+	obj(before.Num, &Stream{Dict: Dict{}, Data: []byte("q\n")})
+	obj(after.Num, &Stream{Dict: Dict{}, Data: []byte(overlay)})
+	obj(ref.Num, newPage)
+
+	trailer := Dict{"Prev": r.startxref}
+	for _, k := range []Name{"Root", "Info", "ID"} {
+		if v, ok := r.trailer[k]; ok {
+			trailer[k] = v
+		}
+	}
+
+	if r.xrefStream {
+		// Documents with cross-reference streams are updated with a stream,
+		// the stream itself is the last object.
+		xrefNum := size + 2
+		offsets[xrefNum] = int64(buf.Len())
+		trailer["Type"] = Name("XRef")
+		trailer["Size"] = int64(xrefNum + 1)
+		trailer["W"] = Array{int64(1), int64(4), int64(2)}
+
+		var index Array
+		var entries []byte
+		for _, sec := range sections(offsets) {
+			index = append(index, int64(sec[0]), int64(len(sec)))
+			for _, n := range sec {
+				o, g := offsets[n], gens[n]
+				entries = append(entries, 1, byte(o>>24), byte(o>>16), byte(o>>8), byte(o), byte(g>>8), byte(g))
+			}
+		}
+		trailer["Index"] = index
+
+		fmt.Fprintf(&buf, "%d 0 obj\n", xrefNum)
+		writeObject(&buf, &Stream{Dict: trailer, Data: entries})
+		buf.WriteString("\nendobj\n")
+		fmt.Fprintf(&buf, "startxref\n%d\n%%%%EOF\n", offsets[xrefNum])
+	} else {
+		xref := buf.Len()
+		buf.WriteString("xref\n")
+		for _, sec := range sections(offsets) {
+			fmt.Fprintf(&buf, "%d %d\n", sec[0], len(sec))
+			for _, n := range sec {
+				fmt.Fprintf(&buf, "%010d %05d n \n", offsets[n], gens[n])
+			}
+		}
+		trailer["Size"] = int64(size + 2)
+		buf.WriteString("trailer\n")
+		writeObject(&buf, trailer)
+		fmt.Fprintf(&buf, "\nstartxref\n%d\n%%%%EOF\n", xref)
+	}
+
+	_, err = w.Write(buf.Bytes())
+
+	return err
+}
+
+// sections groups the object numbers into runs of consecutive numbers.
+func sections(offsets map[int]int64) [][]int {
+	nums := make([]int, 0, len(offsets))
+	for n := range offsets {
+		nums = append(nums, n)
+	}
+	sort.Ints(nums)
+
+	var secs [][]int
+	for i, n := range nums {
+		if i == 0 || n != nums[i-1]+1 {
+			secs = append(secs, nil)
+		}
+		secs[len(secs)-1] = append(secs[len(secs)-1], n)
+	}
+
+	return secs
+}
+
+// page returns the reference, dictionary and media box of the page numbered
+// from 1.
+func (r *Reader) page(n int) (Ref, Dict, [4]float64, error) {
+	root, err := r.Resolve(r.trailer["Root"])
+	if err != nil {
+		return Ref{}, nil, [4]float64{}, err
+	}
+	catalog, ok := root.(Dict)
+	if !ok {
+		return Ref{}, nil, [4]float64{}, errors.New("pdf: no document catalog")
+	}
+
+	count := 0
+	seen := map[Ref]bool{}
+	var walk func(node interface{}, box interface{}, depth int) (Ref, Dict, interface{}, error)
+	walk = func(node interface{}, box interface{}, depth int) (Ref, Dict, interface{}, error) {
+		ref, isRef := node.(Ref)
+		if isRef && seen[ref] {
+			return Ref{}, nil, nil, errors.New("pdf: loop in page tree")
+		}
+		if isRef {
+			seen[ref] = true
+		}
+		obj, err := r.Resolve(node)
+		if err != nil {
+			return Ref{}, nil, nil, err
+		}
+		d, ok := obj.(Dict)
+		if !ok || depth > 64 {
+			return Ref{}, nil, nil, errors.New("pdf: invalid page tree")
+		}
+		if mb, ok := d["MediaBox"]; ok {
+			box = mb
+		}
+
+		if d["Type"] == Name("Page") || d["Kids"] == nil {
+			count++
+			if count == n {
+				return ref, d, box, nil
+			}
+			return Ref{}, nil, nil, nil
+		}
+
+		kids, err := r.Resolve(d["Kids"])
+		if err != nil {
+			return Ref{}, nil, nil, err
+		}
+		kidsArray, _ := kids.(Array)
+		for _, kid := range kidsArray {
+			ref, page, pageBox, err := walk(kid, box, depth+1)
+			if err != nil || page != nil {
+				return ref, page, pageBox, err
+			}
+		}
+
+		return Ref{}, nil, nil, nil
+	}
+
+	ref, page, box, err := walk(catalog["Pages"], nil, 0)
+	if err != nil {
+		return Ref{}, nil, [4]float64{}, err
+	}
+	if page == nil {
+		return Ref{}, nil, [4]float64{}, fmt.Errorf("pdf: page %d not found, the document has %d pages", n, count)
+	}
+	if ref == (Ref{}) {
+		return Ref{}, nil, [4]float64{}, errors.New("pdf: page is not an indirect object")
+	}
+
+	box, err = r.Resolve(box)
+	if err != nil {
+		return Ref{}, nil, [4]float64{}, err
+	}
+	a, ok := box.(Array)
+	if !ok || len(a) != 4 {
+		return Ref{}, nil, [4]float64{}, errors.New("pdf: page has no media box")
+	}
+
+	var mediaBox [4]float64
+	for i, v := range a {
+		switch f := v.(type) {
+		case int64:
+			mediaBox[i] = float64(f)
+		case float64:
+			mediaBox[i] = f
+		default:
+			return Ref{}, nil, [4]float64{}, errors.New("pdf: invalid media box")
+		}
+	}
+
+	return ref, page, mediaBox, nil
+}
+
+// contents returns the references to the content streams of the page.
+func (r *Reader) contents(page Dict) (Array, error) {
+	c, ok := page["Contents"]
+	if !ok {
+		return nil, nil
+	}
+	if ref, ok := c.(Ref); ok {
+		obj, err := r.Resolve(ref)
+		if err != nil {
+			return nil, err
+		}
+		if a, ok := obj.(Array); ok {
+			return a, nil
+		}
+		return Array{ref}, nil
+	}
+	if a, ok := c.(Array); ok {
+		return a, nil
+	}
+
+	return nil, errors.New("pdf: invalid page contents")
+}
@@ -0,0 +1,137 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// xrefStreamDocument returns a one page document with a cross-reference
+// stream using the PNG up predictor and the page in an object stream.
+func xrefStreamDocument(t testing.TB) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.5\n")
+	offsets := map[int]int{}
+	obj := func(num int, body string) {
+		offsets[num] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", num, body)
+	}
+
+	obj(1, "<< /Type /Catalog /Pages 2 0 R >>")
+	obj(2, "<< /Type /Pages /Kids [3 0 R] /Count 1 /MediaBox [0 0 200 100] >>")
+	page := "<< /Type /Page /Parent 2 0 R /Contents 5 0 R >>"
+	header := "3 0 "
+	obj(4, fmt.Sprintf("<< /Type /ObjStm /N 1 /First %d /Length %d >>\nstream\n%s%s\nendstream", len(header), len(header)+len(page), header, page))
+	obj(5, "<< /Length 10 >>\nstream\n0 0 m 1 1 l\nendstream")
+
+	// Entries of type, 2 byte offset and 1 byte index or generation.
+	entries := [][4]byte{
+		{0, 0, 0, 255},
+		{1, byte(offsets[1] >> 8), byte(offsets[1]), 0},
+		{1, byte(offsets[2] >> 8), byte(offsets[2]), 0},
+		{2, 0, 4, 0},
+		{1, byte(offsets[4] >> 8), byte(offsets[4]), 0},
+		{1, byte(offsets[5] >> 8), byte(offsets[5]), 0},
+		{1, byte(buf.Len() >> 8), byte(buf.Len()), 0},
+	}
+	var raw []byte
+	var prev [4]byte
+	for _, e := range entries {
+		raw = append(raw, 2)
+		for i := range e {
+			raw = append(raw, e[i]-prev[i])
+		}
+		prev = e
+	}
+	var z bytes.Buffer
+	zw := zlib.NewWriter(&z)
+	_, err := zw.Write(raw)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "6 0 obj\n<< /Type /XRef /Size 7 /W [1 2 1] /Root 1 0 R /Filter /FlateDecode /DecodeParms << /Predictor 12 /Columns 4 >> /Length %d >>\nstream\n", z.Len())
+	buf.Write(z.Bytes())
+	fmt.Fprintf(&buf, "\nendstream\nendobj\nstartxref\n%d\n%%%%EOF\n", xref)
+
+	return buf.Bytes()
+}
+
+func TestOverlay(t *testing.T) {
+	d := New()
+	d.AddPage(A4Width, A4Height)
+	d.AddPage(LetterWidth, LetterHeight).Text(10, 10, Helvetica, 12, "Page 2")
+
+	tests := []struct {
+		name       string
+		have       []byte
+		page       int
+		wantWidth  float64
+		wantStream bool
+		wantErr    bool
+	}{
+		{name: "Cross-reference table", have: d.Bytes(), page: 2, wantWidth: LetterWidth},
+		{name: "Cross-reference stream", have: xrefStreamDocument(t), page: 1, wantWidth: 200, wantStream: true},
+		{name: "Missing page", have: d.Bytes(), page: 3, wantErr: true},
+		{name: "Not a PDF", have: []byte("hello"), page: 1, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var width float64
+			var out bytes.Buffer
+			err := Overlay(test.have, &out, test.page, func(p *Page) {
+				width = p.Width
+				p.Rect(1, 2, 3, 4, true, 0)
+			})
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.wantWidth, width)
+			assert.True(t, bytes.HasPrefix(out.Bytes(), test.have))
+
+			r, err := NewReader(out.Bytes())
+			require.NoError(t, err)
+			assert.Equal(t, test.wantStream, r.xrefStream)
+
+			_, page, _, err := r.page(test.page)
+			require.NoError(t, err)
+			contents, err := r.contents(page)
+			require.NoError(t, err)
+			require.Len(t, contents, 3)
+
+			first, err := r.Resolve(contents[0])
+			require.NoError(t, err)
+			assert.Equal(t, "q\n", string(first.(*Stream).Data))
+			last, err := r.Resolve(contents[2])
+			require.NoError(t, err)
+			assert.Contains(t, string(last.(*Stream).Data), "1 2 3 4 re f")
+
+			// The original content is still there.
+			orig, err := r.Resolve(contents[1])
+			require.NoError(t, err)
+			assert.IsType(t, &Stream{}, orig)
+		})
+	}
+}
+
+func TestParser(t *testing.T) {
+	p := &parser{data: []byte(`<< /A (a\(b\)\101) /B <4869> /C [1 2.5 -3 0 R true null] /D#20E /x >>`)}
+	assert.Equal(t, Dict{
+		"A":   String("a(b)A"),
+		"B":   String("Hi"),
+		"C":   Array{int64(1), 2.5, Ref{Num: -3}, true, nil},
+		"D E": Name("x"),
+	}, p.next())
+	assert.NoError(t, p.err)
+
+	var buf bytes.Buffer
+	writeObject(&buf, Dict{"D E": Array{Name("x"), String("Hi"), Ref{Num: 3}}})
+	assert.Equal(t, "<< /D#20E [/x <4869> 3 0 R] >>", buf.String())
+}
@@ -0,0 +1,262 @@
+// Package pdf is a minimal PDF writer for documents of text, lines, filled
+// rectangles and QR codes drawn as vector graphics, using the standard 14
+// fonts so nothing needs to be embedded.
+package pdf
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Page sizes in points.
+const (
+	A4Width      = 595.28
+	A4Height     = 841.89
+	LetterWidth  = 612
+	LetterHeight = 792
+)
+
+// MM is the number of points per millimeter.
+const MM = 72 / 25.4
+
+// Font is one of the standard fonts.
+type Font int
+
+const (
+	Helvetica Font = iota
+	HelveticaBold
+	Courier
+)
+
+var fontNames = [...]string{"Helvetica", "Helvetica-Bold", "Courier"}
+
+// Document is a PDF document.
+type Document struct {
+	pages []*Page
+}
+
+// New returns an empty document.
+func New() *Document {
+	return &Document{}
+}
+
+// AddPage adds a page of the size in points.
+func (d *Document) AddPage(width, height float64) *Page {
+	p := &Page{Width: width, Height: height}
+	d.pages = append(d.pages, p)
+
+	return p
+}
+
+// Pages returns the number of pages.
+func (d *Document) Pages() int {
+	return len(d.pages)
+}
+
+// Page is a page of the document. Coordinates are in points from the bottom
+// left corner, as in PDF.
+type Page struct {
+	Width, Height float64
+
+	content bytes.Buffer
+}
+
+// Text draws the text with its baseline starting at x, y.
+func (p *Page) Text(x, y float64, font Font, size float64, s string) {
+	fmt.Fprintf(&p.content, "BT /F%d %s Tf %s %s Td (%s) Tj ET\n", font+1, num(size), num(x), num(y), escape(s))
+}
+
+// TextRight draws the text with its baseline ending at x, y.
+func (p *Page) TextRight(x, y float64, font Font, size float64, s string) {
+	p.Text(x-TextWidth(font, size, s), y, font, size, s)
+}
+
+// Line draws a line of the width.
+func (p *Page) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s m %s %s l S\n", num(width), num(x1), num(y1), num(x2), num(y2))
+}
+
+// Rect draws a rectangle with the lower left corner at x, y, filled or as an
+// outline of the width.
+func (p *Page) Rect(x, y, w, h float64, fill bool, width float64) {
+	if fill {
+		fmt.Fprintf(&p.content, "%s %s %s %s re f\n", num(x), num(y), num(w), num(h))
+		return
+	}
+	fmt.Fprintf(&p.content, "%s w %s %s %s %s re S\n", num(width), num(x), num(y), num(w), num(h))
+}
+
+// Gray sets the fill and stroke color, 0 is black and 1 white.
+func (p *Page) Gray(level float64) {
+	fmt.Fprintf(&p.content, "%s g %s G\n", num(level), num(level))
+}
+
+// Modules draws the dark modules of a bitmap, such as a QR code, as a square
+// of size points with the upper left corner at x, y+size. Horizontal runs of
+// modules are joined into one rectangle.
+func (p *Page) Modules(x, y, size float64, bitmap [][]bool) {
+	n := len(bitmap)
+	if n == 0 {
+		return
+	}
+	m := size / float64(n)
+
+	for row, line := range bitmap {
+		for col := 0; col < len(line); col++ {
+			if !line[col] {
+				continue
+			}
+			start := col
+			for col < len(line) && line[col] {
+				col++
+			}
+			fmt.Fprintf(&p.content, "%s %s %s %s re\n", num(x+float64(start)*m), num(y+size-float64(row+1)*m), num(float64(col-start)*m), num(m))
+		}
+	}
+	p.content.WriteString("f\n")
+}
+
+// Raw appends raw content stream operators to the page.
+func (p *Page) Raw(ops string) {
+	p.content.WriteString(ops)
+}
+
+// WriteTo writes the document.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	cw := &countWriter{w: bw}
+	var offsets []int64
+
+	obj := func(body string) {
+		offsets = append(offsets, cw.n)
+		fmt.Fprintf(cw, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	cw.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Objects 1 and 2 are the catalog and page tree, followed by the fonts
+	// and then a page and its content per page.
+	first := 3 + len(fontNames)
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", first+2*i)
+	}
+
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+
+	var fonts strings.Builder
+	for i, name := range fontNames {
+		obj(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", name))
+		fmt.Fprintf(&fonts, "/F%d %d 0 R ", i+1, 3+i)
+	}
+
+	for i, p := range d.pages {
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << %s>> >> /Contents %d 0 R >>",
+			num(p.Width), num(p.Height), fonts.String(), first+2*i+1))
+
+		var z bytes.Buffer
+		zw := zlib.NewWriter(&z)
+		if _, err := zw.Write(p.content.Bytes()); err != nil {
+			return cw.n, err
+		}
+		if err := zw.Close(); err != nil {
+			return cw.n, err
+		}
+		obj(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", z.Len(), z.Bytes()))
+	}
+
+	xref := cw.n
+	fmt.Fprintf(cw, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(cw, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(cw, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	if cw.err != nil {
+		return cw.n, cw.err
+	}
+
+	return cw.n, bw.Flush()
+}
+
+// Bytes returns the document.
+func (d *Document) Bytes() []byte {
+	var buf bytes.Buffer
+	_, _ = d.WriteTo(&buf)
+
+	return buf.Bytes()
+}
+
+// TextWidth returns the width of the text in points.
+func TextWidth(font Font, size float64, s string) float64 {
+	var units int
+	for _, b := range []byte(encode(s)) {
+		units += charWidth(font, b)
+	}
+
+	return float64(units) * size / 1000
+}
+
+// escape encodes the text as WinAnsi and escapes it for a PDF string.
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`, "\r", `\r`, "\n", `\n`).Replace(encode(s))
+}
+
+// encode encodes the text as WinAnsi, characters that can not be encoded are
+// replaced by "?".
+func encode(s string) string {
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r == '€':
+			b = append(b, 0x80)
+		case r == '–':
+			b = append(b, 0x96)
+		case r == '—':
+			b = append(b, 0x97)
+		case r < 0x80 || (r >= 0xa0 && r <= 0xff):
+			b = append(b, byte(r))
+		default:
+			b = append(b, '?')
+		}
+	}
+
+	return string(b)
+}
+
+// num formats a number with at most two decimals.
+func num(f float64) string {
+	s := fmt.Sprintf("%.2f", f)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "-0" || s == "" {
+		return "0"
+	}
+
+	return s
+}
+
+type countWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countWriter) Write(b []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(b)
+	c.n += int64(n)
+	c.err = err
+
+	return n, err
+}
+
+func (c *countWriter) WriteString(s string) {
+	_, _ = c.Write([]byte(s))
+}
@@ -0,0 +1,57 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteTo(t *testing.T) {
+	d := New()
+	p := d.AddPage(A4Width, A4Height)
+	p.Text(10, 20, Helvetica, 12, "Räkning (1)")
+	p.Modules(10, 10, 30, [][]bool{{true, true, false}, {false, true, false}, {true, false, true}})
+	d.AddPage(LetterWidth, LetterHeight)
+
+	var buf bytes.Buffer
+	n, err := d.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+
+	b := buf.Bytes()
+	assert.True(t, bytes.HasPrefix(b, []byte("%PDF-1.4")))
+	assert.True(t, bytes.HasSuffix(b, []byte("%%EOF\n")))
+	assert.Contains(t, string(b), "/Count 2")
+
+	// The xref offsets point at the objects.
+	start := regexp.MustCompile(`startxref\n(\d+)`).FindSubmatch(b)
+	require.NotNil(t, start)
+	xref, _ := strconv.Atoi(string(start[1]))
+	assert.True(t, bytes.HasPrefix(b[xref:], []byte("xref")))
+	offsets := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllSubmatch(b, -1)
+	for i, off := range offsets {
+		o, _ := strconv.Atoi(string(off[1]))
+		assert.True(t, bytes.HasPrefix(b[o:], []byte(strconv.Itoa(i+1)+" 0 obj")), "object %d", i+1)
+	}
+
+	content := regexp.MustCompile(`(?s)stream\n(.*?)\nendstream`).FindSubmatch(b)
+	require.NotNil(t, content)
+	zr, err := zlib.NewReader(bytes.NewReader(content[1]))
+	require.NoError(t, err)
+	ops, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	assert.Contains(t, string(ops), "BT /F1 12 Tf 10 20 Td (R\xe4kning \\(1\\)) Tj ET")
+	assert.Contains(t, string(ops), "10 30 20 10 re")
+}
+
+func TestTextWidth(t *testing.T) {
+	assert.InDelta(t, 6.672, TextWidth(Helvetica, 12, "0"), 0.001)
+	assert.InDelta(t, 7.2, TextWidth(Courier, 12, "å"), 0.001)
+	assert.InDelta(t, 0, TextWidth(HelveticaBold, 12, ""), 0.001)
+}
@@ -0,0 +1,705 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Reader reads the objects of an existing document.
+type Reader struct {
+	data    []byte
+	xref    map[int]xrefEntry
+	trailer Dict
+	// startxref is the offset of the last cross-reference section.
+	startxref int64
+	// xrefStream is true if the last cross-reference section is a stream.
+	xrefStream bool
+	objStms    map[int]*objStm
+	// reading are the offsets of the objects being read, to detect streams
+	// with a length referencing themselves.
+	reading map[int64]bool
+}
+
+type xrefEntry struct {
+	compressed bool
+	offset     int64 // Or object number of the object stream.
+	index      int   // Index in the object stream.
+	gen        int
+}
+
+type objStm struct {
+	data    []byte
+	offsets map[int]int
+}
+
+// NewReader parses the cross-reference sections and trailer of the document.
+// Encrypted documents are not supported.
+func NewReader(data []byte) (*Reader, error) {
+	r := &Reader{data: data, xref: map[int]xrefEntry{}, objStms: map[int]*objStm{}, reading: map[int64]bool{}}
+
+	i := bytes.LastIndex(data, []byte("startxref"))
+	if i < 0 {
+		return nil, errors.New("pdf: startxref not found")
+	}
+	p := &parser{data: data, pos: i + len("startxref")}
+	start, ok := p.next().(int64)
+	if !ok {
+		return nil, errors.New("pdf: invalid startxref")
+	}
+	r.startxref = start
+
+	seen := map[int64]bool{}
+	for offset := start; ; {
+		if seen[offset] {
+			return nil, fmt.Errorf("pdf: invalid cross-reference offset %d", offset)
+		}
+		seen[offset] = true
+
+		trailer, isStream, err := r.readXref(offset)
+		if err != nil {
+			return nil, err
+		}
+		if r.trailer == nil {
+			r.trailer = trailer
+			r.xrefStream = isStream
+		}
+		if stm, ok := trailer["XRefStm"].(int64); ok {
+			if _, _, err := r.readXref(stm); err != nil {
+				return nil, err
+			}
+		}
+
+		prev, ok := trailer["Prev"].(int64)
+		if !ok {
+			break
+		}
+		offset = prev
+	}
+
+	if _, ok := r.trailer["Encrypt"]; ok {
+		return nil, errors.New("pdf: encrypted documents are not supported")
+	}
+
+	return r, nil
+}
+
+// Trailer returns the trailer dictionary of the last cross-reference section.
+func (r *Reader) Trailer() Dict {
+	return r.trailer
+}
+
+// Size returns the number of objects, as in the trailer.
+func (r *Reader) Size() int {
+	size, _ := r.trailer["Size"].(int64)
+
+	return int(size)
+}
+
+// readXref reads a cross-reference table or stream. Entries already read,
+// from a later section, take precedence.
+func (r *Reader) readXref(offset int64) (Dict, bool, error) {
+	if offset < 0 || offset >= int64(len(r.data)) {
+		return nil, false, fmt.Errorf("pdf: invalid cross-reference offset %d", offset)
+	}
+	p := &parser{data: r.data, pos: int(offset)}
+	if p.keyword("xref") {
+		trailer, err := r.readXrefTable(p)
+		return trailer, false, err
+	}
+
+	_, obj, err := r.readObjectAt(offset)
+	if err != nil {
+		return nil, false, err
+	}
+	s, ok := obj.(*Stream)
+	if !ok || s.Dict["Type"] != Name("XRef") {
+		return nil, false, fmt.Errorf("pdf: no cross-reference at offset %d", offset)
+	}
+
+	return s.Dict, true, r.readXrefStream(s)
+}
+
+func (r *Reader) readXrefTable(p *parser) (Dict, error) {
+	for {
+		if p.keyword("trailer") {
+			trailer, ok := p.next().(Dict)
+			if !ok {
+				return nil, errors.New("pdf: invalid trailer")
+			}
+			return trailer, nil
+		}
+
+		start, ok1 := p.next().(int64)
+		count, ok2 := p.next().(int64)
+		if !ok1 || !ok2 {
+			return nil, errors.New("pdf: invalid cross-reference table")
+		}
+		for i := 0; i < int(count); i++ {
+			offset, ok1 := p.next().(int64)
+			gen, ok2 := p.next().(int64)
+			typ := p.word()
+			if !ok1 || !ok2 || (typ != "n" && typ != "f") {
+				return nil, errors.New("pdf: invalid cross-reference entry")
+			}
+			num := int(start) + i
+			if _, ok := r.xref[num]; !ok && typ == "n" {
+				r.xref[num] = xrefEntry{offset: offset, gen: int(gen)}
+			}
+		}
+	}
+}
+
+func (r *Reader) readXrefStream(s *Stream) error {
+	data, err := r.decode(s)
+	if err != nil {
+		return err
+	}
+
+	w, err := r.ints(s.Dict["W"])
+	if err != nil || len(w) != 3 || w[0] < 0 || w[1] < 0 || w[2] < 0 || w[0]+w[1]+w[2] == 0 {
+		return errors.New("pdf: invalid cross-reference stream widths")
+	}
+	index := []int{0, 0}
+	if s.Dict["Index"] != nil {
+		if index, err = r.ints(s.Dict["Index"]); err != nil || len(index)%2 != 0 {
+			return errors.New("pdf: invalid cross-reference stream index")
+		}
+	} else {
+		size, _ := s.Dict["Size"].(int64)
+		index[1] = int(size)
+	}
+
+	field := func(b []byte, def int64) int64 {
+		if len(b) == 0 {
+			return def
+		}
+		var v int64
+		for _, c := range b {
+			v = v<<8 | int64(c)
+		}
+		return v
+	}
+
+	width := w[0] + w[1] + w[2]
+	pos := 0
+	for i := 0; i < len(index); i += 2 {
+		for num := index[i]; num < index[i]+index[i+1]; num++ {
+			if pos+width > len(data) {
+				return errors.New("pdf: truncated cross-reference stream")
+			}
+			e := data[pos : pos+width]
+			pos += width
+
+			typ := field(e[:w[0]], 1)
+			f2 := field(e[w[0]:w[0]+w[1]], 0)
+			f3 := field(e[w[0]+w[1]:], 0)
+			if _, ok := r.xref[num]; ok {
+				continue
+			}
+			switch typ {
+			case 1:
+				r.xref[num] = xrefEntry{offset: f2, gen: int(f3)}
+			case 2:
+				r.xref[num] = xrefEntry{compressed: true, offset: f2, index: int(f3)}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Object returns the object with the number.
+func (r *Reader) Object(num int) (interface{}, error) {
+	e, ok := r.xref[num]
+	if !ok {
+		return nil, nil
+	}
+	if e.compressed {
+		return r.compressedObject(int(e.offset), num)
+	}
+
+	gotNum, obj, err := r.readObjectAt(e.offset)
+	if err != nil {
+		return nil, err
+	}
+	if gotNum != num {
+		return nil, fmt.Errorf("pdf: object %d not found at offset %d", num, e.offset)
+	}
+
+	return obj, nil
+}
+
+// Gen returns the generation number of the object.
+func (r *Reader) Gen(num int) int {
+	return r.xref[num].gen
+}
+
+// Resolve returns the object referenced, or the object if it is not a
+// reference.
+func (r *Reader) Resolve(obj interface{}) (interface{}, error) {
+	for i := 0; i < 32; i++ {
+		ref, ok := obj.(Ref)
+		if !ok {
+			return obj, nil
+		}
+		var err error
+		if obj, err = r.Object(ref.Num); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, errors.New("pdf: reference loop")
+}
+
+func (r *Reader) readObjectAt(offset int64) (int, interface{}, error) {
+	if offset < 0 || offset >= int64(len(r.data)) || r.reading[offset] {
+		return 0, nil, fmt.Errorf("pdf: no object at offset %d", offset)
+	}
+	r.reading[offset] = true
+	defer delete(r.reading, offset)
+
+	p := &parser{data: r.data, pos: int(offset)}
+	num, ok1 := p.next().(int64)
+	_, ok2 := p.next().(int64)
+	if !ok1 || !ok2 || !p.keyword("obj") {
+		return 0, nil, fmt.Errorf("pdf: no object at offset %d", offset)
+	}
+
+	obj := p.next()
+	if d, ok := obj.(Dict); ok && p.keyword("stream") {
+		// The stream data starts after the end of line.
+		if p.pos < len(p.data) && p.data[p.pos] == '\r' {
+			p.pos++
+		}
+		if p.pos < len(p.data) && p.data[p.pos] == '\n' {
+			p.pos++
+		}
+
+		length, err := r.Resolve(d["Length"])
+		if err != nil {
+			return 0, nil, err
+		}
+		n, ok := length.(int64)
+		if !ok || n < 0 || n > int64(len(p.data)-p.pos) {
+			return 0, nil, fmt.Errorf("pdf: invalid stream length of object %d", num)
+		}
+		obj = &Stream{Dict: d, Data: p.data[p.pos : p.pos+int(n)]}
+	}
+	if p.err != nil {
+		return 0, nil, p.err
+	}
+
+	return int(num), obj, nil
+}
+
+func (r *Reader) compressedObject(stmNum, num int) (interface{}, error) {
+	stm, ok := r.objStms[stmNum]
+	if !ok {
+		if r.xref[stmNum].compressed {
+			return nil, fmt.Errorf("pdf: object stream %d is compressed", stmNum)
+		}
+		obj, err := r.Object(stmNum)
+		if err != nil {
+			return nil, err
+		}
+		s, ok := obj.(*Stream)
+		if !ok {
+			return nil, fmt.Errorf("pdf: object stream %d not found", stmNum)
+		}
+		data, err := r.decode(s)
+		if err != nil {
+			return nil, err
+		}
+
+		n, _ := s.Dict["N"].(int64)
+		first, _ := s.Dict["First"].(int64)
+		stm = &objStm{data: data, offsets: map[int]int{}}
+		p := &parser{data: data}
+		for i := 0; i < int(n); i++ {
+			objNum, ok1 := p.next().(int64)
+			offset, ok2 := p.next().(int64)
+			if !ok1 || !ok2 {
+				return nil, fmt.Errorf("pdf: invalid object stream %d", stmNum)
+			}
+			stm.offsets[int(objNum)] = int(first + offset)
+		}
+		r.objStms[stmNum] = stm
+	}
+
+	offset, ok := stm.offsets[num]
+	if !ok || offset < 0 || offset >= len(stm.data) {
+		return nil, fmt.Errorf("pdf: object %d not found in object stream %d", num, stmNum)
+	}
+	p := &parser{data: stm.data, pos: offset}
+	obj := p.next()
+
+	return obj, p.err
+}
+
+// decode returns the decoded data of a stream. Only FlateDecode, with or
+// without PNG predictors, is supported.
+func (r *Reader) decode(s *Stream) ([]byte, error) {
+	filter, err := r.Resolve(s.Dict["Filter"])
+	if err != nil {
+		return nil, err
+	}
+	if a, ok := filter.(Array); ok && len(a) == 1 {
+		filter = a[0]
+	}
+
+	switch filter {
+	case nil:
+		return s.Data, nil
+	case Name("FlateDecode"):
+	default:
+		return nil, fmt.Errorf("pdf: unsupported filter %v", filter)
+	}
+
+	zr, err := zlib.NewReader(bytes.NewReader(s.Data))
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+
+	parms, _ := s.Dict["DecodeParms"].(Dict)
+	if predictor, _ := parms["Predictor"].(int64); predictor >= 10 {
+		columns, ok := parms["Columns"].(int64)
+		if !ok {
+			columns = 1
+		}
+		if columns < 1 {
+			return nil, errors.New("pdf: invalid predictor columns")
+		}
+		return unpredict(data, int(columns))
+	}
+
+	return data, nil
+}
+
+// unpredict reverses PNG prediction of rows of columns bytes.
+func unpredict(data []byte, columns int) ([]byte, error) {
+	rowLen := columns + 1
+	if len(data)%rowLen != 0 {
+		return nil, errors.New("pdf: invalid predictor data")
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	out := make([]byte, 0, len(data)/rowLen*columns)
+	prev := make([]byte, columns)
+	for i := 0; i < len(data); i += rowLen {
+		typ, row := data[i], append([]byte(nil), data[i+1:i+rowLen]...)
+		for j := range row {
+			var left, upLeft byte
+			if j > 0 {
+				left, upLeft = row[j-1], prev[j-1]
+			}
+			up := prev[j]
+			switch typ {
+			case 1:
+				row[j] += left
+			case 2:
+				row[j] += up
+			case 3:
+				row[j] += byte((int(left) + int(up)) / 2)
+			case 4:
+				row[j] += paeth(left, up, upLeft)
+			}
+		}
+		out = append(out, row...)
+		prev = row
+	}
+
+	return out, nil
+}
+
+func paeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa, pb, pc := abs(p-int(a)), abs(p-int(b)), abs(p-int(c))
+	switch {
+	case pa <= pb && pa <= pc:
+		return a
+	case pb <= pc:
+		return b
+	}
+
+	return c
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+
+	return i
+}
+
+func (r *Reader) ints(obj interface{}) ([]int, error) {
+	obj, err := r.Resolve(obj)
+	if err != nil {
+		return nil, err
+	}
+	a, ok := obj.(Array)
+	if !ok {
+		return nil, errors.New("pdf: not an array")
+	}
+
+	ints := make([]int, len(a))
+	for i, o := range a {
+		n, ok := o.(int64)
+		if !ok {
+			return nil, errors.New("pdf: not an integer")
+		}
+		ints[i] = int(n)
+	}
+
+	return ints, nil
+}
+
+// parser parses objects from PDF syntax.
+type parser struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (p *parser) skip() {
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		if c == '%' {
+			for p.pos < len(p.data) && p.data[p.pos] != '\n' && p.data[p.pos] != '\r' {
+				p.pos++
+			}
+			continue
+		}
+		if !isWhitespace(c) {
+			return
+		}
+		p.pos++
+	}
+}
+
+// word returns the next regular characters.
+func (p *parser) word() string {
+	p.skip()
+	start := p.pos
+	for p.pos < len(p.data) && !isWhitespace(p.data[p.pos]) && !isDelimiter(p.data[p.pos]) {
+		p.pos++
+	}
+
+	return string(p.data[start:p.pos])
+}
+
+// keyword consumes the keyword if it is next.
+func (p *parser) keyword(kw string) bool {
+	pos := p.pos
+	if p.word() == kw {
+		return true
+	}
+	p.pos = pos
+
+	return false
+}
+
+func (p *parser) fail(format string, args ...interface{}) interface{} {
+	if p.err == nil {
+		p.err = fmt.Errorf("pdf: "+format, args...)
+	}
+	p.pos = len(p.data)
+
+	return nil
+}
+
+// next returns the next object. Integers followed by a generation number and
+// R are returned as references.
+func (p *parser) next() interface{} {
+	p.skip()
+	if p.pos >= len(p.data) {
+		return p.fail("unexpected end of data")
+	}
+
+	switch c := p.data[p.pos]; {
+	case c == '/':
+		p.pos++
+		return p.name()
+	case c == '(':
+		p.pos++
+		return p.literal()
+	case c == '<' && p.pos+1 < len(p.data) && p.data[p.pos+1] == '<':
+		p.pos += 2
+		return p.dict()
+	case c == '<':
+		p.pos++
+		return p.hex()
+	case c == '[':
+		p.pos++
+		var a Array
+		for {
+			p.skip()
+			if p.pos >= len(p.data) {
+				return p.fail("unterminated array")
+			}
+			if p.data[p.pos] == ']' {
+				p.pos++
+				return a
+			}
+			a = append(a, p.next())
+		}
+	}
+
+	w := p.word()
+	switch w {
+	case "":
+		p.pos++
+		return p.fail("unexpected character %q", p.data[p.pos-1])
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+
+	if n, err := strconv.ParseInt(w, 10, 64); err == nil {
+		// Check for a reference, n g R.
+		pos := p.pos
+		if g, err := strconv.ParseInt(p.word(), 10, 64); err == nil && p.word() == "R" {
+			return Ref{Num: int(n), Gen: int(g)}
+		}
+		p.pos = pos
+		return n
+	}
+	if f, err := strconv.ParseFloat(w, 64); err == nil {
+		return f
+	}
+
+	// Operators and keywords are returned as names, they are not used in
+	// the objects read.
+	return Name(w)
+}
+
+func (p *parser) name() Name {
+	var b []byte
+	for p.pos < len(p.data) && !isWhitespace(p.data[p.pos]) && !isDelimiter(p.data[p.pos]) {
+		c := p.data[p.pos]
+		if c == '#' && p.pos+2 < len(p.data) {
+			if v, err := strconv.ParseUint(string(p.data[p.pos+1:p.pos+3]), 16, 8); err == nil {
+				b = append(b, byte(v))
+				p.pos += 3
+				continue
+			}
+		}
+		b = append(b, c)
+		p.pos++
+	}
+
+	return Name(b)
+}
+
+func (p *parser) dict() interface{} {
+	d := Dict{}
+	for {
+		p.skip()
+		if p.pos+1 < len(p.data) && p.data[p.pos] == '>' && p.data[p.pos+1] == '>' {
+			p.pos += 2
+			return d
+		}
+		if p.pos >= len(p.data) || p.data[p.pos] != '/' {
+			return p.fail("invalid dictionary key")
+		}
+		p.pos++
+		key := p.name()
+		d[key] = p.next()
+		if p.err != nil {
+			return nil
+		}
+	}
+}
+
+func (p *parser) literal() interface{} {
+	var b []byte
+	depth := 1
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		p.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return String(b)
+			}
+		case '\\':
+			if p.pos >= len(p.data) {
+				break
+			}
+			e := p.data[p.pos]
+			p.pos++
+			switch e {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r', '\n':
+				if e == '\r' && p.pos < len(p.data) && p.data[p.pos] == '\n' {
+					p.pos++
+				}
+				continue
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && p.pos < len(p.data) && p.data[p.pos] >= '0' && p.data[p.pos] <= '7'; i++ {
+						v = v*8 + int(p.data[p.pos]-'0')
+						p.pos++
+					}
+					c = byte(v)
+				} else {
+					c = e
+				}
+			}
+		}
+		b = append(b, c)
+	}
+
+	return p.fail("unterminated string")
+}
+
+func (p *parser) hex() interface{} {
+	var digits []byte
+	for p.pos < len(p.data) && p.data[p.pos] != '>' {
+		if c := p.data[p.pos]; !isWhitespace(c) {
+			digits = append(digits, c)
+		}
+		p.pos++
+	}
+	p.pos++
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+
+	b := make([]byte, len(digits)/2)
+	for i := range b {
+		v, err := strconv.ParseUint(string(digits[2*i:2*i+2]), 16, 8)
+		if err != nil {
+			return p.fail("invalid hex string")
+		}
+		b[i] = byte(v)
+	}
+
+	return String(b)
+}
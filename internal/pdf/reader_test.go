@@ -0,0 +1,47 @@
+package pdf
+
+import (
+	"io"
+	"testing"
+)
+
+// corruptSeeds are documents to corrupt, with both kinds of cross-reference
+// sections.
+func corruptSeeds(t testing.TB) [][]byte {
+	d := New()
+	d.AddPage(A4Width, A4Height).Text(10, 10, Helvetica, 12, "Page 1")
+
+	return [][]byte{d.Bytes(), xrefStreamDocument(t)}
+}
+
+// overlayCorrupt overlays the corrupted document, which must return an
+// error or a document instead of panicking or looping.
+func overlayCorrupt(data []byte) {
+	_ = Overlay(data, io.Discard, 1, func(p *Page) {
+		p.Rect(1, 2, 3, 4, true, 0)
+	})
+}
+
+func TestOverlayCorrupt(t *testing.T) {
+	replacements := []string{"-", "9", "0", " ", "<", "/"}
+	for _, seed := range corruptSeeds(t) {
+		for i := range seed {
+			for _, r := range replacements {
+				data := append([]byte(nil), seed...)
+				data[i] = r[0]
+				overlayCorrupt(data)
+			}
+			overlayCorrupt(seed[:i])
+		}
+	}
+}
+
+func FuzzOverlay(f *testing.F) {
+	for _, seed := range corruptSeeds(f) {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		overlayCorrupt(data)
+	})
+}
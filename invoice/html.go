@@ -0,0 +1,159 @@
+//go:build !payqr_payloadonly
+
+package invoice
+
+import (
+	"encoding/base64"
+	"html/template"
+	"io"
+
+	"github.com/antonlindstrom/payqr"
+)
+
+// QRImageSize is the size in pixels of the QR code image in HTML output.
+const QRImageSize = 256
+
+// QRDataURI returns the QR code of the payment as a PNG data URI, to be used
+// as the src of an img element.
+func QRDataURI(p *payqr.Payment, size int) (template.URL, error) {
+	q, err := p.QR()
+	if err != nil {
+		return "", err
+	}
+
+	b, err := q.PNG(size)
+	if err != nil {
+		return "", err
+	}
+
+	return template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(b)), nil
+}
+
+// FuncMap returns the functions available to HTML templates: amount and
+// quantity format numbers, date formats a time as yyyy-mm-dd and label
+// returns the name of a payment type.
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"amount":   formatAmount,
+		"quantity": formatQuantity,
+		"date":     formatDate,
+		"label":    accountLabel,
+	}
+}
+
+// TemplateData is the data HTML templates are executed with.
+type TemplateData struct {
+	*Invoice
+
+	VATAmounts []VATAmount
+	Net        float64
+	VATTotal   float64
+	Total      float64
+	Currency   string
+
+	// QR is the payment QR code as a PNG data URI.
+	QR template.URL
+}
+
+// NewTemplateData returns the data to execute an HTML template with for the
+// invoice.
+func NewTemplateData(inv *Invoice) (*TemplateData, error) {
+	if err := inv.Validate(); err != nil {
+		return nil, err
+	}
+
+	qr, err := QRDataURI(inv.Payment, QRImageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &TemplateData{
+		Invoice:    inv,
+		VATAmounts: inv.VAT(),
+		Currency:   inv.currency(),
+		QR:         qr,
+	}
+	data.Net, data.VATTotal, data.Total = inv.Totals()
+
+	return data, nil
+}
+
+// HTML renders the invoice with DefaultTemplate, a responsive summary with
+// inline styles and the QR code as inline image, suitable as an email body.
+func (inv *Invoice) HTML(w io.Writer) error {
+	return inv.ExecuteTemplate(w, DefaultTemplate)
+}
+
+// ExecuteTemplate renders the invoice with the template, which is executed
+// with *TemplateData. Templates should be created with FuncMap.
+func (inv *Invoice) ExecuteTemplate(w io.Writer, t *template.Template) error {
+	data, err := NewTemplateData(inv)
+	if err != nil {
+		return err
+	}
+
+	return t.Execute(w, data)
+}
+
+// DefaultTemplate is the template used by HTML. Email clients ignore style
+// sheets in the head to a varying degree, the layout is therefore made with
+// tables and inline styles, and only narrows the layout with a media query.
+var DefaultTemplate = template.Must(template.New("invoice").Funcs(FuncMap()).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Invoice {{.Number}}</title>
+<style>
+@media (max-width: 480px) {
+  .column { display: block !important; width: 100% !important; }
+}
+</style>
+</head>
+<body style="margin: 0; padding: 0; background: #f4f4f4; font-family: Helvetica, Arial, sans-serif; color: #222;">
+<table role="presentation" width="100%" cellpadding="0" cellspacing="0" style="background: #f4f4f4;">
+<tr><td align="center" style="padding: 16px;">
+<table role="presentation" width="100%" cellpadding="0" cellspacing="0" style="max-width: 600px; background: #fff; border-radius: 4px;">
+<tr><td style="padding: 24px;">
+<h1 style="margin: 0 0 4px; font-size: 22px;">{{.Seller.Name}}</h1>
+<p style="margin: 0; color: #666; font-size: 14px;">Invoice {{.Number}}{{with date .Date}}, {{.}}{{end}}</p>
+</td></tr>
+<tr><td style="padding: 0 24px;">
+<table role="presentation" width="100%" cellpadding="0" cellspacing="0" style="font-size: 14px;">
+<tr>
+<th align="left" style="padding: 8px 0; border-bottom: 1px solid #ddd;">Description</th>
+<th align="right" style="padding: 8px 0; border-bottom: 1px solid #ddd;">Amount</th>
+</tr>
+{{range .Items}}<tr>
+<td style="padding: 6px 0; border-bottom: 1px solid #eee;">{{.Description}} <span style="color: #666;">{{quantity .Quantity}}{{with .Unit}} {{.}}{{end}} &times; {{amount .UnitPrice}}</span></td>
+<td align="right" style="padding: 6px 0; border-bottom: 1px solid #eee;">{{amount .Net}}</td>
+</tr>
+{{end}}<tr><td style="padding: 6px 0;">Net</td><td align="right" style="padding: 6px 0;">{{amount .Net}}</td></tr>
+{{range .VATAmounts}}<tr><td style="padding: 2px 0;">VAT {{quantity .Rate}}%</td><td align="right" style="padding: 2px 0;">{{amount .VAT}}</td></tr>
+{{end}}<tr><td style="padding: 8px 0; font-weight: bold; font-size: 16px;">Total {{.Currency}}</td><td align="right" style="padding: 8px 0; font-weight: bold; font-size: 16px;">{{amount .Total}}</td></tr>
+</table>
+</td></tr>
+<tr><td style="padding: 24px;">
+<table role="presentation" width="100%" cellpadding="0" cellspacing="0" style="background: #fafafa; border: 1px solid #ddd; font-size: 14px;">
+<tr>
+<td class="column" width="60%" valign="top" style="padding: 16px;">
+<p style="margin: 0 0 8px; font-weight: bold;">Payment</p>
+{{with .Payment}}<p style="margin: 0 0 4px;">{{label .PaymentType}}: <strong>{{.AccountNumber}}</strong></p>
+{{with .Reference}}<p style="margin: 0 0 4px;">OCR: <strong>{{.}}</strong></p>
+{{end}}<p style="margin: 0 0 4px;">Amount: <strong>{{amount .DueAmount}} {{$.Currency}}</strong></p>
+{{end}}{{with date .DueDate}}<p style="margin: 0;">Due date: <strong>{{.}}</strong></p>
+{{end}}</td>
+<td class="column" width="40%" align="center" valign="top" style="padding: 16px;">
+<img src="{{.QR}}" width="160" height="160" alt="Payment QR code" style="display: block; width: 160px; height: 160px;">
+<p style="margin: 4px 0 0; color: #666; font-size: 12px;">Scan to pay</p>
+</td>
+</tr>
+</table>
+{{with .Note}}<p style="margin: 16px 0 0; font-size: 14px;">{{.}}</p>
+{{end}}</td></tr>
+</table>
+</td></tr>
+</table>
+</body>
+</html>
+`))
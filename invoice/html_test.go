@@ -0,0 +1,39 @@
+//go:build !payqr_payloadonly
+
+package invoice
+
+import (
+	"bytes"
+	"html/template"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTML(t *testing.T) {
+	inv := testInvoice(2)
+	inv.Note = "Thanks & welcome back"
+
+	var buf bytes.Buffer
+	require.NoError(t, inv.HTML(&buf))
+
+	out := buf.String()
+	assert.Contains(t, out, `<img src="data:image/png;base64,iVBOR`)
+	assert.Contains(t, out, "Consulting 2")
+	assert.Contains(t, out, "Total SEK")
+	assert.Contains(t, out, "2606.00")
+	assert.Contains(t, out, "OCR: <strong>52250047151</strong>")
+	assert.Contains(t, out, "Thanks &amp; welcome back")
+
+	inv.Payment = nil
+	assert.Error(t, inv.HTML(&buf))
+}
+
+func TestExecuteTemplate(t *testing.T) {
+	tmpl := template.Must(template.New("").Funcs(FuncMap()).Parse(`{{.Number}} {{amount .Total}} {{.Currency}} {{date .DueDate}} {{label .Payment.PaymentType}}`))
+
+	var buf bytes.Buffer
+	require.NoError(t, testInvoice(1).ExecuteTemplate(&buf, tmpl))
+	assert.Equal(t, "1001 1356.00 SEK 2022-08-06 Bankgiro", buf.String())
+}
@@ -0,0 +1,122 @@
+// Package invoice renders complete invoices, with seller, buyer, line items,
+// totals and the payment QR code, as A4 PDF documents or HTML emails.
+package invoice
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+)
+
+// Party is the seller or buyer of an invoice.
+type Party struct {
+	Name               string
+	Address            []string
+	OrganizationNumber string
+	VATNumber          string
+}
+
+// Item is a line item of an invoice.
+type Item struct {
+	Description string
+	Quantity    float64
+	Unit        string
+	UnitPrice   float64 // Excluding VAT.
+	VATRate     float64 // In percent, such as 25.
+}
+
+// Net returns the amount of the item excluding VAT, rounded to cents.
+func (i Item) Net() float64 {
+	return round(i.Quantity * i.UnitPrice)
+}
+
+// Invoice is an invoice with the payment it is paid by.
+type Invoice struct {
+	Number   string
+	Date     time.Time
+	DueDate  time.Time
+	Seller   Party
+	Buyer    Party
+	Items    []Item
+	Currency string // Default is the currency of the payment, or SEK.
+	Note     string
+
+	// Payment is the payment of the invoice, its reference is printed as
+	// OCR number and its amount must match the total.
+	Payment *payqr.Payment
+}
+
+// VATAmount is the VAT of an invoice for one rate.
+type VATAmount struct {
+	Rate float64
+	Net  float64
+	VAT  float64
+}
+
+// VAT returns the VAT per rate, in order of rate.
+func (inv *Invoice) VAT() []VATAmount {
+	byRate := map[float64]float64{}
+	for _, item := range inv.Items {
+		byRate[item.VATRate] += item.Net()
+	}
+
+	amounts := make([]VATAmount, 0, len(byRate))
+	for rate, net := range byRate {
+		amounts = append(amounts, VATAmount{Rate: rate, Net: round(net), VAT: round(net * rate / 100)})
+	}
+	sort.Slice(amounts, func(i, j int) bool { return amounts[i].Rate < amounts[j].Rate })
+
+	return amounts
+}
+
+// Totals returns the amount excluding VAT, the VAT and the total.
+func (inv *Invoice) Totals() (net, vat, total float64) {
+	for _, a := range inv.VAT() {
+		net += a.Net
+		vat += a.VAT
+	}
+
+	return round(net), round(vat), round(net + vat)
+}
+
+// currency returns the currency of the invoice.
+func (inv *Invoice) currency() string {
+	switch {
+	case inv.Currency != "":
+		return inv.Currency
+	case inv.Payment != nil && inv.Payment.Currency != "":
+		return inv.Payment.Currency
+	}
+
+	return "SEK"
+}
+
+// Validate checks that the invoice has a number, seller, buyer, items and a
+// payment for the total.
+func (inv *Invoice) Validate() error {
+	if inv.Number == "" {
+		return errors.New("invoice: number is required")
+	}
+	if inv.Seller.Name == "" || inv.Buyer.Name == "" {
+		return errors.New("invoice: seller and buyer names are required")
+	}
+	if len(inv.Items) == 0 {
+		return errors.New("invoice: no items")
+	}
+	if inv.Payment == nil {
+		return errors.New("invoice: payment is required")
+	}
+	if _, _, total := inv.Totals(); math.Abs(total-inv.Payment.DueAmount) >= 0.005 {
+		return fmt.Errorf("invoice: payment amount %.2f does not match total %.2f", inv.Payment.DueAmount, total)
+	}
+
+	return nil
+}
+
+func round(f float64) float64 {
+	return math.Round(f*100) / 100
+}
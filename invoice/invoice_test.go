@@ -0,0 +1,58 @@
+package invoice
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+)
+
+func testInvoice(items int) *Invoice {
+	date := time.Date(2022, time.July, 7, 0, 0, 0, 0, time.UTC)
+	due := date.AddDate(0, 0, 30)
+
+	inv := &Invoice{
+		Number:  "1001",
+		Date:    date,
+		DueDate: due,
+		Seller:  Party{Name: "Test AB", Address: []string{"Storgatan 1", "111 11 Stockholm"}, OrganizationNumber: "555555-5555", VATNumber: "SE555555555501"},
+		Buyer:   Party{Name: "Kund AB", Address: []string{"Lillgatan 2", "222 22 Göteborg"}},
+	}
+	for i := 0; i < items; i++ {
+		inv.Items = append(inv.Items, Item{Description: fmt.Sprintf("Consulting %d", i+1), Quantity: 2, Unit: "h", UnitPrice: 500, VATRate: 25})
+	}
+	inv.Items = append(inv.Items, Item{Description: "Book", Quantity: 1, UnitPrice: 100, VATRate: 6})
+
+	_, _, total := inv.Totals()
+	inv.Payment = payqr.New("5402-9681", "Test AB", "555555-5555", "52250047151", total, due)
+
+	return inv
+}
+
+func TestTotals(t *testing.T) {
+	inv := testInvoice(2)
+
+	net, vat, total := inv.Totals()
+	assert.Equal(t, 2100.0, net)
+	assert.Equal(t, 506.0, vat)
+	assert.Equal(t, 2606.0, total)
+	assert.Equal(t, []VATAmount{{Rate: 6, Net: 100, VAT: 6}, {Rate: 25, Net: 2000, VAT: 500}}, inv.VAT())
+}
+
+func TestValidate(t *testing.T) {
+	inv := testInvoice(1)
+	assert.NoError(t, inv.Validate())
+
+	inv.Payment.DueAmount = 1
+	assert.Error(t, inv.Validate())
+
+	inv = testInvoice(1)
+	inv.Number = ""
+	assert.Error(t, inv.Validate())
+
+	inv = testInvoice(1)
+	inv.Payment = nil
+	assert.Error(t, inv.Validate())
+}
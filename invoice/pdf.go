@@ -0,0 +1,235 @@
+//go:build !payqr_payloadonly
+
+package invoice
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/antonlindstrom/payqr/internal/pdf"
+)
+
+func init() {
+	payqr.RegisterCapability(payqr.CapabilityPDF)
+}
+
+// Placement of the payment QR code on the last page, in millimeters from the
+// top left corner. The code is placed in the payment box at the recommended
+// placement, see payqr.RecommendedPlacement, with a quiet zone of its own.
+const (
+	QRLeft = payqr.A4QRLeft
+	QRTop  = payqr.A4QRTop
+	QRSize = payqr.A4QRSize
+)
+
+// Layout of the pages, in millimeters from the top left corner.
+const (
+	marginLeft   = 20
+	marginRight  = 190
+	tableTop     = 95
+	tableBottom  = 205
+	continuedTop = 30
+	rowHeight    = 6
+)
+
+// columns are the right edges of the item table columns, except description.
+var columns = [...]float64{120, 135, 160, marginRight}
+
+// PDF renders the invoice as an A4 PDF document. Items that do not fit on
+// the first page continue on the following pages, the totals and payment are
+// printed on the last page.
+func (inv *Invoice) PDF(w io.Writer) error {
+	if err := inv.Validate(); err != nil {
+		return err
+	}
+
+	q, err := inv.Payment.QR()
+	if err != nil {
+		return err
+	}
+	q.DisableBorder = true
+
+	doc := pdf.New()
+	page := newPage(doc)
+	inv.header(page)
+
+	y := inv.tableHeader(page, tableTop)
+	for _, item := range inv.Items {
+		if y > tableBottom {
+			page = newPage(doc)
+			y = inv.tableHeader(page, continuedTop)
+		}
+		page.text(marginLeft, y, pdf.Helvetica, 9, item.Description)
+		page.right(columns[0], y, pdf.Helvetica, 9, strings.TrimSpace(formatQuantity(item.Quantity)+" "+item.Unit))
+		page.right(columns[1], y, pdf.Helvetica, 9, formatQuantity(item.VATRate)+"%")
+		page.right(columns[2], y, pdf.Helvetica, 9, formatAmount(item.UnitPrice))
+		page.right(columns[3], y, pdf.Helvetica, 9, formatAmount(item.Net()))
+		y += rowHeight
+	}
+
+	// The totals need room above the payment box.
+	if y > tableBottom {
+		page = newPage(doc)
+		y = continuedTop
+	}
+	inv.totals(page, y)
+	inv.payment(page)
+	page.modules(QRLeft, QRTop, QRSize, q.Bitmap())
+
+	_, err = doc.WriteTo(w)
+
+	return err
+}
+
+func (inv *Invoice) header(p *page) {
+	p.text(marginLeft, 25, pdf.HelveticaBold, 16, inv.Seller.Name)
+	for i, line := range inv.Seller.Address {
+		p.text(marginLeft, 31+float64(i)*5, pdf.Helvetica, 9, line)
+	}
+
+	p.right(marginRight, 25, pdf.HelveticaBold, 20, "Invoice")
+	details := [][2]string{
+		{"Invoice number", inv.Number},
+		{"Invoice date", formatDate(inv.Date)},
+		{"Due date", formatDate(inv.DueDate)},
+	}
+	for i, d := range details {
+		p.text(130, 33+float64(i)*5, pdf.Helvetica, 9, d[0])
+		p.right(marginRight, 33+float64(i)*5, pdf.Helvetica, 9, d[1])
+	}
+
+	p.text(110, 60, pdf.HelveticaBold, 9, "Bill to")
+	p.text(110, 66, pdf.Helvetica, 10, inv.Buyer.Name)
+	for i, line := range inv.Buyer.Address {
+		p.text(110, 71+float64(i)*5, pdf.Helvetica, 10, line)
+	}
+	if inv.Buyer.VATNumber != "" {
+		p.text(110, 71+float64(len(inv.Buyer.Address))*5, pdf.Helvetica, 9, "VAT "+inv.Buyer.VATNumber)
+	}
+
+	var footer []string
+	if inv.Seller.OrganizationNumber != "" {
+		footer = append(footer, "Org. number "+inv.Seller.OrganizationNumber)
+	}
+	if inv.Seller.VATNumber != "" {
+		footer = append(footer, "VAT "+inv.Seller.VATNumber)
+	}
+	p.text(marginLeft, 285, pdf.Helvetica, 8, strings.Join(footer, "   "))
+}
+
+func (inv *Invoice) tableHeader(p *page, y float64) float64 {
+	p.text(marginLeft, y, pdf.HelveticaBold, 9, "Description")
+	for i, label := range []string{"Quantity", "VAT", "Unit price", "Amount"} {
+		p.right(columns[i], y, pdf.HelveticaBold, 9, label)
+	}
+	p.line(marginLeft, y+2, marginRight, y+2)
+
+	return y + rowHeight + 1
+}
+
+func (inv *Invoice) totals(p *page, y float64) {
+	p.line(110, y-3, marginRight, y-3)
+
+	net, _, total := inv.Totals()
+	rows := [][2]string{{"Net", formatAmount(net)}}
+	for _, a := range inv.VAT() {
+		rows = append(rows, [2]string{fmt.Sprintf("VAT %s%%", formatQuantity(a.Rate)), formatAmount(a.VAT)})
+	}
+
+	for _, r := range rows {
+		p.text(130, y+1, pdf.Helvetica, 9, r[0])
+		p.right(marginRight, y+1, pdf.Helvetica, 9, r[1])
+		y += 5
+	}
+	p.text(130, y+2, pdf.HelveticaBold, 11, "Total "+inv.currency())
+	p.right(marginRight, y+2, pdf.HelveticaBold, 11, formatAmount(total))
+
+	if inv.Note != "" {
+		p.text(marginLeft, y+12, pdf.Helvetica, 9, inv.Note)
+	}
+}
+
+func (inv *Invoice) payment(p *page) {
+	p.rect(marginLeft-5, QRTop-8, marginRight-marginLeft+10, QRSize+14)
+	p.text(marginLeft, QRTop-2, pdf.HelveticaBold, 10, "Payment")
+
+	rows := [][2]string{
+		{accountLabel(inv.Payment.PaymentType), inv.Payment.AccountNumber},
+		{"OCR", inv.Payment.Reference},
+		{"Amount", formatAmount(inv.Payment.DueAmount) + " " + inv.currency()},
+		{"Due date", formatDate(inv.DueDate)},
+	}
+	for i, r := range rows {
+		y := QRTop + 5 + float64(i)*6
+		p.text(marginLeft, y, pdf.Helvetica, 9, r[0])
+		p.text(marginLeft+30, y, pdf.HelveticaBold, 10, r[1])
+	}
+	p.text(QRLeft-35, QRTop+QRSize-1, pdf.Helvetica, 7, "Scan to pay")
+}
+
+func accountLabel(typ payqr.PaymentType) string {
+	switch typ {
+	case payqr.PaymentTypeBG:
+		return "Bankgiro"
+	case payqr.PaymentTypePG:
+		return "Plusgiro"
+	case payqr.PaymentTypeIBAN:
+		return "IBAN"
+	}
+
+	return "Account"
+}
+
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format("2006-01-02")
+}
+
+func formatQuantity(q float64) string {
+	return strconv.FormatFloat(q, 'f', -1, 64)
+}
+
+// page wraps a PDF page with coordinates in millimeters from the top left
+// corner.
+type page struct {
+	*pdf.Page
+}
+
+func newPage(doc *pdf.Document) *page {
+	return &page{doc.AddPage(pdf.A4Width, pdf.A4Height)}
+}
+
+func (p *page) y(top float64) float64 {
+	return p.Height - top*pdf.MM
+}
+
+func (p *page) text(left, top float64, font pdf.Font, size float64, s string) {
+	p.Text(left*pdf.MM, p.y(top), font, size, s)
+}
+
+func (p *page) right(right, top float64, font pdf.Font, size float64, s string) {
+	p.TextRight(right*pdf.MM, p.y(top), font, size, s)
+}
+
+func (p *page) line(x1, y1, x2, y2 float64) {
+	p.Line(x1*pdf.MM, p.y(y1), x2*pdf.MM, p.y(y2), 0.5)
+}
+
+func (p *page) rect(left, top, width, height float64) {
+	p.Rect(left*pdf.MM, p.y(top+height), width*pdf.MM, height*pdf.MM, false, 0.5)
+}
+
+func (p *page) modules(left, top, size float64, bitmap [][]bool) {
+	p.Modules(left*pdf.MM, p.y(top+size), size*pdf.MM, bitmap)
+}
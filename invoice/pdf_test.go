@@ -0,0 +1,34 @@
+//go:build !payqr_payloadonly
+
+package invoice
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPDF(t *testing.T) {
+	tests := []struct {
+		name  string
+		items int
+		pages int
+	}{
+		{name: "One page", items: 3, pages: 1},
+		{name: "Continued", items: 25, pages: 2},
+		{name: "Continued twice", items: 60, pages: 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, testInvoice(test.items).PDF(&buf))
+
+			assert.True(t, bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")))
+			assert.True(t, bytes.Contains(buf.Bytes(), []byte(fmt.Sprintf("/Count %d", test.pages))))
+		})
+	}
+}
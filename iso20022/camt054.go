@@ -0,0 +1,213 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antonlindstrom/payqr/reconcile"
+)
+
+type camtAmount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type camtDate struct {
+	Dt   string `xml:"Dt"`
+	DtTm string `xml:"DtTm"`
+}
+
+func (d camtDate) time() time.Time {
+	s := d.Dt
+	if s == "" {
+		s = d.DtTm
+	}
+	t, _ := parseDate(s)
+
+	return t
+}
+
+// camt054 is the part of a camt.054 bank to customer debit credit
+// notification used, in any version.
+type camt054 struct {
+	XMLName       xml.Name `xml:"Document"`
+	Notifications []struct {
+		ID      string `xml:"Id"`
+		Account struct {
+			IBAN  string `xml:"Id>IBAN"`
+			Other string `xml:"Id>Othr>Id"`
+		} `xml:"Acct"`
+		Entries []struct {
+			Amount    camtAmount `xml:"Amt"`
+			Indicator string     `xml:"CdtDbtInd"`
+			Status    struct {
+				Value string `xml:",chardata"`
+				Code  string `xml:"Cd"`
+			} `xml:"Sts"`
+			BookingDate camtDate `xml:"BookgDt"`
+			ValueDate   camtDate `xml:"ValDt"`
+			Reference   string   `xml:"AcctSvcrRef"`
+			Details     []struct {
+				Transactions []struct {
+					Amount     *camtAmount `xml:"Amt"`
+					TxAmount   *camtAmount `xml:"AmtDtls>TxAmt>Amt"`
+					Indicator  string      `xml:"CdtDbtInd"`
+					EndToEndID string      `xml:"Refs>EndToEndId"`
+					Debtor     string      `xml:"RltdPties>Dbtr>Nm"`
+					DebtorV8   string      `xml:"RltdPties>Dbtr>Pty>Nm"`
+					Remittance struct {
+						Unstructured []string `xml:"Ustrd"`
+						Reference    []string `xml:"Strd>CdtrRefInf>Ref"`
+					} `xml:"RmtInf"`
+				} `xml:"TxDtls"`
+			} `xml:"NtryDtls"`
+		} `xml:"Ntry"`
+	} `xml:"BkToCstmrDbtCdtNtfctn>Ntfctn"`
+}
+
+// Transaction is a booked transaction of a camt.054 notification.
+type Transaction struct {
+	// Account is the notified account, as IBAN or other identification.
+	Account     string
+	BookingDate time.Time
+	ValueDate   time.Time
+	Amount      float64 // Negative for debits.
+	Currency    string
+
+	// Reference is the structured creditor reference, or else the
+	// unstructured remittance information.
+	Reference   string
+	Information []string
+	EndToEndID  string
+	Debtor      string
+}
+
+// ReadCamt054 reads the booked transactions of a camt.054 bank to customer
+// debit credit notification, in the order of the file. Entries with
+// transaction details give one transaction per detail, others one for the
+// entry. Pending entries are skipped.
+func ReadCamt054(r io.Reader) ([]*Transaction, error) {
+	var doc camt054
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("iso20022: %w", err)
+	}
+	if len(doc.Notifications) == 0 {
+		return nil, errors.New("iso20022: not a camt.054 debit credit notification")
+	}
+
+	var txs []*Transaction
+	for _, n := range doc.Notifications {
+		account := n.Account.IBAN
+		if account == "" {
+			account = n.Account.Other
+		}
+
+		for _, e := range n.Entries {
+			if status := strings.TrimSpace(e.Status.Value) + e.Status.Code; status != "" && status != "BOOK" {
+				continue
+			}
+
+			entry := &Transaction{
+				Account:     account,
+				BookingDate: e.BookingDate.time(),
+				ValueDate:   e.ValueDate.time(),
+				Currency:    e.Amount.Currency,
+			}
+
+			var details int
+			for _, d := range e.Details {
+				for _, tx := range d.Transactions {
+					t := *entry
+
+					amount, indicator := tx.Amount, tx.Indicator
+					if amount == nil {
+						amount = tx.TxAmount
+					}
+					if amount == nil {
+						amount = &e.Amount
+					}
+					if indicator == "" {
+						indicator = e.Indicator
+					}
+
+					var err error
+					if t.Amount, err = signedAmount(*amount, indicator); err != nil {
+						return nil, fmt.Errorf("iso20022: notification %s: %w", n.ID, err)
+					}
+					if amount.Currency != "" {
+						t.Currency = amount.Currency
+					}
+
+					t.Information = tx.Remittance.Unstructured
+					t.Reference = strings.Join(tx.Remittance.Reference, " ")
+					if t.Reference == "" {
+						t.Reference = strings.Join(tx.Remittance.Unstructured, " ")
+					}
+					t.EndToEndID = tx.EndToEndID
+					if t.EndToEndID == "NOTPROVIDED" {
+						t.EndToEndID = ""
+					}
+					t.Debtor = tx.Debtor + tx.DebtorV8
+
+					txs = append(txs, &t)
+					details++
+				}
+			}
+
+			if details == 0 {
+				var err error
+				if entry.Amount, err = signedAmount(e.Amount, e.Indicator); err != nil {
+					return nil, fmt.Errorf("iso20022: notification %s: %w", n.ID, err)
+				}
+				entry.Reference = e.Reference
+				txs = append(txs, entry)
+			}
+		}
+	}
+
+	return txs, nil
+}
+
+func signedAmount(a camtAmount, indicator string) (float64, error) {
+	amount, err := strconv.ParseFloat(strings.TrimSpace(a.Value), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", a.Value)
+	}
+
+	switch indicator {
+	case "CRDT":
+		return amount, nil
+	case "DBIT":
+		return -amount, nil
+	}
+
+	return 0, fmt.Errorf("invalid credit debit indicator %q", indicator)
+}
+
+// Entries returns the credited transactions as entries for reconciliation,
+// with the *Transaction as source. The entries have no account, as the
+// notified account is the bank account behind the account the payments were
+// made to, and are matched by reference only.
+func Entries(txs []*Transaction) []reconcile.Entry {
+	var entries []reconcile.Entry
+	for _, t := range txs {
+		if t.Amount <= 0 {
+			continue
+		}
+
+		entries = append(entries, reconcile.Entry{
+			Date:      t.BookingDate,
+			Reference: t.Reference,
+			Amount:    t.Amount,
+			Currency:  t.Currency,
+			Source:    t,
+		})
+	}
+
+	return entries
+}
@@ -0,0 +1,141 @@
+package iso20022
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/antonlindstrom/payqr/reconcile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCamt054(t *testing.T) {
+	date := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		file string
+		want []*Transaction
+	}{
+		{
+			name: "Version 2",
+			file: "testdata/camt.054.001.02.xml",
+			want: []*Transaction{
+				{
+					Account:     "SE4550000000058398257466",
+					BookingDate: date,
+					ValueDate:   date,
+					Amount:      1000,
+					Currency:    "SEK",
+					Reference:   "52250047157",
+					Debtor:      "Kund Ett AB",
+				},
+				{
+					Account:     "SE4550000000058398257466",
+					BookingDate: date,
+					ValueDate:   date,
+					Amount:      350,
+					Currency:    "SEK",
+					Reference:   "99999999999",
+					Information: []string{"99999999999"},
+					EndToEndID:  "E2E-2",
+					Debtor:      "Kund Två AB",
+				},
+				{
+					Account:     "SE4550000000058398257466",
+					BookingDate: date,
+					ValueDate:   date,
+					Amount:      -25,
+					Currency:    "SEK",
+					Reference:   "FEE-20220806",
+				},
+			},
+		},
+		{
+			name: "Version 8",
+			file: "testdata/camt.054.001.08.xml",
+			want: []*Transaction{
+				{
+					Account:     "SE4550000000058398257466",
+					BookingDate: date.AddDate(0, 0, 2),
+					ValueDate:   date.AddDate(0, 0, 2),
+					Amount:      250,
+					Currency:    "SEK",
+					Reference:   "5225 0047 157",
+					EndToEndID:  "E2E-3",
+					Debtor:      "Kund Ett AB",
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f, err := os.Open(test.file)
+			require.NoError(t, err)
+			defer f.Close()
+
+			txs, err := ReadCamt054(f)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, txs)
+		})
+	}
+}
+
+func TestReadCamt054Error(t *testing.T) {
+	tests := []struct {
+		name string
+		have string
+	}{
+		{name: "Not XML", have: "camt"},
+		{name: "Other message", have: `<Document><CstmrCdtTrfInitn/></Document>`},
+		{
+			name: "Invalid amount",
+			have: `<Document><BkToCstmrDbtCdtNtfctn><Ntfctn><Ntry><Amt Ccy="SEK">1,00</Amt><CdtDbtInd>CRDT</CdtDbtInd></Ntry></Ntfctn></BkToCstmrDbtCdtNtfctn></Document>`,
+		},
+		{
+			name: "Invalid indicator",
+			have: `<Document><BkToCstmrDbtCdtNtfctn><Ntfctn><Ntry><Amt Ccy="SEK">1.00</Amt></Ntry></Ntfctn></BkToCstmrDbtCdtNtfctn></Document>`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ReadCamt054(strings.NewReader(test.have))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestReconcileCamt054(t *testing.T) {
+	var txs []*Transaction
+	for _, file := range []string{"testdata/camt.054.001.02.xml", "testdata/camt.054.001.08.xml"} {
+		f, err := os.Open(file)
+		require.NoError(t, err)
+		defer f.Close()
+
+		read, err := ReadCamt054(f)
+		require.NoError(t, err)
+		txs = append(txs, read...)
+	}
+
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+	invoices := []*payqr.Payment{
+		payqr.New("5402-9681", "Test AB", "", "52250047157", 1250, due, payqr.WithPaymentType(payqr.PaymentTypeBG)),
+		payqr.New("5402-9681", "Test AB", "", "4455", 100, due, payqr.WithPaymentType(payqr.PaymentTypeBG)),
+	}
+
+	res := reconcile.Reconcile(invoices, Entries(txs))
+	require.Len(t, res.Matches, 2)
+	assert.Equal(t, reconcile.StatusPaid, res.Matches[0].Status)
+	assert.Equal(t, 1250.0, res.Matches[0].Received)
+	require.Len(t, res.Matches[0].Entries, 2)
+	assert.Equal(t, txs[3], res.Matches[0].Entries[1].Source)
+	assert.Equal(t, reconcile.StatusUnpaid, res.Matches[1].Status)
+
+	require.Len(t, res.Unmatched, 1)
+	assert.Equal(t, "99999999999", res.Unmatched[0].Reference)
+}
@@ -0,0 +1,158 @@
+// Package iso20022 reads ISO 20022 payment messages into payments, and bank
+// to customer notifications into transactions for reconciliation.
+package iso20022
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+)
+
+// pain001 is the part of a pain.001 customer credit transfer initiation
+// used, in any version. Elements are matched by local name, so the namespace
+// of the version does not matter.
+type pain001 struct {
+	XMLName     xml.Name `xml:"Document"`
+	PaymentInfo []struct {
+		ID            string `xml:"PmtInfId"`
+		ExecutionDate struct {
+			Date string `xml:",chardata"`
+			Dt   string `xml:"Dt"`
+			DtTm string `xml:"DtTm"`
+		} `xml:"ReqdExctnDt"`
+		Transactions []struct {
+			EndToEndID string `xml:"PmtId>EndToEndId"`
+			Amount     struct {
+				Currency string `xml:"Ccy,attr"`
+				Value    string `xml:",chardata"`
+			} `xml:"Amt>InstdAmt"`
+			AgentBIC   string `xml:"CdtrAgt>FinInstnId>BIC"`
+			AgentBICFI string `xml:"CdtrAgt>FinInstnId>BICFI"`
+			Creditor   struct {
+				Name    string   `xml:"Nm"`
+				Country string   `xml:"PstlAdr>Ctry"`
+				Address []string `xml:"PstlAdr>AdrLine"`
+				OrgID   string   `xml:"Id>OrgId>Othr>Id"`
+			} `xml:"Cdtr"`
+			Account struct {
+				IBAN  string `xml:"Id>IBAN"`
+				Other struct {
+					ID          string `xml:"Id"`
+					Code        string `xml:"SchmeNm>Cd"`
+					Proprietary string `xml:"SchmeNm>Prtry"`
+				} `xml:"Id>Othr"`
+			} `xml:"CdtrAcct"`
+			Remittance struct {
+				Unstructured []string `xml:"Ustrd"`
+				Reference    []string `xml:"Strd>CdtrRefInf>Ref"`
+			} `xml:"RmtInf"`
+		} `xml:"CdtTrfTxInf"`
+	} `xml:"CstmrCdtTrfInitn>PmtInf"`
+}
+
+// ReadPain001 reads the credit transfers of a pain.001 customer credit
+// transfer initiation as payments, one per transaction, in the order of the
+// file.
+//
+// The creditor account is read as IBAN, as Bankgiro number if its scheme is
+// BGNR, as Plusgiro number if its scheme is PGNR, or as BBAN otherwise. A
+// structured creditor reference is used as reference, otherwise the
+// unstructured remittance information. The requested execution date is used
+// as due date and the country of an IBAN, or else of the creditor address, as
+// country code.
+func ReadPain001(r io.Reader) ([]*payqr.Payment, error) {
+	var doc pain001
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("iso20022: %w", err)
+	}
+	if len(doc.PaymentInfo) == 0 {
+		return nil, errors.New("iso20022: not a pain.001 credit transfer initiation")
+	}
+
+	var payments []*payqr.Payment
+	for _, info := range doc.PaymentInfo {
+		date := info.ExecutionDate.Dt
+		if date == "" {
+			date = info.ExecutionDate.DtTm
+		}
+		if date == "" {
+			date = strings.TrimSpace(info.ExecutionDate.Date)
+		}
+		due, err := parseDate(date)
+		if err != nil {
+			return nil, fmt.Errorf("iso20022: payment information %s: %w", info.ID, err)
+		}
+
+		for _, tx := range info.Transactions {
+			amount, err := strconv.ParseFloat(strings.TrimSpace(tx.Amount.Value), 64)
+			if err != nil {
+				return nil, fmt.Errorf("iso20022: transaction %s: invalid amount %q", tx.EndToEndID, tx.Amount.Value)
+			}
+
+			options := []payqr.Option{payqr.WithCurrency(tx.Amount.Currency)}
+			country := tx.Creditor.Country
+
+			account := tx.Account.IBAN
+			switch {
+			case account != "":
+				options = append(options, payqr.WithPaymentType(payqr.PaymentTypeIBAN))
+				country = account[:min(2, len(account))]
+			case tx.Account.Other.Proprietary == "BGNR":
+				account = tx.Account.Other.ID
+				options = append(options, payqr.WithPaymentType(payqr.PaymentTypeBG))
+			case tx.Account.Other.Proprietary == "PGNR":
+				account = tx.Account.Other.ID
+				options = append(options, payqr.WithPaymentType(payqr.PaymentTypePG))
+			case tx.Account.Other.ID != "":
+				account = tx.Account.Other.ID
+				options = append(options, payqr.WithPaymentType(payqr.PaymentTypeBBAN))
+			default:
+				return nil, fmt.Errorf("iso20022: transaction %s has no creditor account", tx.EndToEndID)
+			}
+
+			if bic := tx.AgentBICFI + tx.AgentBIC; bic != "" {
+				options = append(options, payqr.WithBankCode(bic))
+			}
+			if country != "" {
+				options = append(options, payqr.WithCountryCode(country))
+			}
+			if len(tx.Creditor.Address) > 0 {
+				options = append(options, payqr.WithAddress(strings.Join(tx.Creditor.Address, "\n")))
+			}
+
+			reference := strings.Join(tx.Remittance.Reference, " ")
+			if reference == "" {
+				reference = strings.Join(tx.Remittance.Unstructured, " ")
+			}
+
+			payments = append(payments, payqr.New(account, tx.Creditor.Name, tx.Creditor.OrgID, reference, amount, due, options...))
+		}
+	}
+
+	return payments, nil
+}
+
+// parseDate parses an ISO date or date and time.
+func parseDate(s string) (time.Time, error) {
+	if len(s) >= 10 {
+		if t, err := time.Parse("2006-01-02", s[:10]); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
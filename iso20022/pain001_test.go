@@ -0,0 +1,71 @@
+package iso20022
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadPain001(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		want []*payqr.Payment
+	}{
+		{
+			name: "Version 3",
+			file: "testdata/pain.001.001.03.xml",
+			want: []*payqr.Payment{
+				payqr.New("DE75512108001245126199", "Test GmbH", "", "Invoice 123", 200.5, time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC),
+					payqr.WithCurrency("EUR"), payqr.WithPaymentType(payqr.PaymentTypeIBAN), payqr.WithBankCode("SOGEDEFFXXX"), payqr.WithCountryCode("DE"), payqr.WithAddress("Hauptstrasse 1\n10115 Berlin")),
+				payqr.New("54029681", "Leverantör AB", "5555555555", "52250047157", 1250, time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC),
+					payqr.WithCurrency("SEK")),
+			},
+		},
+		{
+			name: "Version 9",
+			file: "testdata/pain.001.001.09.xml",
+			want: []*payqr.Payment{
+				payqr.New("86011117947", "Test AS", "", "Order 1 Order 2", 99.5, time.Date(2022, time.August, 31, 0, 0, 0, 0, time.UTC),
+					payqr.WithCurrency("NOK"), payqr.WithPaymentType(payqr.PaymentTypeBBAN), payqr.WithCountryCode("NO")),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f, err := os.Open(test.file)
+			require.NoError(t, err)
+			defer f.Close()
+
+			payments, err := ReadPain001(f)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, payments)
+		})
+	}
+}
+
+func TestReadPain001Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		have string
+	}{
+		{name: "Not XML", have: "hello"},
+		{name: "Other message", have: `<Document><BkToCstmrDbtCdtNtfctn/></Document>`},
+		{name: "Invalid amount", have: `<Document><CstmrCdtTrfInitn><PmtInf><ReqdExctnDt>2022-08-06</ReqdExctnDt><CdtTrfTxInf><Amt><InstdAmt Ccy="EUR">x</InstdAmt></Amt></CdtTrfTxInf></PmtInf></CstmrCdtTrfInitn></Document>`},
+		{name: "No account", have: `<Document><CstmrCdtTrfInitn><PmtInf><ReqdExctnDt>2022-08-06</ReqdExctnDt><CdtTrfTxInf><Amt><InstdAmt Ccy="EUR">1</InstdAmt></Amt></CdtTrfTxInf></PmtInf></CstmrCdtTrfInitn></Document>`},
+		{name: "No date", have: `<Document><CstmrCdtTrfInitn><PmtInf><CdtTrfTxInf/></PmtInf></CstmrCdtTrfInitn></Document>`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ReadPain001(strings.NewReader(test.have))
+			assert.Error(t, err)
+		})
+	}
+}
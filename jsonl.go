@@ -0,0 +1,203 @@
+package payqr
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// jsonlPayment is a payment in JSON Lines, with long field names and ISO
+// dates, unlike the compact JSON of the QR payload.
+type jsonlPayment struct {
+	QRVersion              int         `json:"qr_version"`
+	Type                   Type        `json:"type"`
+	AccountName            string      `json:"account_name"`
+	CompanyID              string      `json:"company_id"`
+	Reference              string      `json:"reference"`
+	CreditInvoiceReference string      `json:"credit_invoice_reference,omitempty"`
+	Currency               string      `json:"currency,omitempty"`
+	VAT                    float64     `json:"vat,omitempty"`
+	HighVAT                float64     `json:"high_vat,omitempty"`
+	MediumVAT              float64     `json:"medium_vat,omitempty"`
+	LowVAT                 float64     `json:"low_vat,omitempty"`
+	CreatedDate            string      `json:"created_date"`
+	DueDate                string      `json:"due_date"`
+	DueAmount              float64     `json:"due_amount"`
+	PaymentType            PaymentType `json:"payment_type"`
+	AccountNumber          string      `json:"account_number"`
+	BankCode               string      `json:"bank_code,omitempty"`
+	CountryCode            string      `json:"country_code,omitempty"`
+	Address                string      `json:"address,omitempty"`
+}
+
+// isoDate converts a yyyymmdd date to yyyy-mm-dd, other values are kept.
+func isoDate(date string) string {
+	if t, err := time.Parse("20060102", date); err == nil {
+		return t.Format("2006-01-02")
+	}
+
+	return date
+}
+
+// payloadDate converts a yyyy-mm-dd date to yyyymmdd, other values are kept.
+func payloadDate(date string) string {
+	if t, err := time.Parse("2006-01-02", date); err == nil {
+		return t.Format("20060102")
+	}
+
+	return date
+}
+
+// JSONLEncoder writes payments as JSON Lines, one JSON object per line.
+type JSONLEncoder struct {
+	w io.Writer
+}
+
+// NewJSONLEncoder returns an encoder writing to w.
+func NewJSONLEncoder(w io.Writer) *JSONLEncoder {
+	return &JSONLEncoder{w: w}
+}
+
+// Encode writes the payment as one line.
+func (e *JSONLEncoder) Encode(d *Payment) error {
+	b, err := json.Marshal(jsonlPayment{
+		QRVersion:              d.UsingQRVersion,
+		Type:                   d.Type,
+		AccountName:            d.AccountName,
+		CompanyID:              d.CompanyID,
+		Reference:              d.Reference,
+		CreditInvoiceReference: d.CreditInvoiceReference,
+		Currency:               d.Currency,
+		VAT:                    d.VAT,
+		HighVAT:                d.HighVAT,
+		MediumVAT:              d.MediumVAT,
+		LowVAT:                 d.LowVAT,
+		CreatedDate:            isoDate(d.CreatedDate),
+		DueDate:                isoDate(d.DueDate),
+		DueAmount:              d.DueAmount,
+		PaymentType:            d.PaymentType,
+		AccountNumber:          d.AccountNumber,
+		BankCode:               d.BankCode,
+		CountryCode:            d.CountryCode,
+		Address:                d.Address,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = e.w.Write(append(b, '\n'))
+
+	return err
+}
+
+// JSONLDecoder reads payments from JSON Lines, one JSON object per line.
+// Blank lines are skipped.
+type JSONLDecoder struct {
+	scanner *bufio.Scanner
+	line    int
+	err     error
+}
+
+// NewJSONLDecoder returns a decoder reading from r.
+func NewJSONLDecoder(r io.Reader) *JSONLDecoder {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+
+	return &JSONLDecoder{scanner: scanner}
+}
+
+// Decode reads the next payment. It returns io.EOF when there are no more
+// payments. Fields left out default as for New. An error reading the input,
+// such as a line longer than 1 MiB, is returned once, see Err, and io.EOF
+// after it.
+func (dec *JSONLDecoder) Decode() (*Payment, error) {
+	if dec.err != nil {
+		return nil, io.EOF
+	}
+
+	for dec.scanner.Scan() {
+		dec.line++
+
+		line := bytes.TrimSpace(dec.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		v := jsonlPayment{QRVersion: 1, Type: InvoiceType, PaymentType: PaymentTypeBG}
+		if err := json.Unmarshal(line, &v); err != nil {
+			return nil, fmt.Errorf("jsonl: line %d: %w", dec.line, err)
+		}
+
+		return &Payment{
+			UsingQRVersion:         v.QRVersion,
+			Type:                   v.Type,
+			AccountName:            v.AccountName,
+			CompanyID:              v.CompanyID,
+			Reference:              v.Reference,
+			CreditInvoiceReference: v.CreditInvoiceReference,
+			Currency:               v.Currency,
+			VAT:                    v.VAT,
+			HighVAT:                v.HighVAT,
+			MediumVAT:              v.MediumVAT,
+			LowVAT:                 v.LowVAT,
+			CreatedDate:            payloadDate(v.CreatedDate),
+			DueDate:                payloadDate(v.DueDate),
+			DueAmount:              v.DueAmount,
+			PaymentType:            v.PaymentType,
+			AccountNumber:          v.AccountNumber,
+			BankCode:               v.BankCode,
+			CountryCode:            v.CountryCode,
+			Address:                v.Address,
+		}, nil
+	}
+	if err := dec.scanner.Err(); err != nil {
+		dec.err = fmt.Errorf("jsonl: line %d: %w", dec.line+1, err)
+		return nil, dec.err
+	}
+
+	return nil, io.EOF
+}
+
+// Line returns the line of the payment last decoded, counted from 1.
+func (dec *JSONLDecoder) Line() int {
+	return dec.line
+}
+
+// Err returns the error that stopped reading the input, if any. Unlike the
+// errors of invalid lines, no more payments can be decoded after it.
+func (dec *JSONLDecoder) Err() error {
+	return dec.err
+}
+
+// WriteJSONL writes the payments as JSON Lines.
+func WriteJSONL(w io.Writer, payments []*Payment) error {
+	bw := bufio.NewWriter(w)
+	enc := NewJSONLEncoder(bw)
+	for _, d := range payments {
+		if err := enc.Encode(d); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// ReadJSONL reads all payments of JSON Lines.
+func ReadJSONL(r io.Reader) ([]*Payment, error) {
+	var payments []*Payment
+
+	dec := NewJSONLDecoder(r)
+	for {
+		d, err := dec.Decode()
+		if err == io.EOF {
+			return payments, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, d)
+	}
+}
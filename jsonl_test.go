@@ -0,0 +1,76 @@
+package payqr
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONL(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+	created := time.Date(2022, time.July, 7, 0, 0, 0, 0, time.Local)
+	payments := []*Payment{
+		New("5402-9681", "Test AB", "5555555555", "52250047157", 1250, due, WithCreationDate(created)),
+		New("DE75512108001245126199", "Test GmbH", "HRB 1234", "Invoice 123", 200.5, due, WithCreationDate(created),
+			WithPaymentType(PaymentTypeIBAN), WithCurrency("EUR"), WithBankCode("SOGEDEFFXXX")),
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteJSONL(&buf, payments))
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, `{"qr_version":1,"type":1,"account_name":"Test AB","company_id":"5555555555","reference":"52250047157",`+
+		`"created_date":"2022-07-07","due_date":"2022-08-06","due_amount":1250,"payment_type":"BG","account_number":"5402-9681"}`, lines[0])
+
+	got, err := ReadJSONL(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, payments, got)
+}
+
+func TestJSONLDecoder(t *testing.T) {
+	have := `{"account_name":"Test AB","company_id":"5555555555","account_number":"5402-9681","due_date":"2022-08-06","due_amount":50}` + "\n\n" +
+		`{"account_name":"Test AB",` + "\n"
+
+	dec := NewJSONLDecoder(strings.NewReader(have))
+
+	d, err := dec.Decode()
+	require.NoError(t, err)
+	assert.Equal(t, PaymentTypeBG, d.PaymentType, "defaults as New")
+	assert.Equal(t, InvoiceType, d.Type)
+	assert.Equal(t, "20220806", d.DueDate)
+	assert.NoError(t, d.Validate())
+
+	_, err = dec.Decode()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 3")
+
+	_, err = dec.Decode()
+	assert.Equal(t, io.EOF, err)
+	assert.NoError(t, dec.Err())
+}
+
+func TestJSONLDecoderLongLine(t *testing.T) {
+	have := `{"account_name":"Test AB"}` + "\n" + `{"reference":"` + strings.Repeat("1", 1<<20) + `"}` + "\n" + `{"account_name":"Test AB"}` + "\n"
+
+	dec := NewJSONLDecoder(strings.NewReader(have))
+
+	_, err := dec.Decode()
+	require.NoError(t, err)
+
+	_, err = dec.Decode()
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, bufio.ErrTooLong))
+	assert.Contains(t, err.Error(), "line 2")
+	assert.Equal(t, err, dec.Err())
+
+	_, err = dec.Decode()
+	assert.Equal(t, io.EOF, err)
+}
@@ -0,0 +1,79 @@
+package payqr
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// commonCurrencies are the currencies that Swedish bank apps commonly pay
+// invoices in.
+var commonCurrencies = map[string]bool{
+	"SEK": true, "EUR": true, "NOK": true, "DKK": true, "USD": true, "GBP": true,
+}
+
+// maxAddressLine and maxAddressLines are how much of the address bank apps
+// commonly show.
+const (
+	maxAddressLine  = 35
+	maxAddressLines = 3
+)
+
+// Report is the result of Lint.
+type Report struct {
+	// Errors are violations of the specification, the payment should not be
+	// encoded.
+	Errors []error
+
+	// Warnings are issues that may keep some bank apps from showing or
+	// paying the payment correctly, such as a long address or an unusual
+	// currency.
+	Warnings []error
+}
+
+// OK reports whether the payment had no errors, warnings aside.
+func (r Report) OK() bool {
+	return len(r.Errors) == 0
+}
+
+// Lint checks the payment for violations of the specification, as Validate,
+// and for interoperability issues reported as warnings, so that invoicing
+// systems can block on errors and log the warnings.
+func Lint(p *Payment) Report {
+	var r Report
+	if err := p.Validate(); err != nil {
+		r.Errors = append(r.Errors, err)
+	}
+
+	warn := func(err error) {
+		r.Warnings = append(r.Warnings, err)
+	}
+
+	if err := p.ValidateDueDate(WithDueDateWarnings(warn)); err != nil {
+		r.Errors = append(r.Errors, err)
+	}
+	if p.Currency != "" && !commonCurrencies[strings.ToUpper(p.Currency)] {
+		warn(fmt.Errorf("currency %s is not supported by all bank apps", p.Currency))
+	}
+	if p.Type != CreditInvoiceType && p.DueAmount == 0 {
+		warn(fmt.Errorf("payment has no due amount, the payer has to enter it"))
+	}
+	for _, l := range fieldLimits {
+		for _, err := range undisplayable(l.key, *l.field(p)) {
+			warn(err)
+		}
+	}
+	if p.Address != "" {
+		lines := strings.Split(p.Address, "\n")
+		if len(lines) > maxAddressLines {
+			warn(fmt.Errorf("address has %d lines, bank apps may only show %d", len(lines), maxAddressLines))
+		}
+		for _, line := range lines {
+			if n := utf8.RuneCountInString(line); n > maxAddressLine {
+				warn(fmt.Errorf("address line %q is %d characters, bank apps may only show %d", line, n, maxAddressLine))
+			}
+		}
+	}
+
+	return r
+}
@@ -0,0 +1,79 @@
+package payqr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLint(t *testing.T) {
+	created := time.Date(2022, time.August, 1, 0, 0, 0, 0, time.Local)
+	due := time.Date(2022, time.August, 31, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name         string
+		have         *Payment
+		wantErrors   []string
+		wantWarnings []string
+	}{
+		{
+			name: "Invoice",
+			have: New("5402-9681", "Test AB", "5555555555", "52250047157", 50, due, WithCreationDate(created), WithAddress("Storgatan 1\n111 22 Stockholm")),
+		},
+		{
+			name:       "Invalid account",
+			have:       New("5402-9680", "Test AB", "5555555555", "52250047157", 50, due, WithCreationDate(created)),
+			wantErrors: []string{`bankgiro account "5402-9680" has an invalid check digit`},
+		},
+		{
+			name:         "Unusual currency",
+			have:         New("DE89370400440532013000", "Test GmbH", "1234", "1001", 50, due, WithCreationDate(created), WithPaymentType(PaymentTypeIBAN), WithBankCode("COBADEFF"), WithCountryCode("DE"), WithCurrency("CHF")),
+			wantWarnings: []string{"currency CHF is not supported by all bank apps"},
+		},
+		{
+			name:         "Without amount",
+			have:         New("5402-9681", "Test AB", "5555555555", "52250047157", 0, due, WithCreationDate(created)),
+			wantWarnings: []string{"payment has no due amount, the payer has to enter it"},
+		},
+		{
+			name: "Long address",
+			have: New("5402-9681", "Test AB", "5555555555", "52250047157", 50, due, WithCreationDate(created), WithAddress("Kungliga Tekniska högskolans väg 100\nc/o Test\nBox 1\n111 22 Stockholm")),
+			wantWarnings: []string{
+				"address has 4 lines, bank apps may only show 3",
+				`address line "Kungliga Tekniska högskolans väg 100" is 36 characters, bank apps may only show 35`,
+			},
+		},
+		{
+			name: "Characters outside Latin-1",
+			have: New("5402-9681", "Łódź Sp. z o.o.", "5555555555", "52250047157", 50, due, WithCreationDate(created)),
+			wantWarnings: []string{
+				`field nme has the character 'Ł' which bank apps may not display`,
+				`field nme has the character 'ź' which bank apps may not display`,
+			},
+		},
+		{
+			name:         "Due before creation",
+			have:         New("5402-9681", "Test AB", "5555555555", "52250047157", 50, created, WithCreationDate(due)),
+			wantWarnings: []string{"due date 20220801 is before the creation date 20220831"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := Lint(test.have)
+			assert.Equal(t, test.wantErrors, errorStrings(r.Errors))
+			assert.Equal(t, test.wantWarnings, errorStrings(r.Warnings))
+			assert.Equal(t, len(test.wantErrors) == 0, r.OK())
+		})
+	}
+}
+
+func errorStrings(errs []error) []string {
+	var s []string
+	for _, err := range errs {
+		s = append(s, err.Error())
+	}
+
+	return s
+}
@@ -0,0 +1,130 @@
+// Package mobilepay encodes payments as MobilePay payment links, which open
+// the payment prefilled in the MobilePay app in Denmark and Finland, and
+// links to MobilePay Box collections.
+package mobilepay
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/antonlindstrom/payqr"
+)
+
+// Country is a country where MobilePay payment links are supported.
+type Country string
+
+const (
+	Denmark Country = "DK"
+	Finland Country = "FI"
+)
+
+// links are the payment link base URLs per country.
+var links = map[Country]string{
+	Denmark: "https://mobilepay.dk/erhverv/betalingslink/betalingslink-svar",
+	Finland: "https://mobilepay.fi/yrityksille/maksulinkki/maksulinkki-vastaus",
+}
+
+// currencies are the currencies of the payments per country.
+var currencies = map[Country]string{
+	Denmark: "DKK",
+	Finland: "EUR",
+}
+
+// BoxURL is the base URL of MobilePay Box collections.
+const BoxURL = "https://mobilepay.dk/box"
+
+// Option is a modifier for the link.
+type Option func(*config)
+
+type config struct {
+	lock bool
+}
+
+// WithLock locks the amount and comment so the payer can not change them.
+func WithLock() Option {
+	return func(c *config) {
+		c.lock = true
+	}
+}
+
+// Link returns the payment link of the payment. The country code of the
+// payment selects the country, default is Denmark. The account number is the
+// phone number or MobilePay number of the payee and the reference is the
+// comment. A zero amount lets the payer enter it.
+func Link(p *payqr.Payment, options ...Option) (string, error) {
+	c := &config{}
+	for _, opt := range options {
+		opt(c)
+	}
+
+	country := Country(p.CountryCode)
+	if country == "" {
+		country = Denmark
+	}
+	base, ok := links[country]
+	if !ok {
+		return "", fmt.Errorf("mobilepay: country %s is not supported", p.CountryCode)
+	}
+	if p.Currency != "" && !strings.EqualFold(p.Currency, currencies[country]) {
+		return "", fmt.Errorf("mobilepay: currency must be %s, got %s", currencies[country], p.Currency)
+	}
+
+	phone, err := normalizePhone(country, p.AccountNumber)
+	if err != nil {
+		return "", err
+	}
+	if p.DueAmount < 0 {
+		return "", fmt.Errorf("mobilepay: amount %.2f is negative", p.DueAmount)
+	}
+	if len([]rune(p.Reference)) > 25 {
+		return "", fmt.Errorf("mobilepay: comment is longer than 25 characters")
+	}
+
+	params := []string{"phone=" + phone}
+	if p.DueAmount > 0 {
+		params = append(params, "amount="+strconv.FormatFloat(p.DueAmount, 'f', 2, 64))
+	}
+	if p.Reference != "" {
+		params = append(params, "comment="+strings.ReplaceAll(url.QueryEscape(p.Reference), "+", "%20"))
+	}
+	if c.lock {
+		params = append(params, "lock=1")
+	}
+
+	return base + "?" + strings.Join(params, "&"), nil
+}
+
+// BoxLink returns the link to the MobilePay Box collection with the ID.
+func BoxLink(id string) (string, error) {
+	if id == "" || strings.IndexFunc(id, func(r rune) bool { return r < '0' || r > '9' }) >= 0 {
+		return "", fmt.Errorf("mobilepay: invalid box ID %q", id)
+	}
+
+	return BoxURL + "?id=" + id, nil
+}
+
+// normalizePhone returns the number without country code and formatting.
+// Danish numbers have 8 digits and Finnish numbers up to 10 digits with the
+// leading zero, MobilePay numbers for businesses have 4 to 6 digits.
+func normalizePhone(country Country, phone string) (string, error) {
+	digits := strings.NewReplacer(" ", "", "-", "").Replace(phone)
+	prefix := map[Country]string{Denmark: "+45", Finland: "+358"}[country]
+	if strings.HasPrefix(digits, prefix) {
+		digits = digits[len(prefix):]
+		if country == Finland {
+			digits = "0" + digits
+		}
+	}
+
+	maxLength := 8
+	if country == Finland {
+		maxLength = 10
+	}
+	if len(digits) < 4 || len(digits) > maxLength || strings.IndexFunc(digits, func(r rune) bool { return r < '0' || r > '9' }) >= 0 {
+		return "", fmt.Errorf("mobilepay: invalid phone number %q", phone)
+	}
+
+	return digits, nil
+}
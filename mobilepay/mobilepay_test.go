@@ -0,0 +1,80 @@
+package mobilepay
+
+import (
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLink(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		have    *payqr.Payment
+		options []Option
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "Denmark",
+			have:    payqr.New("+45 12 34 56 78", "Test A/S", "", "Faktura 123", 150, due, payqr.WithCurrency("DKK")),
+			options: []Option{WithLock()},
+			want:    "https://mobilepay.dk/erhverv/betalingslink/betalingslink-svar?phone=12345678&amount=150.00&comment=Faktura%20123&lock=1",
+		},
+		{
+			name: "Finland",
+			have: payqr.New("+358 40 1234567", "Test Oy", "", "", 9.9, due, payqr.WithCountryCode("FI"), payqr.WithCurrency("EUR")),
+			want: "https://mobilepay.fi/yrityksille/maksulinkki/maksulinkki-vastaus?phone=0401234567&amount=9.90",
+		},
+		{
+			name: "Business number without amount",
+			have: payqr.New("12345", "Test A/S", "", "", 0, due),
+			want: "https://mobilepay.dk/erhverv/betalingslink/betalingslink-svar?phone=12345",
+		},
+		{
+			name:    "Invalid phone",
+			have:    payqr.New("123", "Test A/S", "", "", 10, due),
+			wantErr: true,
+		},
+		{
+			name:    "Wrong currency",
+			have:    payqr.New("12345678", "Test A/S", "", "", 10, due, payqr.WithCurrency("EUR")),
+			wantErr: true,
+		},
+		{
+			name:    "Unsupported country",
+			have:    payqr.New("12345678", "Test AB", "", "", 10, due, payqr.WithCountryCode("SE")),
+			wantErr: true,
+		},
+		{
+			name:    "Long comment",
+			have:    payqr.New("12345678", "Test A/S", "", "This comment is far too long for MobilePay", 10, due),
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Link(test.have, test.options...)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestBoxLink(t *testing.T) {
+	got, err := BoxLink("1234567")
+	require.NoError(t, err)
+	assert.Equal(t, "https://mobilepay.dk/box?id=1234567", got)
+
+	_, err = BoxLink("abc")
+	assert.Error(t, err)
+}
@@ -0,0 +1,28 @@
+//go:build !payqr_payloadonly
+
+package mobilepay
+
+import (
+	"github.com/antonlindstrom/payqr"
+	"github.com/skip2/go-qrcode"
+)
+
+// QR returns a QR code of the payment link of the payment.
+func QR(p *payqr.Payment, options ...Option) (*qrcode.QRCode, error) {
+	link, err := Link(p, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return qrcode.New(link, qrcode.Medium)
+}
+
+// BoxQR returns a QR code of the link to the MobilePay Box collection.
+func BoxQR(id string) (*qrcode.QRCode, error) {
+	link, err := BoxLink(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return qrcode.New(link, qrcode.Medium)
+}
@@ -0,0 +1,35 @@
+//go:build !payqr_payloadonly
+
+package mobilepay
+
+import (
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQR(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+	p := payqr.New("+45 12 34 56 78", "Test A/S", "", "Faktura 123", 150, due, payqr.WithCurrency("DKK"))
+
+	q, err := QR(p, WithLock())
+	require.NoError(t, err)
+	want, err := Link(p, WithLock())
+	require.NoError(t, err)
+	assert.Equal(t, want, q.Content)
+
+	_, err = QR(payqr.New("+45 12 34 56 78", "Test A/S", "", "Faktura 123", 150, due, payqr.WithCurrency("SEK")))
+	assert.Error(t, err)
+}
+
+func TestBoxQR(t *testing.T) {
+	q, err := BoxQR("1234567")
+	require.NoError(t, err)
+	assert.Equal(t, "https://mobilepay.dk/box?id=1234567", q.Content)
+
+	_, err = BoxQR("abc")
+	assert.Error(t, err)
+}
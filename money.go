@@ -0,0 +1,115 @@
+package payqr
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Money is an exact amount, as a whole number of the minor units of its
+// currency, e.g. öre for SEK or cents for EUR. An empty currency is SEK.
+// Payments still store their amounts as floats, Money is converted when
+// setting and reading them, see Payment.
+type Money struct {
+	Minor    int64
+	Currency string
+}
+
+// NewMoney returns the amount of minor units of the currency, e.g.
+// NewMoney(1075, "SEK") for 10.75 kronor.
+func NewMoney(minor int64, currency string) Money {
+	return Money{Minor: minor, Currency: currency}
+}
+
+// ParseMoney parses a decimal amount with a point, such as "10.75", of the
+// currency. An error is returned if it has more decimals than the minor
+// units of the currency.
+func ParseMoney(amount, currency string) (Money, error) {
+	s := strings.TrimPrefix(amount, "-")
+	whole, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		whole, frac = s[:i], s[i+1:]
+	}
+
+	units := MinorUnits(currency)
+	if whole == "" || !isDigits(whole) || !isDigits(frac) || strings.HasSuffix(s, ".") {
+		return Money{}, fmt.Errorf("invalid amount %q", amount)
+	}
+	if len(frac) > units {
+		return Money{}, fmt.Errorf("amount %q has more than %d decimals", amount, units)
+	}
+
+	minor, err := strconv.ParseInt(whole+frac+strings.Repeat("0", units-len(frac)), 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("invalid amount %q", amount)
+	}
+	if s != amount {
+		minor = -minor
+	}
+
+	return Money{Minor: minor, Currency: currency}, nil
+}
+
+// MoneyFromFloat returns the amount rounded to the minor units of the
+// currency, see RoundAmount.
+//
+// It converts the float amounts of payments, such as the due amount given to
+// New, which cannot represent most decimal amounts exactly. Prefer NewMoney or
+// ParseMoney for new amounts.
+func MoneyFromFloat(amount float64, currency string) Money {
+	return Money{Minor: int64(math.Round(amount * math.Pow10(MinorUnits(currency)))), Currency: currency}
+}
+
+// Float64 returns the amount as a float, the closest to the decimal amount
+// which is encoded as the decimal amount in payloads.
+func (m Money) Float64() float64 {
+	return float64(m.Minor) / math.Pow10(MinorUnits(m.Currency))
+}
+
+// String returns the amount with the decimals of the currency, e.g. "10.75"
+// or "1250" for JPY.
+func (m Money) String() string {
+	units := MinorUnits(m.Currency)
+
+	s := strconv.FormatInt(m.Minor, 10)
+	sign := ""
+	if m.Minor < 0 {
+		sign, s = "-", s[1:]
+	}
+	if units == 0 {
+		return sign + s
+	}
+	if len(s) <= units {
+		s = strings.Repeat("0", units-len(s)+1) + s
+	}
+
+	return sign + s[:len(s)-units] + "." + s[len(s)-units:]
+}
+
+// Due returns the due amount of the payment in its currency.
+func (d *Payment) Due() Money {
+	return MoneyFromFloat(d.DueAmount, d.Currency)
+}
+
+// WithDue sets the due amount, and the currency unless the amount has
+// none.
+func WithDue(due Money) Option {
+	return func(p *Payment) {
+		if due.Currency != "" {
+			p.Currency = due.Currency
+		}
+		p.DueAmount = due.Float64()
+	}
+}
+
+// isDigits reports whether s only has the digits 0-9.
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,71 @@
+package payqr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		name     string
+		have     string
+		currency string
+		want     Money
+		wantErr  string
+	}{
+		{name: "Decimals", have: "10.75", currency: "SEK", want: NewMoney(1075, "SEK")},
+		{name: "One decimal", have: "10.5", want: NewMoney(1050, "")},
+		{name: "Whole", have: "10", currency: "EUR", want: NewMoney(1000, "EUR")},
+		{name: "Negative", have: "-0.05", currency: "SEK", want: NewMoney(-5, "SEK")},
+		{name: "Without minor units", have: "1250", currency: "JPY", want: NewMoney(1250, "JPY")},
+		{name: "Three decimals", have: "1.234", currency: "KWD", want: NewMoney(1234, "KWD")},
+		{name: "Too many decimals", have: "10.755", currency: "SEK", wantErr: `amount "10.755" has more than 2 decimals`},
+		{name: "Decimals without minor units", have: "1250.5", currency: "JPY", wantErr: `amount "1250.5" has more than 0 decimals`},
+		{name: "Comma", have: "10,75", wantErr: `invalid amount "10,75"`},
+		{name: "Trailing point", have: "10.", wantErr: `invalid amount "10."`},
+		{name: "Leading point", have: ".75", wantErr: `invalid amount ".75"`},
+		{name: "Empty", have: "", wantErr: `invalid amount ""`},
+		{name: "Overflow", have: "999999999999999999999", wantErr: `invalid amount "999999999999999999999"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			have, err := ParseMoney(test.have, test.currency)
+			if test.wantErr != "" {
+				assert.EqualError(t, err, test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, have)
+		})
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	assert.Equal(t, "10.75", NewMoney(1075, "SEK").String())
+	assert.Equal(t, "0.05", NewMoney(5, "").String())
+	assert.Equal(t, "-0.05", NewMoney(-5, "EUR").String())
+	assert.Equal(t, "10.00", NewMoney(1000, "EUR").String())
+	assert.Equal(t, "1250", NewMoney(1250, "JPY").String())
+	assert.Equal(t, "1.234", NewMoney(1234, "KWD").String())
+}
+
+func TestWithDue(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+
+	d := New("5402-9681", "Test AB", "5555555555", "52250047157", 0, due, WithCreationDate(due), WithDue(NewMoney(1075, "SEK")))
+	assert.Equal(t, "SEK", d.Currency)
+	assert.Equal(t, NewMoney(1075, "SEK"), d.Due())
+
+	payload, err := d.Payload()
+	require.NoError(t, err)
+	assert.Contains(t, payload, `"due":10.75,`)
+
+	d = New("5402-9681", "Test AB", "5555555555", "52250047157", 0, due, WithDue(NewMoney(1, "")))
+	assert.Equal(t, "", d.Currency)
+	assert.Equal(t, 0.01, d.DueAmount)
+	assert.Equal(t, NewMoney(1075, "SEK"), MoneyFromFloat(10.75, "SEK"))
+}
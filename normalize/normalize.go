@@ -0,0 +1,77 @@
+// Package normalize normalizes the text of payments for bank apps: names and
+// addresses are converted to Unicode NFC, so that e.g. an "å" typed as "a"
+// and a combining ring is one character, and optionally transliterated to
+// Latin-1 for older apps that mangle other characters. Payments are UTF-8
+// and left as they are unless one of the options is given.
+package normalize
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/antonlindstrom/payqr"
+	"golang.org/x/text/unicode/norm"
+)
+
+// transliterations are the characters outside Latin-1 that do not decompose
+// into a Latin-1 character and combining marks.
+var transliterations = map[rune]string{
+	'Đ': "D", 'đ': "d", 'Ħ': "H", 'ħ': "h", 'ı': "i", 'Ł': "L", 'ł': "l",
+	'Ŋ': "N", 'ŋ': "n", 'Œ': "OE", 'œ': "oe", 'Ŧ': "T", 'ŧ': "t",
+	'‐': "-", '‑': "-", '‒': "-", '–': "-", '—': "-", '‘': "'", '’': "'",
+	'‚': ",", '“': "\"", '”': "\"", '„': "\"", '…': "...", '€': "EUR",
+	'™': "TM",
+}
+
+// NFC converts the name and address of the payment to Unicode NFC. As an
+// option it applies to the fields as set by the options before it.
+func NFC() payqr.Option {
+	return func(p *payqr.Payment) {
+		p.AccountName = norm.NFC.String(p.AccountName)
+		p.Address = norm.NFC.String(p.Address)
+	}
+}
+
+// Latin1 converts the name and address of the payment to Unicode NFC and
+// transliterates them to Latin-1, see Transliterate. As an option it applies
+// to the fields as set by the options before it.
+func Latin1() payqr.Option {
+	return func(p *payqr.Payment) {
+		p.AccountName = Transliterate(p.AccountName)
+		p.Address = Transliterate(p.Address)
+	}
+}
+
+// Transliterate converts s to Unicode NFC and replaces the characters
+// outside Latin-1 with similar ones, such as "ł" with "l" and "č" with "c",
+// or else with "?".
+func Transliterate(s string) string {
+	s = norm.NFC.String(s)
+
+	var b strings.Builder
+	for _, r := range s {
+		if r <= unicode.MaxLatin1 {
+			b.WriteRune(r)
+			continue
+		}
+		if t, ok := transliterations[r]; ok {
+			b.WriteString(t)
+			continue
+		}
+
+		base := false
+		for _, d := range norm.NFD.String(string(r)) {
+			if d <= unicode.MaxLatin1 {
+				b.WriteRune(d)
+				base = true
+			} else if !unicode.Is(unicode.Mn, d) {
+				break
+			}
+		}
+		if !base {
+			b.WriteByte('?')
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,48 @@
+package normalize
+
+import (
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransliterate(t *testing.T) {
+	tests := []struct {
+		name string
+		have string
+		want string
+	}{
+		{name: "Latin-1", have: "Smörgåsbordet AB", want: "Smörgåsbordet AB"},
+		{name: "Combining marks", have: "Smo\u0308rga\u030asbordet", want: "Smörgåsbordet"},
+		{name: "Decomposed outside Latin-1", have: "Dvořák Šťastný", want: "Dvorák Stastný"},
+		{name: "Without decomposition", have: "Łódź", want: "Lódz"},
+		{name: "Punctuation", have: "“Test” – AB…", want: "\"Test\" - AB..."},
+		{name: "Unknown", have: "Test AB 🐟 東京", want: "Test AB ? ??"},
+		{name: "Empty", have: "", want: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, Transliterate(test.have))
+		})
+	}
+}
+
+func TestOptions(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+	name, address := "Smo\u0308rga\u030asbordet Łódź", "Storgatan 1\n111 22 Sto\u0308ckholm"
+
+	d := payqr.New("5402-9681", name, "5555555555", "52250047157", 50, due)
+	assert.Equal(t, name, d.AccountName)
+
+	d = payqr.New("5402-9681", name, "5555555555", "52250047157", 50, due, payqr.WithAddress(address), NFC())
+	assert.Equal(t, "Smörgåsbordet Łódź", d.AccountName)
+	assert.Equal(t, "Storgatan 1\n111 22 Stöckholm", d.Address)
+
+	d = payqr.New("5402-9681", name, "5555555555", "52250047157", 50, due, payqr.WithAddress(address), Latin1())
+	assert.Equal(t, "Smörgåsbordet Lódz", d.AccountName)
+	assert.Equal(t, "Storgatan 1\n111 22 Stöckholm", d.Address)
+	assert.NoError(t, d.ValidateFields())
+}
@@ -0,0 +1,83 @@
+// Package norway encodes payments for Norwegian bank apps, using KID numbers
+// as references.
+//
+// Norwegian banks have no common QR format for invoices. The payload produced
+// here is the OCR line of the giro form, which is what bank apps recognise
+// when scanning a giro.
+package norway
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/antonlindstrom/payqr"
+)
+
+// Currency is the currency of Norwegian giro payments.
+const Currency = "NOK"
+
+// GenerateKID returns the base with a KID check digit appended, using mod 11
+// if mod11 is true and mod 10 otherwise.
+func GenerateKID(base string, mod11 bool) (string, error) {
+	if mod11 {
+		return payqr.ReferenceKIDMod11.Generate(base)
+	}
+
+	return payqr.ReferenceKIDMod10.Generate(base)
+}
+
+// KIDScheme returns the scheme a KID validates with, mod 10 is tried first.
+// The creditor's bank agreement decides which scheme is in use, so a KID may
+// be valid in both.
+func KIDScheme(kid string) (payqr.ReferenceScheme, error) {
+	for _, scheme := range []payqr.ReferenceScheme{payqr.ReferenceKIDMod10, payqr.ReferenceKIDMod11} {
+		if scheme.Validate(kid) == nil {
+			return scheme, nil
+		}
+	}
+
+	return payqr.ReferenceFree, fmt.Errorf("norway: %q is not a valid KID", kid)
+}
+
+// GiroLine returns the OCR line of the giro for the payment, with the KID,
+// the amount in kroner and øre with its check digit and the account number:
+//
+//	H 12345678903 # 1250 00 0 > 86011117947 #
+//
+// The payment must be a BBAN payment to a Norwegian account in NOK, or
+// without currency. If the reference scheme of the payment is set it is used
+// to validate the KID.
+func GiroLine(p *payqr.Payment) (string, error) {
+	if p.PaymentType != payqr.PaymentTypeBBAN {
+		return "", fmt.Errorf("norway: payment type must be %s, got %s", payqr.PaymentTypeBBAN, p.PaymentType)
+	}
+	if p.Currency != "" && !strings.EqualFold(p.Currency, Currency) {
+		return "", fmt.Errorf("norway: currency must be %s, got %s", Currency, p.Currency)
+	}
+
+	account := strings.NewReplacer(".", "", " ", "").Replace(p.AccountNumber)
+	if err := payqr.ValidateAccount("NO", payqr.PaymentTypeBBAN, account); err != nil {
+		return "", fmt.Errorf("norway: %w", err)
+	}
+
+	if scheme := p.ReferenceScheme(); scheme != payqr.ReferenceFree {
+		if err := scheme.Validate(p.Reference); err != nil {
+			return "", fmt.Errorf("norway: %w", err)
+		}
+	} else if _, err := KIDScheme(p.Reference); err != nil {
+		return "", err
+	}
+
+	if p.DueAmount <= 0 || p.DueAmount >= 1e8 {
+		return "", fmt.Errorf("norway: amount %.2f is out of range", p.DueAmount)
+	}
+
+	øre := int64(p.DueAmount*100 + 0.5)
+	kroner, rest := øre/100, øre%100
+	check, err := payqr.ReferenceKIDMod10.Generate(fmt.Sprintf("%d%02d", kroner, rest))
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("H %s # %d %02d %s > %s #", p.Reference, kroner, rest, check[len(check)-1:], account), nil
+}
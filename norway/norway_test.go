@@ -0,0 +1,76 @@
+package norway
+
+import (
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGiroLine(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		have    *payqr.Payment
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "KID mod 10",
+			have: payqr.New("8601.11.17947", "Test AS", "", "12345678903", 1250, due, payqr.WithPaymentType(payqr.PaymentTypeBBAN)),
+			want: "H 12345678903 # 1250 00 0 > 86011117947 #",
+		},
+		{
+			name: "KID mod 11",
+			have: payqr.New("86011117947", "Test AS", "", "1009-", 99.5, due, payqr.WithPaymentType(payqr.PaymentTypeBBAN), payqr.WithReferenceScheme(payqr.ReferenceKIDMod11), payqr.WithCurrency("NOK")),
+			want: "H 1009- # 99 50 7 > 86011117947 #",
+		},
+		{
+			name:    "Invalid KID",
+			have:    payqr.New("86011117947", "Test AS", "", "12345678904", 1250, due, payqr.WithPaymentType(payqr.PaymentTypeBBAN), payqr.WithReferenceScheme(payqr.ReferenceKIDMod10)),
+			wantErr: true,
+		},
+		{
+			name:    "Invalid account",
+			have:    payqr.New("86011117948", "Test AS", "", "12345678903", 1250, due, payqr.WithPaymentType(payqr.PaymentTypeBBAN)),
+			wantErr: true,
+		},
+		{
+			name:    "SEK",
+			have:    payqr.New("86011117947", "Test AS", "", "12345678903", 1250, due, payqr.WithPaymentType(payqr.PaymentTypeBBAN), payqr.WithCurrency("SEK")),
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := GiroLine(test.have)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestKID(t *testing.T) {
+	kid, err := GenerateKID("1234567890", false)
+	require.NoError(t, err)
+	assert.Equal(t, "12345678903", kid)
+
+	kid, err = GenerateKID("1009", true)
+	require.NoError(t, err)
+	assert.Equal(t, "1009-", kid)
+
+	scheme, err := KIDScheme("1009-")
+	require.NoError(t, err)
+	assert.Equal(t, payqr.ReferenceKIDMod11, scheme)
+
+	_, err = KIDScheme("10091")
+	assert.Error(t, err)
+}
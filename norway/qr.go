@@ -0,0 +1,18 @@
+//go:build !payqr_payloadonly
+
+package norway
+
+import (
+	"github.com/antonlindstrom/payqr"
+	"github.com/skip2/go-qrcode"
+)
+
+// QR returns a QR code of the giro OCR line.
+func QR(p *payqr.Payment) (*qrcode.QRCode, error) {
+	line, err := GiroLine(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return qrcode.New(line, qrcode.Medium)
+}
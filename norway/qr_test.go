@@ -0,0 +1,26 @@
+//go:build !payqr_payloadonly
+
+package norway
+
+import (
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQR(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+	p := payqr.New("8601.11.17947", "Test AS", "", "12345678903", 1250, due, payqr.WithPaymentType(payqr.PaymentTypeBBAN))
+
+	q, err := QR(p)
+	require.NoError(t, err)
+	want, err := GiroLine(p)
+	require.NoError(t, err)
+	assert.Equal(t, want, q.Content)
+
+	_, err = QR(payqr.New("8601.11.17947", "Test AS", "", "12345678903", 1250, due, payqr.WithPaymentType(payqr.PaymentTypeBBAN), payqr.WithCurrency("SEK")))
+	assert.Error(t, err)
+}
@@ -0,0 +1,27 @@
+package payqr
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestOptionTypes checks that the option types of the package are distinct,
+// so that e.g. a SwishOption given to New does not compile.
+func TestOptionTypes(t *testing.T) {
+	types := []reflect.Type{
+		reflect.TypeOf(Option(nil)),
+		reflect.TypeOf(SwishOption(nil)),
+		reflect.TypeOf(DueDateOption(nil)),
+		reflect.TypeOf(FieldOption(nil)),
+	}
+
+	for _, a := range types {
+		for _, b := range types {
+			if a != b {
+				assert.False(t, a.AssignableTo(b), "%s is assignable to %s", a, b)
+			}
+		}
+	}
+}
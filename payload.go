@@ -0,0 +1,98 @@
+package payqr
+
+import "strconv"
+
+// AppendPayload appends the payload of the QR code of the payment to dst and
+// returns the extended buffer. The payload is the JSON of the payment, with
+// the fields in the order of the example of the specification, built without
+// the cost of reflection: uqr, tp, nme, cid, cc, iref, cref, idt, ddt, due,
+// cur, vat, vh, vm, vl, pt, acc, bc and adr. Unlike encoding/json of the
+// fields, the due amount and the VAT amounts are rounded to the minor units
+// of the currency, see RoundAmount, and extra fields follow the fields, see
+// WithExtraField. An error is returned for extra fields that are not
+// registered or are fields of the specification.
+func (d *Payment) AppendPayload(dst []byte) ([]byte, error) {
+	dst = append(dst, `{"uqr":`...)
+	dst = strconv.AppendInt(dst, int64(d.UsingQRVersion), 10)
+	dst = append(dst, `,"tp":`...)
+	dst = strconv.AppendInt(dst, int64(d.Type), 10)
+	dst = append(dst, `,"nme":`...)
+	dst = appendJSONString(dst, d.AccountName)
+	dst = append(dst, `,"cid":`...)
+	dst = appendJSONString(dst, d.CompanyID)
+	dst = appendOptionalString(dst, `,"cc":`, d.CountryCode)
+	dst = append(dst, `,"iref":`...)
+	dst = appendJSONString(dst, d.Reference)
+	dst = appendOptionalString(dst, `,"cref":`, d.CreditInvoiceReference)
+	dst = append(dst, `,"idt":`...)
+	dst = appendJSONString(dst, d.CreatedDate)
+	dst = append(dst, `,"ddt":`...)
+	dst = appendJSONString(dst, d.DueDate)
+	dst = append(dst, `,"due":`...)
+	dst, err := appendJSONFloat(dst, RoundAmount(d.DueAmount, d.Currency))
+	if err != nil {
+		return dst, err
+	}
+	dst = appendOptionalString(dst, `,"cur":`, d.Currency)
+	for _, vat := range []struct {
+		key    string
+		amount float64
+	}{{`,"vat":`, d.VAT}, {`,"vh":`, d.HighVAT}, {`,"vm":`, d.MediumVAT}, {`,"vl":`, d.LowVAT}} {
+		if dst, err = appendOptionalAmount(dst, vat.key, vat.amount, d.Currency); err != nil {
+			return dst, err
+		}
+	}
+	dst = append(dst, `,"pt":`...)
+	dst = appendJSONString(dst, string(d.PaymentType))
+	dst = append(dst, `,"acc":`...)
+	dst = appendJSONString(dst, d.AccountNumber)
+	dst = appendOptionalString(dst, `,"bc":`, d.BankCode)
+	dst = appendOptionalString(dst, `,"adr":`, d.Address)
+	if dst, err = d.appendExtraFields(dst); err != nil {
+		return dst, err
+	}
+
+	return append(dst, '}'), nil
+}
+
+// Payload returns the payload of the QR code of the payment, as encoded by
+// Payment.QR.
+func (d *Payment) Payload() (string, error) {
+	// Most payloads fit in the buffer, leaving the string as the only
+	// allocation.
+	var buf [256]byte
+	b, err := d.AppendPayload(buf[:0])
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// MarshalJSON returns the payload of the payment, so that the fields are in
+// the order of the specification and the optional fields are left out when
+// not set, see AppendPayload. It has a value receiver so that payments are
+// marshalled the same as pointers to them.
+func (d Payment) MarshalJSON() ([]byte, error) {
+	return d.AppendPayload(nil)
+}
+
+// appendOptionalString appends the key and value of an omitempty field
+// unless it is empty.
+func appendOptionalString(dst []byte, key, s string) []byte {
+	if s == "" {
+		return dst
+	}
+
+	return appendJSONString(append(dst, key...), s)
+}
+
+// appendOptionalAmount appends the key and the amount, rounded to the minor
+// units of the currency, of an omitempty field unless it is zero.
+func appendOptionalAmount(dst []byte, key string, amount float64, currency string) ([]byte, error) {
+	if amount == 0 {
+		return dst, nil
+	}
+
+	return appendJSONFloat(append(dst, key...), RoundAmount(amount, currency))
+}
@@ -0,0 +1,179 @@
+package payqr
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayload(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name    string
+		have    *Payment
+		wantErr bool
+	}{
+		{
+			name: "Invoice",
+			have: New("5402-9681", "Test AB", "5555555555", "52250047157", 1250, due, WithCreationDate(due)),
+		},
+		{
+			name: "All fields",
+			have: &Payment{
+				UsingQRVersion:         1,
+				Type:                   CreditInvoiceType,
+				AccountName:            "Test AB",
+				CompanyID:              "5555555555",
+				Reference:              "52250047157",
+				CreditInvoiceReference: "1234",
+				Currency:               "EUR",
+				VAT:                    250,
+				HighVAT:                200,
+				MediumVAT:              30,
+				LowVAT:                 20,
+				CreatedDate:            "20220801",
+				DueDate:                "20220806",
+				DueAmount:              1250.5,
+				PaymentType:            PaymentTypeIBAN,
+				AccountNumber:          "SE4550000000058398257466",
+				BankCode:               "ESSESESS",
+				CountryCode:            "SE",
+				Address:                "Storgatan 1\n111 22 Stockholm",
+			},
+		},
+		{
+			name: "Escaped strings",
+			have: New("5402-9681", "Smörgås & <Söner> \"AB\"\t \xff", "5555555555", "52250047157", 0, due),
+		},
+		{
+			name: "Large amount",
+			have: New("5402-9681", "Test AB", "5555555555", "52250047157", 1e21, due, WithAddress(""), WithCurrency("SEK")),
+		},
+		{
+			name: "Amount without minor units",
+			have: New("DE89370400440532013000", "Test GmbH", "1234", "1001", 1234567, due, WithCurrency("JPY")),
+		},
+		{
+			name: "Empty payment",
+			have: &Payment{},
+		},
+		{
+			name:    "Amount not a number",
+			have:    New("5402-9681", "Test AB", "5555555555", "52250047157", math.NaN(), due),
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// The payload has the fields encoding/json gives by the struct
+			// tags, without the MarshalJSON of the payment, but in the order
+			// of the specification, see TestPayloadOrder.
+			type plain Payment
+			want, err := json.Marshal((*plain)(test.have))
+
+			have, payloadErr := test.have.Payload()
+			if test.wantErr {
+				require.Error(t, err)
+				assert.EqualError(t, payloadErr, err.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, payloadErr)
+			assert.JSONEq(t, string(want), have)
+		})
+	}
+}
+
+func TestPayloadOrder(t *testing.T) {
+	// The example of a foreign payment of the specification.
+	d := New("DK4830004073013895", "Test company AB", "555555-5555", "934000000000159", 10.75, time.Date(2012, time.February, 15, 0, 0, 0, 0, time.Local),
+		WithCreationDate(time.Date(2012, time.February, 15, 0, 0, 0, 0, time.Local)), WithPaymentType(PaymentTypeIBAN), WithCurrency("DKK"), WithAddress("1092 Köpenhamn"), WithCountryCode("SE"), WithBankCode("DABADKKK"))
+
+	payload, err := d.Payload()
+	require.NoError(t, err)
+	assert.Equal(t, `{"uqr":1,"tp":1,"nme":"Test company AB","cid":"555555-5555","cc":"SE","iref":"934000000000159","idt":"20120215","ddt":"20120215","due":10.75,"cur":"DKK","pt":"IBAN","acc":"DK4830004073013895","bc":"DABADKKK","adr":"1092 Köpenhamn"}`, payload)
+
+	d = New("5402-9681", "Test AB", "5555555555", "52250047157", 1250, time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local),
+		WithCreationDate(time.Date(2022, time.August, 1, 0, 0, 0, 0, time.Local)), WithCreditInvoice("1234"), WithVATBreakdown(NewMoney(20000, "SEK"), NewMoney(0, "SEK"), NewMoney(0, "SEK")))
+
+	b, err := json.Marshal(d)
+	require.NoError(t, err)
+	assert.Equal(t, `{"uqr":1,"tp":2,"nme":"Test AB","cid":"5555555555","iref":"52250047157","cref":"1234","idt":"20220801","ddt":"20220806","due":-1250,"vat":200,"vh":200,"pt":"BG","acc":"5402-9681"}`, string(b))
+}
+
+func TestPayloadAllocs(t *testing.T) {
+	d := New("5402-9681", "Test AB", "5555555555", "52250047157", 1250, time.Now())
+
+	dst := make([]byte, 0, 512)
+	allocs := testing.AllocsPerRun(10, func() {
+		if _, err := d.AppendPayload(dst[:0]); err != nil {
+			t.Fatal(err)
+		}
+	})
+	assert.Zero(t, allocs)
+
+	allocs = testing.AllocsPerRun(10, func() {
+		if _, err := d.Payload(); err != nil {
+			t.Fatal(err)
+		}
+	})
+	assert.Equal(t, 1.0, allocs)
+}
+
+func BenchmarkPayload(b *testing.B) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+	d := New("5402-9681", "Test AB", "5555555555", "52250047157", 1250, due)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := d.Payload(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAppendPayload(b *testing.B) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+	d := New("5402-9681", "Test AB", "5555555555", "52250047157", 1250, due)
+
+	var (
+		dst []byte
+		err error
+	)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if dst, err = d.AppendPayload(dst[:0]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+	d := New("5402-9681", "Test AB", "5555555555", "52250047157", 0.1+0.2, due, WithCreationDate(due), WithAddress("Storgatan 1"))
+
+	payload, err := d.Payload()
+	require.NoError(t, err)
+
+	b, err := json.Marshal(d)
+	require.NoError(t, err)
+	assert.Equal(t, payload, string(b))
+
+	b, err = json.Marshal(*d)
+	require.NoError(t, err)
+	assert.Equal(t, payload, string(b))
+
+	b, err = json.Marshal(map[string]*Payment{"payment": d})
+	require.NoError(t, err)
+	assert.Equal(t, `{"payment":`+payload+`}`, string(b))
+
+	var decoded Payment
+	require.NoError(t, json.Unmarshal(b[len(`{"payment":`):len(b)-1], &decoded))
+	assert.True(t, d.Equal(&decoded))
+}
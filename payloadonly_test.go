@@ -0,0 +1,38 @@
+//go:build payqr_payloadonly
+
+package payqr
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayloadOnly(t *testing.T) {
+	assert.False(t, HasCapability(CapabilityPNG))
+
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+	tmpl, err := NewTemplate(New("5402-9681", "Test AB", "5555555555", "", 0, due, WithCreationDate(due)))
+	require.NoError(t, err)
+	payload, err := tmpl.Payload("52250047157", 1250)
+	require.NoError(t, err)
+	assert.Equal(t, `{"uqr":1,"tp":1,"nme":"Test AB","cid":"5555555555","iref":"52250047157","idt":"20220806","ddt":"20220806","due":1250,"pt":"BG","acc":"5402-9681"}`, payload)
+
+	assert.Equal(t, "C1231111111;50.00;My message;0", NewSwish("1231111111", 50, "My message").Encode())
+}
+
+func TestPayloadOnlyDependencies(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go is not installed")
+	}
+
+	out, err := exec.Command("go", "list", "-deps", "-tags", "payqr_payloadonly", "./...").Output()
+	require.NoError(t, err)
+	for _, pkg := range strings.Fields(string(out)) {
+		assert.NotEqual(t, "github.com/skip2/go-qrcode", pkg)
+	}
+}
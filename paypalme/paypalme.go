@@ -0,0 +1,72 @@
+// Package paypalme builds PayPal.Me links, https://paypal.me/<handle>/<amount>,
+// and renders them as QR codes.
+package paypalme
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/antonlindstrom/payqr"
+)
+
+// BaseURL is the base URL of PayPal.Me links.
+const BaseURL = "https://paypal.me/"
+
+var handle = regexp.MustCompile(`^[A-Za-z0-9]{1,20}$`)
+
+// currencies are the currencies accepted by PayPal.
+var currencies = map[string]bool{
+	"AUD": true, "BRL": true, "CAD": true, "CHF": true, "CZK": true, "DKK": true,
+	"EUR": true, "GBP": true, "HKD": true, "HUF": true, "ILS": true, "JPY": true,
+	"MXN": true, "MYR": true, "NOK": true, "NZD": true, "PHP": true, "PLN": true,
+	"SEK": true, "SGD": true, "THB": true, "TWD": true, "USD": true,
+}
+
+// noDecimals are the currencies PayPal does not accept decimals for.
+var noDecimals = map[string]bool{"HUF": true, "JPY": true, "TWD": true}
+
+// ValidateHandle checks that the PayPal.Me handle is 1 to 20 letters and
+// digits.
+func ValidateHandle(h string) error {
+	if !handle.MatchString(h) {
+		return fmt.Errorf("paypalme: invalid handle %q", h)
+	}
+
+	return nil
+}
+
+// Link returns the PayPal.Me link to the handle for the amount, such as
+// https://paypal.me/example/10.50EUR. A zero amount gives a link to the
+// handle where the payer enters it, an empty currency uses the default
+// currency of the account.
+func Link(h string, amount float64, currency string) (string, error) {
+	if err := ValidateHandle(h); err != nil {
+		return "", err
+	}
+	if amount < 0 {
+		return "", fmt.Errorf("paypalme: amount %.2f is negative", amount)
+	}
+	if amount == 0 {
+		return BaseURL + h, nil
+	}
+
+	currency = strings.ToUpper(currency)
+	if currency != "" && !currencies[currency] {
+		return "", fmt.Errorf("paypalme: currency %s is not supported", currency)
+	}
+
+	decimals := 2
+	if noDecimals[currency] {
+		decimals = 0
+	}
+
+	return BaseURL + h + "/" + strconv.FormatFloat(amount, 'f', decimals, 64) + currency, nil
+}
+
+// PaymentLink returns the PayPal.Me link of the payment, with the account
+// number as handle. The reference can not be carried by the link.
+func PaymentLink(p *payqr.Payment) (string, error) {
+	return Link(strings.TrimPrefix(p.AccountNumber, BaseURL), p.DueAmount, p.Currency)
+}
@@ -0,0 +1,50 @@
+package paypalme
+
+import (
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLink(t *testing.T) {
+	tests := []struct {
+		name     string
+		handle   string
+		amount   float64
+		currency string
+		want     string
+		wantErr  bool
+	}{
+		{name: "EUR", handle: "example", amount: 10.5, currency: "EUR", want: "https://paypal.me/example/10.50EUR"},
+		{name: "JPY", handle: "example", amount: 1000, currency: "jpy", want: "https://paypal.me/example/1000JPY"},
+		{name: "Default currency", handle: "example", amount: 5, want: "https://paypal.me/example/5.00"},
+		{name: "Open amount", handle: "example", want: "https://paypal.me/example"},
+		{name: "Invalid handle", handle: "ex ample", amount: 5, wantErr: true},
+		{name: "Long handle", handle: "abcdefghijklmnopqrstu", amount: 5, wantErr: true},
+		{name: "Unsupported currency", handle: "example", amount: 5, currency: "INR", wantErr: true},
+		{name: "Negative", handle: "example", amount: -1, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Link(test.handle, test.amount, test.currency)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestPaymentLink(t *testing.T) {
+	p := payqr.New("https://paypal.me/example", "Example", "", "", 25, time.Now(), payqr.WithCurrency("SEK"))
+
+	got, err := PaymentLink(p)
+	require.NoError(t, err)
+	assert.Equal(t, "https://paypal.me/example/25.00SEK", got)
+}
@@ -0,0 +1,18 @@
+//go:build !payqr_payloadonly
+
+package paypalme
+
+import (
+	"github.com/antonlindstrom/payqr"
+	"github.com/skip2/go-qrcode"
+)
+
+// QR returns a QR code of the PayPal.Me link of the payment.
+func QR(p *payqr.Payment) (*qrcode.QRCode, error) {
+	link, err := PaymentLink(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return qrcode.New(link, qrcode.Medium)
+}
@@ -0,0 +1,20 @@
+//go:build !payqr_payloadonly
+
+package paypalme
+
+import (
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQR(t *testing.T) {
+	p := payqr.New("https://paypal.me/example", "Example", "", "", 25, time.Now(), payqr.WithCurrency("SEK"))
+
+	q, err := QR(p)
+	require.NoError(t, err)
+	assert.Equal(t, "https://paypal.me/example/25.00SEK", q.Content)
+}
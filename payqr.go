@@ -4,13 +4,16 @@
 // Further documentation and basis of this library can be found at:
 // * https://www.qrkod.info/
 // * https://www.qrkod.info/specification.pdf
+//
+// Services that only need the payloads, such as the JSON of Payment or
+// SwishPayment.Encode, can build with the payqr_payloadonly tag. It leaves
+// out the QR codes and everything rendering them, so that go-qrcode and the
+// image packages are not linked in.
 package payqr
 
 import (
-	"encoding/json"
+	"math"
 	"time"
-
-	"github.com/skip2/go-qrcode"
 )
 
 // Type defines the type of QR transfer.
@@ -35,6 +38,12 @@ const (
 
 // Payment is the structure for storing the data about a payment. Shoulc not
 // be used directly but can be used as you see fit.
+//
+// The amounts, DueAmount and the VAT fields, are stored as floats, which
+// cannot represent most decimal amounts exactly. They are only rounded to
+// the minor units of the currency when the payment is encoded, see
+// RoundAmount. Set them with Money, see WithDue and WithVAT, and read them
+// with Due and TotalVAT to keep the arithmetic of callers exact.
 type Payment struct {
 	UsingQRVersion         int         `json:"uqr"`
 	Type                   Type        `json:"tp"`
@@ -43,10 +52,10 @@ type Payment struct {
 	Reference              string      `json:"iref"`
 	CreditInvoiceReference string      `json:"cref,omitempty"`
 	Currency               string      `json:"cur,omitempty"`
-	VAT                    int         `json:"vat,omitempty"`
-	HighVAT                int         `json:"vh,omitempty"`
-	MediumVAT              int         `json:"vm,omitempty"`
-	LowVAT                 int         `json:"vl,omitempty"`
+	VAT                    float64     `json:"vat,omitempty"`
+	HighVAT                float64     `json:"vh,omitempty"`
+	MediumVAT              float64     `json:"vm,omitempty"`
+	LowVAT                 float64     `json:"vl,omitempty"`
 	CreatedDate            string      `json:"idt"`
 	DueDate                string      `json:"ddt"`
 	DueAmount              float64     `json:"due"`
@@ -56,7 +65,8 @@ type Payment struct {
 	CountryCode            string      `json:"cc,omitempty"`
 	Address                string      `json:"adr,omitempty"`
 
-	swishEditableFields byte
+	referenceScheme ReferenceScheme
+	extra           map[string]string
 }
 
 // Option is a modifyier for a Payment to add more data to it.
@@ -115,8 +125,23 @@ func WithBankCode(bankCode string) Option {
 	}
 }
 
+// WithCreditInvoice makes the payment a credit invoice of the invoice with
+// the reference, such as a credit note. The due amount is the credited
+// amount, which is negative in the payload.
+func WithCreditInvoice(invoiceReference string) Option {
+	return func(p *Payment) {
+		p.Type = CreditInvoiceType
+		p.CreditInvoiceReference = invoiceReference
+		p.DueAmount = -math.Abs(p.DueAmount)
+	}
+}
+
 // New creates a new payment with the defined options. The input should give a
 // fair default but may be modified with options.
+//
+// The due amount is a float for compatibility, which cannot represent most
+// decimal amounts exactly. Prefer giving zero and the amount as Money with
+// WithDue.
 func New(accountNumber, accountName, companyID, reference string, dueAmount float64, dueDate time.Time, options ...Option) *Payment {
 	p := &Payment{
 		UsingQRVersion: 1,
@@ -138,6 +163,22 @@ func New(accountNumber, accountName, companyID, reference string, dueAmount floa
 	return p
 }
 
+// NewValidated creates a new payment as New and validates it, so that
+// invalid input fails when the payment is created rather than when it is
+// encoded. The payment is checked by Validate and ValidateDueDate with the
+// default checks.
+func NewValidated(accountNumber, accountName, companyID, reference string, dueAmount float64, dueDate time.Time, options ...Option) (*Payment, error) {
+	p := New(accountNumber, accountName, companyID, reference, dueAmount, dueDate, options...)
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	if err := p.ValidateDueDate(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
 // HasRequiredFields checks if the payment has the required fields set per
 // Type.
 func (d *Payment) HasRequiredFields() bool {
@@ -158,12 +199,27 @@ func (d *Payment) HasRequiredFields() bool {
 	return true
 }
 
-// QR returns a QR code that can be used to communicate how to send transfers.
-func (d *Payment) QR() (*qrcode.QRCode, error) {
-	b, err := json.Marshal(d)
-	if err != nil {
-		return nil, err
+// validateCredit checks that credit invoices carry the reference of the
+// credited invoice and a negative amount, and that other payments carry
+// neither.
+func (d *Payment) validateCredit() error {
+	if d.Type == CreditInvoiceType {
+		if d.CreditInvoiceReference == "" {
+			return invalid("cref", ErrInvalidField, "credit invoice requires the reference of the credited invoice")
+		}
+		if d.DueAmount > 0 {
+			return invalid("due", ErrInvalidAmount, "credit invoice amount %.2f must be negative", d.DueAmount)
+		}
+
+		return nil
+	}
+
+	if d.CreditInvoiceReference != "" {
+		return invalid("cref", ErrInvalidField, "credited invoice reference %q is only allowed on credit invoices", d.CreditInvoiceReference)
+	}
+	if d.DueAmount < 0 {
+		return invalid("due", ErrInvalidAmount, "negative amount %.2f is only allowed on credit invoices", d.DueAmount)
 	}
 
-	return qrcode.New(string(b), qrcode.High)
+	return nil
 }
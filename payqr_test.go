@@ -1,3 +1,5 @@
+//go:build !payqr_payloadonly
+
 package payqr
 
 import (
@@ -133,3 +135,86 @@ func ExamplePayment_QR() {
 	fmt.Printf(`<img src="data:image/png;base64,%s" alt="QR code" />`, base64.StdEncoding.EncodeToString(b))
 	// Output: <img src="data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAgAAAAIAAQMAAADOtka5AAAABlBMVEX///8AAABVwtN+AAAFTUlEQVR42uydPbKkOgyF1UVAyBJYCkuDpbEUluCQgEJT1pFsmts90QSD73Hw6o2r55tEZf0dCeHh4eHh4eHh+T/PpHZ26Y5OT1ERmddpHTUNqdc4MusyreMmMuDiJKAxwJr/O+y9Ht15oW6Dpl73/KeXii75f0bdZMAVAQTcAaNqytfdISJmZuuk25hkMPt8ZUOUeZlXkXGzf0oPAloF5Gu7UPupyJAkG1Jnj48us+o6bmMigIC/Aw7Bi6T59Zk2+LA9W+KZ72ZdxVwbAY0C4JlEpNPL2YYk0u9OlVnNkOS7ayPg2QAPdfujy+ah9qDkqHbI1pUN6bQIZbZQd0xfY2UCfjcgTu/Xml8kPFO49J8awC6/HAKeDZi20QwppzwvpMnr1TPZ64MIReovCSDgHYDHx5wYXqRsc2pFGEU0k6GXUBdUAloDWLlEVY9sM5Ym5+R5E3imHPfYO+WJkODyJKAxgD0oZkiCBwXZTX47Ev6+oohilRULdfvjGuoSQAAs0ZxQ8TfwTOIvkoW6XqJT+/upFFsIaAugHtUeqKBYpFvLJYh1Uav1ULd/r6EQQACKMCjkd54eoRjn/m7Hj+yZUrW3q2RXBDQF0JIQ5xfJ28b5cjDPdIgHPovIiOwIQTEBbQGQ3eQ8prOqmcA1IdTVw0PdJWM3L7YcQgABN0C4pj6afajkuzhFUJT1V8qjIbnlTAQ0AdjCZvD0QI+Etq/01ZDMh9m/dDckAgiwYMYCF+nCFHWZ1pJJRSIlKMaZNuUeKxPQBsANyeqvltyY8sh0aTmojVu7RgvwPdQloAEAxGYJoe4rfBIq9mJitRCmWdfX3dVbu48AAmqoq4cg1A1NaymXlFAX13u/v3eNCWgCoOWnkTsHNfXZM2mYF4rzKON/qKEQ8LsB8GLoF9Y6/jpGMS7ahajuj3FJQGMAT2SkjxcpGnvDmx5p1kWgp5efQlcCng9A19cEz7X8mlPf91oaDAm/vIW6BBAQLxKqJaZH8m6fl1B2VPddnFIskYD2AFOkOailaTGkeuneyrs8un8c9iXg2QCLOkwc0vlYr9XSPIDFaB6oKM7v/X7PnQkg4GKIIT3C9JVClya122e3yYd2TgIaA6w+QRERSunHXIuyMZI1Xoc5CSDgVpTF8gD0eKCczzGOQhx9qe7njPre5SGgEYCMVjZzv2ROCBp5n6S56JGgM7i7NgJaAECDhrDWDMllJAldX/RofIJiDHd1EkDAOyA8k4+C2+OzlpwplgdgbHyLNPvn1DkBDwfYsIQMu3QuH7Byib9Ie4/RPLWZb3WdKwEE/AQgZwq5GcY5V7nkTKW6P62jbt8WrhHwfMA2pKH29SB4rpM0Z5nJ+hzqEtAEAEMzCY09Vw65Z7LUt8iJYm7zQ3GeAAJcOH8JdWNPgHsmxy6zTZLLkD7pUAh4PsAUIwmC55fX4UvYYsHIS6s22l3YfQsFAQR4AOt78zDbF88UdqjZ3jwbyYJrk7vkmoDnA8RVhxHpio/6+vCVdKdl1LE377JRgICmAFJmvn0RkQ9L1C1VnvtetysqAQR8XX9YirJrESmZprUsPfJlevu3xXsEPBdQPx3gZbP7nJbAkpZoCH3Yk0UAAfXTAZAjiXi7MBaPHB7qzlXguH/QoRDweEDZ9F1CnMkFSdULqQvnQ7pEQLsAC0Yk9ieO10EaTFDYp0Skh0aeAAI+Auq3SIqkNUnZDh8TFNvgBToCmgPEt4miySPzRVHgW2nwTZr4dEB3fPy4EQG/GlC/lobPWcUmowHz4V2ZyEJ1/9sIBwHPBvDw8PDw8PDw/MvzJwAA//9bOjn9jq1/AAAAAABJRU5ErkJggg==" alt="QR code" />
 }
+
+func TestSwishQRValidation(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name        string
+		have        *Payment
+		haveOptions []SwishOption
+		wantErr     bool
+	}{
+		{
+			name: "Within default limits",
+			have: New("5536-7742", "Test AB", "1234", "My message", 50, due),
+		},
+		{
+			name:    "Below minimum amount",
+			have:    New("5536-7742", "Test AB", "1234", "My message", 0.5, due),
+			wantErr: true,
+		},
+		{
+			name:    "Locked amount of zero",
+			have:    New("5536-7742", "Test AB", "1234", "My message", 0, due),
+			wantErr: true,
+		},
+		{
+			name:        "Editable amount of zero",
+			have:        New("5536-7742", "Test AB", "1234", "My message", 0, due),
+			haveOptions: []SwishOption{WithEditableFields(SwishAmountEditable)},
+		},
+		{
+			name:    "Locked empty message",
+			have:    New("5536-7742", "Test AB", "1234", "", 50, due),
+			wantErr: true,
+		},
+		{
+			name:        "Editable empty message",
+			have:        New("5536-7742", "Test AB", "1234", "", 50, due),
+			haveOptions: []SwishOption{WithEditableFields(SwishMessageEditable)},
+		},
+		{
+			name:        "Unknown editable fields",
+			have:        New("5536-7742", "Test AB", "1234", "My message", 50, due),
+			haveOptions: []SwishOption{WithEditableFields(0b1000)},
+			wantErr:     true,
+		},
+		{
+			name:        "Above merchant maximum",
+			have:        New("5536-7742", "Test AB", "1234", "My message", 5000, due),
+			haveOptions: []SwishOption{WithAmountLimits(10, 1000)},
+			wantErr:     true,
+		},
+		{
+			name:        "Below merchant minimum",
+			have:        New("5536-7742", "Test AB", "1234", "My message", 5, due),
+			haveOptions: []SwishOption{WithAmountLimits(10, 1000)},
+			wantErr:     true,
+		},
+		{
+			name: "Explicit SEK currency",
+			have: New("5536-7742", "Test AB", "1234", "My message", 50, due, WithCurrency("SEK")),
+		},
+		{
+			name: "Lowercase SEK currency",
+			have: New("5536-7742", "Test AB", "1234", "My message", 50, due, WithCurrency("sek")),
+		},
+		{
+			name:    "Non-SEK currency",
+			have:    New("5536-7742", "Test AB", "1234", "My message", 50, due, WithCurrency("EUR")),
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := test.have.SwishQR("1231111111", test.haveOptions...)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,777 @@
+// Schema of the payqr gRPC service, generating payment QR codes for services
+// written in other languages.
+//
+// The Go code is generated with protoc-gen-go and protoc-gen-go-grpc:
+//
+//	protoc --go_out=. --go_opt=paths=source_relative \
+//		--go-grpc_out=. --go-grpc_opt=paths=source_relative payqr.proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: payqr.proto
+
+package payqrpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Type is the type of an invoice payment.
+type Type int32
+
+const (
+	Type_TYPE_UNSPECIFIED       Type = 0 // An invoice.
+	Type_TYPE_INVOICE           Type = 1
+	Type_TYPE_CREDIT_INVOICE    Type = 2
+	Type_TYPE_CASH_PAID_INVOICE Type = 3
+)
+
+// Enum value maps for Type.
+var (
+	Type_name = map[int32]string{
+		0: "TYPE_UNSPECIFIED",
+		1: "TYPE_INVOICE",
+		2: "TYPE_CREDIT_INVOICE",
+		3: "TYPE_CASH_PAID_INVOICE",
+	}
+	Type_value = map[string]int32{
+		"TYPE_UNSPECIFIED":       0,
+		"TYPE_INVOICE":           1,
+		"TYPE_CREDIT_INVOICE":    2,
+		"TYPE_CASH_PAID_INVOICE": 3,
+	}
+)
+
+func (x Type) Enum() *Type {
+	p := new(Type)
+	*p = x
+	return p
+}
+
+func (x Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_payqr_proto_enumTypes[0].Descriptor()
+}
+
+func (Type) Type() protoreflect.EnumType {
+	return &file_payqr_proto_enumTypes[0]
+}
+
+func (x Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Type.Descriptor instead.
+func (Type) EnumDescriptor() ([]byte, []int) {
+	return file_payqr_proto_rawDescGZIP(), []int{0}
+}
+
+// PaymentType is the transfer system of a payment.
+type PaymentType int32
+
+const (
+	PaymentType_PAYMENT_TYPE_UNSPECIFIED PaymentType = 0 // Bankgiro.
+	PaymentType_PAYMENT_TYPE_BG          PaymentType = 1
+	PaymentType_PAYMENT_TYPE_PG          PaymentType = 2
+	PaymentType_PAYMENT_TYPE_IBAN        PaymentType = 3
+	PaymentType_PAYMENT_TYPE_BBAN        PaymentType = 4
+)
+
+// Enum value maps for PaymentType.
+var (
+	PaymentType_name = map[int32]string{
+		0: "PAYMENT_TYPE_UNSPECIFIED",
+		1: "PAYMENT_TYPE_BG",
+		2: "PAYMENT_TYPE_PG",
+		3: "PAYMENT_TYPE_IBAN",
+		4: "PAYMENT_TYPE_BBAN",
+	}
+	PaymentType_value = map[string]int32{
+		"PAYMENT_TYPE_UNSPECIFIED": 0,
+		"PAYMENT_TYPE_BG":          1,
+		"PAYMENT_TYPE_PG":          2,
+		"PAYMENT_TYPE_IBAN":        3,
+		"PAYMENT_TYPE_BBAN":        4,
+	}
+)
+
+func (x PaymentType) Enum() *PaymentType {
+	p := new(PaymentType)
+	*p = x
+	return p
+}
+
+func (x PaymentType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PaymentType) Descriptor() protoreflect.EnumDescriptor {
+	return file_payqr_proto_enumTypes[1].Descriptor()
+}
+
+func (PaymentType) Type() protoreflect.EnumType {
+	return &file_payqr_proto_enumTypes[1]
+}
+
+func (x PaymentType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PaymentType.Descriptor instead.
+func (PaymentType) EnumDescriptor() ([]byte, []int) {
+	return file_payqr_proto_rawDescGZIP(), []int{1}
+}
+
+// Format is an image format.
+type Format int32
+
+const (
+	Format_FORMAT_UNSPECIFIED Format = 0 // PNG.
+	Format_FORMAT_PNG         Format = 1
+	Format_FORMAT_SVG         Format = 2
+)
+
+// Enum value maps for Format.
+var (
+	Format_name = map[int32]string{
+		0: "FORMAT_UNSPECIFIED",
+		1: "FORMAT_PNG",
+		2: "FORMAT_SVG",
+	}
+	Format_value = map[string]int32{
+		"FORMAT_UNSPECIFIED": 0,
+		"FORMAT_PNG":         1,
+		"FORMAT_SVG":         2,
+	}
+)
+
+func (x Format) Enum() *Format {
+	p := new(Format)
+	*p = x
+	return p
+}
+
+func (x Format) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Format) Descriptor() protoreflect.EnumDescriptor {
+	return file_payqr_proto_enumTypes[2].Descriptor()
+}
+
+func (Format) Type() protoreflect.EnumType {
+	return &file_payqr_proto_enumTypes[2]
+}
+
+func (x Format) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Format.Descriptor instead.
+func (Format) EnumDescriptor() ([]byte, []int) {
+	return file_payqr_proto_rawDescGZIP(), []int{2}
+}
+
+// Payment is an invoice payment. Dates are formatted as YYYY-MM-DD.
+type Payment struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	Type                   Type                   `protobuf:"varint,1,opt,name=type,proto3,enum=payqr.v1.Type" json:"type,omitempty"`
+	AccountName            string                 `protobuf:"bytes,2,opt,name=account_name,json=accountName,proto3" json:"account_name,omitempty"`
+	CompanyId              string                 `protobuf:"bytes,3,opt,name=company_id,json=companyId,proto3" json:"company_id,omitempty"`
+	Reference              string                 `protobuf:"bytes,4,opt,name=reference,proto3" json:"reference,omitempty"`
+	CreditInvoiceReference string                 `protobuf:"bytes,5,opt,name=credit_invoice_reference,json=creditInvoiceReference,proto3" json:"credit_invoice_reference,omitempty"`
+	Currency               string                 `protobuf:"bytes,6,opt,name=currency,proto3" json:"currency,omitempty"`
+	CreatedDate            string                 `protobuf:"bytes,11,opt,name=created_date,json=createdDate,proto3" json:"created_date,omitempty"` // Default is today.
+	DueDate                string                 `protobuf:"bytes,12,opt,name=due_date,json=dueDate,proto3" json:"due_date,omitempty"`
+	DueAmount              float64                `protobuf:"fixed64,13,opt,name=due_amount,json=dueAmount,proto3" json:"due_amount,omitempty"`
+	PaymentType            PaymentType            `protobuf:"varint,14,opt,name=payment_type,json=paymentType,proto3,enum=payqr.v1.PaymentType" json:"payment_type,omitempty"`
+	AccountNumber          string                 `protobuf:"bytes,15,opt,name=account_number,json=accountNumber,proto3" json:"account_number,omitempty"`
+	BankCode               string                 `protobuf:"bytes,16,opt,name=bank_code,json=bankCode,proto3" json:"bank_code,omitempty"`
+	CountryCode            string                 `protobuf:"bytes,17,opt,name=country_code,json=countryCode,proto3" json:"country_code,omitempty"`
+	Address                string                 `protobuf:"bytes,18,opt,name=address,proto3" json:"address,omitempty"`
+	Vat                    float64                `protobuf:"fixed64,19,opt,name=vat,proto3" json:"vat,omitempty"`
+	HighVat                float64                `protobuf:"fixed64,20,opt,name=high_vat,json=highVat,proto3" json:"high_vat,omitempty"`
+	MediumVat              float64                `protobuf:"fixed64,21,opt,name=medium_vat,json=mediumVat,proto3" json:"medium_vat,omitempty"`
+	LowVat                 float64                `protobuf:"fixed64,22,opt,name=low_vat,json=lowVat,proto3" json:"low_vat,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *Payment) Reset() {
+	*x = Payment{}
+	mi := &file_payqr_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Payment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Payment) ProtoMessage() {}
+
+func (x *Payment) ProtoReflect() protoreflect.Message {
+	mi := &file_payqr_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Payment.ProtoReflect.Descriptor instead.
+func (*Payment) Descriptor() ([]byte, []int) {
+	return file_payqr_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Payment) GetType() Type {
+	if x != nil {
+		return x.Type
+	}
+	return Type_TYPE_UNSPECIFIED
+}
+
+func (x *Payment) GetAccountName() string {
+	if x != nil {
+		return x.AccountName
+	}
+	return ""
+}
+
+func (x *Payment) GetCompanyId() string {
+	if x != nil {
+		return x.CompanyId
+	}
+	return ""
+}
+
+func (x *Payment) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+func (x *Payment) GetCreditInvoiceReference() string {
+	if x != nil {
+		return x.CreditInvoiceReference
+	}
+	return ""
+}
+
+func (x *Payment) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Payment) GetCreatedDate() string {
+	if x != nil {
+		return x.CreatedDate
+	}
+	return ""
+}
+
+func (x *Payment) GetDueDate() string {
+	if x != nil {
+		return x.DueDate
+	}
+	return ""
+}
+
+func (x *Payment) GetDueAmount() float64 {
+	if x != nil {
+		return x.DueAmount
+	}
+	return 0
+}
+
+func (x *Payment) GetPaymentType() PaymentType {
+	if x != nil {
+		return x.PaymentType
+	}
+	return PaymentType_PAYMENT_TYPE_UNSPECIFIED
+}
+
+func (x *Payment) GetAccountNumber() string {
+	if x != nil {
+		return x.AccountNumber
+	}
+	return ""
+}
+
+func (x *Payment) GetBankCode() string {
+	if x != nil {
+		return x.BankCode
+	}
+	return ""
+}
+
+func (x *Payment) GetCountryCode() string {
+	if x != nil {
+		return x.CountryCode
+	}
+	return ""
+}
+
+func (x *Payment) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Payment) GetVat() float64 {
+	if x != nil {
+		return x.Vat
+	}
+	return 0
+}
+
+func (x *Payment) GetHighVat() float64 {
+	if x != nil {
+		return x.HighVat
+	}
+	return 0
+}
+
+func (x *Payment) GetMediumVat() float64 {
+	if x != nil {
+		return x.MediumVat
+	}
+	return 0
+}
+
+func (x *Payment) GetLowVat() float64 {
+	if x != nil {
+		return x.LowVat
+	}
+	return 0
+}
+
+// GenerateRequest is a request for the QR code of an invoice payment.
+type GenerateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Payment       *Payment               `protobuf:"bytes,1,opt,name=payment,proto3" json:"payment,omitempty"`
+	Size          int32                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"` // Size in pixels, default 512.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateRequest) Reset() {
+	*x = GenerateRequest{}
+	mi := &file_payqr_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateRequest) ProtoMessage() {}
+
+func (x *GenerateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payqr_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateRequest.ProtoReflect.Descriptor instead.
+func (*GenerateRequest) Descriptor() ([]byte, []int) {
+	return file_payqr_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GenerateRequest) GetPayment() *Payment {
+	if x != nil {
+		return x.Payment
+	}
+	return nil
+}
+
+func (x *GenerateRequest) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+// GenerateSwishRequest is a request for the QR code of a Swish payment.
+type GenerateSwishRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Payee           string                 `protobuf:"bytes,1,opt,name=payee,proto3" json:"payee,omitempty"` // Phone or Swish number.
+	Amount          float64                `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Message         string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	OpenAmount      bool                   `protobuf:"varint,4,opt,name=open_amount,json=openAmount,proto3" json:"open_amount,omitempty"` // Leave the amount for the payer to fill in.
+	AmountEditable  bool                   `protobuf:"varint,5,opt,name=amount_editable,json=amountEditable,proto3" json:"amount_editable,omitempty"`
+	MessageEditable bool                   `protobuf:"varint,6,opt,name=message_editable,json=messageEditable,proto3" json:"message_editable,omitempty"`
+	Format          Format                 `protobuf:"varint,7,opt,name=format,proto3,enum=payqr.v1.Format" json:"format,omitempty"`
+	Size            int32                  `protobuf:"varint,8,opt,name=size,proto3" json:"size,omitempty"` // Size in pixels, default 512.
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GenerateSwishRequest) Reset() {
+	*x = GenerateSwishRequest{}
+	mi := &file_payqr_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateSwishRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateSwishRequest) ProtoMessage() {}
+
+func (x *GenerateSwishRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payqr_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateSwishRequest.ProtoReflect.Descriptor instead.
+func (*GenerateSwishRequest) Descriptor() ([]byte, []int) {
+	return file_payqr_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GenerateSwishRequest) GetPayee() string {
+	if x != nil {
+		return x.Payee
+	}
+	return ""
+}
+
+func (x *GenerateSwishRequest) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *GenerateSwishRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GenerateSwishRequest) GetOpenAmount() bool {
+	if x != nil {
+		return x.OpenAmount
+	}
+	return false
+}
+
+func (x *GenerateSwishRequest) GetAmountEditable() bool {
+	if x != nil {
+		return x.AmountEditable
+	}
+	return false
+}
+
+func (x *GenerateSwishRequest) GetMessageEditable() bool {
+	if x != nil {
+		return x.MessageEditable
+	}
+	return false
+}
+
+func (x *GenerateSwishRequest) GetFormat() Format {
+	if x != nil {
+		return x.Format
+	}
+	return Format_FORMAT_UNSPECIFIED
+}
+
+func (x *GenerateSwishRequest) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+// Image is a rendered QR code.
+type Image struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	ContentType   string                 `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Payload       string                 `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"` // Text encoded in the code.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Image) Reset() {
+	*x = Image{}
+	mi := &file_payqr_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Image) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Image) ProtoMessage() {}
+
+func (x *Image) ProtoReflect() protoreflect.Message {
+	mi := &file_payqr_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Image.ProtoReflect.Descriptor instead.
+func (*Image) Descriptor() ([]byte, []int) {
+	return file_payqr_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Image) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *Image) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *Image) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+// ValidateResponse is the result of validating a payment.
+type ValidateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Valid         bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"` // Why the payment is invalid.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateResponse) Reset() {
+	*x = ValidateResponse{}
+	mi := &file_payqr_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateResponse) ProtoMessage() {}
+
+func (x *ValidateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payqr_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateResponse.ProtoReflect.Descriptor instead.
+func (*ValidateResponse) Descriptor() ([]byte, []int) {
+	return file_payqr_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ValidateResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *ValidateResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+var File_payqr_proto protoreflect.FileDescriptor
+
+const file_payqr_proto_rawDesc = "" +
+	"\n" +
+	"\vpayqr.proto\x12\bpayqr.v1\"\xe6\x04\n" +
+	"\aPayment\x12\"\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x0e.payqr.v1.TypeR\x04type\x12!\n" +
+	"\faccount_name\x18\x02 \x01(\tR\vaccountName\x12\x1d\n" +
+	"\n" +
+	"company_id\x18\x03 \x01(\tR\tcompanyId\x12\x1c\n" +
+	"\treference\x18\x04 \x01(\tR\treference\x128\n" +
+	"\x18credit_invoice_reference\x18\x05 \x01(\tR\x16creditInvoiceReference\x12\x1a\n" +
+	"\bcurrency\x18\x06 \x01(\tR\bcurrency\x12!\n" +
+	"\fcreated_date\x18\v \x01(\tR\vcreatedDate\x12\x19\n" +
+	"\bdue_date\x18\f \x01(\tR\adueDate\x12\x1d\n" +
+	"\n" +
+	"due_amount\x18\r \x01(\x01R\tdueAmount\x128\n" +
+	"\fpayment_type\x18\x0e \x01(\x0e2\x15.payqr.v1.PaymentTypeR\vpaymentType\x12%\n" +
+	"\x0eaccount_number\x18\x0f \x01(\tR\raccountNumber\x12\x1b\n" +
+	"\tbank_code\x18\x10 \x01(\tR\bbankCode\x12!\n" +
+	"\fcountry_code\x18\x11 \x01(\tR\vcountryCode\x12\x18\n" +
+	"\aaddress\x18\x12 \x01(\tR\aaddress\x12\x10\n" +
+	"\x03vat\x18\x13 \x01(\x01R\x03vat\x12\x19\n" +
+	"\bhigh_vat\x18\x14 \x01(\x01R\ahighVat\x12\x1d\n" +
+	"\n" +
+	"medium_vat\x18\x15 \x01(\x01R\tmediumVat\x12\x17\n" +
+	"\alow_vat\x18\x16 \x01(\x01R\x06lowVatJ\x04\b\a\x10\v\"R\n" +
+	"\x0fGenerateRequest\x12+\n" +
+	"\apayment\x18\x01 \x01(\v2\x11.payqr.v1.PaymentR\apayment\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x05R\x04size\"\x91\x02\n" +
+	"\x14GenerateSwishRequest\x12\x14\n" +
+	"\x05payee\x18\x01 \x01(\tR\x05payee\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x01R\x06amount\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x12\x1f\n" +
+	"\vopen_amount\x18\x04 \x01(\bR\n" +
+	"openAmount\x12'\n" +
+	"\x0famount_editable\x18\x05 \x01(\bR\x0eamountEditable\x12)\n" +
+	"\x10message_editable\x18\x06 \x01(\bR\x0fmessageEditable\x12(\n" +
+	"\x06format\x18\a \x01(\x0e2\x10.payqr.v1.FormatR\x06format\x12\x12\n" +
+	"\x04size\x18\b \x01(\x05R\x04size\"X\n" +
+	"\x05Image\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\x12\x18\n" +
+	"\apayload\x18\x03 \x01(\tR\apayload\">\n" +
+	"\x10ValidateResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error*c\n" +
+	"\x04Type\x12\x14\n" +
+	"\x10TYPE_UNSPECIFIED\x10\x00\x12\x10\n" +
+	"\fTYPE_INVOICE\x10\x01\x12\x17\n" +
+	"\x13TYPE_CREDIT_INVOICE\x10\x02\x12\x1a\n" +
+	"\x16TYPE_CASH_PAID_INVOICE\x10\x03*\x83\x01\n" +
+	"\vPaymentType\x12\x1c\n" +
+	"\x18PAYMENT_TYPE_UNSPECIFIED\x10\x00\x12\x13\n" +
+	"\x0fPAYMENT_TYPE_BG\x10\x01\x12\x13\n" +
+	"\x0fPAYMENT_TYPE_PG\x10\x02\x12\x15\n" +
+	"\x11PAYMENT_TYPE_IBAN\x10\x03\x12\x15\n" +
+	"\x11PAYMENT_TYPE_BBAN\x10\x04*@\n" +
+	"\x06Format\x12\x16\n" +
+	"\x12FORMAT_UNSPECIFIED\x10\x00\x12\x0e\n" +
+	"\n" +
+	"FORMAT_PNG\x10\x01\x12\x0e\n" +
+	"\n" +
+	"FORMAT_SVG\x10\x022\xfa\x01\n" +
+	"\x05PayQR\x129\n" +
+	"\vGeneratePNG\x12\x19.payqr.v1.GenerateRequest\x1a\x0f.payqr.v1.Image\x129\n" +
+	"\vGenerateSVG\x12\x19.payqr.v1.GenerateRequest\x1a\x0f.payqr.v1.Image\x12@\n" +
+	"\rGenerateSwish\x12\x1e.payqr.v1.GenerateSwishRequest\x1a\x0f.payqr.v1.Image\x129\n" +
+	"\bValidate\x12\x11.payqr.v1.Payment\x1a\x1a.payqr.v1.ValidateResponseB3Z1github.com/antonlindstrom/payqr/payqrgrpc/payqrpbb\x06proto3"
+
+var (
+	file_payqr_proto_rawDescOnce sync.Once
+	file_payqr_proto_rawDescData []byte
+)
+
+func file_payqr_proto_rawDescGZIP() []byte {
+	file_payqr_proto_rawDescOnce.Do(func() {
+		file_payqr_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_payqr_proto_rawDesc), len(file_payqr_proto_rawDesc)))
+	})
+	return file_payqr_proto_rawDescData
+}
+
+var file_payqr_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_payqr_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_payqr_proto_goTypes = []any{
+	(Type)(0),                    // 0: payqr.v1.Type
+	(PaymentType)(0),             // 1: payqr.v1.PaymentType
+	(Format)(0),                  // 2: payqr.v1.Format
+	(*Payment)(nil),              // 3: payqr.v1.Payment
+	(*GenerateRequest)(nil),      // 4: payqr.v1.GenerateRequest
+	(*GenerateSwishRequest)(nil), // 5: payqr.v1.GenerateSwishRequest
+	(*Image)(nil),                // 6: payqr.v1.Image
+	(*ValidateResponse)(nil),     // 7: payqr.v1.ValidateResponse
+}
+var file_payqr_proto_depIdxs = []int32{
+	0, // 0: payqr.v1.Payment.type:type_name -> payqr.v1.Type
+	1, // 1: payqr.v1.Payment.payment_type:type_name -> payqr.v1.PaymentType
+	3, // 2: payqr.v1.GenerateRequest.payment:type_name -> payqr.v1.Payment
+	2, // 3: payqr.v1.GenerateSwishRequest.format:type_name -> payqr.v1.Format
+	4, // 4: payqr.v1.PayQR.GeneratePNG:input_type -> payqr.v1.GenerateRequest
+	4, // 5: payqr.v1.PayQR.GenerateSVG:input_type -> payqr.v1.GenerateRequest
+	5, // 6: payqr.v1.PayQR.GenerateSwish:input_type -> payqr.v1.GenerateSwishRequest
+	3, // 7: payqr.v1.PayQR.Validate:input_type -> payqr.v1.Payment
+	6, // 8: payqr.v1.PayQR.GeneratePNG:output_type -> payqr.v1.Image
+	6, // 9: payqr.v1.PayQR.GenerateSVG:output_type -> payqr.v1.Image
+	6, // 10: payqr.v1.PayQR.GenerateSwish:output_type -> payqr.v1.Image
+	7, // 11: payqr.v1.PayQR.Validate:output_type -> payqr.v1.ValidateResponse
+	8, // [8:12] is the sub-list for method output_type
+	4, // [4:8] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_payqr_proto_init() }
+func file_payqr_proto_init() {
+	if File_payqr_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_payqr_proto_rawDesc), len(file_payqr_proto_rawDesc)),
+			NumEnums:      3,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_payqr_proto_goTypes,
+		DependencyIndexes: file_payqr_proto_depIdxs,
+		EnumInfos:         file_payqr_proto_enumTypes,
+		MessageInfos:      file_payqr_proto_msgTypes,
+	}.Build()
+	File_payqr_proto = out.File
+	file_payqr_proto_goTypes = nil
+	file_payqr_proto_depIdxs = nil
+}
@@ -0,0 +1,236 @@
+//go:build !payqr_payloadonly
+
+// Package payqrgrpc serves the generation of payment QR codes over gRPC, so
+// that services in other languages can call the generator without shelling
+// out to the payqr command. The schema is payqrpb/payqr.proto.
+//
+// Register the server on a gRPC server:
+//
+//	s := grpc.NewServer()
+//	payqrpb.RegisterPayQRServer(s, payqrgrpc.NewServer())
+//
+// The package is a module of its own, so that the payqr module does not
+// depend on gRPC.
+package payqrgrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/antonlindstrom/payqr/payqrgrpc/payqrpb"
+	"github.com/skip2/go-qrcode"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Limits of image sizes in pixels.
+const (
+	DefaultSize    = 512
+	DefaultMaxSize = 2048
+	MinSize        = 32
+)
+
+// Option is a modifier for the Server.
+type Option func(*Server)
+
+// WithMaxSize sets the largest image size in pixels. Default is
+// DefaultMaxSize.
+func WithMaxSize(size int) Option {
+	return func(s *Server) {
+		s.maxSize = size
+	}
+}
+
+// Server implements the PayQR gRPC service.
+type Server struct {
+	payqrpb.UnimplementedPayQRServer
+
+	maxSize int
+}
+
+// NewServer returns a server of the PayQR service.
+func NewServer(options ...Option) *Server {
+	s := &Server{maxSize: DefaultMaxSize}
+
+	for _, opt := range options {
+		opt(s)
+	}
+
+	return s
+}
+
+var (
+	types = map[payqrpb.Type]payqr.Type{
+		payqrpb.Type_TYPE_UNSPECIFIED:       payqr.InvoiceType,
+		payqrpb.Type_TYPE_INVOICE:           payqr.InvoiceType,
+		payqrpb.Type_TYPE_CREDIT_INVOICE:    payqr.CreditInvoiceType,
+		payqrpb.Type_TYPE_CASH_PAID_INVOICE: payqr.CashPaidInvoiceType,
+	}
+	paymentTypes = map[payqrpb.PaymentType]payqr.PaymentType{
+		payqrpb.PaymentType_PAYMENT_TYPE_UNSPECIFIED: payqr.PaymentTypeBG,
+		payqrpb.PaymentType_PAYMENT_TYPE_BG:          payqr.PaymentTypeBG,
+		payqrpb.PaymentType_PAYMENT_TYPE_PG:          payqr.PaymentTypePG,
+		payqrpb.PaymentType_PAYMENT_TYPE_IBAN:        payqr.PaymentTypeIBAN,
+		payqrpb.PaymentType_PAYMENT_TYPE_BBAN:        payqr.PaymentTypeBBAN,
+	}
+)
+
+// Payment returns the payment of the message, with the defaults of
+// payqr.New for the fields left out.
+func Payment(m *payqrpb.Payment) (*payqr.Payment, error) {
+	if m == nil {
+		return nil, errors.New("payment is required")
+	}
+
+	typ, ok := types[m.GetType()]
+	if !ok {
+		return nil, fmt.Errorf("unknown type %d", m.GetType())
+	}
+	paymentType, ok := paymentTypes[m.GetPaymentType()]
+	if !ok {
+		return nil, fmt.Errorf("unknown payment type %d", m.GetPaymentType())
+	}
+
+	created := time.Now()
+	if m.GetCreatedDate() != "" {
+		var err error
+		if created, err = time.Parse("2006-01-02", m.GetCreatedDate()); err != nil {
+			return nil, fmt.Errorf("invalid created date %q", m.GetCreatedDate())
+		}
+	}
+	var due time.Time
+	if m.GetDueDate() != "" {
+		var err error
+		if due, err = time.Parse("2006-01-02", m.GetDueDate()); err != nil {
+			return nil, fmt.Errorf("invalid due date %q", m.GetDueDate())
+		}
+	}
+
+	p := payqr.New(m.GetAccountNumber(), m.GetAccountName(), m.GetCompanyId(), m.GetReference(), m.GetDueAmount(), due,
+		payqr.WithType(typ),
+		payqr.WithPaymentType(paymentType),
+		payqr.WithCreationDate(created),
+		payqr.WithCurrency(m.GetCurrency()),
+		payqr.WithAddress(m.GetAddress()),
+		payqr.WithCountryCode(m.GetCountryCode()),
+		payqr.WithBankCode(m.GetBankCode()),
+	)
+	if due.IsZero() {
+		p.DueDate = ""
+	}
+	p.CreditInvoiceReference = m.GetCreditInvoiceReference()
+	p.VAT = m.GetVat()
+	p.HighVAT = m.GetHighVat()
+	p.MediumVAT = m.GetMediumVat()
+	p.LowVAT = m.GetLowVat()
+
+	return p, nil
+}
+
+// size returns the requested size, or an error if it is out of range.
+func (s *Server) size(size int32) (int, error) {
+	if size == 0 {
+		return DefaultSize, nil
+	}
+	if size < MinSize || int(size) > s.maxSize {
+		return 0, status.Errorf(codes.InvalidArgument, "size must be between %d and %d", MinSize, s.maxSize)
+	}
+
+	return int(size), nil
+}
+
+// image renders the code in the format.
+func image(q *qrcode.QRCode, format payqr.Format, size int) (*payqrpb.Image, error) {
+	b, err := payqr.Render(q, format, size)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &payqrpb.Image{Data: b, ContentType: format.ContentType(), Payload: q.Content}, nil
+}
+
+func (s *Server) generate(req *payqrpb.GenerateRequest, format payqr.Format) (*payqrpb.Image, error) {
+	size, err := s.size(req.GetSize())
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := Payment(req.GetPayment())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := p.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	q, err := p.QR()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return image(q, format, size)
+}
+
+// GeneratePNG renders the QR code of the payment as PNG.
+func (s *Server) GeneratePNG(_ context.Context, req *payqrpb.GenerateRequest) (*payqrpb.Image, error) {
+	return s.generate(req, payqr.FormatPNG)
+}
+
+// GenerateSVG renders the QR code of the payment as SVG.
+func (s *Server) GenerateSVG(_ context.Context, req *payqrpb.GenerateRequest) (*payqrpb.Image, error) {
+	return s.generate(req, payqr.FormatSVG)
+}
+
+// GenerateSwish renders the QR code of the Swish payment.
+func (s *Server) GenerateSwish(_ context.Context, req *payqrpb.GenerateSwishRequest) (*payqrpb.Image, error) {
+	size, err := s.size(req.GetSize())
+	if err != nil {
+		return nil, err
+	}
+
+	var format payqr.Format
+	switch req.GetFormat() {
+	case payqrpb.Format_FORMAT_UNSPECIFIED, payqrpb.Format_FORMAT_PNG:
+		format = payqr.FormatPNG
+	case payqrpb.Format_FORMAT_SVG:
+		format = payqr.FormatSVG
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown format %d", req.GetFormat())
+	}
+
+	var editable payqr.SwishEditableField
+	if req.GetAmountEditable() {
+		editable |= payqr.SwishAmountEditable
+	}
+	if req.GetMessageEditable() {
+		editable |= payqr.SwishMessageEditable
+	}
+	options := []payqr.SwishOption{payqr.WithEditableFields(editable)}
+	if req.GetOpenAmount() {
+		options = append(options, payqr.WithOpenAmount())
+	}
+
+	q, err := payqr.NewSwish(req.GetPayee(), req.GetAmount(), req.GetMessage(), options...).QR()
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return image(q, format, size)
+}
+
+// Validate checks the payment. An invalid payment is not an error of the
+// call, the response tells why it is invalid.
+func (s *Server) Validate(_ context.Context, m *payqrpb.Payment) (*payqrpb.ValidateResponse, error) {
+	p, err := Payment(m)
+	if err == nil {
+		err = p.Validate()
+	}
+	if err != nil {
+		return &payqrpb.ValidateResponse{Error: err.Error()}, nil
+	}
+
+	return &payqrpb.ValidateResponse{Valid: true}, nil
+}
@@ -0,0 +1,230 @@
+//go:build !payqr_payloadonly
+
+package payqrgrpc
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+
+	"github.com/antonlindstrom/payqr/payqrgrpc/payqrpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// client returns a client of the server over an in-memory connection.
+func client(t *testing.T, s *Server) payqrpb.PayQRClient {
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	payqrpb.RegisterPayQRServer(srv, s)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return payqrpb.NewPayQRClient(conn)
+}
+
+func testPayment() *payqrpb.Payment {
+	return &payqrpb.Payment{
+		AccountName:   "Test AB",
+		CompanyId:     "5555555555",
+		Reference:     "52250047157",
+		CreatedDate:   "2022-07-07",
+		DueDate:       "2022-08-06",
+		DueAmount:     1250,
+		AccountNumber: "5402-9681",
+	}
+}
+
+func TestPayment(t *testing.T) {
+	tests := []struct {
+		name    string
+		have    *payqrpb.Payment
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Defaults",
+			have: testPayment(),
+			want: `{"uqr":1,"tp":1,"nme":"Test AB","cid":"5555555555","iref":"52250047157","idt":"20220707","ddt":"20220806","due":1250,"pt":"BG","acc":"5402-9681"}`,
+		},
+		{
+			name: "Plusgiro credit invoice",
+			have: &payqrpb.Payment{
+				Type:                   payqrpb.Type_TYPE_CREDIT_INVOICE,
+				AccountName:            "Test AB",
+				CompanyId:              "5555555555",
+				Reference:              "1001",
+				CreditInvoiceReference: "1000",
+				CreatedDate:            "2022-07-07",
+				PaymentType:            payqrpb.PaymentType_PAYMENT_TYPE_PG,
+				AccountNumber:          "90 01 22-3",
+				Vat:                    250,
+			},
+			want: `{"uqr":1,"tp":2,"nme":"Test AB","cid":"5555555555","iref":"1001","cref":"1000","idt":"20220707","ddt":"","due":0,"vat":250,"pt":"PG","acc":"90 01 22-3"}`,
+		},
+		{
+			name: "Fractional VAT",
+			have: &payqrpb.Payment{
+				AccountName:   "Test AB",
+				CompanyId:     "5555555555",
+				Reference:     "52250047157",
+				CreatedDate:   "2022-07-07",
+				DueDate:       "2022-08-06",
+				DueAmount:     62.5,
+				AccountNumber: "5402-9681",
+				Vat:           12.5,
+				HighVat:       12.5,
+			},
+			want: `{"uqr":1,"tp":1,"nme":"Test AB","cid":"5555555555","iref":"52250047157","idt":"20220707","ddt":"20220806","due":62.5,"vat":12.5,"vh":12.5,"pt":"BG","acc":"5402-9681"}`,
+		},
+		{
+			name:    "Missing payment",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid due date",
+			have:    &payqrpb.Payment{DueDate: "06/08/2022"},
+			wantErr: true,
+		},
+		{
+			name:    "Unknown payment type",
+			have:    &payqrpb.Payment{PaymentType: 9},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Payment(tt.have)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			q, err := p.QR()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, q.Content)
+		})
+	}
+}
+
+func TestServer(t *testing.T) {
+	ctx := context.Background()
+	c := client(t, NewServer(WithMaxSize(1024)))
+
+	img, err := c.GeneratePNG(ctx, &payqrpb.GenerateRequest{Payment: testPayment()})
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", img.GetContentType())
+	assert.True(t, bytes.HasPrefix(img.GetData(), []byte("\x89PNG")))
+	assert.Contains(t, img.GetPayload(), `"acc":"5402-9681"`)
+
+	vat := testPayment()
+	vat.Vat, vat.HighVat = 12.5, 12.5
+	img, err = c.GeneratePNG(ctx, &payqrpb.GenerateRequest{Payment: vat})
+	require.NoError(t, err)
+	assert.Contains(t, img.GetPayload(), `"vat":12.5,"vh":12.5,`)
+
+	img, err = c.GenerateSVG(ctx, &payqrpb.GenerateRequest{Payment: testPayment(), Size: 256})
+	require.NoError(t, err)
+	assert.Equal(t, "image/svg+xml", img.GetContentType())
+	assert.Contains(t, string(img.GetData()), "<svg")
+
+	img, err = c.GenerateSwish(ctx, &payqrpb.GenerateSwishRequest{Payee: "1231111111", Amount: 50, Message: "My message", AmountEditable: true, MessageEditable: true})
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", img.GetContentType())
+	assert.Equal(t, "C1231111111;50.00;My message;6", img.GetPayload())
+
+	img, err = c.GenerateSwish(ctx, &payqrpb.GenerateSwishRequest{Payee: "1231111111", OpenAmount: true, Message: "Tips", Format: payqrpb.Format_FORMAT_SVG})
+	require.NoError(t, err)
+	assert.Equal(t, "image/svg+xml", img.GetContentType())
+	assert.Equal(t, "C1231111111;;Tips;2", img.GetPayload())
+
+	res, err := c.Validate(ctx, testPayment())
+	require.NoError(t, err)
+	assert.True(t, res.GetValid())
+	assert.Empty(t, res.GetError())
+
+	invalid := testPayment()
+	invalid.AccountNumber = "5402-9680"
+	res, err = c.Validate(ctx, invalid)
+	require.NoError(t, err)
+	assert.False(t, res.GetValid())
+	assert.Equal(t, `bankgiro account "5402-9680" has an invalid check digit`, res.GetError())
+}
+
+func TestServerErrors(t *testing.T) {
+	ctx := context.Background()
+	c := client(t, NewServer(WithMaxSize(1024)))
+
+	invalid := testPayment()
+	invalid.AccountNumber = "5402-9680"
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{
+			name: "Invalid payment",
+			call: func() error {
+				_, err := c.GeneratePNG(ctx, &payqrpb.GenerateRequest{Payment: invalid})
+				return err
+			},
+		},
+		{
+			name: "Missing payment",
+			call: func() error {
+				_, err := c.GenerateSVG(ctx, &payqrpb.GenerateRequest{})
+				return err
+			},
+		},
+		{
+			name: "Size too large",
+			call: func() error {
+				_, err := c.GeneratePNG(ctx, &payqrpb.GenerateRequest{Payment: testPayment(), Size: 2048})
+				return err
+			},
+		},
+		{
+			name: "Size too small",
+			call: func() error {
+				_, err := c.GenerateSwish(ctx, &payqrpb.GenerateSwishRequest{Payee: "1231111111", Amount: 50, Message: "My message", Size: 8})
+				return err
+			},
+		},
+		{
+			name: "Invalid Swish payment",
+			call: func() error {
+				_, err := c.GenerateSwish(ctx, &payqrpb.GenerateSwishRequest{Payee: "1231111111", Amount: 50})
+				return err
+			},
+		},
+		{
+			name: "Unknown format",
+			call: func() error {
+				_, err := c.GenerateSwish(ctx, &payqrpb.GenerateSwishRequest{Payee: "1231111111", Amount: 50, Message: "My message", Format: 7})
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			require.Error(t, err)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err), err.Error())
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package payqrhttp
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// FeedOption configures the feed handler.
+type FeedOption func(*feedHandler)
+
+// WithFeedTitle sets the title of the feed.
+func WithFeedTitle(title string) FeedOption {
+	return func(h *feedHandler) {
+		h.title = title
+	}
+}
+
+// WithFeedID sets the ID of the feed, preferably the URL it is served from.
+func WithFeedID(id string) FeedOption {
+	return func(h *feedHandler) {
+		h.id = id
+	}
+}
+
+// WithFeedLimit sets the maximum number of entries in the feed. Default is 50.
+func WithFeedLimit(limit int) FeedOption {
+	return func(h *feedHandler) {
+		h.limit = limit
+	}
+}
+
+type feedHandler struct {
+	store Store
+	title string
+	id    string
+	limit int
+}
+
+// NewFeedHandler returns a handler serving the recently issued payments in the
+// store as an Atom feed, or as RSS if the format parameter is set to rss.
+// Account numbers and references are redacted so that the feed can be used
+// for monitoring.
+func NewFeedHandler(store Store, options ...FeedOption) http.Handler {
+	h := &feedHandler{
+		store: store,
+		title: "Issued payments",
+		id:    "urn:payqr:issued",
+		limit: 50,
+	}
+
+	for _, opt := range options {
+		opt(h)
+	}
+
+	return h
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	Title   string `xml:"title"`
+	ID      string `xml:"id"`
+	Updated string `xml:"updated"`
+	Summary string `xml:"summary"`
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title       string    `xml:"title"`
+	Link        string    `xml:"link"`
+	Description string    `xml:"description"`
+	Items       []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string `xml:"title"`
+	GUID        string `xml:"guid"`
+	PubDate     string `xml:"pubDate"`
+	Description string `xml:"description"`
+}
+
+func (h *feedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	recent, err := h.store.Recent(h.limit)
+	if err != nil {
+		http.Error(w, "could not fetch issued payments", http.StatusInternalServerError)
+		return
+	}
+
+	var feed interface{}
+	if r.FormValue("format") == "rss" {
+		w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+		feed = h.rss(recent)
+	} else {
+		w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+		feed = h.atom(recent)
+	}
+
+	_, _ = w.Write([]byte(xml.Header))
+	_ = xml.NewEncoder(w).Encode(feed)
+}
+
+func (h *feedHandler) atom(recent []Issued) atomFeed {
+	feed := atomFeed{
+		Title:   h.title,
+		ID:      h.id,
+		Updated: time.Unix(0, 0).UTC().Format(time.RFC3339),
+	}
+	if len(recent) > 0 {
+		feed.Updated = recent[0].IssuedAt.UTC().Format(time.RFC3339)
+	}
+
+	for _, issued := range recent {
+		feed.Entries = append(feed.Entries, atomEntry{
+			Title:   entryTitle(issued),
+			ID:      h.id + ":" + issued.ID,
+			Updated: issued.IssuedAt.UTC().Format(time.RFC3339),
+			Summary: entrySummary(issued),
+		})
+	}
+
+	return feed
+}
+
+func (h *feedHandler) rss(recent []Issued) rssFeed {
+	feed := rssFeed{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:       h.title,
+			Link:        h.id,
+			Description: h.title,
+		},
+	}
+
+	for _, issued := range recent {
+		feed.Channel.Items = append(feed.Channel.Items, rssItem{
+			Title:       entryTitle(issued),
+			GUID:        h.id + ":" + issued.ID,
+			PubDate:     issued.IssuedAt.UTC().Format(time.RFC1123Z),
+			Description: entrySummary(issued),
+		})
+	}
+
+	return feed
+}
+
+// entryTitle returns the title of the entry, or its ID if the payment is
+// missing.
+func entryTitle(issued Issued) string {
+	p := issued.Payment
+	if p == nil {
+		return issued.ID
+	}
+
+	return fmt.Sprintf("%s: %s", p.AccountName, formatAmount(p.DueAmount, p.Currency))
+}
+
+// entrySummary returns the summary of the entry, the payment with its account
+// number and reference redacted, see payqr.Payment.String.
+func entrySummary(issued Issued) string {
+	if issued.Payment == nil {
+		return ""
+	}
+
+	return issued.Payment.String()
+}
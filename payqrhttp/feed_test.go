@@ -0,0 +1,94 @@
+package payqrhttp
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeedHandler(t *testing.T) {
+	store := NewMemoryStore(2)
+	for i, ref := range []string{"1001", "1002", "1003"} {
+		require.NoError(t, store.Add(Issued{
+			ID:       ref,
+			IssuedAt: time.Date(2022, time.July, 7+i, 0, 0, 0, 0, time.UTC),
+			Payment:  payqr.New("5536-7742", "Test AB", "1234", "934000"+ref, 50, time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)),
+		}))
+	}
+
+	h := NewFeedHandler(store, WithFeedID("https://example.com/feed"))
+
+	t.Run("Atom", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/atom+xml; charset=utf-8", rec.Header().Get("Content-Type"))
+
+		var feed atomFeed
+		require.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &feed))
+		require.Len(t, feed.Entries, 2)
+		assert.Equal(t, "https://example.com/feed:1003", feed.Entries[0].ID)
+		assert.Equal(t, "2022-07-09T00:00:00Z", feed.Updated)
+		assert.Equal(t, "Test AB, BG *****7742, reference ******1003, 50.00 SEK due 20220806", feed.Entries[0].Summary)
+		assert.NotContains(t, rec.Body.String(), "5536-7742")
+	})
+
+	t.Run("RSS", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?format=rss", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var feed rssFeed
+		require.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &feed))
+		require.Len(t, feed.Channel.Items, 2)
+		assert.Equal(t, "Test AB: 50.00 SEK", feed.Channel.Items[1].Title)
+	})
+}
+
+func TestFeedEntry(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		have        Issued
+		wantTitle   string
+		wantSummary string
+	}{
+		{
+			name:        "Minor units",
+			have:        Issued{ID: "1", Payment: payqr.New("5536-7742", "Test AB", "1234", "1001", 50, due)},
+			wantTitle:   "Test AB: 50.00 SEK",
+			wantSummary: "Test AB, BG *****7742, reference ****, 50.00 SEK due 20220806",
+		},
+		{
+			name:        "No minor units",
+			have:        Issued{ID: "2", Payment: payqr.New("5536-7742", "Test KK", "1234", "1001", 1500, due, payqr.WithCurrency("JPY"))},
+			wantTitle:   "Test KK: 1500 JPY",
+			wantSummary: "Test KK, BG *****7742, reference ****, 1500 JPY due 20220806",
+		},
+		{
+			name:        "Three minor units",
+			have:        Issued{ID: "3", Payment: payqr.New("5536-7742", "Test WLL", "1234", "1001", 12.345, due, payqr.WithCurrency("KWD"))},
+			wantTitle:   "Test WLL: 12.345 KWD",
+			wantSummary: "Test WLL, BG *****7742, reference ****, 12.345 KWD due 20220806",
+		},
+		{
+			name:      "Missing payment",
+			have:      Issued{ID: "4"},
+			wantTitle: "4",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.wantTitle, entryTitle(test.have))
+			assert.Equal(t, test.wantSummary, entrySummary(test.have))
+		})
+	}
+}
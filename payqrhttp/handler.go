@@ -0,0 +1,475 @@
+//go:build !payqr_payloadonly
+
+package payqrhttp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/antonlindstrom/payqr/cache"
+	"github.com/skip2/go-qrcode"
+)
+
+// Limits of the handler.
+const (
+	DefaultSize    = 512
+	DefaultMaxSize = 2048
+	MinSize        = 32
+
+	// maxBodySize is the largest JSON body accepted.
+	maxBodySize = 64 << 10
+)
+
+// HandlerOption configures the Handler.
+type HandlerOption func(*Handler)
+
+// WithAllowedPayees restricts the accounts codes are rendered for. Accounts
+// are compared without spaces and dashes. Default is any account.
+func WithAllowedPayees(accounts ...string) HandlerOption {
+	return func(h *Handler) {
+		h.allowed = map[string]bool{}
+		for _, account := range accounts {
+			h.allowed[normalizeAccount(account)] = true
+		}
+	}
+}
+
+// WithFormats sets the formats served. Default is PNG and SVG.
+func WithFormats(formats ...payqr.Format) HandlerOption {
+	return func(h *Handler) {
+		h.formats = map[payqr.Format]bool{}
+		for _, f := range formats {
+			h.formats[f] = true
+		}
+	}
+}
+
+// WithMaxSize sets the largest image size in pixels. Default is
+// DefaultMaxSize.
+func WithMaxSize(size int) HandlerOption {
+	return func(h *Handler) {
+		h.maxSize = size
+	}
+}
+
+// WithCacheControl sets the Cache-Control header of served codes, such as
+// "public, max-age=86400". Default is no header.
+func WithCacheControl(value string) HandlerOption {
+	return func(h *Handler) {
+		h.cacheControl = value
+	}
+}
+
+// WithSigningKey makes the handler serve only URLs signed with the key by
+// Sign, so that public pages can embed codes without letting anyone
+// generate codes of other payments. JSON requests are then refused, as
+// their bodies can not be signed.
+func WithSigningKey(key []byte) HandlerOption {
+	return func(h *Handler) {
+		h.key = key
+	}
+}
+
+// WithCache keeps the rendered images in the cache, so that codes requested
+// again are not rendered again. Default is no cache.
+func WithCache(c cache.Cache) HandlerOption {
+	return func(h *Handler) {
+		h.cache = c
+	}
+}
+
+// WithStore records the invoice payments of the codes served in the store,
+// e.g. for the feed of NewFeedHandler, with their fingerprints as IDs. Swish
+// codes are not recorded. Default is no store.
+func WithStore(store Store) HandlerOption {
+	return func(h *Handler) {
+		h.store = store
+	}
+}
+
+// Handler renders the QR codes of payments given by query parameters or a
+// JSON body, so that pages can embed codes as <img src="/qr?...">.
+//
+// The parameters are account, name, company_id, reference, amount, due as
+// yyyy-mm-dd (default today), type (default BG), currency, and bank_code
+// and country_code as IBAN and foreign BBAN payments need, and for the
+// image format and size in pixels (default 512). A POST request may give
+// them as a JSON object with the same keys instead, with the amount and size
+// as numbers. The payment is validated before it is rendered. With a swish
+// number the code is a Swish payment to it of the amount, with the reference
+// as message, and the invoice fields are not needed.
+//
+// Without a format parameter the response is chosen by the Accept header:
+// image/png (the default), image/svg+xml, application/json for the payload
+// of the code of an invoice payment, or text/plain for the payload as text,
+// such as the Swish string. Other types are answered with 406 Not
+// Acceptable.
+//
+// The rendered code only depends on its payload, so responses have a strong
+// ETag derived from it and conditional requests with a matching
+// If-None-Match are answered with 304 Not Modified without rendering.
+type Handler struct {
+	allowed map[string]bool
+	formats map[payqr.Format]bool
+	maxSize int
+
+	cacheControl string
+	key          []byte
+	cache        cache.Cache
+	store        Store
+	now          func() time.Time
+}
+
+// NewHandler returns a handler rendering QR codes.
+func NewHandler(options ...HandlerOption) *Handler {
+	h := &Handler{
+		formats: map[payqr.Format]bool{payqr.FormatPNG: true, payqr.FormatSVG: true},
+		maxSize: DefaultMaxSize,
+		now:     time.Now,
+	}
+
+	for _, opt := range options {
+		opt(h)
+	}
+
+	return h
+}
+
+// Request is a request for a QR code, as query parameters or a JSON body.
+type Request struct {
+	Account   string  `json:"account"`
+	Name      string  `json:"name"`
+	CompanyID string  `json:"company_id"`
+	Reference string  `json:"reference"`
+	Amount    float64 `json:"amount"`
+	Due       string  `json:"due"`
+	Type      string  `json:"type"`
+	Currency  string  `json:"currency"`
+	BankCode  string  `json:"bank_code"`
+	Country   string  `json:"country_code"`
+	Format    string  `json:"format"`
+	Size      int     `json:"size"`
+	Swish     string  `json:"swish"`
+}
+
+// parseRequest reads the request from the query or the JSON body.
+func parseRequest(r *http.Request) (*Request, error) {
+	req := &Request{}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		q := r.URL.Query()
+		req.Account, req.Name, req.CompanyID, req.Reference = q.Get("account"), q.Get("name"), q.Get("company_id"), q.Get("reference")
+		req.Due, req.Type, req.Currency, req.Format = q.Get("due"), q.Get("type"), q.Get("currency"), q.Get("format")
+		req.BankCode, req.Country, req.Swish = q.Get("bank_code"), q.Get("country_code"), q.Get("swish")
+
+		var err error
+		if req.Amount, err = strconv.ParseFloat(q.Get("amount"), 64); err != nil {
+			return nil, errors.New("invalid amount")
+		}
+		if v := q.Get("size"); v != "" {
+			if req.Size, err = strconv.Atoi(v); err != nil {
+				return nil, errors.New("invalid size")
+			}
+		}
+	case http.MethodPost:
+		if ct, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); ct != "application/json" {
+			return nil, errors.New("body must be application/json")
+		}
+		dec := json.NewDecoder(io.LimitReader(r.Body, maxBodySize))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(req); err != nil {
+			return nil, fmt.Errorf("invalid JSON body: %w", err)
+		}
+	default:
+		return nil, errMethod
+	}
+
+	return req, nil
+}
+
+var errMethod = errors.New("method not allowed")
+
+// Payment returns the payment of the request.
+func (req *Request) Payment() (*payqr.Payment, error) {
+	due := time.Now()
+	if req.Due != "" {
+		var err error
+		if due, err = time.Parse("2006-01-02", req.Due); err != nil {
+			return nil, errors.New("invalid due date")
+		}
+	}
+
+	typ := payqr.PaymentTypeBG
+	if req.Type != "" {
+		typ = payqr.PaymentType(strings.ToUpper(req.Type))
+	}
+
+	p := payqr.New(req.Account, req.Name, req.CompanyID, req.Reference, req.Amount, due,
+		payqr.WithPaymentType(typ), payqr.WithCurrency(req.Currency),
+		payqr.WithBankCode(req.BankCode), payqr.WithCountryCode(strings.ToUpper(req.Country)))
+
+	return p, p.Validate()
+}
+
+// SwishPayment returns the Swish payment of the request, to the Swish
+// number with the reference as message.
+func (req *Request) SwishPayment() (*payqr.SwishPayment, error) {
+	if req.Currency != "" && !strings.EqualFold(req.Currency, payqr.SwishCurrency) {
+		return nil, fmt.Errorf("swish only supports %s, got currency %q", payqr.SwishCurrency, req.Currency)
+	}
+
+	s := payqr.NewSwish(req.Swish, req.Amount, req.Reference)
+
+	return s, s.Validate()
+}
+
+// code returns the payee, the invoice payment and the QR code of the
+// request, a Swish code without a payment if it has a Swish number.
+func (req *Request) code() (string, *payqr.Payment, *qrcode.QRCode, error) {
+	if req.Swish != "" {
+		s, err := req.SwishPayment()
+		if err != nil {
+			return "", nil, nil, err
+		}
+		q, err := s.QR()
+
+		return s.Payee, nil, q, err
+	}
+
+	p, err := req.Payment()
+	if err != nil {
+		return "", nil, nil, err
+	}
+	q, err := p.QR()
+
+	return p.AccountNumber, p, q, err
+}
+
+// Media types served besides the image formats.
+const (
+	mediaJSON = "application/json"
+	mediaText = "text/plain"
+)
+
+// representation returns the media type of the response, from the format
+// parameter or else the Accept header, or an error if none is acceptable.
+func (h *Handler) representation(r *http.Request, req *Request) (string, error) {
+	if req.Format != "" {
+		format, err := payqr.ParseFormat(req.Format)
+		if err != nil {
+			return "", err
+		}
+		if !h.formats[format] {
+			return "", fmt.Errorf("format %s is not served", format)
+		}
+
+		return format.ContentType(), nil
+	}
+
+	var offers []string
+	for _, f := range []payqr.Format{payqr.FormatPNG, payqr.FormatSVG} {
+		if h.formats[f] {
+			offers = append(offers, f.ContentType())
+		}
+	}
+	if req.Swish == "" {
+		offers = append(offers, mediaJSON)
+	}
+	offers = append(offers, mediaText)
+
+	mediaType := negotiate(r.Header.Get("Accept"), offers)
+	if mediaType == "" {
+		return "", errNotAcceptable
+	}
+
+	return mediaType, nil
+}
+
+var errNotAcceptable = errors.New("none of the accepted media types is served")
+
+// negotiate returns the offer the Accept header prefers, or "" if none is
+// acceptable. Each offer has the quality of the most specific media range
+// matching it, and ties go to the earlier offer. Without an Accept header
+// the first offer is returned.
+func negotiate(accept string, offers []string) string {
+	if strings.TrimSpace(accept) == "" && len(offers) > 0 {
+		return offers[0]
+	}
+
+	best, bestQuality := "", 0.0
+	for _, offer := range offers {
+		quality, specificity := 0.0, -1
+		for _, mediaRange := range strings.Split(accept, ",") {
+			mt, params, err := mime.ParseMediaType(mediaRange)
+			if err != nil {
+				continue
+			}
+
+			s := -1
+			switch {
+			case mt == offer:
+				s = 2
+			case strings.HasSuffix(mt, "/*") && strings.HasPrefix(offer, strings.TrimSuffix(mt, "*")):
+				s = 1
+			case mt == "*/*":
+				s = 0
+			}
+			if s <= specificity {
+				continue
+			}
+
+			specificity, quality = s, 1
+			if v, ok := params["q"]; ok {
+				if quality, err = strconv.ParseFloat(v, 64); err != nil {
+					quality = 0
+				}
+			}
+		}
+		if quality > bestQuality {
+			best, bestQuality = offer, quality
+		}
+	}
+
+	return best
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req, err := parseRequest(r)
+	if errors.Is(err, errMethod) {
+		w.Header().Set("Allow", "GET, HEAD, POST")
+		http.Error(w, err.Error(), http.StatusMethodNotAllowed)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if h.key != nil {
+		err := errUnsigned
+		if r.Method != http.MethodPost {
+			err = verify(h.key, r.URL.Query(), h.now())
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+	}
+
+	w.Header().Set("Vary", "Accept")
+	mediaType, err := h.representation(r, req)
+	if errors.Is(err, errNotAcceptable) {
+		http.Error(w, err.Error(), http.StatusNotAcceptable)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	size := req.Size
+	if size == 0 {
+		size = DefaultSize
+	}
+	if size < MinSize || size > h.maxSize {
+		http.Error(w, fmt.Sprintf("size must be between %d and %d", MinSize, h.maxSize), http.StatusBadRequest)
+		return
+	}
+
+	payee, p, q, err := req.code()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if h.allowed != nil && !h.allowed[normalizeAccount(payee)] {
+		http.Error(w, "payee is not allowed", http.StatusForbidden)
+		return
+	}
+
+	etag := entityTag(q.Content, mediaType, size)
+	w.Header().Set("ETag", etag)
+	if h.cacheControl != "" {
+		w.Header().Set("Cache-Control", h.cacheControl)
+	}
+	if noneMatch(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	var b []byte
+	switch mediaType {
+	case mediaJSON:
+		b = []byte(q.Content)
+	case mediaText:
+		mediaType, b = "text/plain; charset=utf-8", []byte(q.Content)
+	default:
+		format := payqr.FormatPNG
+		if mediaType == payqr.FormatSVG.ContentType() {
+			format = payqr.FormatSVG
+		}
+		if h.cache != nil {
+			b, err = cache.Render(r.Context(), h.cache, q, format, size)
+		} else {
+			b, err = payqr.Render(q, format, size)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if h.store != nil && p != nil {
+		if err := h.store.Add(Issued{ID: p.Fingerprint(), IssuedAt: h.now(), Payment: p}); err != nil {
+			http.Error(w, "could not record the issued payment", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", mediaType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(b)))
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(b)
+	}
+}
+
+// entityTag returns the strong entity tag of the representation of the
+// payload. Images depend on the size as well, which is left out for the
+// payload types.
+func entityTag(payload, mediaType string, size int) string {
+	if mediaType == mediaJSON || mediaType == mediaText {
+		size = 0
+	}
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d\x00%s", mediaType, size, payload)))
+
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// noneMatch reports whether the If-None-Match header matches the entity tag,
+// using the weak comparison of RFC 9110.
+func noneMatch(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// normalizeAccount returns the account without separators, for comparing
+// accounts written differently.
+func normalizeAccount(account string) string {
+	return strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(account))
+}
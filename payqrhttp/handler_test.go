@@ -0,0 +1,357 @@
+//go:build !payqr_payloadonly
+
+package payqrhttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/antonlindstrom/payqr/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	const query = "/qr?account=5402-9681&name=Test+AB&company_id=5555555555&reference=52250047157&amount=100&due=2022-08-06"
+
+	tests := []struct {
+		name        string
+		options     []HandlerOption
+		method      string
+		target      string
+		contentType string
+		accept      string
+		body        string
+		want        int
+		wantType    string
+		wantBody    string
+	}{
+		{
+			name:     "PNG from query",
+			target:   query,
+			want:     http.StatusOK,
+			wantType: "image/png",
+		},
+		{
+			name:     "SVG from query",
+			target:   query + "&format=svg&size=256",
+			want:     http.StatusOK,
+			wantType: "image/svg+xml",
+		},
+		{
+			name:     "IBAN with bank and country code",
+			target:   "/qr?account=DE89370400440532013000&name=Test+GmbH&company_id=1234&amount=100&type=iban&currency=EUR&bank_code=COBADEFFXXX&country_code=de",
+			want:     http.StatusOK,
+			wantType: "image/png",
+		},
+		{
+			name:   "IBAN without bank code",
+			target: "/qr?account=DE89370400440532013000&name=Test+GmbH&company_id=1234&amount=100&type=iban&currency=EUR&country_code=de",
+			want:   http.StatusBadRequest,
+		},
+		{
+			name:        "JSON body",
+			method:      http.MethodPost,
+			target:      "/qr",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"account":"5402-9681","name":"Test AB","company_id":"5555555555","amount":100,"format":"svg"}`,
+			want:        http.StatusOK,
+			wantType:    "image/svg+xml",
+		},
+		{
+			name:     "HEAD",
+			method:   http.MethodHead,
+			target:   query,
+			want:     http.StatusOK,
+			wantType: "image/png",
+		},
+		{
+			name:     "Accept SVG",
+			target:   query,
+			accept:   "image/svg+xml",
+			want:     http.StatusOK,
+			wantType: "image/svg+xml",
+		},
+		{
+			name:     "Browser image request",
+			target:   query,
+			accept:   "image/avif,image/webp,image/apng,image/svg+xml,image/*,*/*;q=0.8",
+			want:     http.StatusOK,
+			wantType: "image/png",
+		},
+		{
+			name:     "Accept JSON payload",
+			target:   query,
+			accept:   "application/json",
+			want:     http.StatusOK,
+			wantType: "application/json",
+			wantBody: `{"uqr":1,"tp":1,"nme":"Test AB","cid":"5555555555","iref":"52250047157","idt":"` + time.Now().Format("20060102") + `","ddt":"20220806","due":100,"pt":"BG","acc":"5402-9681"}`,
+		},
+		{
+			name:     "Accept Swish string",
+			target:   "/qr?swish=1231111111&amount=50&reference=My+message",
+			accept:   "text/plain",
+			want:     http.StatusOK,
+			wantType: "text/plain; charset=utf-8",
+			wantBody: "C1231111111;50.00;My message;0",
+		},
+		{
+			name:     "Swish code",
+			target:   "/qr?swish=1231111111&amount=50&reference=My+message",
+			want:     http.StatusOK,
+			wantType: "image/png",
+		},
+		{
+			name:   "Swish payload is not JSON",
+			target: "/qr?swish=1231111111&amount=50",
+			accept: "application/json",
+			want:   http.StatusNotAcceptable,
+		},
+		{
+			name:   "Invalid Swish currency",
+			target: "/qr?swish=1231111111&amount=50&currency=EUR",
+			want:   http.StatusBadRequest,
+		},
+		{
+			name:     "Format parameter overrides Accept",
+			target:   query + "&format=svg",
+			accept:   "image/png",
+			want:     http.StatusOK,
+			wantType: "image/svg+xml",
+		},
+		{
+			name:   "Not acceptable",
+			target: query,
+			accept: "application/pdf",
+			want:   http.StatusNotAcceptable,
+		},
+		{
+			name:    "Accepted format not enabled",
+			options: []HandlerOption{WithFormats(payqr.FormatPNG)},
+			target:  query,
+			accept:  "image/svg+xml, text/*;q=0",
+			want:    http.StatusNotAcceptable,
+		},
+		{
+			name:        "Body not JSON",
+			method:      http.MethodPost,
+			target:      "/qr",
+			contentType: "text/plain",
+			body:        `{"account":"5402-9681"}`,
+			want:        http.StatusBadRequest,
+		},
+		{
+			name:        "Unknown JSON field",
+			method:      http.MethodPost,
+			target:      "/qr",
+			contentType: "application/json",
+			body:        `{"account":"5402-9681","name":"Test AB","company_id":"5555555555","amount":100,"colour":"red"}`,
+			want:        http.StatusBadRequest,
+		},
+		{
+			name:   "Invalid amount",
+			target: "/qr?account=5402-9681&name=Test+AB&company_id=5555555555&amount=many",
+			want:   http.StatusBadRequest,
+		},
+		{
+			name:   "Size too large",
+			target: query + "&size=4096",
+			want:   http.StatusBadRequest,
+		},
+		{
+			name:   "Size too small",
+			target: query + "&size=8",
+			want:   http.StatusBadRequest,
+		},
+		{
+			name:   "Invalid payment",
+			target: "/qr?account=5402-9680&name=Test+AB&company_id=5555555555&amount=100",
+			want:   http.StatusBadRequest,
+		},
+		{
+			name:   "Format not served",
+			target: query + "&format=pdf",
+			want:   http.StatusBadRequest,
+		},
+		{
+			name:    "Format not enabled",
+			options: []HandlerOption{WithFormats(payqr.FormatPNG)},
+			target:  query + "&format=svg",
+			want:    http.StatusBadRequest,
+		},
+		{
+			name:     "Allowed payee",
+			options:  []HandlerOption{WithAllowedPayees("5402 9681")},
+			target:   query,
+			want:     http.StatusOK,
+			wantType: "image/png",
+		},
+		{
+			name:    "Payee not allowed",
+			options: []HandlerOption{WithAllowedPayees("5050-1055")},
+			target:  query,
+			want:    http.StatusForbidden,
+		},
+		{
+			name:    "Max size",
+			options: []HandlerOption{WithMaxSize(256)},
+			target:  query + "&size=512",
+			want:    http.StatusBadRequest,
+		},
+		{
+			name:   "Method not allowed",
+			method: http.MethodPut,
+			target: query,
+			want:   http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			r := httptest.NewRequest(method, tt.target, strings.NewReader(tt.body))
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+			NewHandler(tt.options...).ServeHTTP(w, r)
+
+			assert.Equal(t, tt.want, w.Code, w.Body.String())
+			if tt.want != http.StatusOK {
+				return
+			}
+
+			assert.Equal(t, tt.wantType, w.Header().Get("Content-Type"))
+			assert.Equal(t, "Accept", w.Header().Get("Vary"))
+			if tt.wantBody != "" {
+				assert.Equal(t, tt.wantBody, w.Body.String())
+			}
+			assert.NotEmpty(t, w.Header().Get("Content-Length"))
+			if method == http.MethodHead {
+				assert.Zero(t, w.Body.Len())
+			} else {
+				assert.NotZero(t, w.Body.Len())
+			}
+		})
+	}
+}
+
+func TestHandlerMethodNotAllowed(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewHandler().ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/qr", nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "GET, HEAD, POST", w.Header().Get("Allow"))
+}
+
+func TestNegotiate(t *testing.T) {
+	offers := []string{"image/png", "image/svg+xml", "application/json", "text/plain"}
+
+	tests := []struct {
+		name string
+		have string
+		want string
+	}{
+		{name: "No Accept header", have: "", want: "image/png"},
+		{name: "Any type", have: "*/*", want: "image/png"},
+		{name: "Exact type", have: "text/plain", want: "text/plain"},
+		{name: "Type range", have: "image/*", want: "image/png"},
+		{name: "Quality", have: "image/png;q=0.5, image/svg+xml", want: "image/svg+xml"},
+		{name: "Most specific range counts", have: "image/*, image/png;q=0", want: "image/svg+xml"},
+		{name: "Fallback on any type", have: "application/pdf, */*;q=0.1", want: "image/png"},
+		{name: "Nothing acceptable", have: "application/pdf", want: ""},
+		{name: "Invalid ranges are ignored", have: "/, application/json", want: "application/json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, negotiate(tt.have, offers))
+		})
+	}
+}
+
+func TestHandlerETag(t *testing.T) {
+	const query = "/qr?account=5402-9681&name=Test+AB&company_id=5555555555&amount=100&due=2022-08-06"
+	h := NewHandler(WithCacheControl("public, max-age=86400"))
+
+	get := func(target, ifNoneMatch string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		if ifNoneMatch != "" {
+			r.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		return w
+	}
+
+	w := get(query, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+	assert.Equal(t, "public, max-age=86400", w.Header().Get("Cache-Control"))
+	assert.Equal(t, etag, get(query, "").Header().Get("ETag"), "same payment has the same tag")
+
+	assert.NotEqual(t, etag, get(query+"&size=256", "").Header().Get("ETag"))
+	assert.NotEqual(t, etag, get(query+"&format=svg", "").Header().Get("ETag"))
+	assert.NotEqual(t, etag, get(query+"&reference=52250047157", "").Header().Get("ETag"))
+
+	for _, ifNoneMatch := range []string{etag, `"other", ` + etag, "W/" + etag, "*"} {
+		w := get(query, ifNoneMatch)
+		assert.Equal(t, http.StatusNotModified, w.Code, ifNoneMatch)
+		assert.Equal(t, etag, w.Header().Get("ETag"))
+		assert.Equal(t, "public, max-age=86400", w.Header().Get("Cache-Control"))
+		assert.Zero(t, w.Body.Len())
+	}
+
+	assert.Equal(t, http.StatusOK, get(query, `"other"`).Code)
+
+	w = httptest.NewRecorder()
+	NewHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, query, nil))
+	assert.Empty(t, w.Header().Get("Cache-Control"), "no Cache-Control by default")
+}
+
+func TestHandlerCache(t *testing.T) {
+	const query = "/qr?account=5402-9681&name=Test+AB&company_id=5555555555&amount=100&due=2022-08-06"
+	c := cache.NewLRU(10, time.Hour)
+	h := NewHandler(WithCache(c))
+
+	var bodies []string
+	for _, target := range []string{query, query, query + "&format=svg"} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		bodies = append(bodies, w.Body.String())
+	}
+
+	assert.Equal(t, bodies[0], bodies[1])
+	assert.Equal(t, 2, c.Len(), "one image of each format")
+}
+
+func TestHandlerStore(t *testing.T) {
+	const query = "/qr?account=5402-9681&name=Test+AB&company_id=5555555555&amount=100&due=2022-08-06"
+	store := NewMemoryStore(10)
+	h := NewHandler(WithStore(store))
+
+	for _, target := range []string{query, query + "&format=svg", "/qr?swish=1231111111&amount=100", "/qr?account=5402-9680&name=Test+AB"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	recent, err := store.Recent(10)
+	require.NoError(t, err)
+	require.Len(t, recent, 2, "only the served invoice payments")
+	assert.Equal(t, recent[0].Payment.Fingerprint(), recent[0].ID)
+	assert.Equal(t, "5402-9681", recent[0].Payment.AccountNumber)
+	assert.False(t, recent[0].IssuedAt.IsZero())
+}
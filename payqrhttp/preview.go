@@ -0,0 +1,173 @@
+// Package payqrhttp contains HTTP handlers for working with payment QR codes.
+package payqrhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/antonlindstrom/payqr"
+)
+
+// PreviewField is a single field as it would be listed by a banking app.
+type PreviewField struct {
+	Label    string
+	Value    string
+	Editable bool
+}
+
+// Preview is the parsed representation of a payload.
+type Preview struct {
+	Kind   string
+	Fields []PreviewField
+}
+
+// ParsePreview parses a QR payload, either the JSON used for invoices or the
+// Swish format, into the fields a generic banking app would display.
+func ParsePreview(payload string) (*Preview, error) {
+	payload = strings.TrimSpace(payload)
+
+	switch {
+	case strings.HasPrefix(payload, "{"):
+		return parseInvoicePreview(payload)
+	case strings.HasPrefix(payload, "C"):
+		return parseSwishPreview(payload)
+	}
+
+	return nil, errors.New("unknown payload format")
+}
+
+func parseInvoicePreview(payload string) (*Preview, error) {
+	var p payqr.Payment
+	if err := json.Unmarshal([]byte(payload), &p); err != nil {
+		return nil, fmt.Errorf("invalid invoice payload: %w", err)
+	}
+
+	preview := &Preview{Kind: "Invoice"}
+	add := func(label, value string) {
+		if value != "" {
+			preview.Fields = append(preview.Fields, PreviewField{Label: label, Value: value})
+		}
+	}
+
+	add("Recipient", p.AccountName)
+	add("Company ID", p.CompanyID)
+	add("Payment type", string(p.PaymentType))
+	add("Account", p.AccountNumber)
+	add("Bank code", p.BankCode)
+	add("Reference", p.Reference)
+	add("Credited invoice", p.CreditInvoiceReference)
+	add("Amount", formatAmount(p.DueAmount, p.Currency))
+	add("Due date", formatDate(p.DueDate))
+	add("Invoice date", formatDate(p.CreatedDate))
+	add("Country", p.CountryCode)
+	add("Address", p.Address)
+
+	return preview, nil
+}
+
+func parseSwishPreview(payload string) (*Preview, error) {
+	s, err := payqr.ParseSwish(payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid swish payload: %w", err)
+	}
+
+	fields := s.EditableFields()
+
+	amount := formatAmount(s.Amount, payqr.SwishCurrency)
+	if s.OpenAmount() {
+		amount = ""
+	}
+
+	return &Preview{
+		Kind: "Swish",
+		Fields: []PreviewField{
+			{Label: "Recipient", Value: s.Payee, Editable: fields&payqr.SwishPhoneEditable != 0},
+			{Label: "Amount", Value: amount, Editable: fields&payqr.SwishAmountEditable != 0},
+			{Label: "Message", Value: s.Message, Editable: fields&payqr.SwishMessageEditable != 0},
+		},
+	}, nil
+}
+
+func formatAmount(amount float64, currency string) string {
+	if currency == "" {
+		currency = "SEK"
+	}
+
+	return fmt.Sprintf("%.*f %s", payqr.MinorUnits(currency), amount, currency)
+}
+
+// formatDate formats the yyyymmdd dates of the payload as yyyy-mm-dd.
+func formatDate(date string) string {
+	if len(date) != 8 {
+		return date
+	}
+
+	return date[:4] + "-" + date[4:6] + "-" + date[6:]
+}
+
+var previewTemplate = template.Must(template.New("preview").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>payqr preview</title>
+<style>
+body { font-family: sans-serif; max-width: 28em; margin: 2em auto; }
+textarea { width: 100%; height: 6em; }
+.app { border: 1px solid #ccc; border-radius: 1em; padding: 1em; margin-top: 1em; }
+.field { display: flex; justify-content: space-between; padding: .5em 0; border-bottom: 1px solid #eee; }
+.editable { color: #06c; }
+.locked { color: #999; }
+.error { color: #c00; }
+</style>
+</head>
+<body>
+<form method="get">
+<textarea name="payload">{{.Payload}}</textarea>
+<button type="submit">Preview</button>
+</form>
+{{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+{{with .Preview}}<div class="app">
+<h2>{{.Kind}}</h2>
+{{range .Fields}}<div class="field"><span>{{.Label}}</span><span>{{.Value}} {{if $.Swish}}{{if .Editable}}<span class="editable">(editable)</span>{{else}}<span class="locked">(locked)</span>{{end}}{{end}}</span></div>
+{{end}}</div>{{end}}
+</body>
+</html>
+`))
+
+// NewPreviewHandler returns a handler that renders how a payload would
+// appear in a generic banking app. The payload is read from the payload
+// parameter. It is meant for development, to sanity check invoice templates
+// without scanning the codes with real apps.
+func NewPreviewHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data := struct {
+			Payload string
+			Preview *Preview
+			Swish   bool
+			Error   string
+		}{
+			Payload: r.FormValue("payload"),
+		}
+
+		status := http.StatusOK
+		if data.Payload != "" {
+			preview, err := ParsePreview(data.Payload)
+			if err != nil {
+				status = http.StatusBadRequest
+				data.Error = err.Error()
+			} else {
+				data.Preview = preview
+				data.Swish = preview.Kind == "Swish"
+			}
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(status)
+		_ = previewTemplate.Execute(w, data)
+	})
+}
@@ -0,0 +1,104 @@
+package payqrhttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePreview(t *testing.T) {
+	tests := []struct {
+		name    string
+		have    string
+		want    *Preview
+		wantErr bool
+	}{
+		{
+			name: "Invoice payload",
+			have: `{"uqr":1,"tp":1,"nme":"Test AB","cid":"1234","iref":"1001","idt":"20220707","ddt":"20220806","due":50,"pt":"BG","acc":"5536-7742"}`,
+			want: &Preview{
+				Kind: "Invoice",
+				Fields: []PreviewField{
+					{Label: "Recipient", Value: "Test AB"},
+					{Label: "Company ID", Value: "1234"},
+					{Label: "Payment type", Value: "BG"},
+					{Label: "Account", Value: "5536-7742"},
+					{Label: "Reference", Value: "1001"},
+					{Label: "Amount", Value: "50.00 SEK"},
+					{Label: "Due date", Value: "2022-08-06"},
+					{Label: "Invoice date", Value: "2022-07-07"},
+				},
+			},
+		},
+		{
+			name: "Amount without minor units",
+			have: `{"uqr":1,"tp":1,"nme":"Test KK","cid":"1234","iref":"1001","idt":"20220707","ddt":"20220806","due":1500,"cur":"JPY","pt":"IBAN","acc":"JP0000000000000000","bc":"BOTKJPJT"}`,
+			want: &Preview{
+				Kind: "Invoice",
+				Fields: []PreviewField{
+					{Label: "Recipient", Value: "Test KK"},
+					{Label: "Company ID", Value: "1234"},
+					{Label: "Payment type", Value: "IBAN"},
+					{Label: "Account", Value: "JP0000000000000000"},
+					{Label: "Bank code", Value: "BOTKJPJT"},
+					{Label: "Reference", Value: "1001"},
+					{Label: "Amount", Value: "1500 JPY"},
+					{Label: "Due date", Value: "2022-08-06"},
+					{Label: "Invoice date", Value: "2022-07-07"},
+				},
+			},
+		},
+		{
+			name: "Swish payload",
+			have: "C1231111111;50.00;My message;6",
+			want: &Preview{
+				Kind: "Swish",
+				Fields: []PreviewField{
+					{Label: "Recipient", Value: "1231111111"},
+					{Label: "Amount", Value: "50.00 SEK", Editable: true},
+					{Label: "Message", Value: "My message", Editable: true},
+				},
+			},
+		},
+		{
+			name:    "Swish payload with missing fields",
+			have:    "C1231111111;50.00",
+			wantErr: true,
+		},
+		{
+			name:    "Unknown payload",
+			have:    "hello",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParsePreview(test.have)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestPreviewHandler(t *testing.T) {
+	h := NewPreviewHandler()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?payload="+url.QueryEscape("C1231111111;50.00;My message;2"), nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "My message")
+	assert.Contains(t, rec.Body.String(), "(editable)")
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?payload=bogus", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,84 @@
+package payqrhttp
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Query parameters of signed URLs.
+const (
+	SignatureParam = "sig"
+	ExpiresParam   = "expires"
+)
+
+// Sign returns the URL signed with the key, so that a Handler with the same
+// signing key serves it. The signature covers all query parameters, so none
+// of them, such as the amount, can be changed. If expires is not zero the
+// URL is not served after it.
+func Sign(key []byte, rawURL string, expires time.Time) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("payqrhttp: %w", err)
+	}
+
+	q := u.Query()
+	q.Del(SignatureParam)
+	q.Del(ExpiresParam)
+	if !expires.IsZero() {
+		q.Set(ExpiresParam, strconv.FormatInt(expires.Unix(), 10))
+	}
+	q.Set(SignatureParam, signature(key, q))
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// signature returns the signature of the query parameters other than the
+// signature itself.
+func signature(key []byte, q url.Values) string {
+	unsigned := url.Values{}
+	for k, v := range q {
+		if k != SignatureParam {
+			unsigned[k] = v
+		}
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(unsigned.Encode()))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+var (
+	errUnsigned = errors.New("signature is required")
+	errExpired  = errors.New("link has expired")
+)
+
+// verify checks the signature and expiry of the query parameters.
+func verify(key []byte, q url.Values, now time.Time) error {
+	sig := q.Get(SignatureParam)
+	if sig == "" {
+		return errUnsigned
+	}
+	if !hmac.Equal([]byte(sig), []byte(signature(key, q))) {
+		return errors.New("invalid signature")
+	}
+
+	if v := q.Get(ExpiresParam); v != "" {
+		expires, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return errors.New("invalid expiry")
+		}
+		if !now.Before(time.Unix(expires, 0)) {
+			return errExpired
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+//go:build !payqr_payloadonly
+
+package payqrhttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSign(t *testing.T) {
+	const query = "/qr?account=5402-9681&name=Test+AB&company_id=5555555555&reference=52250047157&amount=100&due=2022-08-06"
+	key := []byte("secret")
+	now := time.Date(2022, 7, 7, 12, 0, 0, 0, time.UTC)
+
+	sign := func(rawURL string, expires time.Time) string {
+		signed, err := Sign(key, rawURL, expires)
+		require.NoError(t, err)
+		return signed
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		want   int
+	}{
+		{
+			name:   "Signed",
+			target: sign(query, time.Time{}),
+			want:   http.StatusOK,
+		},
+		{
+			name:   "Signed with expiry",
+			target: sign(query, now.Add(time.Hour)),
+			want:   http.StatusOK,
+		},
+		{
+			name:   "Signed again",
+			target: sign(sign(query, now.Add(-time.Hour)), now.Add(time.Hour)),
+			want:   http.StatusOK,
+		},
+		{
+			name:   "Expired",
+			target: sign(query, now.Add(-time.Second)),
+			want:   http.StatusForbidden,
+		},
+		{
+			name:   "Unsigned",
+			target: query,
+			want:   http.StatusForbidden,
+		},
+		{
+			name:   "Changed amount",
+			target: strings.Replace(sign(query, time.Time{}), "amount=100", "amount=1", 1),
+			want:   http.StatusForbidden,
+		},
+		{
+			name:   "Added parameter",
+			target: sign(query, time.Time{}) + "&size=256",
+			want:   http.StatusForbidden,
+		},
+		{
+			name:   "Extended expiry",
+			target: strings.Replace(sign(query, now.Add(-time.Second)), "expires=", "expires=9", 1),
+			want:   http.StatusForbidden,
+		},
+		{
+			name: "Other key",
+			target: func() string {
+				signed, err := Sign([]byte("other"), query, time.Time{})
+				require.NoError(t, err)
+				return signed
+			}(),
+			want: http.StatusForbidden,
+		},
+		{
+			name:   "JSON body",
+			method: http.MethodPost,
+			target: sign("/qr", time.Time{}),
+			want:   http.StatusForbidden,
+		},
+	}
+
+	h := NewHandler(WithSigningKey(key))
+	h.now = func() time.Time { return now }
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method, body := tt.method, ""
+			if method == "" {
+				method = http.MethodGet
+			} else {
+				body = `{"account":"5402-9681","name":"Test AB","company_id":"5555555555","amount":100}`
+			}
+
+			r := httptest.NewRequest(method, tt.target, strings.NewReader(body))
+			r.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.want, w.Code, w.Body.String())
+		})
+	}
+}
+
+func TestSignInvalidURL(t *testing.T) {
+	_, err := Sign([]byte("secret"), "%zz", time.Time{})
+	assert.Error(t, err)
+}
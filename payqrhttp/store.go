@@ -0,0 +1,75 @@
+package payqrhttp
+
+import (
+	"sync"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+)
+
+// Issued is a payment that has been issued, e.g. rendered as a QR code.
+type Issued struct {
+	ID       string
+	IssuedAt time.Time
+	Payment  *payqr.Payment
+}
+
+// Store keeps track of issued payments.
+type Store interface {
+	// Add records an issued payment.
+	Add(issued Issued) error
+	// Recent returns at most n of the most recently issued payments, newest
+	// first.
+	Recent(n int) ([]Issued, error)
+}
+
+// MemoryStore is a Store that keeps a fixed number of issued payments in
+// memory.
+type MemoryStore struct {
+	mu     sync.Mutex
+	size   int
+	issued []Issued
+}
+
+// NewMemoryStore creates a MemoryStore that keeps at most size payments.
+func NewMemoryStore(size int) *MemoryStore {
+	if size < 0 {
+		size = 0
+	}
+
+	return &MemoryStore{size: size}
+}
+
+// Add records an issued payment, evicting the oldest one if the store is full.
+func (s *MemoryStore) Add(issued Issued) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.issued = append(s.issued, issued)
+	if len(s.issued) > s.size {
+		s.issued = s.issued[len(s.issued)-s.size:]
+	}
+
+	return nil
+}
+
+// Recent returns at most n of the most recently added payments, newest first,
+// or none if n is not positive.
+func (s *MemoryStore) Recent(n int) ([]Issued, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if n > len(s.issued) {
+		n = len(s.issued)
+	}
+	if n < 0 {
+		n = 0
+	}
+
+	recent := make([]Issued, 0, n)
+	for i := len(s.issued) - 1; i >= len(s.issued)-n; i-- {
+		recent = append(recent, s.issued[i])
+	}
+
+	return recent, nil
+}
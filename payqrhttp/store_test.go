@@ -0,0 +1,40 @@
+package payqrhttp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	s := NewMemoryStore(2)
+	for _, id := range []string{"1", "2", "3"} {
+		require.NoError(t, s.Add(Issued{ID: id}))
+	}
+
+	tests := []struct {
+		name string
+		have int
+		want []Issued
+	}{
+		{name: "All", have: 10, want: []Issued{{ID: "3"}, {ID: "2"}}},
+		{name: "Newest", have: 1, want: []Issued{{ID: "3"}}},
+		{name: "None", have: 0, want: []Issued{}},
+		{name: "Negative", have: -1, want: []Issued{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recent, err := s.Recent(test.have)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, recent)
+		})
+	}
+
+	s = NewMemoryStore(-1)
+	require.NoError(t, s.Add(Issued{ID: "1"}))
+	recent, err := s.Recent(1)
+	require.NoError(t, err)
+	assert.Empty(t, recent)
+}
@@ -0,0 +1,66 @@
+package payqr
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// PaytoURI returns the payment as a payto URI (RFC 8905), such as:
+//
+//	payto://iban/SE4550000000058398257466?amount=SEK:50&receiver-name=Test&message=123
+//
+// IBAN payments use the iban target type, with the BIC as first path segment
+// if the bank code is set. BBAN payments use the bban target type, which is
+// not registered with IANA, with the country code as first path segment.
+// Bankgiro and plusgiro payments can not be expressed as payto URIs.
+func (d *Payment) PaytoURI() (string, error) {
+	account := strings.ToUpper(strings.ReplaceAll(stripAccount(d.AccountNumber), ".", ""))
+	if account == "" {
+		return "", fmt.Errorf("payto: account number is empty")
+	}
+
+	var target string
+	switch d.PaymentType {
+	case PaymentTypeIBAN:
+		target = "iban/" + account
+		if d.BankCode != "" {
+			target = "iban/" + strings.ToUpper(d.BankCode) + "/" + account
+		}
+	case PaymentTypeBBAN:
+		target = "bban/" + d.accountCountry() + "/" + account
+	default:
+		return "", fmt.Errorf("payto: payment type %s is not supported", d.PaymentType)
+	}
+
+	var params []string
+	if d.DueAmount > 0 {
+		// Payments without currency are Swedish invoices.
+		currency := d.Currency
+		if currency == "" {
+			currency = "SEK"
+		}
+		amount := strconv.FormatFloat(math.Round(d.DueAmount*100)/100, 'f', -1, 64)
+		params = append(params, "amount="+strings.ToUpper(currency)+":"+amount)
+	}
+	if d.AccountName != "" {
+		params = append(params, "receiver-name="+paytoEscape(d.AccountName))
+	}
+	if d.Reference != "" {
+		params = append(params, "message="+paytoEscape(d.Reference))
+	}
+
+	uri := "payto://" + target
+	if len(params) > 0 {
+		uri += "?" + strings.Join(params, "&")
+	}
+
+	return uri, nil
+}
+
+// paytoEscape escapes a query value with spaces as %20 rather than "+".
+func paytoEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
@@ -0,0 +1,58 @@
+//go:build !payqr_payloadonly
+
+package payqr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaytoURI(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		have    *Payment
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "IBAN",
+			have: New("SE45 5000 0000 0583 9825 7466", "Test AB", "", "Invoice 123", 50, due, WithPaymentType(PaymentTypeIBAN)),
+			want: "payto://iban/SE4550000000058398257466?amount=SEK:50&receiver-name=Test%20AB&message=Invoice%20123",
+		},
+		{
+			name: "IBAN with BIC",
+			have: New("DE75512108001245126199", "Test GmbH", "", "", 200.5, due, WithPaymentType(PaymentTypeIBAN), WithBankCode("sogedeffxxx"), WithCurrency("EUR")),
+			want: "payto://iban/SOGEDEFFXXX/DE75512108001245126199?amount=EUR:200.5&receiver-name=Test%20GmbH",
+		},
+		{
+			name: "BBAN",
+			have: New("8601.11.17947", "", "", "", 0, due, WithPaymentType(PaymentTypeBBAN), WithCountryCode("NO")),
+			want: "payto://bban/NO/86011117947",
+		},
+		{
+			name:    "Bankgiro",
+			have:    New("5402-9681", "Test AB", "", "", 50, due),
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.have.PaytoURI()
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+
+			_, err = test.have.PaytoQR()
+			assert.NoError(t, err)
+		})
+	}
+}
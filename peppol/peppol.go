@@ -0,0 +1,187 @@
+// Package peppol reads Peppol BIS Billing 3.0 invoices, UBL invoices
+// following EN 16931, into payments, so that an e-invoice can get a payment
+// QR code on its PDF copy.
+package peppol
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+)
+
+// Financial institution identifiers used for Swedish Bankgiro and Plusgiro
+// accounts in Peppol invoices.
+const (
+	BankgiroID = "BGABSESS"
+	PlusgiroID = "PGSISESS"
+)
+
+type party struct {
+	Name       string `xml:"PartyName>Name"`
+	LegalName  string `xml:"PartyLegalEntity>RegistrationName"`
+	CompanyID  string `xml:"PartyLegalEntity>CompanyID"`
+	Street     string `xml:"PostalAddress>StreetName"`
+	Additional string `xml:"PostalAddress>AdditionalStreetName"`
+	City       string `xml:"PostalAddress>CityName"`
+	PostalZone string `xml:"PostalAddress>PostalZone"`
+	Country    string `xml:"PostalAddress>Country>IdentificationCode"`
+}
+
+// name returns the registered name of the party, or its trading name.
+func (p party) name() string {
+	if p.LegalName != "" {
+		return p.LegalName
+	}
+
+	return p.Name
+}
+
+// address returns the postal address of the party as lines.
+func (p party) address() string {
+	var lines []string
+	for _, l := range []string{p.Street, p.Additional, strings.TrimSpace(p.PostalZone + " " + p.City)} {
+		if l != "" {
+			lines = append(lines, l)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// ublInvoice is the part of a UBL invoice used. Elements are matched by local
+// name, so the namespaces do not matter.
+type ublInvoice struct {
+	XMLName      xml.Name `xml:"Invoice"`
+	ID           string   `xml:"ID"`
+	IssueDate    string   `xml:"IssueDate"`
+	DueDate      string   `xml:"DueDate"`
+	CurrencyCode string   `xml:"DocumentCurrencyCode"`
+	Supplier     party    `xml:"AccountingSupplierParty>Party"`
+	Payee        *party   `xml:"PayeeParty"`
+	PaymentMeans []struct {
+		Code    string `xml:"PaymentMeansCode"`
+		DueDate string `xml:"PaymentDueDate"`
+		ID      string `xml:"PaymentID"`
+		Account struct {
+			ID          string `xml:"ID"`
+			Name        string `xml:"Name"`
+			Institution string `xml:"FinancialInstitutionBranch>ID"`
+		} `xml:"PayeeFinancialAccount"`
+	} `xml:"PaymentMeans"`
+	Payable struct {
+		Currency string `xml:"currencyID,attr"`
+		Value    string `xml:",chardata"`
+	} `xml:"LegalMonetaryTotal>PayableAmount"`
+}
+
+// ReadInvoice reads the payment of a UBL invoice, to the account of the first
+// payment means with a payee account.
+//
+// The account is read as Bankgiro or Plusgiro number if its financial
+// institution is BankgiroID or PlusgiroID, as IBAN if it is a valid IBAN and
+// as BBAN otherwise, with the financial institution as bank code. The
+// payment ID is used as reference, or else the invoice number. The payable
+// amount is used as amount, and the due date, or else the issue date, as due
+// date. The payee is the payee party if there is one, otherwise the seller.
+func ReadInvoice(r io.Reader) (*payqr.Payment, error) {
+	var inv ublInvoice
+	if err := xml.NewDecoder(r).Decode(&inv); err != nil {
+		return nil, fmt.Errorf("peppol: %w", err)
+	}
+
+	i := -1
+	for j, means := range inv.PaymentMeans {
+		if means.Account.ID != "" {
+			i = j
+			break
+		}
+	}
+	if i < 0 {
+		return nil, fmt.Errorf("peppol: invoice %s has no payee account", inv.ID)
+	}
+	means := inv.PaymentMeans[i]
+
+	amount, err := strconv.ParseFloat(strings.TrimSpace(inv.Payable.Value), 64)
+	if err != nil {
+		return nil, fmt.Errorf("peppol: invoice %s: invalid payable amount %q", inv.ID, inv.Payable.Value)
+	}
+
+	date := inv.DueDate
+	if date == "" {
+		date = means.DueDate
+	}
+	if date == "" {
+		date = inv.IssueDate
+	}
+	due, err := time.Parse("2006-01-02", strings.TrimSpace(date))
+	if err != nil {
+		return nil, fmt.Errorf("peppol: invoice %s: invalid due date %q", inv.ID, date)
+	}
+
+	payee := inv.Supplier
+	if inv.Payee != nil {
+		payee = *inv.Payee
+		if payee.CompanyID == "" {
+			payee.CompanyID = inv.Supplier.CompanyID
+		}
+	}
+	if payee.name() == "" {
+		return nil, fmt.Errorf("peppol: invoice %s has no payee name", inv.ID)
+	}
+
+	currency := inv.Payable.Currency
+	if currency == "" {
+		currency = inv.CurrencyCode
+	}
+	options := []payqr.Option{payqr.WithCurrency(currency)}
+
+	account := strings.TrimSpace(means.Account.ID)
+	institution := strings.TrimSpace(means.Account.Institution)
+	switch {
+	case strings.EqualFold(institution, BankgiroID):
+		options = append(options, payqr.WithPaymentType(payqr.PaymentTypeBG))
+	case strings.EqualFold(institution, PlusgiroID):
+		options = append(options, payqr.WithPaymentType(payqr.PaymentTypePG))
+	case payqr.ValidateAccount("", payqr.PaymentTypeIBAN, account) == nil:
+		options = append(options, payqr.WithPaymentType(payqr.PaymentTypeIBAN))
+		if institution != "" {
+			options = append(options, payqr.WithBankCode(institution))
+		}
+		// IBAN payments need a country, which the IBAN carries if the
+		// payee has no address.
+		if payee.Country == "" {
+			payee.Country = strings.ToUpper(account[:2])
+		}
+	default:
+		options = append(options, payqr.WithPaymentType(payqr.PaymentTypeBBAN))
+		if institution != "" {
+			options = append(options, payqr.WithBankCode(institution))
+		}
+	}
+
+	if payee.Country != "" {
+		options = append(options, payqr.WithCountryCode(payee.Country))
+	}
+	if address := payee.address(); address != "" {
+		options = append(options, payqr.WithAddress(address))
+	}
+	if issued, err := time.Parse("2006-01-02", strings.TrimSpace(inv.IssueDate)); err == nil {
+		options = append(options, payqr.WithCreationDate(issued))
+	}
+
+	reference := strings.TrimSpace(means.ID)
+	if reference == "" {
+		reference = inv.ID
+	}
+	if reference == "" {
+		return nil, errors.New("peppol: invoice has no payment ID or number")
+	}
+
+	return payqr.New(account, payee.name(), payee.CompanyID, reference, amount, due, options...), nil
+}
@@ -0,0 +1,76 @@
+package peppol
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadInvoice(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		want *payqr.Payment
+	}{
+		{
+			name: "Bankgiro",
+			file: "testdata/bis3-bankgiro.xml",
+			want: payqr.New("54029681", "Leverantör AB", "5555555555", "52250047157", 1250, time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC),
+				payqr.WithCreationDate(time.Date(2022, time.July, 7, 0, 0, 0, 0, time.UTC)), payqr.WithCurrency("SEK"),
+				payqr.WithCountryCode("SE"), payqr.WithAddress("Storgatan 1\n111 22 Stockholm")),
+		},
+		{
+			name: "IBAN to payee",
+			file: "testdata/bis3-iban.xml",
+			want: payqr.New("DE75512108001245126199", "Factoring AG", "HRB 1234", "INV-42", 200.5, time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC),
+				payqr.WithCreationDate(time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)), payqr.WithCurrency("EUR"),
+				payqr.WithPaymentType(payqr.PaymentTypeIBAN), payqr.WithBankCode("SOGEDEFFXXX"), payqr.WithCountryCode("DE")),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f, err := os.Open(test.file)
+			require.NoError(t, err)
+			defer f.Close()
+
+			p, err := ReadInvoice(f)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, p)
+			assert.NoError(t, p.Validate())
+		})
+	}
+}
+
+func TestReadInvoiceError(t *testing.T) {
+	tests := []struct {
+		name string
+		have string
+	}{
+		{name: "Not XML", have: "invoice"},
+		{name: "Credit note", have: `<CreditNote><ID>1</ID></CreditNote>`},
+		{name: "No account", have: `<Invoice><ID>1</ID><IssueDate>2022-08-06</IssueDate></Invoice>`},
+		{
+			name: "Invalid amount",
+			have: `<Invoice><ID>1</ID><IssueDate>2022-08-06</IssueDate><PaymentMeans><PayeeFinancialAccount><ID>54029681</ID></PayeeFinancialAccount></PaymentMeans>` +
+				`<LegalMonetaryTotal><PayableAmount>1,00</PayableAmount></LegalMonetaryTotal></Invoice>`,
+		},
+		{
+			name: "No date",
+			have: `<Invoice><ID>1</ID><PaymentMeans><PayeeFinancialAccount><ID>54029681</ID></PayeeFinancialAccount></PaymentMeans>` +
+				`<LegalMonetaryTotal><PayableAmount>1.00</PayableAmount></LegalMonetaryTotal></Invoice>`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ReadInvoice(strings.NewReader(test.have))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+// Package pix encodes payments as Brazilian Pix QR codes ("BR Code"), an
+// EMVCo merchant-presented mode payload with the Pix merchant account
+// template.
+package pix
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/antonlindstrom/payqr/emvco"
+)
+
+// GUI is the globally unique identifier of the Pix merchant account template.
+const GUI = "br.gov.bcb.pix"
+
+// Currency is the currency of Pix payments.
+const Currency = "BRL"
+
+// Option is a modifier for the encoding.
+type Option func(*config)
+
+type config struct {
+	city        string
+	postalCode  string
+	description string
+}
+
+// WithCity sets the city of the merchant, required by the format.
+func WithCity(city string) Option {
+	return func(c *config) {
+		c.city = city
+	}
+}
+
+// WithPostalCode sets the postal code (CEP) of the merchant.
+func WithPostalCode(postalCode string) Option {
+	return func(c *config) {
+		c.postalCode = postalCode
+	}
+}
+
+// WithDescription sets a message shown to the payer.
+func WithDescription(description string) Option {
+	return func(c *config) {
+		c.description = description
+	}
+}
+
+var (
+	cpfOrCNPJ = regexp.MustCompile(`^(\d{11}|\d{14})$`)
+	phone     = regexp.MustCompile(`^\+55\d{10,11}$`)
+	email     = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+	evp       = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+	txid      = regexp.MustCompile(`^[A-Za-z0-9]{1,25}$`)
+)
+
+// ValidateKey checks that the key is a Pix key: a CPF or CNPJ number, a phone
+// number with +55, an email address or a random key (EVP).
+func ValidateKey(key string) error {
+	if len(key) > 77 {
+		return fmt.Errorf("pix: key is longer than 77 characters")
+	}
+	for _, re := range []*regexp.Regexp{cpfOrCNPJ, phone, email, evp} {
+		if re.MatchString(key) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("pix: %q is not a valid key", key)
+}
+
+// Payload returns the EMVCo payload of the payment. The account number is the
+// Pix key and the reference is the transaction ID (txid), "***" is used if it
+// is empty. A zero amount lets the payer enter the amount.
+func Payload(p *payqr.Payment, options ...Option) (emvco.Payload, error) {
+	c := &config{}
+	for _, opt := range options {
+		opt(c)
+	}
+
+	if p.Currency != "" && !strings.EqualFold(p.Currency, Currency) {
+		return emvco.Payload{}, fmt.Errorf("pix: currency must be %s, got %s", Currency, p.Currency)
+	}
+	if err := ValidateKey(p.AccountNumber); err != nil {
+		return emvco.Payload{}, err
+	}
+	if c.city == "" {
+		return emvco.Payload{}, fmt.Errorf("pix: city is required")
+	}
+	if p.DueAmount < 0 {
+		return emvco.Payload{}, fmt.Errorf("pix: amount %.2f is negative", p.DueAmount)
+	}
+
+	ref := p.Reference
+	if ref == "" {
+		ref = "***"
+	} else if !txid.MatchString(ref) {
+		return emvco.Payload{}, fmt.Errorf("pix: reference must be 1 to 25 letters and digits, got %q", ref)
+	}
+
+	account := []emvco.Field{emvco.Value("00", GUI), emvco.Value("01", p.AccountNumber)}
+	if c.description != "" {
+		account = append(account, emvco.Value("02", c.description))
+	}
+
+	payload := emvco.Payload{
+		MerchantAccounts: []emvco.Field{emvco.Template("26", account...)},
+		MerchantCategory: "0000",
+		Currency:         "986",
+		CountryCode:      "BR",
+		MerchantName:     p.AccountName,
+		MerchantCity:     strings.ToUpper(c.city),
+		PostalCode:       c.postalCode,
+		AdditionalData:   []emvco.Field{emvco.Value("05", ref)},
+	}
+	if p.DueAmount > 0 {
+		payload.Amount = emvco.FormatAmount(p.DueAmount, 2)
+	}
+
+	return payload, nil
+}
+
+// Encode returns the Pix payload of the payment, see Payload.
+func Encode(p *payqr.Payment, options ...Option) (string, error) {
+	payload, err := Payload(p, options...)
+	if err != nil {
+		return "", err
+	}
+
+	return payload.Encode()
+}
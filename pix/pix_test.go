@@ -0,0 +1,80 @@
+package pix
+
+import (
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/antonlindstrom/payqr/emvco"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		have    *payqr.Payment
+		options []Option
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "Static without amount",
+			have:    payqr.New("123e4567-e12b-12d1-a456-426655440000", "Fulano de Tal", "", "", 0, due),
+			options: []Option{WithCity("Brasilia")},
+			want:    "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3D",
+		},
+		{
+			name:    "With amount and txid",
+			have:    payqr.New("+5561912345678", "Fulano de Tal", "", "NF1234", 10.5, due, payqr.WithCurrency("BRL")),
+			options: []Option{WithCity("Brasilia"), WithDescription("Pedido 1234")},
+		},
+		{
+			name:    "Missing city",
+			have:    payqr.New("12345678901", "Fulano de Tal", "", "", 10, due),
+			wantErr: true,
+		},
+		{
+			name:    "Invalid key",
+			have:    payqr.New("1234", "Fulano de Tal", "", "", 10, due),
+			options: []Option{WithCity("Brasilia")},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid txid",
+			have:    payqr.New("12345678901", "Fulano de Tal", "", "NF-1234", 10, due),
+			options: []Option{WithCity("Brasilia")},
+			wantErr: true,
+		},
+		{
+			name:    "SEK",
+			have:    payqr.New("12345678901", "Fulano de Tal", "", "", 10, due, payqr.WithCurrency("SEK")),
+			options: []Option{WithCity("Brasilia")},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Encode(test.have, test.options...)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if test.want != "" {
+				assert.Equal(t, test.want, got)
+			}
+
+			fields, err := emvco.Decode(got)
+			require.NoError(t, err)
+			amount, ok := emvco.Lookup(fields, emvco.IDAmount)
+			assert.Equal(t, test.have.DueAmount > 0, ok)
+			if ok {
+				assert.Equal(t, "10.50", amount)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+//go:build !payqr_payloadonly
+
+package payqr
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// Size of an A4 page in millimeters.
+const (
+	A4Width  = 210
+	A4Height = 297
+)
+
+// Recommended placement of the QR code on an A4 invoice page, in millimeters
+// from the top left corner. The code goes at the bottom right, next to the
+// payment details, below the lower fold of a letter folded in three (at 99 and
+// 198 mm) so that it is not creased when mailed. The size includes the quiet
+// zone and gives modules large enough to scan from paper for the payloads of
+// typical invoices.
+const (
+	A4QRLeft = 160
+	A4QRTop  = 232
+	A4QRSize = 30
+)
+
+// Placement is the position and size of a QR code on a page, in millimeters
+// from the top left corner.
+type Placement struct {
+	Left float64
+	Top  float64
+	Size float64
+}
+
+// RecommendedPlacement returns the recommended placement of the QR code on
+// an A4 invoice page.
+func RecommendedPlacement() Placement {
+	return Placement{Left: A4QRLeft, Top: A4QRTop, Size: A4QRSize}
+}
+
+// Rect returns the placement in pixels at the resolution in dots per inch.
+func (p Placement) Rect(dpi int) image.Rectangle {
+	left, top := pixels(p.Left, dpi), pixels(p.Top, dpi)
+	size := pixels(p.Size, dpi)
+
+	return image.Rect(left, top, left+size, top+size)
+}
+
+// Compose draws the QR code, including its quiet zone, onto the image of a
+// page rendered at the resolution in dots per inch.
+func Compose(dst draw.Image, q *qrcode.QRCode, p Placement, dpi int) {
+	r := p.Rect(dpi).Add(dst.Bounds().Min)
+	img := q.Image(r.Dx())
+
+	draw.Draw(dst, r, img, img.Bounds().Min, draw.Src)
+}
+
+// ComposeA4 returns a white A4 page at the resolution in dots per inch with
+// the QR code at the placement.
+func ComposeA4(q *qrcode.QRCode, p Placement, dpi int) *image.Gray {
+	page := image.NewGray(image.Rect(0, 0, pixels(A4Width, dpi), pixels(A4Height, dpi)))
+	draw.Draw(page, page.Rect, image.NewUniform(color.White), image.Point{}, draw.Src)
+	Compose(page, q, p, dpi)
+
+	return page
+}
+
+// A4 returns a white A4 page at the resolution in dots per inch with the QR
+// code of the payment at the recommended placement.
+func (d *Payment) A4(dpi int) (*image.Gray, error) {
+	q, err := d.QR()
+	if err != nil {
+		return nil, err
+	}
+
+	return ComposeA4(q, RecommendedPlacement(), dpi), nil
+}
+
+// pixels converts millimeters to pixels at the resolution in dots per inch.
+func pixels(mm float64, dpi int) int {
+	return int(math.Round(mm / 25.4 * float64(dpi)))
+}
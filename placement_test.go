@@ -0,0 +1,55 @@
+//go:build !payqr_payloadonly
+
+package payqr
+
+import (
+	"image"
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlacementRect(t *testing.T) {
+	tests := []struct {
+		name string
+		have Placement
+		dpi  int
+		want image.Rectangle
+	}{
+		{name: "Recommended at 300 dpi", have: RecommendedPlacement(), dpi: 300, want: image.Rect(1890, 2740, 2244, 3094)},
+		{name: "Recommended at 72 dpi", have: RecommendedPlacement(), dpi: 72, want: image.Rect(454, 658, 539, 743)},
+		{name: "Inch", have: Placement{Left: 25.4, Top: 0, Size: 25.4}, dpi: 100, want: image.Rect(100, 0, 200, 100)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, test.have.Rect(test.dpi))
+		})
+	}
+}
+
+func TestA4(t *testing.T) {
+	p := New("5402-9681", "Test AB", "1234", "52250047151", 50, time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC))
+
+	page, err := p.A4(150)
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 1240, 1754), page.Bounds())
+
+	// The page is white outside the code and the code has dark modules.
+	r := RecommendedPlacement().Rect(150)
+	assert.Equal(t, color.Gray{Y: 0xff}, page.GrayAt(r.Min.X-1, r.Min.Y-1))
+	assert.Equal(t, color.Gray{Y: 0xff}, page.GrayAt(r.Max.X, r.Max.Y))
+
+	dark := 0
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if page.GrayAt(x, y).Y == 0 {
+				dark++
+			}
+		}
+	}
+	assert.True(t, dark > 0)
+}
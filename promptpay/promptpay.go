@@ -0,0 +1,128 @@
+// Package promptpay encodes payments as Thai PromptPay QR codes, an EMVCo
+// merchant-presented mode payload with the PromptPay credit transfer
+// template (29).
+package promptpay
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/antonlindstrom/payqr/emvco"
+)
+
+// AID is the application ID of the PromptPay credit transfer template.
+const AID = "A000000677010111"
+
+// Currency is the currency of PromptPay payments.
+const Currency = "THB"
+
+// Target types, the sub field IDs of the template.
+const (
+	TargetPhone      = "01"
+	TargetNationalID = "02"
+	TargetEWallet    = "03"
+)
+
+// Option is a modifier for the encoding.
+type Option func(*config)
+
+type config struct {
+	city string
+}
+
+// WithCity sets the city of the merchant, required by the format.
+func WithCity(city string) Option {
+	return func(c *config) {
+		c.city = city
+	}
+}
+
+// Target returns the target type and value of a PromptPay ID: a Thai mobile
+// phone number, a 13 digit national ID or tax ID, or a 15 digit e-wallet ID.
+// Phone numbers are formatted with the country code, 0066 followed by the
+// number without leading zero.
+func Target(id string) (string, string, error) {
+	digits := strings.NewReplacer("-", "", " ", "").Replace(id)
+	if strings.HasPrefix(digits, "+66") {
+		digits = "0" + digits[3:]
+	}
+	if !isDigits(digits) {
+		return "", "", fmt.Errorf("promptpay: %q is not a valid PromptPay ID", id)
+	}
+
+	switch {
+	case len(digits) == 10 && digits[0] == '0':
+		return TargetPhone, "0066" + digits[1:], nil
+	case len(digits) == 13:
+		return TargetNationalID, digits, nil
+	case len(digits) == 15:
+		return TargetEWallet, digits, nil
+	}
+
+	return "", "", fmt.Errorf("promptpay: %q is not a valid PromptPay ID", id)
+}
+
+// Payload returns the EMVCo payload of the payment, with the account number
+// as PromptPay ID. A zero amount gives a static code where the payer enters
+// the amount, otherwise the code is dynamic.
+func Payload(p *payqr.Payment, options ...Option) (emvco.Payload, error) {
+	c := &config{}
+	for _, opt := range options {
+		opt(c)
+	}
+
+	if p.Currency != "" && !strings.EqualFold(p.Currency, Currency) {
+		return emvco.Payload{}, fmt.Errorf("promptpay: currency must be %s, got %s", Currency, p.Currency)
+	}
+	if c.city == "" {
+		return emvco.Payload{}, fmt.Errorf("promptpay: city is required")
+	}
+	if p.DueAmount < 0 {
+		return emvco.Payload{}, fmt.Errorf("promptpay: amount %.2f is negative", p.DueAmount)
+	}
+
+	target, id, err := Target(p.AccountNumber)
+	if err != nil {
+		return emvco.Payload{}, err
+	}
+
+	payload := emvco.Payload{
+		PointOfInitiation: emvco.Static,
+		MerchantAccounts:  []emvco.Field{emvco.Template("29", emvco.Value("00", AID), emvco.Value(target, id))},
+		MerchantCategory:  "0000",
+		Currency:          "764",
+		CountryCode:       "TH",
+		MerchantName:      p.AccountName,
+		MerchantCity:      strings.ToUpper(c.city),
+	}
+	if p.DueAmount > 0 {
+		payload.PointOfInitiation = emvco.Dynamic
+		payload.Amount = emvco.FormatAmount(p.DueAmount, 2)
+	}
+	if p.Reference != "" {
+		payload.AdditionalData = []emvco.Field{emvco.Value("05", p.Reference)}
+	}
+
+	return payload, nil
+}
+
+// Encode returns the PromptPay payload of the payment, see Payload.
+func Encode(p *payqr.Payment, options ...Option) (string, error) {
+	payload, err := Payload(p, options...)
+	if err != nil {
+		return "", err
+	}
+
+	return payload.Encode()
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return s != ""
+}
@@ -0,0 +1,65 @@
+package promptpay
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/antonlindstrom/payqr/emvco"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTarget(t *testing.T) {
+	tests := []struct {
+		name       string
+		have       string
+		wantTarget string
+		wantID     string
+		wantErr    bool
+	}{
+		{name: "Phone", have: "081-234-5678", wantTarget: TargetPhone, wantID: "0066812345678"},
+		{name: "Phone with country code", have: "+66812345678", wantTarget: TargetPhone, wantID: "0066812345678"},
+		{name: "National ID", have: "1-2345-67890-12-3", wantTarget: TargetNationalID, wantID: "1234567890123"},
+		{name: "E-wallet", have: "123456789012345", wantTarget: TargetEWallet, wantID: "123456789012345"},
+		{name: "Too short", have: "12345", wantErr: true},
+		{name: "Letters", have: "08123456ab", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			target, id, err := Target(test.have)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.wantTarget, target)
+			assert.Equal(t, test.wantID, id)
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+
+	got, err := Encode(payqr.New("0812345678", "Somchai", "", "", 0, due), WithCity("Bangkok"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(got, "000201010211"+"29370016A000000677010111011300668123456785204000053037645802TH"), got)
+
+	got, err = Encode(payqr.New("0812345678", "Somchai", "", "INV1", 100.25, due, payqr.WithCurrency("THB")), WithCity("Bangkok"))
+	require.NoError(t, err)
+	fields, err := emvco.Decode(got)
+	require.NoError(t, err)
+	poi, _ := emvco.Lookup(fields, emvco.IDPointOfInitiation)
+	assert.Equal(t, emvco.Dynamic, poi)
+	amount, _ := emvco.Lookup(fields, emvco.IDAmount)
+	assert.Equal(t, "100.25", amount)
+
+	_, err = Encode(payqr.New("0812345678", "Somchai", "", "", 0, due))
+	assert.Error(t, err)
+
+	_, err = Encode(payqr.New("0812345678", "Somchai", "", "", 10, due, payqr.WithCurrency("SEK")), WithCity("Bangkok"))
+	assert.Error(t, err)
+}
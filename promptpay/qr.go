@@ -0,0 +1,18 @@
+//go:build !payqr_payloadonly
+
+package promptpay
+
+import (
+	"github.com/antonlindstrom/payqr"
+	"github.com/skip2/go-qrcode"
+)
+
+// QR returns the PromptPay QR code of the payment.
+func QR(p *payqr.Payment, options ...Option) (*qrcode.QRCode, error) {
+	payload, err := Payload(p, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return payload.QR()
+}
@@ -0,0 +1,67 @@
+//go:build !payqr_payloadonly
+
+package payqr
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+)
+
+func init() {
+	RegisterCapability(CapabilityPNG)
+}
+
+// QR returns a QR code that can be used to communicate how to send transfers.
+func (d *Payment) QR() (*qrcode.QRCode, error) {
+	payload, err := d.Payload()
+	if err != nil {
+		return nil, err
+	}
+
+	return newQR(payload, qrcode.High, d)
+}
+
+// QR returns a QR code that can be used for Swish payments. An error is
+// returned if the payment does not validate.
+func (s *SwishPayment) QR() (*qrcode.QRCode, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+
+	return newQR(s.Encode(), qrcode.High, nil)
+}
+
+// SwishQR returns a QR code that can be used for Swish payments. An error is
+// returned if the amount is outside of the allowed limits, if the currency is
+// not SEK or if a locked field is left empty.
+//
+// Prefer NewSwish, which does not require the invoice fields.
+func (d *Payment) SwishQR(phoneNumber string, options ...SwishOption) (*qrcode.QRCode, error) {
+	if d.Currency != "" && !strings.EqualFold(d.Currency, SwishCurrency) {
+		return nil, fmt.Errorf("swish only supports %s, got currency %q", SwishCurrency, d.Currency)
+	}
+
+	return d.Swish(phoneNumber, options...).QR()
+}
+
+// PaytoQR returns a QR code of the payto URI of the payment.
+func (d *Payment) PaytoQR() (*qrcode.QRCode, error) {
+	uri, err := d.PaytoURI()
+	if err != nil {
+		return nil, err
+	}
+
+	return newQR(uri, qrcode.Medium, nil)
+}
+
+// QR returns the QR code of the payment with the reference and amount.
+func (t *Template) QR(reference string, amount float64) (*qrcode.QRCode, error) {
+	payload, err := t.Payload(reference, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	return newQR(payload, qrcode.High, &t.base)
+}
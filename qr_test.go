@@ -0,0 +1,32 @@
+//go:build !payqr_payloadonly
+
+package payqr
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPNGCapability(t *testing.T) {
+	assert.True(t, HasCapability(CapabilityPNG))
+}
+
+func TestTemplateQR(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+	base := New("5402-9681", "Test AB", "5555555555", "", 0, due)
+	tmpl, err := NewTemplate(base)
+	require.NoError(t, err)
+
+	q, err := tmpl.QR("52250047157", 1250)
+	require.NoError(t, err)
+	want, err := New("5402-9681", "Test AB", "5555555555", "52250047157", 1250, due).QR()
+	require.NoError(t, err)
+	assert.Equal(t, want.Content, q.Content)
+
+	_, err = tmpl.QR("52250047157", math.Inf(1))
+	assert.Error(t, err)
+}
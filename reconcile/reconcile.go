@@ -0,0 +1,115 @@
+// Package reconcile matches received payments, as read from bank files, to
+// the payments they were invoiced by.
+package reconcile
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+)
+
+// Status is the status of an invoiced payment after reconciliation.
+type Status string
+
+const (
+	StatusUnpaid   Status = "unpaid"
+	StatusPartial  Status = "partial"
+	StatusPaid     Status = "paid"
+	StatusOverpaid Status = "overpaid"
+)
+
+// Entry is an amount received for a reference.
+type Entry struct {
+	// Account is the account of the payee the amount was paid to, such as
+	// a Bankgiro number. Entries without an account are matched by
+	// reference only.
+	Account   string
+	Date      time.Time
+	Reference string
+	Amount    float64 // Negative for deductions and credited amounts.
+	Currency  string
+
+	// Source is the record the entry was read from, such as a
+	// *bgmax.Payment.
+	Source interface{}
+}
+
+// Match is an invoiced payment with the entries received for it.
+type Match struct {
+	Invoice  *payqr.Payment
+	Entries  []Entry
+	Received float64
+	Status   Status
+}
+
+// Result is the result of a reconciliation.
+type Result struct {
+	// Matches has one match per invoiced payment, in the order given.
+	Matches []Match
+
+	// Unmatched are the entries without an invoiced payment.
+	Unmatched []Entry
+}
+
+// Reconcile matches the entries to the invoiced payments by reference and
+// payee account. Several entries for the same payment, such as partial
+// payments, are added up.
+func Reconcile(invoices []*payqr.Payment, entries []Entry) *Result {
+	type key struct{ account, reference string }
+
+	res := &Result{Matches: make([]Match, len(invoices))}
+	byAccount := map[key]int{}
+	byReference := map[string]int{}
+	for i := len(invoices) - 1; i >= 0; i-- {
+		inv := invoices[i]
+		res.Matches[i].Invoice = inv
+		byAccount[key{NormalizeAccount(inv.AccountNumber), NormalizeReference(inv.Reference)}] = i
+		byReference[NormalizeReference(inv.Reference)] = i
+	}
+
+	for _, e := range entries {
+		ref := NormalizeReference(e.Reference)
+
+		i, ok := byReference[ref]
+		if e.Account != "" {
+			i, ok = byAccount[key{NormalizeAccount(e.Account), ref}]
+		}
+		if !ok || ref == "" {
+			res.Unmatched = append(res.Unmatched, e)
+			continue
+		}
+		res.Matches[i].Entries = append(res.Matches[i].Entries, e)
+		res.Matches[i].Received += e.Amount
+	}
+
+	for i := range res.Matches {
+		m := &res.Matches[i]
+		m.Received = math.Round(m.Received*100) / 100
+
+		switch diff := m.Received - m.Invoice.DueAmount; {
+		case len(m.Entries) == 0:
+			m.Status = StatusUnpaid
+		case math.Abs(diff) < 0.005:
+			m.Status = StatusPaid
+		case diff < 0:
+			m.Status = StatusPartial
+		default:
+			m.Status = StatusOverpaid
+		}
+	}
+
+	return res
+}
+
+// NormalizeAccount returns the account without separators and leading
+// zeros, as account numbers are often zero padded in bank files.
+func NormalizeAccount(account string) string {
+	return strings.TrimLeft(strings.ToUpper(strings.NewReplacer("-", "", " ", "", ".", "").Replace(account)), "0")
+}
+
+// NormalizeReference returns the reference without spaces.
+func NormalizeReference(ref string) string {
+	return strings.ToUpper(strings.ReplaceAll(ref, " ", ""))
+}
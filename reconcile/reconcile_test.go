@@ -0,0 +1,54 @@
+package reconcile
+
+import (
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconcile(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+	invoices := []*payqr.Payment{
+		payqr.New("5402-9681", "Test AB", "", "52250047157", 1250, due),
+		payqr.New("5402-9681", "Test AB", "", "4455", 100, due),
+		payqr.New("5402-9681", "Test AB", "", "7788", 50, due),
+		payqr.New("5402-9681", "Test AB", "", "1234", 10, due),
+	}
+
+	res := Reconcile(invoices, []Entry{
+		{Account: "0054029681", Reference: "5225 0047 157", Amount: 1000},
+		{Reference: "52250047157", Amount: 250},
+		{Account: "5402-9681", Reference: "4455", Amount: 50},
+		{Account: "5402-9681", Reference: "7788", Amount: 60},
+		{Account: "9912-3456", Reference: "1234", Amount: 10},
+		{Reference: "", Amount: 5},
+	})
+
+	require.Len(t, res.Matches, 4)
+	for i, want := range []Status{StatusPaid, StatusPartial, StatusOverpaid, StatusUnpaid} {
+		assert.Equal(t, want, res.Matches[i].Status, invoices[i].Reference)
+	}
+	assert.Equal(t, 1250.0, res.Matches[0].Received)
+	assert.Len(t, res.Unmatched, 2)
+}
+
+func TestNormalizeAccount(t *testing.T) {
+	tests := []struct {
+		have string
+		want string
+	}{
+		{have: "5402-9681", want: "54029681"},
+		{have: "0054029681", want: "54029681"},
+		{have: "90 01 22-3", want: "9001223"},
+		{have: "se45 5000", want: "SE455000"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.have, func(t *testing.T) {
+			assert.Equal(t, test.want, NormalizeAccount(test.have))
+		})
+	}
+}
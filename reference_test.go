@@ -0,0 +1,51 @@
+package payqr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReferenceScheme(t *testing.T) {
+	tests := []struct {
+		name    string
+		scheme  ReferenceScheme
+		base    string
+		want    string
+		wantErr bool
+	}{
+		{name: "Swedish OCR", scheme: ReferenceOCR, base: "123456789", want: "1234567897"},
+		{name: "KID mod 10", scheme: ReferenceKIDMod10, base: "1234567890", want: "12345678903"},
+		{name: "KID mod 11", scheme: ReferenceKIDMod11, base: "1234567890", want: "12345678903"},
+		{name: "KID mod 11 with dash", scheme: ReferenceKIDMod11, base: "1009", want: "1009-"},
+		{name: "Danish FIK", scheme: ReferenceFIK, base: "00000000012345", want: "000000000123455"},
+		{name: "Danish GIK", scheme: ReferenceGIK, base: "000000000012345", want: "0000000000123455"},
+		{name: "Danish FIK too short", scheme: ReferenceFIK, base: "12345", wantErr: true},
+		{name: "Non-digits", scheme: ReferenceOCR, base: "12A45", wantErr: true},
+		{name: "Free", scheme: ReferenceFree, base: "Invoice 1001", want: "Invoice 1001"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.scheme.Generate(test.base)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+			assert.NoError(t, test.scheme.Validate(got))
+		})
+	}
+}
+
+func TestValidateReference(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+
+	assert.NoError(t, New("5536-7742", "Test AB", "1234", "1234567897", 50, due, WithReferenceScheme(ReferenceOCR)).ValidateReference())
+	assert.Error(t, New("5536-7742", "Test AB", "1234", "1234567890", 50, due, WithReferenceScheme(ReferenceOCR)).ValidateReference())
+	assert.Error(t, New("5536-7742", "Test AB", "1234", "12345678904", 50, due, WithReferenceScheme(ReferenceKIDMod11)).ValidateReference())
+	assert.NoError(t, New("5536-7742", "Test AB", "1234", "1234567890", 50, due).ValidateReference())
+}
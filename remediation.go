@@ -0,0 +1,141 @@
+//go:build !payqr_payloadonly
+
+package payqr
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// Variant describes how a QR code is rendered.
+type Variant struct {
+	Level   qrcode.RecoveryLevel
+	Minimal bool // Drop optional fields from the payload.
+	Size    int  // Image size in pixels.
+}
+
+// DefaultVariants are the variants tried by a Remediator, in order. Each one
+// produces fewer and larger modules than the previous.
+var DefaultVariants = []Variant{
+	{Level: qrcode.High, Size: 512},
+	{Level: qrcode.Medium, Minimal: true, Size: 512},
+	{Level: qrcode.Low, Minimal: true, Size: 768},
+	{Level: qrcode.Low, Minimal: true, Size: 1024},
+}
+
+// Remediator keeps track of reported scan failures per artifact, e.g. a
+// printed invoice, and regenerates the QR code with smaller payloads and
+// larger modules until the artifact is reported to scan.
+type Remediator struct {
+	mu       sync.Mutex
+	variants []Variant
+	failures map[string]int
+	resolved map[Variant]int
+}
+
+// NewRemediator creates a Remediator trying the variants in order, if none
+// are given DefaultVariants are used.
+func NewRemediator(variants ...Variant) *Remediator {
+	if len(variants) == 0 {
+		variants = DefaultVariants
+	}
+
+	return &Remediator{
+		variants: variants,
+		failures: make(map[string]int),
+		resolved: make(map[Variant]int),
+	}
+}
+
+// ReportFailure records that the artifact could not be scanned.
+func (r *Remediator) ReportFailure(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.failures[id]++
+}
+
+// ReportResolved records that the current variant of the artifact could be
+// scanned. The artifact keeps using the variant.
+func (r *Remediator) ReportResolved(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.resolved[r.variant(id)]++
+}
+
+// Variant returns the variant to use for the artifact given the reported
+// failures.
+func (r *Remediator) Variant(id string) Variant {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.variant(id)
+}
+
+func (r *Remediator) variant(id string) Variant {
+	i := r.failures[id]
+	if i >= len(r.variants) {
+		i = len(r.variants) - 1
+	}
+
+	return r.variants[i]
+}
+
+// Resolved returns how many times each variant has been reported to resolve
+// scan failures.
+func (r *Remediator) Resolved() map[Variant]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	resolved := make(map[Variant]int, len(r.resolved))
+	for v, n := range r.resolved {
+		resolved[v] = n
+	}
+
+	return resolved
+}
+
+// Remediate returns the QR code for the artifact using the variant selected
+// from the reported failures. The QR code should be rendered with the size of
+// the returned variant.
+func (r *Remediator) Remediate(id string, p *Payment) (*qrcode.QRCode, Variant, error) {
+	v := r.Variant(id)
+
+	q, err := p.variantQR(v)
+	if err != nil {
+		return nil, v, err
+	}
+
+	return q, v, nil
+}
+
+// variantQR returns the QR code of the payment rendered as the variant.
+func (d *Payment) variantQR(v Variant) (*qrcode.QRCode, error) {
+	p := d
+	if v.Minimal {
+		p = d.minimal()
+	}
+
+	b, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return newQR(string(b), v.Level, p)
+}
+
+// minimal returns a copy of the payment without the optional fields that are
+// not needed to make the payment.
+func (d *Payment) minimal() *Payment {
+	p := *d
+	p.Address = ""
+	p.VAT = 0
+	p.HighVAT = 0
+	p.MediumVAT = 0
+	p.LowVAT = 0
+
+	return &p
+}
@@ -0,0 +1,40 @@
+//go:build !payqr_payloadonly
+
+package payqr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/skip2/go-qrcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemediator(t *testing.T) {
+	p := New("DK4830004073013895", "Test company AB", "555555-5555", "934000000000159", 10.75, time.Date(2012, time.February, 15, 0, 0, 0, 0, time.Local), WithPaymentType(PaymentTypeIBAN), WithAddress("1092 Köpenhamn"))
+	r := NewRemediator()
+
+	q, v, err := r.Remediate("invoice-1", p)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultVariants[0], v)
+	assert.Contains(t, q.Content, "Köpenhamn")
+
+	r.ReportFailure("invoice-1")
+	q, v, err = r.Remediate("invoice-1", p)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultVariants[1], v)
+	assert.Equal(t, qrcode.Medium, q.Level)
+	assert.NotContains(t, q.Content, "Köpenhamn")
+	assert.Equal(t, "1092 Köpenhamn", p.Address, "payment should not be modified")
+
+	for i := 0; i < 10; i++ {
+		r.ReportFailure("invoice-1")
+	}
+	assert.Equal(t, DefaultVariants[len(DefaultVariants)-1], r.Variant("invoice-1"))
+
+	r.ReportResolved("invoice-1")
+	assert.Equal(t, map[Variant]int{DefaultVariants[len(DefaultVariants)-1]: 1}, r.Resolved())
+	assert.Equal(t, DefaultVariants[len(DefaultVariants)-1], r.Variant("invoice-1"))
+	assert.Equal(t, DefaultVariants[0], r.Variant("invoice-2"))
+}
@@ -0,0 +1,72 @@
+//go:build !payqr_payloadonly
+
+package sheet
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Avery label sheets, with the measurements of the templates from Avery.
+var (
+	// AveryL7160 is 21 labels of 63.5x38.1 mm on A4.
+	AveryL7160 = label(A4, 3, 7, 7.21, 15.15, 63.5, 38.1, 66.04, 38.1)
+	// AveryL7163 is 14 labels of 99.1x38.1 mm on A4.
+	AveryL7163 = label(A4, 2, 7, 4.65, 15.15, 99.1, 38.1, 101.6, 38.1)
+	// AveryL7651 is 65 labels of 38.1x21.2 mm on A4.
+	AveryL7651 = label(A4, 5, 13, 4.75, 10.7, 38.1, 21.2, 40.64, 21.2)
+	// Avery5160 is 30 labels of 2 5/8x1 inches on Letter.
+	Avery5160 = label(Letter, 3, 10, 4.7625, 12.7, 66.675, 25.4, 69.85, 25.4)
+	// Avery5163 is 10 labels of 4x2 inches on Letter.
+	Avery5163 = label(Letter, 2, 5, 3.96875, 12.7, 101.6, 50.8, 104.775, 50.8)
+)
+
+// labels are the label sheets by name.
+var labels = map[string]Layout{
+	"L7160": AveryL7160,
+	"L7163": AveryL7163,
+	"L7651": AveryL7651,
+	"5160":  Avery5160,
+	"5163":  Avery5163,
+}
+
+// label returns the layout of a label sheet. The code is placed beside the
+// caption on labels wider than they are high.
+func label(page PageSize, columns, rows int, left, top, width, height, pitchX, pitchY float64) Layout {
+	return Layout{
+		Page:         page,
+		Columns:      columns,
+		Rows:         rows,
+		Left:         left,
+		Top:          top,
+		CellWidth:    width,
+		CellHeight:   height,
+		PitchX:       pitchX,
+		PitchY:       pitchY,
+		Padding:      2,
+		CaptionRight: width > 1.5*height,
+	}
+}
+
+// Label returns the layout of the label sheet with the Avery product code,
+// such as "L7160" or "5160".
+func Label(name string) (Layout, error) {
+	l, ok := labels[strings.TrimSpace(strings.TrimPrefix(strings.ToUpper(name), "AVERY"))]
+	if !ok {
+		return Layout{}, fmt.Errorf("sheet: unknown label sheet %q, supported are %s", name, strings.Join(Labels(), ", "))
+	}
+
+	return l, nil
+}
+
+// Labels returns the names of the supported label sheets.
+func Labels() []string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
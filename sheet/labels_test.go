@@ -0,0 +1,53 @@
+//go:build !payqr_payloadonly
+
+package sheet
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabels(t *testing.T) {
+	for _, name := range Labels() {
+		t.Run(name, func(t *testing.T) {
+			l, err := Label(name)
+			require.NoError(t, err)
+			assert.NoError(t, l.Validate())
+		})
+	}
+}
+
+func TestLabel(t *testing.T) {
+	tests := []struct {
+		name    string
+		have    string
+		want    int
+		wantErr bool
+	}{
+		{name: "L7160", have: "L7160", want: 21},
+		{name: "With brand", have: "Avery L7163", want: 14},
+		{name: "Letter", have: "5160", want: 30},
+		{name: "Unknown", have: "L9999", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l, err := Label(test.have)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, l.PerPage())
+		})
+	}
+}
+
+func TestLabelPDF(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, PDF(&buf, AveryL7160, testItems(t, 22)))
+	assert.Equal(t, 2, bytes.Count(buf.Bytes(), []byte("/Type /Page ")))
+}
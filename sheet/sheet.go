@@ -0,0 +1,276 @@
+//go:build !payqr_payloadonly
+
+// Package sheet lays out many QR codes with captions on printable pages, in
+// a grid on A4 or Letter paper or on Avery label sheets, as PDF or PNG. It is
+// meant for printing batches of personalized codes, such as Swish codes for
+// each member of an association.
+package sheet
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/antonlindstrom/payqr/internal/canvas"
+	"github.com/antonlindstrom/payqr/internal/pdf"
+	"github.com/skip2/go-qrcode"
+)
+
+func init() {
+	payqr.RegisterCapability(payqr.CapabilityPDF)
+}
+
+// PageSize is the size of a page in millimeters.
+type PageSize struct {
+	Width  float64
+	Height float64
+}
+
+var (
+	A4     = PageSize{Width: 210, Height: 297}
+	Letter = PageSize{Width: 215.9, Height: 279.4}
+)
+
+// Item is a QR code with the lines of text printed below it. The code is
+// printed with its quiet zone unless it is disabled on the code.
+type Item struct {
+	QR      *qrcode.QRCode
+	Caption []string
+}
+
+// Layout is the placement of the cells on a page, in millimeters from the
+// top left corner. Cells are filled row by row.
+type Layout struct {
+	Page    PageSize
+	Columns int
+	Rows    int
+
+	// Left and Top are the position of the first cell.
+	Left float64
+	Top  float64
+
+	// CellWidth and CellHeight are the size of each cell, and PitchX and
+	// PitchY the distance from one cell to the next, including any gap.
+	CellWidth  float64
+	CellHeight float64
+	PitchX     float64
+	PitchY     float64
+
+	// Padding is the space kept clear inside each cell.
+	Padding float64
+
+	// Outline draws the border of each cell, as a guide for cutting.
+	Outline bool
+
+	// CaptionRight places the caption to the right of the code instead of
+	// below it, for wide cells such as address labels.
+	CaptionRight bool
+}
+
+// Grid returns a layout of columns by rows cells filling the page inside the
+// margin, with outlined cells.
+func Grid(page PageSize, columns, rows int, margin float64) Layout {
+	l := Layout{
+		Page:    page,
+		Columns: columns,
+		Rows:    rows,
+		Left:    margin,
+		Top:     margin,
+		Padding: 3,
+		Outline: true,
+	}
+	if columns > 0 && rows > 0 {
+		l.CellWidth = (page.Width - 2*margin) / float64(columns)
+		l.CellHeight = (page.Height - 2*margin) / float64(rows)
+		l.PitchX, l.PitchY = l.CellWidth, l.CellHeight
+	}
+
+	return l
+}
+
+// PerPage returns the number of cells on each page.
+func (l Layout) PerPage() int {
+	return l.Columns * l.Rows
+}
+
+// Pages returns the number of pages needed for n items.
+func (l Layout) Pages(n int) int {
+	if l.PerPage() == 0 {
+		return 0
+	}
+
+	return (n + l.PerPage() - 1) / l.PerPage()
+}
+
+// Validate checks that the layout has cells and that they are on the page.
+func (l Layout) Validate() error {
+	if l.Columns < 1 || l.Rows < 1 {
+		return fmt.Errorf("sheet: layout needs at least one column and row, got %dx%d", l.Columns, l.Rows)
+	}
+	if l.CellWidth <= 2*l.Padding || l.CellHeight <= 2*l.Padding {
+		return fmt.Errorf("sheet: cells of %.1fx%.1f mm are too small", l.CellWidth, l.CellHeight)
+	}
+
+	const tolerance = 0.01
+	right := l.Left + float64(l.Columns-1)*l.PitchX + l.CellWidth
+	bottom := l.Top + float64(l.Rows-1)*l.PitchY + l.CellHeight
+	if l.Left < 0 || l.Top < 0 || right > l.Page.Width+tolerance || bottom > l.Page.Height+tolerance {
+		return errors.New("sheet: cells are outside of the page")
+	}
+
+	return nil
+}
+
+// Caption text size in points and line height in millimeters.
+const (
+	captionSize = 8
+	lineHeight  = 3.5
+)
+
+// drawCell draws the item in the cell with its top left corner at left, top.
+// The code is as large as fits above the caption, both centered.
+func (l Layout) drawCell(c canvas.Canvas, left, top float64, item Item) {
+	if l.Outline {
+		c.Rect(left, top, l.CellWidth, l.CellHeight)
+	}
+
+	if l.CaptionRight {
+		l.drawCellRight(c, left, top, item)
+		return
+	}
+
+	width := l.CellWidth - 2*l.Padding
+	size := math.Min(width, l.CellHeight-2*l.Padding-float64(len(item.Caption))*lineHeight)
+	if size > 0 && item.QR != nil {
+		c.Modules(left+(l.CellWidth-size)/2, top+l.Padding, size, item.QR.Bitmap())
+	}
+
+	y := top + l.Padding + math.Max(size, 0)
+	for _, line := range item.Caption {
+		line = fit(line, width)
+		y += lineHeight
+		c.Text(left+(l.CellWidth-canvas.TextWidth(pdf.Helvetica, captionSize, line))/2, y-0.8, pdf.Helvetica, captionSize, line)
+	}
+}
+
+// drawCellRight draws the item with the code at the left of the cell, as
+// high as the cell, and the caption vertically centered to its right.
+func (l Layout) drawCellRight(c canvas.Canvas, left, top float64, item Item) {
+	size := math.Min(l.CellHeight, l.CellWidth) - 2*l.Padding
+	if item.QR != nil {
+		c.Modules(left+l.Padding, top+l.Padding, size, item.QR.Bitmap())
+	}
+
+	x := left + 2*l.Padding + size
+	width := left + l.CellWidth - l.Padding - x
+	y := top + (l.CellHeight-float64(len(item.Caption))*lineHeight)/2
+	for _, line := range item.Caption {
+		y += lineHeight
+		c.Text(x, y-0.8, pdf.Helvetica, captionSize, fit(line, width))
+	}
+}
+
+// drawPage draws the items of a page.
+func (l Layout) drawPage(c canvas.Canvas, items []Item) {
+	for i, item := range items {
+		col, row := i%l.Columns, i/l.Columns
+		l.drawCell(c, l.Left+float64(col)*l.PitchX, l.Top+float64(row)*l.PitchY, item)
+	}
+}
+
+// pages splits the items by page.
+func (l Layout) pages(items []Item) [][]Item {
+	var pages [][]Item
+	for len(items) > 0 {
+		n := l.PerPage()
+		if n > len(items) {
+			n = len(items)
+		}
+		pages = append(pages, items[:n])
+		items = items[n:]
+	}
+
+	return pages
+}
+
+// fit shortens the text to fit in the width, ending it with "..." when
+// shortened.
+func fit(s string, width float64) string {
+	if canvas.TextWidth(pdf.Helvetica, captionSize, s) <= width {
+		return s
+	}
+
+	r := []rune(s)
+	for len(r) > 0 && canvas.TextWidth(pdf.Helvetica, captionSize, string(r)+"...") > width {
+		r = r[:len(r)-1]
+	}
+
+	return string(r) + "..."
+}
+
+// PDF renders the items on as many pages as needed.
+func PDF(w io.Writer, l Layout, items []Item) error {
+	if err := l.Validate(); err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return errors.New("sheet: no items")
+	}
+
+	doc := pdf.New()
+	for _, page := range l.pages(items) {
+		p := doc.AddPage(l.Page.Width*pdf.MM, l.Page.Height*pdf.MM)
+		l.drawPage(canvas.NewPDF(p, 0, 0), page)
+	}
+
+	_, err := doc.WriteTo(w)
+
+	return err
+}
+
+// PNG renders the items as one PNG image per page at the resolution in dots
+// per inch.
+func PNG(l Layout, items []Item, dpi int) ([][]byte, error) {
+	if err := l.Validate(); err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, errors.New("sheet: no items")
+	}
+
+	var images [][]byte
+	for _, page := range l.pages(items) {
+		c := canvas.NewImage(l.Page.Width, l.Page.Height, dpi)
+		l.drawPage(c, page)
+
+		b, err := c.PNG()
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, b)
+	}
+
+	return images, nil
+}
+
+// SwishItem returns an item with the QR code of the Swish payment, captioned
+// with the lines, or with the message and amount if there are none.
+func SwishItem(s *payqr.SwishPayment, caption ...string) (Item, error) {
+	q, err := s.QR()
+	if err != nil {
+		return Item{}, err
+	}
+
+	if len(caption) == 0 {
+		if s.Message != "" {
+			caption = append(caption, s.Message)
+		}
+		if !s.OpenAmount() {
+			caption = append(caption, fmt.Sprintf("%.2f %s", s.Amount, payqr.SwishCurrency))
+		}
+	}
+
+	return Item{QR: q, Caption: caption}, nil
+}
@@ -0,0 +1,86 @@
+//go:build !payqr_payloadonly
+
+package sheet
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testItems(t *testing.T, n int) []Item {
+	items := make([]Item, 0, n)
+	for i := 0; i < n; i++ {
+		item, err := SwishItem(payqr.NewSwish("1231111111", 100, fmt.Sprintf("Member %d", i+1)))
+		require.NoError(t, err)
+		items = append(items, item)
+	}
+
+	return items
+}
+
+func TestLayoutValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		have    Layout
+		wantErr bool
+	}{
+		{name: "A4 grid", have: Grid(A4, 3, 4, 10)},
+		{name: "Letter grid", have: Grid(Letter, 2, 2, 12.7)},
+		{name: "No columns", have: Grid(A4, 0, 4, 10), wantErr: true},
+		{name: "Too small", have: Grid(A4, 100, 4, 10), wantErr: true},
+		{name: "Outside page", have: Layout{Page: A4, Columns: 2, Rows: 1, CellWidth: 100, CellHeight: 100, PitchX: 120}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.have.Validate()
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestPages(t *testing.T) {
+	l := Grid(A4, 3, 4, 10)
+	assert.Equal(t, 12, l.PerPage())
+	assert.Equal(t, 0, l.Pages(0))
+	assert.Equal(t, 1, l.Pages(12))
+	assert.Equal(t, 2, l.Pages(13))
+}
+
+func TestPDF(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, PDF(&buf, Grid(A4, 3, 4, 10), testItems(t, 13)))
+	assert.True(t, bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")))
+	assert.Equal(t, 2, bytes.Count(buf.Bytes(), []byte("/Type /Page ")))
+
+	assert.Error(t, PDF(&buf, Grid(A4, 3, 4, 10), nil))
+}
+
+func TestPNG(t *testing.T) {
+	pages, err := PNG(Grid(Letter, 2, 2, 12.7), testItems(t, 5), 50)
+	require.NoError(t, err)
+	require.Len(t, pages, 2)
+
+	img, err := png.Decode(bytes.NewReader(pages[0]))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 425, 550), img.Bounds())
+}
+
+func TestFit(t *testing.T) {
+	assert.Equal(t, "Short", fit("Short", 50))
+
+	long := fit("A caption that is far too long to fit in a small cell", 30)
+	assert.Contains(t, long, "...")
+	assert.True(t, len(long) < 50)
+}
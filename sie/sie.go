@@ -0,0 +1,279 @@
+// Package sie exports invoiced payments as SIE 4 verifications, the Swedish
+// accounting interchange format, so that the receivables can be imported
+// into a bookkeeping system.
+//
+// Each payment is booked as a verification debiting the receivables account
+// and crediting the sales account, and for invoices the VAT accounts of its
+// rates. Files are encoded as PC8 (code page 437), as the format requires.
+package sie
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/antonlindstrom/payqr/invoice"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Accounts are the accounts of the chart of accounts the verifications are
+// booked on.
+type Accounts struct {
+	Receivables int
+
+	// Sales and VAT are the sales and output VAT accounts by VAT rate in
+	// percent. Payments, which have no VAT, are booked on the sales
+	// account of rate 0.
+	Sales map[float64]int
+	VAT   map[float64]int
+}
+
+// BAS returns the accounts of the BAS chart of accounts for Swedish
+// receivables, sales and output VAT.
+func BAS() Accounts {
+	return Accounts{
+		Receivables: 1510,
+		Sales:       map[float64]int{25: 3001, 12: 3002, 6: 3003, 0: 3004},
+		VAT:         map[float64]int{25: 2611, 12: 2621, 6: 2631},
+	}
+}
+
+// accountNames are the names of the BAS accounts, written for the accounts
+// used.
+var accountNames = map[int]string{
+	1510: "Kundfordringar",
+	2611: "Utgående moms på försäljning inom Sverige, 25 %",
+	2621: "Utgående moms på försäljning inom Sverige, 12 %",
+	2631: "Utgående moms på försäljning inom Sverige, 6 %",
+	3001: "Försäljning inom Sverige, 25 % moms",
+	3002: "Försäljning inom Sverige, 12 % moms",
+	3003: "Försäljning inom Sverige, 6 % moms",
+	3004: "Försäljning inom Sverige, momsfri",
+}
+
+// Option is a modifier for the export.
+type Option func(*config)
+
+type config struct {
+	company     string
+	orgNumber   string
+	series      string
+	firstNumber int
+	date        time.Time
+	accounts    Accounts
+}
+
+// WithCompany sets the name and organization number of the company.
+func WithCompany(name, organizationNumber string) Option {
+	return func(c *config) {
+		c.company = name
+		c.orgNumber = organizationNumber
+	}
+}
+
+// WithSeries sets the verification series, default is A.
+func WithSeries(series string) Option {
+	return func(c *config) {
+		c.series = series
+	}
+}
+
+// WithFirstNumber sets the number of the first verification, default is 1.
+func WithFirstNumber(n int) Option {
+	return func(c *config) {
+		c.firstNumber = n
+	}
+}
+
+// WithDate sets the date the file is generated, and the date of
+// verifications of payments, default is today.
+func WithDate(date time.Time) Option {
+	return func(c *config) {
+		c.date = date
+	}
+}
+
+// WithAccounts sets the accounts, default is BAS.
+func WithAccounts(accounts Accounts) Option {
+	return func(c *config) {
+		c.accounts = accounts
+	}
+}
+
+type transaction struct {
+	account int
+	amount  float64
+}
+
+type verification struct {
+	date         time.Time
+	text         string
+	transactions []transaction
+}
+
+// WritePayments writes a verification for each payment, booking its amount
+// as receivable on the date of the file. Payments must be in SEK.
+func WritePayments(w io.Writer, payments []*payqr.Payment, options ...Option) error {
+	cfg := newConfig(options)
+
+	sales, ok := cfg.accounts.Sales[0]
+	if !ok {
+		return errors.New("sie: no sales account for VAT rate 0")
+	}
+
+	vers := make([]verification, 0, len(payments))
+	for _, p := range payments {
+		if err := checkCurrency(p.Currency); err != nil {
+			return err
+		}
+
+		text := "Faktura " + p.Reference
+		if p.Reference == "" {
+			text = "Faktura " + p.AccountName
+		}
+		vers = append(vers, verification{
+			date: cfg.date,
+			text: text,
+			transactions: []transaction{
+				{account: cfg.accounts.Receivables, amount: p.DueAmount},
+				{account: sales, amount: -p.DueAmount},
+			},
+		})
+	}
+
+	return write(w, cfg, vers)
+}
+
+// WriteInvoices writes a verification for each invoice on its date, booking
+// the total as receivable and the net and VAT amounts of each rate on the
+// sales and VAT accounts of the rate. Invoices must be in SEK.
+func WriteInvoices(w io.Writer, invoices []*invoice.Invoice, options ...Option) error {
+	cfg := newConfig(options)
+
+	vers := make([]verification, 0, len(invoices))
+	for _, inv := range invoices {
+		if err := inv.Validate(); err != nil {
+			return err
+		}
+		if err := checkCurrency(inv.Currency); err != nil {
+			return err
+		}
+		if err := checkCurrency(inv.Payment.Currency); err != nil {
+			return err
+		}
+
+		_, _, total := inv.Totals()
+		v := verification{
+			date:         inv.Date,
+			text:         strings.TrimSpace("Faktura " + inv.Number + " " + inv.Buyer.Name),
+			transactions: []transaction{{account: cfg.accounts.Receivables, amount: total}},
+		}
+
+		for _, a := range inv.VAT() {
+			sales, ok := cfg.accounts.Sales[a.Rate]
+			if !ok {
+				return fmt.Errorf("sie: invoice %s: no sales account for VAT rate %g", inv.Number, a.Rate)
+			}
+			v.transactions = append(v.transactions, transaction{account: sales, amount: -a.Net})
+
+			if a.VAT == 0 {
+				continue
+			}
+			vat, ok := cfg.accounts.VAT[a.Rate]
+			if !ok {
+				return fmt.Errorf("sie: invoice %s: no VAT account for VAT rate %g", inv.Number, a.Rate)
+			}
+			v.transactions = append(v.transactions, transaction{account: vat, amount: -a.VAT})
+		}
+
+		vers = append(vers, v)
+	}
+
+	return write(w, cfg, vers)
+}
+
+func newConfig(options []Option) *config {
+	cfg := &config{
+		series:      "A",
+		firstNumber: 1,
+		date:        time.Now(),
+		accounts:    BAS(),
+	}
+	for _, opt := range options {
+		opt(cfg)
+	}
+
+	return cfg
+}
+
+func checkCurrency(currency string) error {
+	if currency != "" && currency != "SEK" {
+		return fmt.Errorf("sie: currency must be SEK, got %s", currency)
+	}
+
+	return nil
+}
+
+// write writes the file with the verifications, numbered from the first
+// number.
+func write(w io.Writer, cfg *config, vers []verification) error {
+	if cfg.company == "" {
+		return errors.New("sie: company name is required")
+	}
+
+	enc := encoding.ReplaceUnsupported(charmap.CodePage437.NewEncoder())
+	bw := bufio.NewWriter(enc.Writer(w))
+
+	line := func(label string, fields ...string) {
+		bw.WriteString(strings.Join(append([]string{label}, fields...), " ") + "\r\n")
+	}
+
+	line("#FLAGGA", "0")
+	line("#FORMAT", "PC8")
+	line("#SIETYP", "4")
+	line("#PROGRAM", quote("payqr"), "1.0")
+	line("#GEN", cfg.date.Format("20060102"))
+	line("#FNAMN", quote(cfg.company))
+	if cfg.orgNumber != "" {
+		line("#ORGNR", cfg.orgNumber)
+	}
+
+	used := map[int]bool{}
+	for _, v := range vers {
+		for _, t := range v.transactions {
+			used[t.account] = true
+		}
+	}
+	accounts := make([]int, 0, len(used))
+	for account := range used {
+		accounts = append(accounts, account)
+	}
+	sort.Ints(accounts)
+	for _, account := range accounts {
+		if name, ok := accountNames[account]; ok {
+			line("#KONTO", fmt.Sprint(account), quote(name))
+		}
+	}
+
+	for i, v := range vers {
+		line("#VER", quote(cfg.series), fmt.Sprint(cfg.firstNumber+i), v.date.Format("20060102"), quote(v.text))
+		line("{")
+		for _, t := range v.transactions {
+			line("", "#TRANS", fmt.Sprint(t.account), "{}", fmt.Sprintf("%.2f", t.amount))
+		}
+		line("}")
+	}
+
+	return bw.Flush()
+}
+
+// quote returns the field in quotes, escaping quotes and backslashes.
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
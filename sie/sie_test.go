@@ -0,0 +1,114 @@
+package sie
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/antonlindstrom/payqr/invoice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+)
+
+func decode(t *testing.T, b []byte) string {
+	s, err := charmap.CodePage437.NewDecoder().Bytes(b)
+	require.NoError(t, err)
+
+	return string(s)
+}
+
+func TestWritePayments(t *testing.T) {
+	date := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+	payments := []*payqr.Payment{
+		payqr.New("5402-9681", "Test AB", "", "52250047157", 1250, date),
+		payqr.New("5402-9681", "Test AB", "", "", 99.5, date),
+	}
+
+	var buf bytes.Buffer
+	err := WritePayments(&buf, payments, WithCompany("Företaget AB", "555555-5555"), WithDate(date), WithSeries("B"), WithFirstNumber(10))
+	require.NoError(t, err)
+
+	assert.Equal(t, "#FLAGGA 0\r\n"+
+		"#FORMAT PC8\r\n"+
+		"#SIETYP 4\r\n"+
+		"#PROGRAM \"payqr\" 1.0\r\n"+
+		"#GEN 20220806\r\n"+
+		"#FNAMN \"Företaget AB\"\r\n"+
+		"#ORGNR 555555-5555\r\n"+
+		"#KONTO 1510 \"Kundfordringar\"\r\n"+
+		"#KONTO 3004 \"Försäljning inom Sverige, momsfri\"\r\n"+
+		"#VER \"B\" 10 20220806 \"Faktura 52250047157\"\r\n"+
+		"{\r\n"+
+		" #TRANS 1510 {} 1250.00\r\n"+
+		" #TRANS 3004 {} -1250.00\r\n"+
+		"}\r\n"+
+		"#VER \"B\" 11 20220806 \"Faktura Test AB\"\r\n"+
+		"{\r\n"+
+		" #TRANS 1510 {} 99.50\r\n"+
+		" #TRANS 3004 {} -99.50\r\n"+
+		"}\r\n", decode(t, buf.Bytes()))
+	assert.Contains(t, buf.String(), "F\x94retaget", "encoded as code page 437")
+}
+
+func TestWriteInvoices(t *testing.T) {
+	date := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+	inv := &invoice.Invoice{
+		Number: "1001",
+		Date:   date,
+		Seller: invoice.Party{Name: "Test AB"},
+		Buyer:  invoice.Party{Name: "Kund \"Ett\" AB"},
+		Items: []invoice.Item{
+			{Description: "Konsult", Quantity: 10, UnitPrice: 100, VATRate: 25},
+			{Description: "Bok", Quantity: 1, UnitPrice: 50, VATRate: 6},
+		},
+		Payment: payqr.New("5402-9681", "Test AB", "", "52250047157", 1303, date.AddDate(0, 0, 30)),
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteInvoices(&buf, []*invoice.Invoice{inv}, WithCompany("Test AB", ""), WithDate(date)))
+
+	s := decode(t, buf.Bytes())
+	assert.NotContains(t, s, "#ORGNR")
+	assert.Contains(t, s, "#VER \"A\" 1 20220806 \"Faktura 1001 Kund \\\"Ett\\\" AB\"\r\n"+
+		"{\r\n"+
+		" #TRANS 1510 {} 1303.00\r\n"+
+		" #TRANS 3003 {} -50.00\r\n"+
+		" #TRANS 2631 {} -3.00\r\n"+
+		" #TRANS 3001 {} -1000.00\r\n"+
+		" #TRANS 2611 {} -250.00\r\n"+
+		"}\r\n")
+}
+
+func TestWriteError(t *testing.T) {
+	date := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		have    []*payqr.Payment
+		options []Option
+	}{
+		{
+			name: "No company",
+			have: []*payqr.Payment{payqr.New("5402-9681", "Test AB", "", "52250047157", 1250, date)},
+		},
+		{
+			name:    "Currency",
+			have:    []*payqr.Payment{payqr.New("5402-9681", "Test AB", "", "52250047157", 1250, date, payqr.WithCurrency("EUR"))},
+			options: []Option{WithCompany("Test AB", "")},
+		},
+		{
+			name:    "No sales account",
+			have:    []*payqr.Payment{payqr.New("5402-9681", "Test AB", "", "52250047157", 1250, date)},
+			options: []Option{WithCompany("Test AB", ""), WithAccounts(Accounts{Receivables: 1510})},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.Error(t, WritePayments(&buf, test.have, test.options...))
+		})
+	}
+}
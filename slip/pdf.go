@@ -0,0 +1,30 @@
+//go:build !payqr_payloadonly
+
+package slip
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/antonlindstrom/payqr/internal/canvas"
+	"github.com/antonlindstrom/payqr/internal/pdf"
+)
+
+// PDF renders the slip of the payment at the bottom of an A4 page, below a
+// dashed line to cut or tear along, leaving the top of the page blank for
+// the invoice.
+func PDF(w io.Writer, p *payqr.Payment, options ...Option) error {
+	doc := pdf.New()
+	page := doc.AddPage(pdf.A4Width, pdf.A4Height)
+
+	if err := draw(canvas.NewPDF(page, 0, payqr.A4Height-Height), p, options...); err != nil {
+		return err
+	}
+	top := Height * pdf.MM
+	page.Raw(fmt.Sprintf("[3 2] 0 d 0.5 w 0 %.2f m %.2f %.2f l S [] 0 d\n", top, page.Width, top))
+
+	_, err := doc.WriteTo(w)
+
+	return err
+}
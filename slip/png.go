@@ -0,0 +1,35 @@
+//go:build !payqr_payloadonly
+
+package slip
+
+import (
+	"image"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/antonlindstrom/payqr/internal/canvas"
+)
+
+// Image renders the slip of the payment as an image at the resolution in
+// dots per inch.
+func Image(p *payqr.Payment, dpi int, options ...Option) (*image.Gray, error) {
+	c := canvas.NewImage(Width, Height, dpi)
+	if err := draw(c, p, options...); err != nil {
+		return nil, err
+	}
+	if err := c.Err(); err != nil {
+		return nil, err
+	}
+
+	return c.Gray, nil
+}
+
+// PNG renders the slip of the payment as a PNG image at the resolution in
+// dots per inch.
+func PNG(p *payqr.Payment, dpi int, options ...Option) ([]byte, error) {
+	c := canvas.NewImage(Width, Height, dpi)
+	if err := draw(c, p, options...); err != nil {
+		return nil, err
+	}
+
+	return c.PNG()
+}
@@ -0,0 +1,193 @@
+//go:build !payqr_payloadonly
+
+// Package slip renders Swedish Bankgiro and Plusgiro payment slips
+// (inbetalningskort), with the payee, amount, OCR number, the machine
+// readable OCR line and the payment QR code, as PDF or PNG.
+package slip
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/antonlindstrom/payqr/internal/canvas"
+	"github.com/antonlindstrom/payqr/internal/pdf"
+)
+
+func init() {
+	payqr.RegisterCapability(payqr.CapabilityPDF)
+}
+
+// Size of the slip in millimeters. The slip is the bottom part of an A4 page,
+// four inches high.
+const (
+	Width  = payqr.A4Width
+	Height = 101.6
+)
+
+// Type codes printed last on the OCR line.
+const (
+	TypeBankgiro = "41"
+	TypePlusgiro = "14"
+)
+
+// Option is a modifier for the slip.
+type Option func(*config)
+
+type config struct {
+	payer []string
+}
+
+// WithPayer sets the name and address of the payer, printed in the payer box.
+func WithPayer(lines ...string) Option {
+	return func(c *config) {
+		c.payer = lines
+	}
+}
+
+// OCRLine returns the machine readable line of the slip for the payment, with
+// the OCR number, the amount in kronor and öre with its check digit, the
+// account number and the type code:
+//
+//	# 52250047157 #    1250 00   0 > 54029681#41#
+//
+// The payment must be a Bankgiro or Plusgiro payment in SEK, with a valid
+// OCR number as reference. Without an amount the amount fields are left
+// blank for the payer to fill in.
+func OCRLine(p *payqr.Payment) (string, error) {
+	typ, err := typeCode(p)
+	if err != nil {
+		return "", err
+	}
+	if p.Currency != "" && !strings.EqualFold(p.Currency, "SEK") {
+		return "", fmt.Errorf("slip: currency must be SEK, got %s", p.Currency)
+	}
+	if err := payqr.ValidateAccount("SE", p.PaymentType, p.AccountNumber); err != nil {
+		return "", fmt.Errorf("slip: %w", err)
+	}
+	if p.Reference == "" {
+		return "", errors.New("slip: reference (OCR number) is required")
+	}
+	if err := payqr.ReferenceOCR.Validate(p.Reference); err != nil {
+		return "", fmt.Errorf("slip: %w", err)
+	}
+	if p.DueAmount < 0 || p.DueAmount >= 1e8 {
+		return "", fmt.Errorf("slip: amount %.2f is out of range", p.DueAmount)
+	}
+
+	account := strings.NewReplacer("-", "", " ", "").Replace(p.AccountNumber)
+
+	amount := strings.Repeat(" ", 15)
+	if p.DueAmount > 0 {
+		öre := int64(p.DueAmount*100 + 0.5)
+		kronor, rest := öre/100, öre%100
+		check, err := payqr.ReferenceOCR.Generate(fmt.Sprintf("%d%02d", kronor, rest))
+		if err != nil {
+			return "", err
+		}
+		amount = fmt.Sprintf("%8d %02d   %s", kronor, rest, check[len(check)-1:])
+	}
+
+	return fmt.Sprintf("# %s #%s > %s#%s#", p.Reference, amount, account, typ), nil
+}
+
+func typeCode(p *payqr.Payment) (string, error) {
+	switch p.PaymentType {
+	case payqr.PaymentTypeBG:
+		return TypeBankgiro, nil
+	case payqr.PaymentTypePG:
+		return TypePlusgiro, nil
+	}
+
+	return "", fmt.Errorf("slip: payment type must be %s or %s, got %s", payqr.PaymentTypeBG, payqr.PaymentTypePG, p.PaymentType)
+}
+
+// Layout of the slip, in millimeters from its top left corner.
+const (
+	marginLeft  = 10
+	marginRight = 200
+	qrLeft      = 170
+	qrTop       = 12
+	qrSize      = 30
+
+	// The OCR line is printed in 12 point Courier, ten characters per inch,
+	// with its baseline two lines of 1/6 inch above the bottom edge.
+	ocrTop  = Height - 2*25.4/6
+	ocrSize = 12
+)
+
+// draw draws the slip of the payment on the canvas, with the origin at the
+// top left corner of the slip.
+func draw(c canvas.Canvas, p *payqr.Payment, options ...Option) error {
+	cfg := &config{}
+	for _, opt := range options {
+		opt(cfg)
+	}
+
+	line, err := OCRLine(p)
+	if err != nil {
+		return err
+	}
+
+	q, err := p.QR()
+	if err != nil {
+		return err
+	}
+	q.DisableBorder = true
+
+	giro, label := "Bankgiro", "Till bankgironummer"
+	if p.PaymentType == payqr.PaymentTypePG {
+		giro, label = "PlusGiro", "Till plusgironummer"
+	}
+
+	c.Text(marginLeft, 8, pdf.HelveticaBold, 10, "INBETALNING/GIRERING AVI")
+	c.TextRight(marginRight, 8, pdf.HelveticaBold, 12, giro)
+
+	box := func(left, top, width, height float64, label string, lines ...string) {
+		c.Rect(left, top, width, height)
+		c.Text(left+1.5, top+3, pdf.Helvetica, 6, label)
+		for i, l := range lines {
+			c.Text(left+1.5, top+8+float64(i)*4.5, pdf.Helvetica, 10, l)
+		}
+	}
+
+	payee := []string{p.AccountName}
+	if p.Address != "" {
+		payee = append(payee, strings.Split(p.Address, "\n")...)
+	}
+	box(marginLeft, 12, 95, 20, "Betalningsmottagare", payee...)
+	box(marginLeft, 35, 95, 24, "Inbetalare", cfg.payer...)
+
+	box(110, 12, 55, 10, label, p.AccountNumber)
+	box(110, 25, 55, 10, "OCR-nummer", p.Reference)
+	box(110, 38, 55, 10, "Förfallodag", formatDate(p.DueDate))
+
+	var kronor, öre string
+	if p.DueAmount > 0 {
+		amount := fmt.Sprintf("%.2f", p.DueAmount)
+		kronor, öre = amount[:len(amount)-3], amount[len(amount)-2:]
+	}
+	box(110, 51, 40, 10, "Kronor")
+	c.TextRight(148.5, 59, pdf.HelveticaBold, 11, kronor)
+	box(150, 51, 15, 10, "Öre")
+	c.TextRight(163.5, 59, pdf.HelveticaBold, 11, öre)
+
+	c.Modules(qrLeft, qrTop, qrSize, q.Bitmap())
+	c.Text(qrLeft, qrTop+qrSize+4, pdf.Helvetica, 7, "Skanna och betala")
+
+	// The area around the OCR line is read by machine and is kept blank.
+	c.Text(marginLeft, ocrTop-10, pdf.Helvetica, 6, "VAR GOD GÖR INGA ÄNDRINGAR I DEN MASKINLÄSBARA RADEN")
+	c.TextRight(marginRight, ocrTop, pdf.Courier, ocrSize, line)
+
+	return nil
+}
+
+// formatDate formats the yyyymmdd due date as yyyy-mm-dd.
+func formatDate(date string) string {
+	if len(date) != 8 {
+		return date
+	}
+
+	return date[:4] + "-" + date[4:6] + "-" + date[6:]
+}
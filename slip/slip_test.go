@@ -0,0 +1,95 @@
+//go:build !payqr_payloadonly
+
+package slip
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var due = time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+
+func TestOCRLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		have    *payqr.Payment
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Bankgiro",
+			have: payqr.New("5402-9681", "Test AB", "", "52250047157", 1250, due),
+			want: "# 52250047157 #    1250 00   0 > 54029681#41#",
+		},
+		{
+			name: "Plusgiro",
+			have: payqr.New("90 01 22-3", "Test AB", "", "52250047157", 99.5, due, payqr.WithPaymentType(payqr.PaymentTypePG)),
+			want: "# 52250047157 #      99 50   7 > 9001223#14#",
+		},
+		{
+			name: "Open amount",
+			have: payqr.New("5402-9681", "Test AB", "", "52250047157", 0, due),
+			want: "# 52250047157 #                > 54029681#41#",
+		},
+		{
+			name:    "Invalid OCR number",
+			have:    payqr.New("5402-9681", "Test AB", "", "52250047158", 1250, due),
+			wantErr: true,
+		},
+		{
+			name:    "Without OCR number",
+			have:    payqr.New("5402-9681", "Test AB", "", "", 1250, due),
+			wantErr: true,
+		},
+		{
+			name:    "IBAN",
+			have:    payqr.New("SE4550000000058398257466", "Test AB", "", "52250047157", 1250, due, payqr.WithPaymentType(payqr.PaymentTypeIBAN)),
+			wantErr: true,
+		},
+		{
+			name:    "Currency",
+			have:    payqr.New("5402-9681", "Test AB", "", "52250047157", 1250, due, payqr.WithCurrency("EUR")),
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			line, err := OCRLine(test.have)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, line)
+		})
+	}
+}
+
+func TestPDF(t *testing.T) {
+	p := payqr.New("5402-9681", "Test AB", "", "52250047157", 1250, due, payqr.WithAddress("Storgatan 1\n111 11 Stockholm"))
+
+	var buf bytes.Buffer
+	require.NoError(t, PDF(&buf, p, WithPayer("Kund AB", "Lillgatan 2")))
+	assert.True(t, bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")))
+
+	assert.Error(t, PDF(&buf, payqr.New("5402-9681", "Test AB", "", "", 1250, due)))
+}
+
+func TestPNG(t *testing.T) {
+	p := payqr.New("5402-9681", "Test AB", "", "52250047157", 1250, due)
+
+	b, err := PNG(p, 150, WithPayer("Kund AB"))
+	require.NoError(t, err)
+
+	img, err := png.Decode(bytes.NewReader(b))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 1240, 600), img.Bounds())
+}
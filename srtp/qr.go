@@ -0,0 +1,15 @@
+//go:build !payqr_payloadonly
+
+package srtp
+
+import "github.com/skip2/go-qrcode"
+
+// QR returns a QR code of the link to the request.
+func QR(baseURL string, r *Request) (*qrcode.QRCode, error) {
+	link, err := Link(baseURL, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return qrcode.New(link, qrcode.Medium)
+}
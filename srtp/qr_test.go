@@ -0,0 +1,25 @@
+//go:build !payqr_payloadonly
+
+package srtp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQR(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+	r, err := NewRequest(payqr.New("DE89370400440532013000", "Test GmbH", "", "RF18539007547034", 12.3, due, payqr.WithPaymentType(payqr.PaymentTypeIBAN)), WithCreationTime(time.Date(2022, time.August, 1, 12, 0, 0, 0, time.UTC)), WithDebtor("Max Mustermann", "DE02120300000000202051"))
+	require.NoError(t, err)
+
+	q, err := QR("https://rtp.example.com/r/", r)
+	require.NoError(t, err)
+	assert.Equal(t, "https://rtp.example.com/r/RF18539007547034", q.Content)
+
+	_, err = QR("http://rtp.example.com", r)
+	assert.Error(t, err)
+}
@@ -0,0 +1,284 @@
+// Package srtp builds SEPA Request-to-Pay (SRTP) requests from payments, as
+// ISO 20022 pain.013 messages sent through an RTP service provider, and QR
+// codes of the links that open a request in the payer's banking app.
+package srtp
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/antonlindstrom/payqr/epc"
+)
+
+// Namespace is the XML namespace of the pain.013 version used by the SRTP
+// scheme.
+const Namespace = "urn:iso:std:iso:20022:tech:xsd:pain.013.001.07"
+
+// Option is a modifier for the request.
+type Option func(*Request)
+
+// WithDebtor sets the name and, if known, the IBAN of the payer. The name is
+// required.
+func WithDebtor(name, iban string) Option {
+	return func(r *Request) {
+		r.DebtorName = name
+		r.DebtorIBAN = strings.ToUpper(strings.ReplaceAll(iban, " ", ""))
+	}
+}
+
+// WithExpiry sets when the request expires, default is the end of the due
+// date.
+func WithExpiry(t time.Time) Option {
+	return func(r *Request) {
+		r.Expiry = t
+	}
+}
+
+// WithCreationTime sets the creation time of the message, default is now.
+func WithCreationTime(t time.Time) Option {
+	return func(r *Request) {
+		r.Created = t
+	}
+}
+
+// Request is a request to pay.
+type Request struct {
+	ID            string // Message and end to end ID, at most 35 characters.
+	Created       time.Time
+	Expiry        time.Time
+	ExecutionDate time.Time
+	Amount        float64 // In EUR.
+	CreditorName  string
+	CreditorIBAN  string
+	CreditorBIC   string
+	DebtorName    string
+	DebtorIBAN    string
+	Reference     string // RF creditor reference, or text.
+}
+
+// NewRequest creates a request to pay the payment, which must be to an IBAN
+// in EUR. The reference of the payment identifies the request.
+func NewRequest(p *payqr.Payment, options ...Option) (*Request, error) {
+	if p.PaymentType != payqr.PaymentTypeIBAN {
+		return nil, fmt.Errorf("srtp: payment type must be %s, got %s", payqr.PaymentTypeIBAN, p.PaymentType)
+	}
+	if p.Currency != "" && !strings.EqualFold(p.Currency, epc.Currency) {
+		return nil, fmt.Errorf("srtp: currency must be %s, got %s", epc.Currency, p.Currency)
+	}
+
+	due, err := time.Parse("20060102", p.DueDate)
+	if err != nil {
+		return nil, fmt.Errorf("srtp: invalid due date %q", p.DueDate)
+	}
+
+	r := &Request{
+		ID:            strings.ReplaceAll(p.Reference, " ", ""),
+		Created:       time.Now(),
+		Expiry:        due.Add(24*time.Hour - time.Second),
+		ExecutionDate: due,
+		Amount:        p.DueAmount,
+		CreditorName:  p.AccountName,
+		CreditorIBAN:  strings.ToUpper(strings.ReplaceAll(p.AccountNumber, " ", "")),
+		CreditorBIC:   p.BankCode,
+		Reference:     p.Reference,
+	}
+	for _, opt := range options {
+		opt(r)
+	}
+
+	return r, r.Validate()
+}
+
+// Validate checks the required fields and their lengths.
+func (r *Request) Validate() error {
+	if r.ID == "" || len(r.ID) > 35 {
+		return fmt.Errorf("srtp: ID must be 1 to 35 characters, got %q", r.ID)
+	}
+	if err := payqr.ValidateAccount(r.CreditorIBAN[:min(2, len(r.CreditorIBAN))], payqr.PaymentTypeIBAN, r.CreditorIBAN); err != nil {
+		return fmt.Errorf("srtp: %w", err)
+	}
+	if r.DebtorIBAN != "" {
+		if err := payqr.ValidateAccount(r.DebtorIBAN[:min(2, len(r.DebtorIBAN))], payqr.PaymentTypeIBAN, r.DebtorIBAN); err != nil {
+			return fmt.Errorf("srtp: debtor: %w", err)
+		}
+	}
+	if r.Amount < 0.01 || r.Amount > 999999999.99 {
+		return fmt.Errorf("srtp: amount %.2f must be between 0.01 and 999999999.99", r.Amount)
+	}
+	if r.CreditorName == "" || r.DebtorName == "" {
+		return fmt.Errorf("srtp: creditor and debtor names are required")
+	}
+	if utf8.RuneCountInString(r.CreditorName) > 70 || utf8.RuneCountInString(r.DebtorName) > 70 {
+		return fmt.Errorf("srtp: names must be at most 70 characters")
+	}
+	if utf8.RuneCountInString(r.Reference) > 140 {
+		return fmt.Errorf("srtp: reference is longer than 140 characters")
+	}
+	if !r.Expiry.After(r.Created) {
+		return fmt.Errorf("srtp: request expires before it is created")
+	}
+
+	return nil
+}
+
+// MarshalXML encodes the request as a pain.013 document.
+func (r *Request) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	tx := creditTransfer{
+		EndToEndID:  r.ID,
+		ServiceCode: "SEPA",
+		Amount:      amount{Currency: epc.Currency, Value: fmt.Sprintf("%.2f", r.Amount)},
+		ChargeBear:  "SLEV",
+		Creditor:    party{Name: r.CreditorName},
+		CreditorAcc: account{IBAN: r.CreditorIBAN},
+	}
+	tx.CreditorAgent.BIC = r.CreditorBIC
+	if tx.CreditorAgent.BIC == "" {
+		tx.CreditorAgent.Other = &other{ID: "NOTPROVIDED"}
+	}
+	if epc.IsCreditorReference(r.Reference) {
+		tx.Remittance.Structured = &structured{Code: "SCOR", Ref: strings.ToUpper(strings.ReplaceAll(r.Reference, " ", ""))}
+	} else if r.Reference != "" {
+		tx.Remittance.Unstructured = r.Reference
+	}
+
+	info := paymentInformation{
+		ID:            r.ID,
+		Method:        "TRF",
+		ExecutionDate: date{r.ExecutionDate.Format("2006-01-02")},
+		Expiry:        dateTime{r.Expiry.UTC().Format("2006-01-02T15:04:05Z")},
+		Debtor:        party{Name: r.DebtorName},
+		Transfer:      tx,
+	}
+	info.DebtorAgent.Other = &other{ID: "NOTPROVIDED"}
+	if r.DebtorIBAN != "" {
+		info.DebtorAcc = &account{IBAN: r.DebtorIBAN}
+	}
+
+	doc := document{
+		Namespace: Namespace,
+		Header: groupHeader{
+			MessageID:    r.ID,
+			Created:      r.Created.UTC().Format("2006-01-02T15:04:05Z"),
+			Transactions: 1,
+			Initiator:    party{Name: r.CreditorName},
+		},
+		Payment: info,
+	}
+
+	start.Name = xml.Name{Local: "Document"}
+
+	return e.EncodeElement(doc, start)
+}
+
+// Encode returns the pain.013 XML of the request.
+func (r *Request) Encode() ([]byte, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	b, err := xml.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), b...), nil
+}
+
+// Link returns the link to the request at the RTP service provider, the
+// escaped request ID appended to the base URL.
+func Link(baseURL string, r *Request) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return "", fmt.Errorf("srtp: %q is not an https URL", baseURL)
+	}
+
+	return strings.TrimSuffix(baseURL, "/") + "/" + url.PathEscape(r.ID), nil
+}
+
+type document struct {
+	Namespace string             `xml:"xmlns,attr"`
+	Header    groupHeader        `xml:"CdtrPmtActvtnReq>GrpHdr"`
+	Payment   paymentInformation `xml:"CdtrPmtActvtnReq>PmtInf"`
+}
+
+type groupHeader struct {
+	MessageID    string `xml:"MsgId"`
+	Created      string `xml:"CreDtTm"`
+	Transactions int    `xml:"NbOfTxs"`
+	Initiator    party  `xml:"InitgPty"`
+}
+
+type paymentInformation struct {
+	ID            string         `xml:"PmtInfId"`
+	Method        string         `xml:"PmtMtd"`
+	ExecutionDate date           `xml:"ReqdExctnDt"`
+	Expiry        dateTime       `xml:"XpryDt"`
+	Debtor        party          `xml:"Dbtr"`
+	DebtorAcc     *account       `xml:"DbtrAcct,omitempty"`
+	DebtorAgent   agent          `xml:"DbtrAgt"`
+	Transfer      creditTransfer `xml:"CdtTrfTx"`
+}
+
+type creditTransfer struct {
+	EndToEndID    string     `xml:"PmtId>EndToEndId"`
+	ServiceCode   string     `xml:"PmtTpInf>SvcLvl>Cd"`
+	Amount        amount     `xml:"Amt>InstdAmt"`
+	ChargeBear    string     `xml:"ChrgBr"`
+	CreditorAgent agent      `xml:"CdtrAgt"`
+	Creditor      party      `xml:"Cdtr"`
+	CreditorAcc   account    `xml:"CdtrAcct"`
+	Remittance    remittance `xml:"RmtInf"`
+}
+
+type date struct {
+	Date string `xml:"Dt"`
+}
+
+type dateTime struct {
+	DateTime string `xml:"DtTm"`
+}
+
+type party struct {
+	Name string `xml:"Nm"`
+}
+
+type account struct {
+	IBAN string `xml:"Id>IBAN"`
+}
+
+type agent struct {
+	BIC   string `xml:"FinInstnId>BICFI,omitempty"`
+	Other *other `xml:"FinInstnId>Othr,omitempty"`
+}
+
+type other struct {
+	ID string `xml:"Id"`
+}
+
+type amount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type remittance struct {
+	Unstructured string      `xml:"Ustrd,omitempty"`
+	Structured   *structured `xml:"Strd,omitempty"`
+}
+
+type structured struct {
+	Code string `xml:"CdtrRefInf>Tp>CdOrPrtry>Cd"`
+	Ref  string `xml:"CdtrRefInf>Ref"`
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
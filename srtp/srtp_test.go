@@ -0,0 +1,98 @@
+package srtp
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRequest(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+	created := WithCreationTime(time.Date(2022, time.August, 1, 12, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name    string
+		have    *payqr.Payment
+		options []Option
+		wantErr bool
+	}{
+		{
+			name:    "Valid",
+			have:    payqr.New("DE89 3704 0044 0532 0130 00", "Test GmbH", "", "RF18539007547034", 12.3, due, payqr.WithPaymentType(payqr.PaymentTypeIBAN)),
+			options: []Option{created, WithDebtor("Max Mustermann", "")},
+		},
+		{
+			name:    "Missing debtor",
+			have:    payqr.New("DE89370400440532013000", "Test GmbH", "", "RF18539007547034", 12.3, due, payqr.WithPaymentType(payqr.PaymentTypeIBAN)),
+			options: []Option{created},
+			wantErr: true,
+		},
+		{
+			name:    "Bankgiro",
+			have:    payqr.New("5402-9681", "Test AB", "", "123", 12.3, due),
+			options: []Option{created, WithDebtor("Max Mustermann", "")},
+			wantErr: true,
+		},
+		{
+			name:    "Expired",
+			have:    payqr.New("DE89370400440532013000", "Test GmbH", "", "RF18539007547034", 12.3, due, payqr.WithPaymentType(payqr.PaymentTypeIBAN)),
+			options: []Option{created, WithDebtor("Max Mustermann", ""), WithExpiry(due.AddDate(0, 0, -10))},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewRequest(test.have, test.options...)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+	p := payqr.New("DE89370400440532013000", "Test GmbH", "", "RF18539007547034", 12.3, due, payqr.WithPaymentType(payqr.PaymentTypeIBAN))
+
+	r, err := NewRequest(p, WithCreationTime(time.Date(2022, time.August, 1, 12, 0, 0, 0, time.UTC)), WithDebtor("Max Mustermann", "DE02120300000000202051"))
+	require.NoError(t, err)
+
+	b, err := r.Encode()
+	require.NoError(t, err)
+
+	var doc struct {
+		XMLName xml.Name `xml:"Document"`
+		Amount  struct {
+			Currency string `xml:"Ccy,attr"`
+			Value    string `xml:",chardata"`
+		} `xml:"CdtrPmtActvtnReq>PmtInf>CdtTrfTx>Amt>InstdAmt"`
+		Ref     string `xml:"CdtrPmtActvtnReq>PmtInf>CdtTrfTx>RmtInf>Strd>CdtrRefInf>Ref"`
+		IBAN    string `xml:"CdtrPmtActvtnReq>PmtInf>CdtTrfTx>CdtrAcct>Id>IBAN"`
+		Debtor  string `xml:"CdtrPmtActvtnReq>PmtInf>DbtrAcct>Id>IBAN"`
+		Expiry  string `xml:"CdtrPmtActvtnReq>PmtInf>XpryDt>DtTm"`
+		Created string `xml:"CdtrPmtActvtnReq>GrpHdr>CreDtTm"`
+	}
+	require.NoError(t, xml.Unmarshal(b, &doc))
+	assert.Equal(t, Namespace, doc.XMLName.Space)
+	assert.Equal(t, "EUR", doc.Amount.Currency)
+	assert.Equal(t, "12.30", doc.Amount.Value)
+	assert.Equal(t, "RF18539007547034", doc.Ref)
+	assert.Equal(t, "DE89370400440532013000", doc.IBAN)
+	assert.Equal(t, "DE02120300000000202051", doc.Debtor)
+	assert.Equal(t, "2022-08-06T23:59:59Z", doc.Expiry)
+	assert.Equal(t, "2022-08-01T12:00:00Z", doc.Created)
+
+	link, err := Link("https://rtp.example.com/r/", r)
+	require.NoError(t, err)
+	assert.Equal(t, "https://rtp.example.com/r/RF18539007547034", link)
+
+	_, err = Link("http://rtp.example.com", r)
+	assert.Error(t, err)
+}
@@ -0,0 +1,19 @@
+//go:build !payqr_payloadonly
+
+package stripe
+
+import "github.com/skip2/go-qrcode"
+
+// QR returns a QR code of the payment link.
+func QR(link string) (*qrcode.QRCode, error) {
+	if err := ValidateLink(link); err != nil {
+		return nil, err
+	}
+
+	return qrcode.New(link, qrcode.Medium)
+}
+
+// QR returns a QR code of the payment link.
+func (l *PaymentLink) QR() (*qrcode.QRCode, error) {
+	return QR(l.URL)
+}
@@ -0,0 +1,44 @@
+//go:build !payqr_payloadonly
+
+package stripe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQR(t *testing.T) {
+	tests := []struct {
+		name    string
+		have    string
+		wantErr bool
+	}{
+		{name: "Payment link", have: "https://buy.stripe.com/test_eVa5kZ0Up8Jm2QM5kk"},
+		{name: "Custom domain", have: "https://pay.example.com/b/eVa5kZ0Up8Jm2QM5kk"},
+		{name: "Not https", have: "http://buy.stripe.com/test_eVa5kZ0Up8Jm2QM5kk", wantErr: true},
+		{name: "No path", have: "https://buy.stripe.com/", wantErr: true},
+		{name: "Not a link", have: "eVa5kZ0Up8Jm2QM5kk", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q, err := QR(test.have)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.have, q.Content)
+		})
+	}
+}
+
+func TestPaymentLinkQR(t *testing.T) {
+	link := &PaymentLink{ID: "plink_1", URL: "https://buy.stripe.com/test_eVa5kZ0Up8Jm2QM5kk"}
+
+	q, err := link.QR()
+	require.NoError(t, err)
+	assert.Equal(t, link.URL, q.Content)
+}
@@ -0,0 +1,184 @@
+// Package stripe renders Stripe Payment Links as QR codes, so that card
+// payments can be offered next to giro and Swish codes on the same invoice.
+// Links can be given or created through the Stripe API for an amount.
+//
+// The codes are *qrcode.QRCode like the other codes of payqr, and are styled
+// and rendered the same way, see payqr.Render.
+package stripe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// APIURL is the base URL of the Stripe API.
+const APIURL = "https://api.stripe.com/v1"
+
+// LinkHost is the host of Stripe Payment Links.
+const LinkHost = "buy.stripe.com"
+
+// ValidateLink checks that the link is an https link to a Stripe Payment
+// Link, on LinkHost or on a custom domain.
+func ValidateLink(link string) error {
+	u, err := url.Parse(link)
+	if err != nil || u.Scheme != "https" || u.Host == "" || strings.Trim(u.Path, "/") == "" {
+		return fmt.Errorf("stripe: invalid payment link %q", link)
+	}
+
+	return nil
+}
+
+// zeroDecimal are the currencies without minor units in the Stripe API.
+var zeroDecimal = map[string]bool{
+	"BIF": true, "CLP": true, "DJF": true, "GNF": true, "JPY": true, "KMF": true,
+	"KRW": true, "MGA": true, "PYG": true, "RWF": true, "UGX": true, "VND": true,
+	"VUV": true, "XAF": true, "XOF": true, "XPF": true,
+}
+
+// unitAmount returns the amount in the smallest unit of the currency.
+func unitAmount(amount float64, currency string) int64 {
+	if zeroDecimal[strings.ToUpper(currency)] {
+		return int64(math.Round(amount))
+	}
+
+	return int64(math.Round(amount * 100))
+}
+
+// Client is a client for the Stripe API, used to create payment links.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+	secretKey  string
+}
+
+// Option is a modifier for the Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithBaseURL sets the base URL of the API. Default is APIURL.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// New creates a client authenticating with the secret or restricted API key.
+// Restricted keys need write access to prices, products and payment links.
+func New(secretKey string, options ...Option) *Client {
+	c := &Client{
+		httpClient: http.DefaultClient,
+		baseURL:    APIURL,
+		secretKey:  secretKey,
+	}
+
+	for _, opt := range options {
+		opt(c)
+	}
+
+	return c
+}
+
+// Error is returned when the Stripe API responds with an unexpected status.
+type Error struct {
+	StatusCode int
+	Type       string
+	Code       string
+	Message    string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("stripe: unexpected status %d", e.StatusCode)
+	}
+
+	return fmt.Sprintf("stripe: status %d: %s", e.StatusCode, e.Message)
+}
+
+// post posts the form and decodes the JSON response into v.
+func (c *Client) post(ctx context.Context, path string, form url.Values, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.secretKey, "")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var body struct {
+			Error struct {
+				Type    string `json:"type"`
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+		_ = json.Unmarshal(b, &body)
+
+		return &Error{StatusCode: resp.StatusCode, Type: body.Error.Type, Code: body.Error.Code, Message: body.Error.Message}
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// PaymentLink is a Stripe Payment Link.
+type PaymentLink struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+}
+
+// CreatePaymentLink creates a payment link for one item of the amount in the
+// currency, named by the description. A price is created for the item, as
+// payment links are made of prices.
+func (c *Client) CreatePaymentLink(ctx context.Context, amount float64, currency, description string) (*PaymentLink, error) {
+	if amount <= 0 {
+		return nil, fmt.Errorf("stripe: amount %.2f must be positive", amount)
+	}
+	if len(currency) != 3 {
+		return nil, fmt.Errorf("stripe: invalid currency %q", currency)
+	}
+	if description == "" {
+		return nil, fmt.Errorf("stripe: description is required")
+	}
+
+	var price struct {
+		ID string `json:"id"`
+	}
+	err := c.post(ctx, "/prices", url.Values{
+		"currency":           {strings.ToLower(currency)},
+		"unit_amount":        {strconv.FormatInt(unitAmount(amount, currency), 10)},
+		"product_data[name]": {description},
+	}, &price)
+	if err != nil {
+		return nil, err
+	}
+
+	var link PaymentLink
+	err = c.post(ctx, "/payment_links", url.Values{
+		"line_items[0][price]":    {price.ID},
+		"line_items[0][quantity]": {"1"},
+	}, &link)
+	if err != nil {
+		return nil, err
+	}
+
+	return &link, nil
+}
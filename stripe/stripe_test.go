@@ -0,0 +1,64 @@
+package stripe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePaymentLink(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key, _, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "sk_test_123", key)
+		require.NoError(t, r.ParseForm())
+
+		switch r.URL.Path {
+		case "/prices":
+			assert.Equal(t, "sek", r.PostForm.Get("currency"))
+			assert.Equal(t, "125050", r.PostForm.Get("unit_amount"))
+			assert.Equal(t, "Faktura 1001", r.PostForm.Get("product_data[name]"))
+			fmt.Fprint(w, `{"id":"price_1","object":"price"}`)
+		case "/payment_links":
+			assert.Equal(t, "price_1", r.PostForm.Get("line_items[0][price]"))
+			assert.Equal(t, "1", r.PostForm.Get("line_items[0][quantity]"))
+			fmt.Fprint(w, `{"id":"plink_1","object":"payment_link","url":"https://buy.stripe.com/test_eVa5kZ0Up8Jm2QM5kk"}`)
+		}
+	}))
+	defer srv.Close()
+
+	c := New("sk_test_123", WithBaseURL(srv.URL), WithHTTPClient(srv.Client()))
+
+	link, err := c.CreatePaymentLink(context.Background(), 1250.5, "SEK", "Faktura 1001")
+	require.NoError(t, err)
+	assert.Equal(t, &PaymentLink{ID: "plink_1", URL: "https://buy.stripe.com/test_eVa5kZ0Up8Jm2QM5kk"}, link)
+}
+
+func TestCreatePaymentLinkError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error":{"type":"invalid_request_error","message":"Invalid API Key provided: sk_test_***123"}}`)
+	}))
+	defer srv.Close()
+
+	c := New("sk_test_123", WithBaseURL(srv.URL))
+
+	_, err := c.CreatePaymentLink(context.Background(), 100, "SEK", "Faktura 1001")
+	var apiErr *Error
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "invalid_request_error", apiErr.Type)
+
+	_, err = c.CreatePaymentLink(context.Background(), 0, "SEK", "Faktura 1001")
+	assert.Error(t, err)
+}
+
+func TestUnitAmount(t *testing.T) {
+	assert.Equal(t, int64(125050), unitAmount(1250.5, "SEK"))
+	assert.Equal(t, int64(1251), unitAmount(1250.5, "jpy"))
+}
@@ -0,0 +1,53 @@
+//go:build !payqr_payloadonly
+
+package payqr
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// SVG renders the QR code as an SVG image of size pixels. Each dark module is
+// drawn as a path segment so the image scales without artifacts.
+func SVG(q *qrcode.QRCode, size int) []byte {
+	bitmap := q.Bitmap()
+	n := len(bitmap)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, size, size, n, n)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#fff"/><path fill="#000" d="`, n, n)
+
+	for y, row := range bitmap {
+		for x := 0; x < len(row); x++ {
+			if !row[x] {
+				continue
+			}
+
+			// Join horizontal runs of dark modules into one rectangle.
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			fmt.Fprintf(&buf, "M%d %dh%dv1h-%dz", start, y, x-start, x-start)
+		}
+	}
+
+	buf.WriteString(`"/></svg>`)
+
+	return buf.Bytes()
+}
+
+// Render renders the QR code as an image in the format. PNG and SVG are
+// supported.
+func Render(q *qrcode.QRCode, format Format, size int) ([]byte, error) {
+	switch format {
+	case FormatPNG:
+		return q.PNG(size)
+	case FormatSVG:
+		return SVG(q, size), nil
+	}
+
+	return nil, fmt.Errorf("rendering %s is not supported", format)
+}
@@ -0,0 +1,31 @@
+//go:build !payqr_payloadonly
+
+package payqr
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/skip2/go-qrcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSVG(t *testing.T) {
+	q, err := qrcode.New("C1231111111;50.00;My message;0", qrcode.High)
+	require.NoError(t, err)
+
+	b, err := Render(q, FormatSVG, 256)
+	require.NoError(t, err)
+
+	var svg struct {
+		Width   string `xml:"width,attr"`
+		ViewBox string `xml:"viewBox,attr"`
+	}
+	require.NoError(t, xml.Unmarshal(b, &svg))
+	assert.Equal(t, "256", svg.Width)
+	assert.Equal(t, "0 0 37 37", svg.ViewBox)
+
+	_, err = Render(q, FormatEPS, 256)
+	assert.Error(t, err)
+}
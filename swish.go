@@ -1,13 +1,39 @@
 package payqr
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// SwishMinAmount is the lowest amount Swish accepts for a payment.
+	SwishMinAmount = 1.00
+
+	// SwishMaxAmount is the highest amount the Swish format can carry. Banks
+	// and merchants usually have lower caps, see WithAmountLimits.
+	SwishMaxAmount = 999999999999.99
 
-	"github.com/skip2/go-qrcode"
+	// SwishCurrency is the only currency supported by Swish.
+	SwishCurrency = "SEK"
 )
 
+// SwishPayment is a payment made with Swish. Unlike Payment it only carries
+// the fields used by Swish.
+type SwishPayment struct {
+	Payee   string  // Phone or Swish number of the receiving party.
+	Amount  float64 // Amount in SEK.
+	Message string
+
+	editableFields SwishEditableField
+	openAmount     bool
+	minAmount      float64
+	maxAmount      float64
+}
+
 // SwishOption defines options for Swish QRs.
-type SwishOption func(*Payment)
+type SwishOption func(*SwishPayment)
 
 // SwishEditableField is a way to set/lock different editable fields.
 type SwishEditableField byte
@@ -22,21 +48,164 @@ const (
 // opens the QR code in their app. Setting fields as editable will
 // automatically lock the other ones.
 func WithEditableFields(fields SwishEditableField) SwishOption {
-	return func(p *Payment) {
-		p.swishEditableFields = byte(fields)
+	return func(s *SwishPayment) {
+		s.editableFields = fields
 	}
 }
 
-// swishEncode encodes a payment to the format used by Swish in QR codes.
-func (d *Payment) swishEncode(phoneNumber string, options ...SwishOption) string {
+// WithAmountLimits sets the allowed amount range for Swish payments, for
+// merchants with their own caps. The limits are clamped to SwishMinAmount and
+// SwishMaxAmount, a zero max is no cap of the merchant. Negative limits and a
+// min above the max are rejected by Validate.
+func WithAmountLimits(min, max float64) SwishOption {
+	return func(s *SwishPayment) {
+		s.minAmount = min
+		s.maxAmount = max
+	}
+}
+
+// WithOpenAmount omits the amount from the payload, leaving it for the user
+// to fill in, e.g. for donations or tips. The amount is made editable.
+func WithOpenAmount() SwishOption {
+	return func(s *SwishPayment) {
+		s.Amount = 0
+		s.openAmount = true
+		s.editableFields |= SwishAmountEditable
+	}
+}
+
+// NewSwish creates a new Swish payment to the payee.
+func NewSwish(payee string, amount float64, message string, options ...SwishOption) *SwishPayment {
+	s := &SwishPayment{
+		Payee:   payee,
+		Amount:  amount,
+		Message: message,
+	}
+
 	for _, opt := range options {
-		opt(d)
+		opt(s)
+	}
+
+	return s
+}
+
+// ParseSwish parses a Swish QR payload as produced by Encode.
+func ParseSwish(payload string) (*SwishPayment, error) {
+	if !strings.HasPrefix(payload, "C") {
+		return nil, errors.New("swish payload must start with C")
+	}
+
+	parts := strings.Split(payload[1:], ";")
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("swish payload has %d fields, expected 4", len(parts))
 	}
 
-	return fmt.Sprintf("C%s;%.2f;%s;%d", phoneNumber, d.DueAmount, d.Reference, int(d.swishEditableFields))
+	s := &SwishPayment{
+		Payee:   parts[0],
+		Message: parts[2],
+	}
+
+	if parts[1] == "" {
+		s.openAmount = true
+	} else {
+		amount, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("swish amount %q is not a number", parts[1])
+		}
+		s.Amount = amount
+	}
+
+	editable, err := strconv.ParseUint(parts[3], 10, 8)
+	if err != nil {
+		return nil, fmt.Errorf("swish editable fields %q is not a number", parts[3])
+	}
+	s.editableFields = SwishEditableField(editable)
+
+	return s, nil
+}
+
+// EditableFields returns the fields the user may edit in their app.
+func (s *SwishPayment) EditableFields() SwishEditableField {
+	return s.editableFields
 }
 
-// SwishQR returns a QR code that can be used for Swish payments.
-func (d *Payment) SwishQR(phoneNumber string, options ...SwishOption) (*qrcode.QRCode, error) {
-	return qrcode.New(d.swishEncode(phoneNumber, options...), qrcode.High)
+// amountLimits returns the effective amount limits of the payment.
+func (s *SwishPayment) amountLimits() (float64, float64) {
+	min, max := s.minAmount, s.maxAmount
+	if min < SwishMinAmount {
+		min = SwishMinAmount
+	}
+	if max == 0 || max > SwishMaxAmount {
+		max = SwishMaxAmount
+	}
+
+	return min, max
+}
+
+// Validate checks that the amount is within the limits and that the editable
+// fields are consistent with the values present.
+func (s *SwishPayment) Validate() error {
+	if s.minAmount < 0 || s.maxAmount < 0 {
+		return fmt.Errorf("swish amount limits %.2f to %.2f are negative", s.minAmount, s.maxAmount)
+	}
+	if s.maxAmount != 0 && s.minAmount > s.maxAmount {
+		return fmt.Errorf("swish amount limit minimum %.2f is above the maximum %.2f", s.minAmount, s.maxAmount)
+	}
+
+	editable := s.editableFields
+	if editable&^(SwishPhoneEditable|SwishAmountEditable|SwishMessageEditable) != 0 {
+		return fmt.Errorf("swish editable fields %#b contains unknown fields", editable)
+	}
+	if s.Payee == "" && editable&SwishPhoneEditable == 0 {
+		return errors.New("swish phone number is locked but empty")
+	}
+	if s.Message == "" && editable&SwishMessageEditable == 0 {
+		return errors.New("swish message is locked but empty")
+	}
+	if s.openAmount && s.Amount != 0 {
+		return errors.New("swish amount is open but set")
+	}
+	if s.Amount == 0 {
+		if editable&SwishAmountEditable == 0 {
+			return errors.New("swish amount is locked but zero")
+		}
+
+		// The amount is left for the user to fill in.
+		return nil
+	}
+
+	min, max := s.amountLimits()
+	if s.Amount < min {
+		return fmt.Errorf("swish amount %.2f is below the minimum %.2f", s.Amount, min)
+	}
+	if s.Amount > max {
+		return fmt.Errorf("swish amount %.2f is above the maximum %.2f", s.Amount, max)
+	}
+
+	return nil
+}
+
+// Encode encodes the payment to the format used by Swish in QR codes.
+func (s *SwishPayment) Encode() string {
+	if s.openAmount {
+		return fmt.Sprintf("C%s;;%s;%d", s.Payee, s.Message, int(s.editableFields))
+	}
+
+	return fmt.Sprintf("C%s;%.2f;%s;%d", s.Payee, s.Amount, s.Message, int(s.editableFields))
+}
+
+// OpenAmount reports whether the amount is omitted from the payload.
+func (s *SwishPayment) OpenAmount() bool {
+	return s.openAmount
+}
+
+// Swish returns a Swish payment to the phone number with the amount and
+// reference of the payment as message.
+func (d *Payment) Swish(phoneNumber string, options ...SwishOption) *SwishPayment {
+	return NewSwish(phoneNumber, d.DueAmount, d.Reference, options...)
+}
+
+// swishEncode encodes a payment to the format used by Swish in QR codes.
+func (d *Payment) swishEncode(phoneNumber string, options ...SwishOption) string {
+	return d.Swish(phoneNumber, options...).Encode()
 }
@@ -0,0 +1,30 @@
+//go:build !payqr_payloadonly
+
+package payqr
+
+import (
+	"context"
+	"fmt"
+)
+
+// SwishRenderer renders Swish payments as images in the format.
+type SwishRenderer interface {
+	RenderSwish(ctx context.Context, s *SwishPayment, format Format, size int) ([]byte, error)
+}
+
+// LocalSwishRenderer renders Swish payments locally with go-qrcode.
+type LocalSwishRenderer struct{}
+
+// RenderSwish renders the payment as a square image of size pixels.
+func (LocalSwishRenderer) RenderSwish(_ context.Context, s *SwishPayment, format Format, size int) ([]byte, error) {
+	if format != FormatPNG {
+		return nil, fmt.Errorf("format %s is not supported by the local renderer", format)
+	}
+
+	q, err := s.QR()
+	if err != nil {
+		return nil, err
+	}
+
+	return q.PNG(size)
+}
@@ -0,0 +1,132 @@
+//go:build !payqr_payloadonly
+
+package payqr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSwish(t *testing.T) {
+	tests := []struct {
+		name    string
+		have    *SwishPayment
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Locked payment",
+			have: NewSwish("1231111111", 50, "My message"),
+			want: "C1231111111;50.00;My message;0",
+		},
+		{
+			name: "Editable message",
+			have: NewSwish("1231111111", 100.5, "", WithEditableFields(SwishMessageEditable)),
+			want: "C1231111111;100.50;;4",
+		},
+		{
+			name: "Open amount",
+			have: NewSwish("1231111111", 0, "Tips", WithOpenAmount()),
+			want: "C1231111111;;Tips;2",
+		},
+		{
+			name: "Open amount with other editable fields",
+			have: NewSwish("1231111111", 0, "", WithEditableFields(SwishMessageEditable), WithOpenAmount()),
+			want: "C1231111111;;;6",
+		},
+		{
+			name:    "Above merchant maximum",
+			have:    NewSwish("1231111111", 2000, "My message", WithAmountLimits(1, 1000)),
+			want:    "C1231111111;2000.00;My message;0",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, test.have.Encode())
+
+			_, err := test.have.QR()
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestSwishAmountLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		min, max float64
+		wantErr  string
+	}{
+		{name: "Within limits", min: 10, max: 1000},
+		{name: "Without maximum", min: 10},
+		{name: "Negative minimum", min: -1, max: 1000, wantErr: "swish amount limits -1.00 to 1000.00 are negative"},
+		{name: "Negative maximum", min: 1, max: -1, wantErr: "swish amount limits 1.00 to -1.00 are negative"},
+		{name: "Minimum above maximum", min: 1000, max: 10, wantErr: "swish amount limit minimum 1000.00 is above the maximum 10.00"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := NewSwish("1231111111", 0, "Tips", WithOpenAmount(), WithAmountLimits(test.min, test.max)).Validate()
+			if test.wantErr != "" {
+				assert.EqualError(t, err, test.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestParseSwish(t *testing.T) {
+	tests := []struct {
+		name    string
+		have    string
+		want    *SwishPayment
+		wantErr bool
+	}{
+		{
+			name: "Round trip",
+			have: "C1231111111;50.00;My message;6",
+			want: NewSwish("1231111111", 50, "My message", WithEditableFields(SwishAmountEditable|SwishMessageEditable)),
+		},
+		{
+			name: "Open amount",
+			have: "C1231111111;;Tips;2",
+			want: NewSwish("1231111111", 0, "Tips", WithOpenAmount()),
+		},
+		{
+			name:    "Missing prefix",
+			have:    "1231111111;50.00;My message;6",
+			wantErr: true,
+		},
+		{
+			name:    "Missing fields",
+			have:    "C1231111111;50.00",
+			wantErr: true,
+		},
+		{
+			name:    "Bad amount",
+			have:    "C1231111111;fifty;My message;0",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseSwish(test.have)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+			assert.Equal(t, test.have, got.Encode())
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package swishapi
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// Amount is an amount as returned by the Swish API, which may be either a
+// JSON number or a string.
+type Amount float64
+
+// UnmarshalJSON accepts both numbers and strings.
+func (a *Amount) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		var f float64
+		if err := json.Unmarshal(b, &f); err != nil {
+			return err
+		}
+		*a = Amount(f)
+		return nil
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return err
+	}
+	*a = Amount(f)
+
+	return nil
+}
@@ -0,0 +1,211 @@
+package swishapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// Callback is the body Swish posts to the callback URL when a payment
+// request or refund changes status.
+type Callback struct {
+	ID                       string `json:"id"`
+	PayeePaymentReference    string `json:"payeePaymentReference,omitempty"`
+	PayerPaymentReference    string `json:"payerPaymentReference,omitempty"`
+	PaymentReference         string `json:"paymentReference,omitempty"`
+	OriginalPaymentReference string `json:"originalPaymentReference,omitempty"` // Only set for refunds.
+	CallbackURL              string `json:"callbackUrl,omitempty"`
+	PayerAlias               string `json:"payerAlias,omitempty"`
+	PayeeAlias               string `json:"payeeAlias,omitempty"`
+	Amount                   Amount `json:"amount"`
+	Currency                 string `json:"currency,omitempty"`
+	Message                  string `json:"message,omitempty"`
+	Status                   Status `json:"status"`
+	DateCreated              string `json:"dateCreated,omitempty"`
+	DatePaid                 string `json:"datePaid,omitempty"`
+	ErrorCode                string `json:"errorCode,omitempty"`
+	ErrorMessage             string `json:"errorMessage,omitempty"`
+}
+
+// IsRefund reports whether the callback is for a refund.
+func (c *Callback) IsRefund() bool {
+	return c.OriginalPaymentReference != ""
+}
+
+// Reference returns the merchant reference of the callback, the payee
+// payment reference for payment requests and the payer payment reference for
+// refunds. The references are optional, without one the ID of the payment
+// request or refund is returned, so that callbacks are kept apart.
+func (c *Callback) Reference() string {
+	ref := c.PayeePaymentReference
+	if c.IsRefund() {
+		ref = c.PayerPaymentReference
+	}
+	if ref == "" {
+		return c.ID
+	}
+
+	return ref
+}
+
+// ErrNotFound is returned by a StatusStore when there is no status for a
+// reference.
+var ErrNotFound = errors.New("swish api: not found")
+
+// StatusStore keeps track of the latest callback per merchant reference.
+type StatusStore interface {
+	SetStatus(ctx context.Context, reference string, callback *Callback) error
+	Status(ctx context.Context, reference string) (*Callback, error)
+}
+
+// MemoryStatusStore is a StatusStore keeping the statuses in memory.
+type MemoryStatusStore struct {
+	mu       sync.RWMutex
+	statuses map[string]*Callback
+}
+
+// NewMemoryStatusStore creates an empty MemoryStatusStore.
+func NewMemoryStatusStore() *MemoryStatusStore {
+	return &MemoryStatusStore{statuses: make(map[string]*Callback)}
+}
+
+// SetStatus stores the callback for the reference.
+func (s *MemoryStatusStore) SetStatus(_ context.Context, reference string, callback *Callback) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.statuses[reference] = callback
+
+	return nil
+}
+
+// Status returns the latest callback for the reference.
+func (s *MemoryStatusStore) Status(_ context.Context, reference string) (*Callback, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	cb, ok := s.statuses[reference]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return cb, nil
+}
+
+// CallbackVerifier verifies that a callback is genuine.
+type CallbackVerifier func(ctx context.Context, callback *Callback) error
+
+// VerifyWithClient returns a CallbackVerifier that fetches the payment
+// request or refund from the Swish API and checks that it matches the
+// callback: the status, amount, merchant reference and alias. Since callbacks
+// are not signed, this is the recommended way to make sure they originate
+// from Swish. The verified callback has the references of the fetched
+// object, so that it is stored under the verified reference.
+func VerifyWithClient(c *Client) CallbackVerifier {
+	return func(ctx context.Context, cb *Callback) error {
+		var (
+			status    Status
+			amount    Amount
+			reference string
+			alias     string
+			gotRef    string
+			gotAlias  string
+		)
+
+		if cb.IsRefund() {
+			r, err := c.GetRefund(ctx, cb.ID)
+			if err != nil {
+				return err
+			}
+			status, amount = r.Status, r.Amount
+			reference, alias = r.PayerPaymentReference, r.PayerAlias
+			gotRef, gotAlias = cb.PayerPaymentReference, cb.PayerAlias
+			if r.OriginalPaymentReference != cb.OriginalPaymentReference {
+				return fmt.Errorf("swish api: callback for %s does not match, got original payment %q, expected %q", cb.ID, cb.OriginalPaymentReference, r.OriginalPaymentReference)
+			}
+		} else {
+			p, err := c.GetPaymentRequest(ctx, cb.ID)
+			if err != nil {
+				return err
+			}
+			status, amount = p.Status, p.Amount
+			reference, alias = p.PayeePaymentReference, p.PayeeAlias
+			gotRef, gotAlias = cb.PayeePaymentReference, cb.PayeeAlias
+		}
+
+		if status != cb.Status || amount != cb.Amount {
+			return fmt.Errorf("swish api: callback for %s does not match, got %s %.2f, expected %s %.2f", cb.ID, cb.Status, cb.Amount, status, amount)
+		}
+		if gotRef != reference {
+			return fmt.Errorf("swish api: callback for %s does not match, got reference %q, expected %q", cb.ID, gotRef, reference)
+		}
+		if gotAlias != alias {
+			return fmt.Errorf("swish api: callback for %s does not match, got alias %q, expected %q", cb.ID, gotAlias, alias)
+		}
+
+		return nil
+	}
+}
+
+// CallbackOption is a modifier for the callback handler.
+type CallbackOption func(*callbackHandler)
+
+// WithCallbackVerifier sets the verifier of received callbacks.
+func WithCallbackVerifier(verifier CallbackVerifier) CallbackOption {
+	return func(h *callbackHandler) {
+		h.verify = verifier
+	}
+}
+
+type callbackHandler struct {
+	store  StatusStore
+	verify CallbackVerifier
+}
+
+// NewCallbackHandler returns a handler for Swish payment and refund
+// callbacks, storing the status of each in the store. Without a verifier,
+// callbacks are trusted as is, see VerifyWithClient.
+func NewCallbackHandler(store StatusStore, options ...CallbackOption) http.Handler {
+	h := &callbackHandler{store: store}
+
+	for _, opt := range options {
+		opt(h)
+	}
+
+	return h
+}
+
+func (h *callbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var cb Callback
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16)).Decode(&cb); err != nil {
+		http.Error(w, "invalid callback", http.StatusBadRequest)
+		return
+	}
+	if cb.ID == "" || cb.Status == "" {
+		http.Error(w, "callback is missing id or status", http.StatusBadRequest)
+		return
+	}
+
+	if h.verify != nil {
+		if err := h.verify(r.Context(), &cb); err != nil {
+			http.Error(w, "callback could not be verified", http.StatusForbidden)
+			return
+		}
+	}
+
+	if err := h.store.SetStatus(r.Context(), cb.Reference(), &cb); err != nil {
+		http.Error(w, "could not store status", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,139 @@
+package swishapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallbackHandler(t *testing.T) {
+	references := map[string]string{
+		"AB23D7406ECE4542A80152D909EF9F6B": "1001",
+		"CD23D7406ECE4542A80152D909EF9F6B": "",
+	}
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/api/v1/paymentrequests/")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":                    id,
+			"payeePaymentReference": references[id],
+			"payeeAlias":            "1231181189",
+			"amount":                100,
+			"status":                StatusPaid,
+		})
+	}))
+	defer api.Close()
+
+	store := NewMemoryStatusStore()
+	h := NewCallbackHandler(store, WithCallbackVerifier(VerifyWithClient(New("1231181189", WithBaseURL(api.URL)))))
+
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		wantStatus int
+	}{
+		{
+			name:       "Paid payment request",
+			method:     http.MethodPost,
+			body:       `{"id":"AB23D7406ECE4542A80152D909EF9F6B","payeePaymentReference":"1001","payeeAlias":"1231181189","amount":"100.00","status":"PAID"}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Paid without reference",
+			method:     http.MethodPost,
+			body:       `{"id":"CD23D7406ECE4542A80152D909EF9F6B","payeeAlias":"1231181189","amount":"100.00","status":"PAID"}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Forged amount",
+			method:     http.MethodPost,
+			body:       `{"id":"AB23D7406ECE4542A80152D909EF9F6B","payeePaymentReference":"1001","payeeAlias":"1231181189","amount":"1.00","status":"PAID"}`,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "Forged reference",
+			method:     http.MethodPost,
+			body:       `{"id":"AB23D7406ECE4542A80152D909EF9F6B","payeePaymentReference":"1002","payeeAlias":"1231181189","amount":"100.00","status":"PAID"}`,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "Forged alias",
+			method:     http.MethodPost,
+			body:       `{"id":"AB23D7406ECE4542A80152D909EF9F6B","payeePaymentReference":"1001","payeeAlias":"1234567890","amount":"100.00","status":"PAID"}`,
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "Invalid body",
+			method:     http.MethodPost,
+			body:       `{`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Wrong method",
+			method:     http.MethodGet,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(test.method, "/callback", strings.NewReader(test.body)))
+			assert.Equal(t, test.wantStatus, rec.Code)
+		})
+	}
+
+	cb, err := store.Status(context.Background(), "1001")
+	require.NoError(t, err)
+	assert.Equal(t, StatusPaid, cb.Status)
+	assert.Equal(t, Amount(100), cb.Amount)
+
+	_, err = store.Status(context.Background(), "1002")
+	assert.Equal(t, ErrNotFound, err)
+
+	cb, err = store.Status(context.Background(), "CD23D7406ECE4542A80152D909EF9F6B")
+	require.NoError(t, err)
+	assert.Equal(t, StatusPaid, cb.Status)
+
+	_, err = store.Status(context.Background(), "")
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestCallbackReference(t *testing.T) {
+	tests := []struct {
+		name string
+		have Callback
+		want string
+	}{
+		{name: "Payment request", have: Callback{ID: "AB23", PayeePaymentReference: "1001", PayerPaymentReference: "x"}, want: "1001"},
+		{name: "Payment request without reference", have: Callback{ID: "AB23"}, want: "AB23"},
+		{name: "Refund", have: Callback{ID: "CD45", OriginalPaymentReference: "EF67", PayerPaymentReference: "R1001"}, want: "R1001"},
+		{name: "Refund without reference", have: Callback{ID: "CD45", OriginalPaymentReference: "EF67"}, want: "CD45"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, test.have.Reference())
+		})
+	}
+}
+
+func TestVerifyWithClientEscapesID(t *testing.T) {
+	var paths []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.EscapedPath())
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer api.Close()
+
+	verify := VerifyWithClient(New("1231181189", WithBaseURL(api.URL)))
+	assert.Error(t, verify(context.Background(), &Callback{ID: "../refunds/AB23?x=1"}))
+	assert.Error(t, verify(context.Background(), &Callback{ID: "../paymentrequests/AB23", OriginalPaymentReference: "CD45"}))
+	assert.Equal(t, []string{"/api/v1/paymentrequests/..%2Frefunds%2FAB23%3Fx=1", "/api/v1/refunds/..%2Fpaymentrequests%2FAB23"}, paths)
+}
@@ -0,0 +1,139 @@
+package swishapi
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// CertOption is a modifier for loading certificates.
+type CertOption func(*certConfig)
+
+type certConfig struct {
+	rootCAs    []byte
+	warnWithin time.Duration
+	warn       func(cert *x509.Certificate, remaining time.Duration)
+	now        func() time.Time
+}
+
+// WithRootCAs sets the PEM encoded root CA chain used to verify the Swish
+// server. If not set, the system roots are used.
+func WithRootCAs(pem []byte) CertOption {
+	return func(c *certConfig) {
+		c.rootCAs = pem
+	}
+}
+
+// WithExpiryWarning calls warn for each certificate in the client chain that
+// expires within the duration.
+func WithExpiryWarning(within time.Duration, warn func(cert *x509.Certificate, remaining time.Duration)) CertOption {
+	return func(c *certConfig) {
+		c.warnWithin = within
+		c.warn = warn
+	}
+}
+
+// TLSConfigFromPEM builds a TLS config for the Swish API from a PEM encoded
+// client certificate chain and private key. An error is returned if any
+// certificate in the chain has expired.
+func TLSConfigFromPEM(certPEM, keyPEM []byte, options ...CertOption) (*tls.Config, error) {
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("swish api: could not load certificate: %w", err)
+	}
+
+	return tlsConfig(cert, options...)
+}
+
+// TLSConfigFromPKCS12 builds a TLS config for the Swish API from PKCS#12
+// (.p12) data, as issued by the Swish certificate management. An error is
+// returned if any certificate in the chain has expired.
+func TLSConfigFromPKCS12(data []byte, password string, options ...CertOption) (*tls.Config, error) {
+	key, leaf, chain, err := pkcs12.DecodeChain(data, password)
+	if err != nil {
+		return nil, fmt.Errorf("swish api: could not decode pkcs12: %w", err)
+	}
+
+	cert := tls.Certificate{
+		Certificate: [][]byte{leaf.Raw},
+		PrivateKey:  key,
+		Leaf:        leaf,
+	}
+	for _, c := range chain {
+		cert.Certificate = append(cert.Certificate, c.Raw)
+	}
+
+	return tlsConfig(cert, options...)
+}
+
+func tlsConfig(cert tls.Certificate, options ...CertOption) (*tls.Config, error) {
+	c := &certConfig{now: time.Now}
+	for _, opt := range options {
+		opt(c)
+	}
+
+	if err := c.checkExpiry(cert); err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if c.rootCAs != nil {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(c.rootCAs) {
+			return nil, errors.New("swish api: no certificates found in root CAs")
+		}
+		config.RootCAs = pool
+	}
+
+	return config, nil
+}
+
+func (c *certConfig) checkExpiry(cert tls.Certificate) error {
+	now := c.now()
+
+	for _, der := range cert.Certificate {
+		x, err := x509.ParseCertificate(der)
+		if err != nil {
+			return fmt.Errorf("swish api: could not parse certificate: %w", err)
+		}
+
+		if now.After(x.NotAfter) {
+			return fmt.Errorf("swish api: certificate %q expired %s", x.Subject.CommonName, x.NotAfter.Format(time.RFC3339))
+		}
+		if now.Before(x.NotBefore) {
+			return fmt.Errorf("swish api: certificate %q is not valid until %s", x.Subject.CommonName, x.NotBefore.Format(time.RFC3339))
+		}
+
+		if remaining := x.NotAfter.Sub(now); c.warn != nil && remaining < c.warnWithin {
+			c.warn(x, remaining)
+		}
+	}
+
+	return nil
+}
+
+// CertificateSerialNumber returns the serial number, as hex, of the first
+// certificate in the PEM data. It is used to identify the signing certificate
+// for payouts.
+func CertificateSerialNumber(certPEM []byte) (string, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return "", errors.New("swish api: no certificate found")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%X", cert.SerialNumber), nil
+}
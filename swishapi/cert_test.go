@@ -0,0 +1,83 @@
+package swishapi
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+func testCertificate(t *testing.T, notAfter time.Time) (*ecdsa.PrivateKey, *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(0x4512b3eb),
+		Subject:      pkix.Name{CommonName: "1231181189"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return key, cert
+}
+
+func TestTLSConfigFromPEM(t *testing.T) {
+	key, cert := testCertificate(t, time.Now().Add(10*24*time.Hour))
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	var warned []string
+	config, err := TLSConfigFromPEM(certPEM, keyPEM, WithRootCAs(certPEM), WithExpiryWarning(30*24*time.Hour, func(cert *x509.Certificate, _ time.Duration) {
+		warned = append(warned, cert.Subject.CommonName)
+	}))
+	require.NoError(t, err)
+	assert.Len(t, config.Certificates, 1)
+	assert.NotNil(t, config.RootCAs)
+	assert.Equal(t, []string{"1231181189"}, warned)
+
+	serial, err := CertificateSerialNumber(certPEM)
+	require.NoError(t, err)
+	assert.Equal(t, "4512B3EB", serial)
+}
+
+func TestTLSConfigFromPKCS12(t *testing.T) {
+	key, cert := testCertificate(t, time.Now().Add(365*24*time.Hour))
+
+	data, err := pkcs12.Encode(rand.Reader, key, cert, nil, "swish")
+	require.NoError(t, err)
+
+	config, err := TLSConfigFromPKCS12(data, "swish")
+	require.NoError(t, err)
+	assert.Equal(t, cert, config.Certificates[0].Leaf)
+
+	_, err = TLSConfigFromPKCS12(data, "wrong")
+	assert.Error(t, err)
+}
+
+func TestTLSConfigExpired(t *testing.T) {
+	key, cert := testCertificate(t, time.Now().Add(-time.Minute))
+
+	data, err := pkcs12.Encode(rand.Reader, key, cert, nil, "swish")
+	require.NoError(t, err)
+
+	_, err = TLSConfigFromPKCS12(data, "swish")
+	assert.Error(t, err)
+}
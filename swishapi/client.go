@@ -0,0 +1,258 @@
+// Package swishapi is a client for the Swish Commerce API, used to create
+// payment requests that can be paid by scanning a QR code.
+//
+// The API requires mutual TLS, the client certificate issued for the merchant
+// should be configured in the TLS config given with WithTLSConfig.
+package swishapi
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/antonlindstrom/payqr"
+)
+
+func init() {
+	payqr.RegisterCapability(payqr.CapabilitySwishAPI)
+}
+
+const (
+	// ProductionURL is the base URL of the Swish Commerce API.
+	ProductionURL = "https://cpc.getswish.net/swish-cpcapi"
+
+	// SimulatorURL is the base URL of the Merchant Swish Simulator (MSS),
+	// used for integration tests with the test certificates from Swish.
+	SimulatorURL = "https://mss.cpc.getswish.net/swish-cpcapi"
+)
+
+// Environment is the Swish environment the client talks to.
+type Environment string
+
+const (
+	Production Environment = "production"
+	Simulator  Environment = "simulator"
+)
+
+// ParseEnvironment returns the environment with the name, "production" or
+// "simulator" ("mss" is accepted as an alias).
+func ParseEnvironment(name string) (Environment, error) {
+	switch strings.ToLower(name) {
+	case "production", "prod":
+		return Production, nil
+	case "simulator", "mss", "test":
+		return Simulator, nil
+	}
+
+	return "", fmt.Errorf("swish api: unknown environment %q", name)
+}
+
+// BaseURL returns the base URL of the environment.
+func (e Environment) BaseURL() string {
+	if e == Simulator {
+		return SimulatorURL
+	}
+
+	return ProductionURL
+}
+
+// Client is a client for the Swish Commerce API.
+type Client struct {
+	httpClient   *http.Client
+	baseURL      string
+	payeeAlias   string
+	signer       crypto.Signer
+	signerSerial string
+
+	retry          retryConfig
+	breaker        *breaker
+	staticFallback bool
+}
+
+// Option is a modifier for the Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests. The client needs to
+// be configured for mutual TLS with the merchant certificate.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTLSConfig sets the TLS config, holding the merchant certificate, used
+// for requests.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(c *Client) {
+		c.httpClient = &http.Client{
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: config,
+			},
+		}
+	}
+}
+
+// WithEnvironment sets the environment of the client. Default is Production.
+func WithEnvironment(env Environment) Option {
+	return func(c *Client) {
+		c.baseURL = env.BaseURL()
+	}
+}
+
+// WithBaseURL sets the base URL of the API, overriding the environment.
+// Default is ProductionURL.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// New creates a client for the merchant with the Swish number payeeAlias.
+func New(payeeAlias string, options ...Option) *Client {
+	c := &Client{
+		httpClient: http.DefaultClient,
+		baseURL:    ProductionURL,
+		payeeAlias: payeeAlias,
+	}
+
+	for _, opt := range options {
+		opt(c)
+	}
+
+	return c
+}
+
+// ErrorDetail is an error as returned by the Swish API.
+type ErrorDetail struct {
+	ErrorCode             string `json:"errorCode"`
+	ErrorMessage          string `json:"errorMessage"`
+	AdditionalInformation string `json:"additionalInformation,omitempty"`
+}
+
+// Error is returned when the Swish API responds with an unexpected status.
+type Error struct {
+	StatusCode int
+	Details    []ErrorDetail
+}
+
+func (e *Error) Error() string {
+	if len(e.Details) == 0 {
+		return fmt.Sprintf("swish api: unexpected status %d", e.StatusCode)
+	}
+
+	msgs := make([]string, 0, len(e.Details))
+	for _, d := range e.Details {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", d.ErrorCode, d.ErrorMessage))
+	}
+
+	return fmt.Sprintf("swish api: status %d: %s", e.StatusCode, strings.Join(msgs, ", "))
+}
+
+// do sends a request with an optional JSON body and returns the response if
+// the status is one of the wanted ones. Idempotent requests are retried if
+// configured with WithRetry.
+func (c *Client) do(ctx context.Context, method, path string, body interface{}, wantStatus ...int) (*http.Response, error) {
+	var b []byte
+	if body != nil {
+		var err error
+		if b, err = json.Marshal(body); err != nil {
+			return nil, err
+		}
+	}
+
+	if !c.breaker.allow() {
+		return nil, ErrCircuitOpen
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := c.send(ctx, method, path, b)
+		if attempt >= c.retry.attempts || !retryable(method, resp, err) {
+			c.breaker.record(err != nil || resp.StatusCode >= 500)
+			if err != nil {
+				return nil, err
+			}
+			return checkStatus(resp, wantStatus)
+		}
+
+		if resp != nil {
+			resp.Body.Close()
+		}
+		if err := sleep(ctx, c.retry.backoff(attempt)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// send sends a single request.
+func (c *Client) send(ctx context.Context, method, path string, body []byte) (*http.Response, error) {
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, r)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return c.httpClient.Do(req)
+}
+
+// checkStatus returns the response if the status is one of the wanted ones,
+// otherwise an *Error with the details from the body.
+func checkStatus(resp *http.Response, wantStatus []int) (*http.Response, error) {
+	for _, status := range wantStatus {
+		if resp.StatusCode == status {
+			return resp, nil
+		}
+	}
+
+	defer resp.Body.Close()
+
+	apiErr := &Error{StatusCode: resp.StatusCode}
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	_ = json.Unmarshal(b, &apiErr.Details)
+
+	return nil, apiErr
+}
+
+// decode decodes the JSON body of the response into v and closes it.
+func decode(resp *http.Response, v interface{}) error {
+	defer resp.Body.Close()
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// NewInstructionUUID returns a random instruction UUID in the format used by
+// Swish, 32 upper case hex characters. Generate and persist it before sending
+// a request so that retries reuse it.
+func NewInstructionUUID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return strings.ToUpper(hex.EncodeToString(b)), nil
+}
+
+// IsConflict reports whether the error is returned because a request with the
+// same instruction UUID has already been created.
+func IsConflict(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict
+}
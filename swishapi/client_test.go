@@ -0,0 +1,31 @@
+package swishapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvironment(t *testing.T) {
+	tests := []struct {
+		have string
+		want string
+	}{
+		{have: "production", want: ProductionURL},
+		{have: "MSS", want: SimulatorURL},
+		{have: "simulator", want: SimulatorURL},
+	}
+
+	for _, test := range tests {
+		t.Run(test.have, func(t *testing.T) {
+			env, err := ParseEnvironment(test.have)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, New("1231181189", WithEnvironment(env)).baseURL)
+		})
+	}
+
+	_, err := ParseEnvironment("staging")
+	assert.Error(t, err)
+	assert.Equal(t, ProductionURL, New("1231181189").baseURL)
+}
@@ -0,0 +1,60 @@
+//go:build !payqr_payloadonly
+
+package swishapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"fmt"
+	"github.com/antonlindstrom/payqr"
+	"github.com/skip2/go-qrcode"
+)
+
+// PaymentQR creates a payment request for the Swish payment with the
+// instruction UUID and returns the QR code for its token. Callers retrying
+// should give the same UUID, such as from NewInstructionUUID, so that no
+// duplicate payment requests are created; if empty one is generated. If the
+// request fails and WithStaticFallback is set, a static prefilled QR code is
+// returned instead, with a nil CreatedPaymentRequest, so the customer can
+// still pay.
+func (c *Client) PaymentQR(ctx context.Context, s *payqr.SwishPayment, callbackURL, instructionUUID string) (*qrcode.QRCode, *CreatedPaymentRequest, error) {
+	req := NewPaymentRequest(s, callbackURL)
+	req.InstructionUUID = instructionUUID
+
+	created, err := c.CreatePaymentRequest(ctx, req)
+	if err == nil {
+		q, err := created.QR()
+		return q, created, err
+	}
+
+	var apiErr *Error
+	if !c.staticFallback || (errors.As(err, &apiErr) && apiErr.StatusCode < 500 && apiErr.StatusCode != http.StatusTooManyRequests) {
+		return nil, nil, err
+	}
+
+	static := *s
+	if static.Payee == "" {
+		static.Payee = c.payeeAlias
+	}
+
+	q, err := static.QR()
+
+	return q, nil, err
+}
+
+// QR returns the QR code for the payment request.
+func (p *CreatedPaymentRequest) QR() (*qrcode.QRCode, error) {
+	return QRFromToken(p.Token)
+}
+
+// QRFromToken returns a QR code for a payment request token, to be scanned
+// with the Swish app.
+func QRFromToken(token string) (*qrcode.QRCode, error) {
+	if token == "" {
+		return nil, fmt.Errorf("swish api: empty payment request token")
+	}
+
+	return qrcode.New("D"+token, qrcode.High)
+}
@@ -0,0 +1,68 @@
+//go:build !payqr_payloadonly
+
+package swishapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaymentQR(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if len(paths) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("PaymentRequestToken", "c28a4061470f4af48973bd2a4642b4fa")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	c := New("1231181189", WithBaseURL(srv.URL))
+	id, err := NewInstructionUUID()
+	require.NoError(t, err)
+
+	_, _, err = c.PaymentQR(context.Background(), payqr.NewSwish("", 100, "1001"), "https://example.com/callback", id)
+	assert.Error(t, err)
+	q, created, err := c.PaymentQR(context.Background(), payqr.NewSwish("", 100, "1001"), "https://example.com/callback", id)
+	require.NoError(t, err)
+	assert.Equal(t, id, created.ID)
+	assert.Equal(t, "D"+created.Token, q.Content)
+	assert.Equal(t, []string{"/api/v2/paymentrequests/" + id, "/api/v2/paymentrequests/" + id}, paths)
+}
+
+func TestPaymentQRStaticFallback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	c := New("1231181189", WithBaseURL(srv.URL), WithCircuitBreaker(2, time.Hour), WithStaticFallback())
+	for i := 0; i < 2; i++ {
+		_, err := c.GetPaymentRequest(context.Background(), "1")
+		assert.Error(t, err)
+	}
+
+	q, created, err := c.PaymentQR(context.Background(), payqr.NewSwish("", 100, "1001"), "https://example.com/callback", "")
+	require.NoError(t, err)
+	assert.Nil(t, created)
+	assert.Equal(t, "C1231181189;100.00;1001;0", q.Content)
+}
+
+func TestQRFromToken(t *testing.T) {
+	q, err := (&CreatedPaymentRequest{ID: "AB23D7406ECE4542A80152D909EF9F6B", Token: "f34DS34lfd0d03fdDselkfd3ffk21"}).QR()
+	require.NoError(t, err)
+	assert.Equal(t, "Df34DS34lfd0d03fdDselkfd3ffk21", q.Content)
+
+	_, err = QRFromToken("")
+	assert.Error(t, err)
+}
@@ -0,0 +1,78 @@
+package swishapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/antonlindstrom/payqr"
+)
+
+// PaymentRequest is a request for a payment sent to Swish.
+type PaymentRequest struct {
+	// InstructionUUID identifies the request, retrying with the same UUID
+	// will not create duplicate payment requests. It is generated if empty.
+	InstructionUUID string `json:"-"`
+
+	PayeePaymentReference string `json:"payeePaymentReference,omitempty"`
+	CallbackURL           string `json:"callbackUrl"`
+	PayeeAlias            string `json:"payeeAlias"`
+	PayerAlias            string `json:"payerAlias,omitempty"` // Leave empty for m-commerce (QR) requests.
+	Amount                string `json:"amount"`
+	Currency              string `json:"currency"`
+	Message               string `json:"message,omitempty"`
+}
+
+// NewPaymentRequest creates a payment request from a Swish payment, the
+// message is used as the payee payment reference.
+func NewPaymentRequest(s *payqr.SwishPayment, callbackURL string) *PaymentRequest {
+	return &PaymentRequest{
+		PayeePaymentReference: s.Message,
+		CallbackURL:           callbackURL,
+		PayeeAlias:            s.Payee,
+		Amount:                fmt.Sprintf("%.2f", s.Amount),
+		Currency:              payqr.SwishCurrency,
+		Message:               s.Message,
+	}
+}
+
+// CreatedPaymentRequest is the result of creating a payment request.
+type CreatedPaymentRequest struct {
+	ID       string // The instruction UUID.
+	Location string
+	Token    string // Payment request token, only set for m-commerce requests.
+}
+
+// CreatePaymentRequest creates a payment request. If the payee alias is
+// empty, the alias of the client is used. A request with an instruction UUID
+// that has already been created fails with an error for which IsConflict is
+// true.
+func (c *Client) CreatePaymentRequest(ctx context.Context, req *PaymentRequest) (*CreatedPaymentRequest, error) {
+	r := *req
+	if r.PayeeAlias == "" {
+		r.PayeeAlias = c.payeeAlias
+	}
+	if r.Currency == "" {
+		r.Currency = payqr.SwishCurrency
+	}
+	if r.InstructionUUID == "" {
+		id, err := NewInstructionUUID()
+		if err != nil {
+			return nil, err
+		}
+		r.InstructionUUID = id
+	}
+
+	resp, err := c.do(ctx, http.MethodPut, "/api/v2/paymentrequests/"+url.PathEscape(r.InstructionUUID), &r, http.StatusCreated)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return &CreatedPaymentRequest{
+		ID:       r.InstructionUUID,
+		Location: resp.Header.Get("Location"),
+		Token:    resp.Header.Get("PaymentRequestToken"),
+	}, nil
+}
@@ -0,0 +1,81 @@
+package swishapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePaymentRequest(t *testing.T) {
+	var got PaymentRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/api/v2/paymentrequests/AB23D7406ECE4542A80152D909EF9F6B", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+
+		w.Header().Set("Location", "https://example.com/api/v1/paymentrequests/AB23D7406ECE4542A80152D909EF9F6B")
+		w.Header().Set("PaymentRequestToken", "f34DS34lfd0d03fdDselkfd3ffk21")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	c := New("1231181189", WithBaseURL(srv.URL), WithHTTPClient(srv.Client()))
+
+	req := NewPaymentRequest(payqr.NewSwish("", 100, "Order 1001"), "https://example.com/callback")
+	req.InstructionUUID = "AB23D7406ECE4542A80152D909EF9F6B"
+
+	created, err := c.CreatePaymentRequest(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, PaymentRequest{
+		PayeePaymentReference: "Order 1001",
+		CallbackURL:           "https://example.com/callback",
+		PayeeAlias:            "1231181189",
+		Amount:                "100.00",
+		Currency:              "SEK",
+		Message:               "Order 1001",
+	}, got)
+	assert.Equal(t, "AB23D7406ECE4542A80152D909EF9F6B", created.ID)
+	assert.Equal(t, "f34DS34lfd0d03fdDselkfd3ffk21", created.Token)
+}
+
+func TestCreatePaymentRequestError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`[{"errorCode":"PA02","errorMessage":"Amount value is missing or not a valid number."}]`))
+	}))
+	defer srv.Close()
+
+	c := New("1231181189", WithBaseURL(srv.URL))
+
+	_, err := c.CreatePaymentRequest(context.Background(), &PaymentRequest{CallbackURL: "https://example.com/callback"})
+	require.Error(t, err)
+
+	var apiErr *Error
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusUnprocessableEntity, apiErr.StatusCode)
+	assert.Equal(t, "PA02", apiErr.Details[0].ErrorCode)
+}
+
+func TestCreatePaymentRequestGeneratesInstructionUUID(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	c := New("1231181189", WithBaseURL(srv.URL))
+
+	created, err := c.CreatePaymentRequest(context.Background(), &PaymentRequest{CallbackURL: "https://example.com/callback", Amount: "100.00"})
+	require.NoError(t, err)
+	assert.Len(t, created.ID, 32)
+	assert.Equal(t, []string{"/api/v2/paymentrequests/" + created.ID}, paths)
+}
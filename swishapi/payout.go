@@ -0,0 +1,154 @@
+package swishapi
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+)
+
+// PayoutType is the type of a payout.
+type PayoutType string
+
+const PayoutTypePayout PayoutType = "PAYOUT"
+
+// WithSigner sets the signer used to sign payouts, holding the private key of
+// the signing certificate with the serial number (hex).
+func WithSigner(signer crypto.Signer, certificateSerialNumber string) Option {
+	return func(c *Client) {
+		c.signer = signer
+		c.signerSerial = strings.ToUpper(certificateSerialNumber)
+	}
+}
+
+// Payout is a payout (utbetalning) from the merchant to a private person.
+type Payout struct {
+	PayoutInstructionUUID          string     `json:"payoutInstructionUUID"`
+	PayerPaymentReference          string     `json:"payerPaymentReference,omitempty"`
+	PayerAlias                     string     `json:"payerAlias"` // The merchant Swish number.
+	PayeeAlias                     string     `json:"payeeAlias"`
+	PayeeSSN                       string     `json:"payeeSSN"` // Personal identity number, yyyymmddnnnn.
+	Amount                         string     `json:"amount"`
+	Currency                       string     `json:"currency"`
+	PayoutType                     PayoutType `json:"payoutType"`
+	Message                        string     `json:"message,omitempty"`
+	InstructionDate                string     `json:"instructionDate"`
+	SigningCertificateSerialNumber string     `json:"signingCertificateSerialNumber"`
+}
+
+type signedPayout struct {
+	Payload     json.RawMessage `json:"payload"`
+	CallbackURL string          `json:"callbackUrl"`
+	Signature   string          `json:"signature"`
+}
+
+// PayoutStatus is the current state of a payout.
+type PayoutStatus struct {
+	PaymentReference      string `json:"paymentReference"`
+	PayoutInstructionUUID string `json:"payoutInstructionUUID"`
+	PayerPaymentReference string `json:"payerPaymentReference"`
+	CallbackURL           string `json:"callbackUrl"`
+	PayerAlias            string `json:"payerAlias"`
+	PayeeAlias            string `json:"payeeAlias"`
+	PayeeSSN              string `json:"payeeSSN"`
+	Amount                Amount `json:"amount"`
+	Currency              string `json:"currency"`
+	Message               string `json:"message"`
+	PayoutType            string `json:"payoutType"`
+	Status                Status `json:"status"`
+	DateCreated           string `json:"dateCreated"`
+	DatePaid              string `json:"datePaid"`
+	ErrorCode             string `json:"errorCode"`
+	ErrorMessage          string `json:"errorMessage"`
+}
+
+// CreatePayout signs and creates a payout, returning the instruction UUID. The
+// instruction UUID, payer alias, currency, type, date and certificate serial
+// number are set if empty.
+func (c *Client) CreatePayout(ctx context.Context, payout *Payout, callbackURL string) (string, error) {
+	if c.signer == nil {
+		return "", errors.New("swish api: payouts require a signer, see WithSigner")
+	}
+
+	p := *payout
+	if p.PayoutInstructionUUID == "" {
+		id, err := NewInstructionUUID()
+		if err != nil {
+			return "", err
+		}
+		p.PayoutInstructionUUID = id
+	}
+	if p.PayerAlias == "" {
+		p.PayerAlias = c.payeeAlias
+	}
+	if p.Currency == "" {
+		p.Currency = payqr.SwishCurrency
+	}
+	if p.PayoutType == "" {
+		p.PayoutType = PayoutTypePayout
+	}
+	if p.InstructionDate == "" {
+		p.InstructionDate = time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+	}
+	if p.SigningCertificateSerialNumber == "" {
+		p.SigningCertificateSerialNumber = c.signerSerial
+	}
+
+	payload, err := json.Marshal(&p)
+	if err != nil {
+		return "", err
+	}
+
+	signature, err := c.sign(payload)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := c.do(ctx, http.MethodPost, "/api/v1/payouts", &signedPayout{
+		Payload:     payload,
+		CallbackURL: callbackURL,
+		Signature:   signature,
+	}, http.StatusCreated)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	return p.PayoutInstructionUUID, nil
+}
+
+// GetPayout returns the current state of the payout.
+func (c *Client) GetPayout(ctx context.Context, instructionUUID string) (*PayoutStatus, error) {
+	resp, err := c.do(ctx, http.MethodGet, "/api/v1/payouts/"+url.PathEscape(instructionUUID), nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+
+	var status PayoutStatus
+	if err := decode(resp, &status); err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
+
+// sign returns the base64 encoded signature of the SHA-512 hash of payload.
+func (c *Client) sign(payload []byte) (string, error) {
+	hash := sha512.Sum512(payload)
+
+	signature, err := c.signer.Sign(rand.Reader, hash[:], crypto.SHA512)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
@@ -0,0 +1,58 @@
+package swishapi
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePayout(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	var got signedPayout
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/payouts", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	c := New("1231181189", WithBaseURL(srv.URL), WithSigner(key, "4512b3ebda6e3ce6bfb14aba6274a02c"))
+
+	id, err := c.CreatePayout(context.Background(), &Payout{
+		PayeeAlias: "46712347689",
+		PayeeSSN:   "196210123235",
+		Amount:     "100.00",
+	}, "https://example.com/callback")
+	require.NoError(t, err)
+	assert.Len(t, id, 32)
+
+	var payout Payout
+	require.NoError(t, json.Unmarshal(got.Payload, &payout))
+	assert.Equal(t, id, payout.PayoutInstructionUUID)
+	assert.Equal(t, "1231181189", payout.PayerAlias)
+	assert.Equal(t, PayoutTypePayout, payout.PayoutType)
+	assert.Equal(t, "4512B3EBDA6E3CE6BFB14ABA6274A02C", payout.SigningCertificateSerialNumber)
+	assert.Equal(t, "https://example.com/callback", got.CallbackURL)
+
+	signature, err := base64.StdEncoding.DecodeString(got.Signature)
+	require.NoError(t, err)
+	hash := sha512.Sum512(got.Payload)
+	assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA512, hash[:], signature))
+}
+
+func TestCreatePayoutWithoutSigner(t *testing.T) {
+	_, err := New("1231181189").CreatePayout(context.Background(), &Payout{}, "https://example.com/callback")
+	assert.Error(t, err)
+}
@@ -0,0 +1,7 @@
+//go:build !payqr_payloadonly
+
+package swishapi
+
+import "github.com/antonlindstrom/payqr"
+
+var _ payqr.SwishRenderer = &QRGenerator{}
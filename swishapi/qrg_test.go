@@ -0,0 +1,37 @@
+package swishapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQRGenerator(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/prefilled", r.URL.Path)
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		got = string(b)
+
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write([]byte("png"))
+	}))
+	defer srv.Close()
+
+	g := NewQRGenerator(WithQRGeneratorURL(srv.URL))
+
+	b, err := g.RenderSwish(context.Background(), payqr.NewSwish("1231181189", 0, "Tips", payqr.WithOpenAmount()), payqr.FormatPNG, 300)
+	require.NoError(t, err)
+	assert.Equal(t, "png", string(b))
+	assert.JSONEq(t, `{"format":"png","payee":{"value":"1231181189","editable":false},"message":{"value":"Tips","editable":false},"size":300}`, got)
+
+	_, err = g.RenderSwish(context.Background(), payqr.NewSwish("1231181189", 100, "Tips"), payqr.FormatPDF, 300)
+	assert.Error(t, err)
+}
@@ -0,0 +1,111 @@
+package swishapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+)
+
+// Refund is a request to refund a payment.
+type Refund struct {
+	// InstructionUUID identifies the refund, retrying with the same UUID
+	// will not create duplicate refunds. It is generated if empty.
+	InstructionUUID string `json:"-"`
+
+	PayerPaymentReference    string `json:"payerPaymentReference,omitempty"`
+	OriginalPaymentReference string `json:"originalPaymentReference"` // Payment reference of the paid payment request.
+	CallbackURL              string `json:"callbackUrl"`
+	PayerAlias               string `json:"payerAlias"` // The merchant Swish number.
+	Amount                   string `json:"amount"`
+	Currency                 string `json:"currency"`
+	Message                  string `json:"message,omitempty"`
+}
+
+// RefundStatus is the current state of a refund.
+type RefundStatus struct {
+	ID                       string `json:"id"`
+	PayerPaymentReference    string `json:"payerPaymentReference"`
+	OriginalPaymentReference string `json:"originalPaymentReference"`
+	PaymentReference         string `json:"paymentReference"`
+	CallbackURL              string `json:"callbackUrl"`
+	PayerAlias               string `json:"payerAlias"`
+	PayeeAlias               string `json:"payeeAlias"`
+	Amount                   Amount `json:"amount"`
+	Currency                 string `json:"currency"`
+	Message                  string `json:"message"`
+	Status                   Status `json:"status"`
+	DateCreated              string `json:"dateCreated"`
+	DatePaid                 string `json:"datePaid"`
+	ErrorCode                string `json:"errorCode"`
+	ErrorMessage             string `json:"errorMessage"`
+}
+
+// CreatedRefund is the result of creating a refund.
+type CreatedRefund struct {
+	ID       string // The instruction UUID.
+	Location string
+}
+
+// CreateRefund creates a refund of a paid payment. If the payer alias is
+// empty, the alias of the client is used. A refund with an instruction UUID
+// that has already been created fails with an error for which IsConflict is
+// true.
+func (c *Client) CreateRefund(ctx context.Context, refund *Refund) (*CreatedRefund, error) {
+	r := *refund
+	if r.PayerAlias == "" {
+		r.PayerAlias = c.payeeAlias
+	}
+	if r.Currency == "" {
+		r.Currency = payqr.SwishCurrency
+	}
+	if r.InstructionUUID == "" {
+		id, err := NewInstructionUUID()
+		if err != nil {
+			return nil, err
+		}
+		r.InstructionUUID = id
+	}
+
+	resp, err := c.do(ctx, http.MethodPut, "/api/v2/refunds/"+url.PathEscape(r.InstructionUUID), &r, http.StatusCreated)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return &CreatedRefund{ID: r.InstructionUUID, Location: resp.Header.Get("Location")}, nil
+}
+
+// GetRefund returns the current state of the refund.
+func (c *Client) GetRefund(ctx context.Context, id string) (*RefundStatus, error) {
+	resp, err := c.do(ctx, http.MethodGet, "/api/v1/refunds/"+url.PathEscape(id), nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+
+	var status RefundStatus
+	if err := decode(resp, &status); err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
+
+// WaitForRefund polls the refund every interval until it reaches a final
+// status or the context is done.
+func (c *Client) WaitForRefund(ctx context.Context, id string, interval time.Duration) (*RefundStatus, error) {
+	var status *RefundStatus
+
+	err := poll(ctx, interval, func() (Status, error) {
+		var err error
+		status, err = c.GetRefund(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		return status.Status, nil
+	})
+
+	return status, err
+}
@@ -0,0 +1,61 @@
+package swishapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefund(t *testing.T) {
+	polls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/api/v2/refunds/ABC2D7406ECE4542A80152D909EF9F6B":
+			var refund Refund
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&refund))
+			assert.Equal(t, "1231181189", refund.PayerAlias)
+			assert.Equal(t, "6D6CD7406ECE4542A80152D909EF9F6B", refund.OriginalPaymentReference)
+			assert.Equal(t, "SEK", refund.Currency)
+
+			w.Header().Set("Location", "https://example.com/api/v1/refunds/ABC2D7406ECE4542A80152D909EF9F6B")
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/refunds/ABC2D7406ECE4542A80152D909EF9F6B":
+			polls++
+			status := StatusCreated
+			if polls > 2 {
+				status = StatusPaid
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"id":     "ABC2D7406ECE4542A80152D909EF9F6B",
+				"amount": 100,
+				"status": status,
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c := New("1231181189", WithBaseURL(srv.URL))
+
+	created, err := c.CreateRefund(context.Background(), &Refund{
+		InstructionUUID:          "ABC2D7406ECE4542A80152D909EF9F6B",
+		OriginalPaymentReference: "6D6CD7406ECE4542A80152D909EF9F6B",
+		CallbackURL:              "https://example.com/callback",
+		Amount:                   "100.00",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "ABC2D7406ECE4542A80152D909EF9F6B", created.ID)
+
+	status, err := c.WaitForRefund(context.Background(), created.ID, time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, StatusPaid, status.Status)
+	assert.Equal(t, Amount(100), status.Amount)
+	assert.Equal(t, 3, polls)
+}
@@ -0,0 +1,135 @@
+package swishapi
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned when the circuit breaker is open after repeated
+// failures and requests are not sent to Swish.
+var ErrCircuitOpen = errors.New("swish api: circuit breaker is open")
+
+type retryConfig struct {
+	attempts   int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+// WithRetry retries idempotent requests (GET, and PUT with an instruction
+// UUID) that fail with network errors, 429 or 5xx responses. Up to attempts
+// requests are sent, with exponential backoff and jitter between minBackoff
+// and maxBackoff.
+func WithRetry(attempts int, minBackoff, maxBackoff time.Duration) Option {
+	return func(c *Client) {
+		c.retry = retryConfig{attempts: attempts, minBackoff: minBackoff, maxBackoff: maxBackoff}
+	}
+}
+
+// WithCircuitBreaker stops sending requests for the cooldown after threshold
+// consecutive failed requests, failing fast with ErrCircuitOpen. After the
+// cooldown one request is let through to probe if Swish has recovered.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(c *Client) {
+		c.breaker = &breaker{threshold: threshold, cooldown: cooldown, now: time.Now}
+	}
+}
+
+// WithStaticFallback makes PaymentQR fall back to a static, prefilled Swish
+// QR code when the payment request can not be created.
+func WithStaticFallback() Option {
+	return func(c *Client) {
+		c.staticFallback = true
+	}
+}
+
+// backoff returns the time to wait before the attempt, starting at 1.
+func (r retryConfig) backoff(attempt int) time.Duration {
+	d := r.minBackoff << (attempt - 1)
+	if d > r.maxBackoff || d <= 0 {
+		d = r.maxBackoff
+	}
+
+	// Full jitter in the upper half to spread out retries from many clients.
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// retryable reports whether the request may be retried after the error or
+// response.
+func retryable(method string, resp *http.Response, err error) bool {
+	if method != http.MethodGet && method != http.MethodPut {
+		return false
+	}
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// sleep waits for d or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+type breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openUntil time.Time
+	now       func() time.Time
+}
+
+// allow reports whether a request may be sent.
+func (b *breaker) allow() bool {
+	if b == nil {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return true
+	}
+	if b.now().Before(b.openUntil) {
+		return false
+	}
+
+	// Half open, let one request through and close again on success.
+	b.openUntil = b.now().Add(b.cooldown)
+
+	return true
+}
+
+// record records the outcome of a request.
+func (b *breaker) record(failed bool) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !failed {
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openUntil = b.now().Add(b.cooldown)
+	}
+}
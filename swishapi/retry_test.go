@@ -0,0 +1,74 @@
+package swishapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetry(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"1","status":"PAID"}`))
+	}))
+	defer srv.Close()
+
+	c := New("1231181189", WithBaseURL(srv.URL), WithRetry(3, time.Millisecond, 5*time.Millisecond))
+
+	status, err := c.GetPaymentRequest(context.Background(), "1")
+	require.NoError(t, err)
+	assert.Equal(t, StatusPaid, status.Status)
+	assert.Equal(t, int32(3), calls)
+}
+
+func TestRetryNotIdempotent(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	c := New("1231181189", WithBaseURL(srv.URL), WithRetry(3, time.Millisecond, 5*time.Millisecond))
+
+	_, err := c.do(context.Background(), http.MethodPost, "/api/v1/payouts", struct{}{}, http.StatusCreated)
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), calls)
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	c := New("1231181189", WithBaseURL(srv.URL), WithCircuitBreaker(2, time.Hour))
+	now := time.Now()
+	c.breaker.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		_, err := c.GetPaymentRequest(context.Background(), "1")
+		assert.Error(t, err)
+	}
+
+	_, err := c.GetPaymentRequest(context.Background(), "1")
+	assert.Equal(t, ErrCircuitOpen, err)
+	assert.Equal(t, int32(2), calls)
+
+	now = now.Add(2 * time.Hour)
+	_, err = c.GetPaymentRequest(context.Background(), "1")
+	assert.NotEqual(t, ErrCircuitOpen, err)
+	assert.Equal(t, int32(3), calls)
+}
@@ -0,0 +1,87 @@
+package swishapi
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Status is the status of a payment request, refund or payout.
+type Status string
+
+const (
+	StatusCreated   Status = "CREATED"
+	StatusValidated Status = "VALIDATED"
+	StatusDebited   Status = "DEBITED"
+	StatusPaid      Status = "PAID"
+	StatusDeclined  Status = "DECLINED"
+	StatusError     Status = "ERROR"
+	StatusCancelled Status = "CANCELLED"
+)
+
+// Final reports whether the status will not change anymore.
+func (s Status) Final() bool {
+	switch s {
+	case StatusPaid, StatusDeclined, StatusError, StatusCancelled:
+		return true
+	}
+
+	return false
+}
+
+// PaymentRequestStatus is the current state of a payment request.
+type PaymentRequestStatus struct {
+	ID                    string `json:"id"`
+	PayeePaymentReference string `json:"payeePaymentReference"`
+	PaymentReference      string `json:"paymentReference"` // Set by Swish once paid, used for refunds.
+	CallbackURL           string `json:"callbackUrl"`
+	PayerAlias            string `json:"payerAlias"`
+	PayeeAlias            string `json:"payeeAlias"`
+	Amount                Amount `json:"amount"`
+	Currency              string `json:"currency"`
+	Message               string `json:"message"`
+	Status                Status `json:"status"`
+	DateCreated           string `json:"dateCreated"`
+	DatePaid              string `json:"datePaid"`
+	ErrorCode             string `json:"errorCode"`
+	ErrorMessage          string `json:"errorMessage"`
+}
+
+// GetPaymentRequest returns the current state of the payment request.
+func (c *Client) GetPaymentRequest(ctx context.Context, id string) (*PaymentRequestStatus, error) {
+	resp, err := c.do(ctx, http.MethodGet, "/api/v1/paymentrequests/"+url.PathEscape(id), nil, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+
+	var status PaymentRequestStatus
+	if err := decode(resp, &status); err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
+
+// poll calls get every interval until it returns a final status, an error or
+// the context is done.
+func poll(ctx context.Context, interval time.Duration, get func() (Status, error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		status, err := get()
+		if err != nil {
+			return err
+		}
+		if status.Final() {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
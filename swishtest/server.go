@@ -0,0 +1,461 @@
+// Package swishtest provides an in-process fake of the Swish Commerce API for
+// tests, so payment flows can be tested without certificates or network
+// access.
+//
+//	srv := swishtest.NewServer()
+//	defer srv.Close()
+//
+//	c := srv.Client("1231181189")
+//	created, _ := c.CreatePaymentRequest(ctx, req)
+//	_ = srv.Pay(created.ID) // Sends the callback and updates the status.
+package swishtest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/antonlindstrom/payqr/swishapi"
+)
+
+// Server is a fake Swish Commerce API.
+type Server struct {
+	*httptest.Server
+
+	// CallbackClient is used to send callbacks. Default is
+	// http.DefaultClient.
+	CallbackClient *http.Client
+
+	mu              sync.Mutex
+	paymentRequests map[string]*swishapi.PaymentRequestStatus
+	refunds         map[string]*swishapi.RefundStatus
+	payouts         map[string]*swishapi.PayoutStatus
+}
+
+// NewServer starts a fake Swish Commerce API. It should be closed when done.
+func NewServer() *Server {
+	s := &Server{
+		CallbackClient:  http.DefaultClient,
+		paymentRequests: make(map[string]*swishapi.PaymentRequestStatus),
+		refunds:         make(map[string]*swishapi.RefundStatus),
+		payouts:         make(map[string]*swishapi.PayoutStatus),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/paymentrequests/", s.handleCreatePaymentRequest)
+	mux.HandleFunc("/api/v1/paymentrequests/", s.handleGetPaymentRequest)
+	mux.HandleFunc("/api/v2/refunds/", s.handleCreateRefund)
+	mux.HandleFunc("/api/v1/refunds/", s.handleGetRefund)
+	mux.HandleFunc("/api/v1/payouts", s.handleCreatePayout)
+	mux.HandleFunc("/api/v1/payouts/", s.handleGetPayout)
+
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// Client returns a Swish API client for the merchant talking to the server.
+func (s *Server) Client(payeeAlias string, options ...swishapi.Option) *swishapi.Client {
+	options = append([]swishapi.Option{swishapi.WithHTTPClient(s.Server.Client()), swishapi.WithBaseURL(s.URL)}, options...)
+
+	return swishapi.New(payeeAlias, options...)
+}
+
+// PaymentRequest returns the current state of the payment request.
+func (s *Server) PaymentRequest(id string) (swishapi.PaymentRequestStatus, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.paymentRequests[id]
+	if !ok {
+		return swishapi.PaymentRequestStatus{}, false
+	}
+
+	return *p, true
+}
+
+// Pay marks the payment request as paid and sends the callback.
+func (s *Server) Pay(id string) error {
+	return s.transitionPaymentRequest(id, swishapi.StatusPaid, "", "")
+}
+
+// Decline marks the payment request as declined by the payer and sends the
+// callback.
+func (s *Server) Decline(id string) error {
+	return s.transitionPaymentRequest(id, swishapi.StatusDeclined, "", "")
+}
+
+// Fail marks the payment request as failed with the error and sends the
+// callback.
+func (s *Server) Fail(id, errorCode, errorMessage string) error {
+	return s.transitionPaymentRequest(id, swishapi.StatusError, errorCode, errorMessage)
+}
+
+func (s *Server) transitionPaymentRequest(id string, status swishapi.Status, errorCode, errorMessage string) error {
+	s.mu.Lock()
+	p, ok := s.paymentRequests[id]
+	if !ok {
+		s.mu.Unlock()
+		return fmt.Errorf("swishtest: no payment request %s", id)
+	}
+	if p.Status.Final() {
+		s.mu.Unlock()
+		return fmt.Errorf("swishtest: payment request %s is already %s", id, p.Status)
+	}
+
+	p.Status = status
+	p.ErrorCode = errorCode
+	p.ErrorMessage = errorMessage
+	if status == swishapi.StatusPaid {
+		p.PaymentReference = newID()
+		p.DatePaid = now()
+	}
+
+	cb := swishapi.Callback{
+		ID:                    p.ID,
+		PayeePaymentReference: p.PayeePaymentReference,
+		PaymentReference:      p.PaymentReference,
+		CallbackURL:           p.CallbackURL,
+		PayerAlias:            p.PayerAlias,
+		PayeeAlias:            p.PayeeAlias,
+		Amount:                p.Amount,
+		Currency:              p.Currency,
+		Message:               p.Message,
+		Status:                p.Status,
+		DateCreated:           p.DateCreated,
+		DatePaid:              p.DatePaid,
+		ErrorCode:             p.ErrorCode,
+		ErrorMessage:          p.ErrorMessage,
+	}
+	s.mu.Unlock()
+
+	return s.sendCallback(&cb)
+}
+
+// Refund returns the current state of the refund.
+func (s *Server) Refund(id string) (swishapi.RefundStatus, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.refunds[id]
+	if !ok {
+		return swishapi.RefundStatus{}, false
+	}
+
+	return *r, true
+}
+
+// CompleteRefund marks the refund as paid and sends the callback.
+func (s *Server) CompleteRefund(id string) error {
+	s.mu.Lock()
+	r, ok := s.refunds[id]
+	if !ok {
+		s.mu.Unlock()
+		return fmt.Errorf("swishtest: no refund %s", id)
+	}
+
+	r.Status = swishapi.StatusPaid
+	r.PaymentReference = newID()
+	r.DatePaid = now()
+
+	cb := swishapi.Callback{
+		ID:                       r.ID,
+		PayerPaymentReference:    r.PayerPaymentReference,
+		PaymentReference:         r.PaymentReference,
+		OriginalPaymentReference: r.OriginalPaymentReference,
+		CallbackURL:              r.CallbackURL,
+		PayerAlias:               r.PayerAlias,
+		Amount:                   r.Amount,
+		Currency:                 r.Currency,
+		Message:                  r.Message,
+		Status:                   r.Status,
+		DateCreated:              r.DateCreated,
+		DatePaid:                 r.DatePaid,
+	}
+	s.mu.Unlock()
+
+	return s.sendCallback(&cb)
+}
+
+// Payout returns the current state of the payout.
+func (s *Server) Payout(instructionUUID string) (swishapi.PayoutStatus, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.payouts[instructionUUID]
+	if !ok {
+		return swishapi.PayoutStatus{}, false
+	}
+
+	return *p, true
+}
+
+func (s *Server) sendCallback(cb *swishapi.Callback) error {
+	b, err := json.Marshal(cb)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.CallbackClient.Post(cb.CallbackURL, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("swishtest: callback failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("swishtest: callback responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (s *Server) handleCreatePaymentRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req swishapi.PaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrors(w, swishapi.ErrorDetail{ErrorCode: "PA01", ErrorMessage: "Invalid request body."})
+		return
+	}
+
+	amount, details := validate(req.PayeeAlias, req.CallbackURL, req.Amount, req.Currency)
+	if len(details) > 0 {
+		writeErrors(w, details...)
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/api/v2/paymentrequests/")
+	p := &swishapi.PaymentRequestStatus{
+		ID:                    id,
+		PayeePaymentReference: req.PayeePaymentReference,
+		CallbackURL:           req.CallbackURL,
+		PayerAlias:            req.PayerAlias,
+		PayeeAlias:            req.PayeeAlias,
+		Amount:                amount,
+		Currency:              req.Currency,
+		Message:               req.Message,
+		Status:                swishapi.StatusCreated,
+		DateCreated:           now(),
+	}
+
+	s.mu.Lock()
+	_, exists := s.paymentRequests[id]
+	if !exists {
+		s.paymentRequests[id] = p
+	}
+	s.mu.Unlock()
+
+	if exists {
+		writeJSON(w, http.StatusConflict, []swishapi.ErrorDetail{{ErrorCode: "RP06", ErrorMessage: "A payment request with the instruction UUID already exists."}})
+		return
+	}
+
+	w.Header().Set("Location", s.URL+"/api/v1/paymentrequests/"+id)
+	if req.PayerAlias == "" {
+		w.Header().Set("PaymentRequestToken", strings.ToLower(newID()))
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) handleGetPaymentRequest(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/api/v1/paymentrequests/")
+
+	p, ok := s.PaymentRequest(id)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, p)
+}
+
+func (s *Server) handleCreateRefund(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req swishapi.Refund
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrors(w, swishapi.ErrorDetail{ErrorCode: "PA01", ErrorMessage: "Invalid request body."})
+		return
+	}
+
+	amount, details := validate(req.PayerAlias, req.CallbackURL, req.Amount, req.Currency)
+	if !s.hasPaymentReference(req.OriginalPaymentReference) {
+		details = append(details, swishapi.ErrorDetail{ErrorCode: "RF02", ErrorMessage: "Original Payment not found or original payment is more than 13 months old."})
+	}
+	if len(details) > 0 {
+		writeErrors(w, details...)
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/api/v2/refunds/")
+	refund := &swishapi.RefundStatus{
+		ID:                       id,
+		PayerPaymentReference:    req.PayerPaymentReference,
+		OriginalPaymentReference: req.OriginalPaymentReference,
+		CallbackURL:              req.CallbackURL,
+		PayerAlias:               req.PayerAlias,
+		Amount:                   amount,
+		Currency:                 req.Currency,
+		Message:                  req.Message,
+		Status:                   swishapi.StatusCreated,
+		DateCreated:              now(),
+	}
+
+	s.mu.Lock()
+	_, exists := s.refunds[id]
+	if !exists {
+		s.refunds[id] = refund
+	}
+	s.mu.Unlock()
+
+	if exists {
+		writeJSON(w, http.StatusConflict, []swishapi.ErrorDetail{{ErrorCode: "RF06", ErrorMessage: "A refund with the instruction UUID already exists."}})
+		return
+	}
+
+	w.Header().Set("Location", s.URL+"/api/v1/refunds/"+id)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) hasPaymentReference(reference string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, p := range s.paymentRequests {
+		if p.Status == swishapi.StatusPaid && p.PaymentReference == reference {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Server) handleGetRefund(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/api/v1/refunds/")
+
+	refund, ok := s.Refund(id)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, refund)
+}
+
+func (s *Server) handleCreatePayout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Payload     swishapi.Payout `json:"payload"`
+		CallbackURL string          `json:"callbackUrl"`
+		Signature   string          `json:"signature"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrors(w, swishapi.ErrorDetail{ErrorCode: "PA01", ErrorMessage: "Invalid request body."})
+		return
+	}
+
+	amount, details := validate(req.Payload.PayerAlias, req.CallbackURL, req.Payload.Amount, req.Payload.Currency)
+	if req.Signature == "" {
+		details = append(details, swishapi.ErrorDetail{ErrorCode: "RP09", ErrorMessage: "Signature is missing."})
+	}
+	if len(details) > 0 {
+		writeErrors(w, details...)
+		return
+	}
+
+	p := &swishapi.PayoutStatus{
+		PaymentReference:      newID(),
+		PayoutInstructionUUID: req.Payload.PayoutInstructionUUID,
+		PayerPaymentReference: req.Payload.PayerPaymentReference,
+		CallbackURL:           req.CallbackURL,
+		PayerAlias:            req.Payload.PayerAlias,
+		PayeeAlias:            req.Payload.PayeeAlias,
+		PayeeSSN:              req.Payload.PayeeSSN,
+		Amount:                amount,
+		Currency:              req.Payload.Currency,
+		Message:               req.Payload.Message,
+		PayoutType:            string(req.Payload.PayoutType),
+		Status:                swishapi.StatusCreated,
+		DateCreated:           now(),
+	}
+
+	s.mu.Lock()
+	s.payouts[p.PayoutInstructionUUID] = p
+	s.mu.Unlock()
+
+	w.Header().Set("Location", s.URL+"/api/v1/payouts/"+p.PayoutInstructionUUID)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) handleGetPayout(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/api/v1/payouts/")
+
+	p, ok := s.Payout(id)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, p)
+}
+
+// validate checks the fields common to all requests like the Swish API does.
+func validate(alias, callbackURL, amount, currency string) (swishapi.Amount, []swishapi.ErrorDetail) {
+	var details []swishapi.ErrorDetail
+
+	if alias == "" {
+		details = append(details, swishapi.ErrorDetail{ErrorCode: "RP01", ErrorMessage: "Missing Merchant Swish Number."})
+	}
+	if callbackURL == "" {
+		details = append(details, swishapi.ErrorDetail{ErrorCode: "RP03", ErrorMessage: "Callback URL is missing or does not use HTTPS."})
+	}
+
+	f, err := strconv.ParseFloat(amount, 64)
+	if err != nil || f < 1 || f > 999999999999.99 {
+		details = append(details, swishapi.ErrorDetail{ErrorCode: "PA02", ErrorMessage: "Amount value is missing or not a valid number."})
+	}
+	if currency != "SEK" {
+		details = append(details, swishapi.ErrorDetail{ErrorCode: "AC05", ErrorMessage: "Invalid currency."})
+	}
+
+	return swishapi.Amount(f), details
+}
+
+func writeErrors(w http.ResponseWriter, details ...swishapi.ErrorDetail) {
+	writeJSON(w, http.StatusUnprocessableEntity, details)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	return strings.ToUpper(hex.EncodeToString(b))
+}
+
+func now() string {
+	return time.Now().UTC().Format("2006-01-02T15:04:05.000Z")
+}
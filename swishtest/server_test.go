@@ -0,0 +1,88 @@
+package swishtest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/antonlindstrom/payqr/swishapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaymentFlow(t *testing.T) {
+	ctx := context.Background()
+
+	srv := NewServer()
+	defer srv.Close()
+
+	store := swishapi.NewMemoryStatusStore()
+	c := srv.Client("1231181189")
+
+	callbacks := httptest.NewServer(swishapi.NewCallbackHandler(store, swishapi.WithCallbackVerifier(swishapi.VerifyWithClient(c))))
+	defer callbacks.Close()
+
+	created, err := c.CreatePaymentRequest(ctx, swishapi.NewPaymentRequest(payqr.NewSwish("", 100, "1001"), callbacks.URL))
+	require.NoError(t, err)
+	assert.NotEmpty(t, created.Token)
+
+	require.NoError(t, srv.Pay(created.ID))
+	assert.Error(t, srv.Pay(created.ID), "already paid")
+
+	cb, err := store.Status(ctx, "1001")
+	require.NoError(t, err)
+	assert.Equal(t, swishapi.StatusPaid, cb.Status)
+
+	refund, err := c.CreateRefund(ctx, &swishapi.Refund{
+		PayerPaymentReference:    "R1001",
+		OriginalPaymentReference: cb.PaymentReference,
+		CallbackURL:              callbacks.URL,
+		Amount:                   "100.00",
+	})
+	require.NoError(t, err)
+	require.NoError(t, srv.CompleteRefund(refund.ID))
+
+	status, err := c.WaitForRefund(ctx, refund.ID, time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, swishapi.StatusPaid, status.Status)
+
+	cb, err = store.Status(ctx, "R1001")
+	require.NoError(t, err)
+	assert.True(t, cb.IsRefund())
+}
+
+func TestIdempotentPaymentRequest(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	c := srv.Client("1231181189")
+	req := &swishapi.PaymentRequest{InstructionUUID: "11A86BE70EA346E4B1C39C874173F088", CallbackURL: "https://example.com/callback", Amount: "100.00"}
+
+	created, err := c.CreatePaymentRequest(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, req.InstructionUUID, created.ID)
+
+	_, err = c.CreatePaymentRequest(context.Background(), req)
+	assert.True(t, swishapi.IsConflict(err))
+}
+
+func TestValidation(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	_, err := srv.Client("1231181189").CreatePaymentRequest(context.Background(), &swishapi.PaymentRequest{Amount: "0.50"})
+
+	var apiErr *swishapi.Error
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusUnprocessableEntity, apiErr.StatusCode)
+
+	codes := []string{}
+	for _, d := range apiErr.Details {
+		codes = append(codes, d.ErrorCode)
+	}
+	assert.Equal(t, []string{"RP03", "PA02"}, codes)
+}
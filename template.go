@@ -0,0 +1,164 @@
+package payqr
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Template is a payment of which only the reference and amount vary, such
+// as the invoices of a merchant. The other fields are encoded once, and the
+// payload of each payment is spliced together from them and the reference
+// and amount, which is many times faster than encoding the payment.
+type Template struct {
+	base Payment
+
+	// The payload is prefix, reference, middle, amount and suffix.
+	prefix []byte
+	middle []byte
+	suffix []byte
+}
+
+// NewTemplate returns a template of the payment. Its reference and amount
+// are ignored.
+func NewTemplate(d *Payment) (*Template, error) {
+	t := &Template{base: *d}
+	t.base.Reference, t.base.DueAmount = "", 0
+
+	b, err := t.base.AppendPayload(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Quotes in values are escaped, so the keys are only found as keys. The
+	// empty reference and zero amount are left out of the parts.
+	i, j := bytes.Index(b, []byte(`"iref":""`)), bytes.Index(b, []byte(`"due":0,`))
+	if i < 0 || j < i {
+		return nil, errors.New("template: unexpected payload")
+	}
+	i += len(`"iref":`)
+	j += len(`"due":`)
+
+	t.prefix, t.middle, t.suffix = b[:i], b[i+len(`""`):j], b[j+len(`0`):]
+
+	return t, nil
+}
+
+// Payment returns the payment of the template with the reference and
+// amount.
+func (t *Template) Payment(reference string, amount float64) *Payment {
+	d := t.base
+	d.Reference, d.DueAmount = reference, amount
+
+	return &d
+}
+
+// AppendPayload appends the payload of the QR code of the payment with the
+// reference and amount to dst, as encoded by Payment.QR, and returns the
+// extended buffer.
+func (t *Template) AppendPayload(dst []byte, reference string, amount float64) ([]byte, error) {
+	dst = append(dst, t.prefix...)
+	dst = appendJSONString(dst, reference)
+	dst = append(dst, t.middle...)
+	dst, err := appendJSONFloat(dst, RoundAmount(amount, t.base.Currency))
+	if err != nil {
+		return dst, err
+	}
+
+	return append(dst, t.suffix...), nil
+}
+
+// Payload returns the payload of the QR code of the payment with the
+// reference and amount.
+func (t *Template) Payload(reference string, amount float64) (string, error) {
+	b, err := t.AppendPayload(make([]byte, 0, len(t.prefix)+len(t.middle)+len(t.suffix)+len(reference)+16), reference, amount)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// appendJSONFloat appends the number as encoding/json encodes a float64.
+func appendJSONFloat(dst []byte, f float64) ([]byte, error) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return dst, errors.New("json: unsupported value: " + strconv.FormatFloat(f, 'g', -1, 64))
+	}
+
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	dst = strconv.AppendFloat(dst, f, format, -1, 64)
+	if format == 'e' {
+		// Clean up e-09 to e-9.
+		if n := len(dst); n >= 4 && dst[n-4] == 'e' && dst[n-3] == '-' && dst[n-2] == '0' {
+			dst[n-2] = dst[n-1]
+			dst = dst[:n-1]
+		}
+	}
+
+	return dst, nil
+}
+
+const hexDigits = "0123456789abcdef"
+
+// appendJSONString appends the string as encoding/json encodes it, quoted
+// and with HTML characters escaped.
+func appendJSONString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+
+	start := 0
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' && c != '<' && c != '>' && c != '&' {
+				i++
+				continue
+			}
+
+			dst = append(dst, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				dst = append(dst, '\\', c)
+			case '\b':
+				dst = append(dst, '\\', 'b')
+			case '\f':
+				dst = append(dst, '\\', 'f')
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, "\ufffd"...)
+			i += size
+			start = i
+			continue
+		}
+		if r == '\u2028' || r == '\u2029' {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', hexDigits[r&0xf])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+
+	dst = append(dst, s[start:]...)
+
+	return append(dst, '"')
+}
@@ -0,0 +1,84 @@
+package payqr
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplate(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+	bases := []*Payment{
+		New("5402-9681", "Test AB", "5555555555", "", 0, due),
+		New("SE4550000000058398257466", `Tricky "iref":"" & Co`, "5555555555", "ignored", 99, due,
+			WithPaymentType(PaymentTypeIBAN), WithCurrency("EUR"), WithAddress(`"due":0,"pt":`), WithCountryCode("SE")),
+	}
+	references := []string{"52250047157", "", `quote " and \ backslash`, "<b>&</b>", "Åäö €", "tab\tnew\nline\r\x00\x1f\b\f", "  ", "invalid \xff utf-8"}
+	amounts := []float64{0, 1250, 0.1, 12.345, -5, 1e21, 1e-7, 123456789.5}
+
+	for _, base := range bases {
+		tmpl, err := NewTemplate(base)
+		require.NoError(t, err)
+
+		for _, ref := range references {
+			for _, amount := range amounts {
+				d := tmpl.Payment(ref, amount)
+				assert.Equal(t, ref, d.Reference)
+				assert.Equal(t, amount, d.DueAmount)
+				assert.Equal(t, base.AccountName, d.AccountName)
+
+				want, err := d.Payload()
+				require.NoError(t, err)
+
+				payload, err := tmpl.Payload(ref, amount)
+				require.NoError(t, err)
+				assert.Equal(t, want, payload, "reference %q amount %v", ref, amount)
+			}
+		}
+	}
+}
+
+func TestTemplateInvalidAmount(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+	tmpl, err := NewTemplate(New("5402-9681", "Test AB", "5555555555", "", 0, due))
+	require.NoError(t, err)
+
+	_, err = tmpl.Payload("52250047157", math.NaN())
+	assert.Error(t, err)
+	_, err = tmpl.Payload("52250047157", math.Inf(1))
+	assert.Error(t, err)
+}
+
+func BenchmarkTemplatePayload(b *testing.B) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+	tmpl, err := NewTemplate(New("5402-9681", "Test AB", "5555555555", "", 0, due))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	var dst []byte
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if dst, err = tmpl.AppendPayload(dst[:0], "52250047157", 1250); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMarshalPayload(b *testing.B) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+	d := New("5402-9681", "Test AB", "5555555555", "52250047157", 1250, due)
+
+	// Marshal the fields without MarshalJSON, which is AppendPayload, as the
+	// baseline of encoding/json.
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := json.Marshal((*payloadFields)(d)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package payqr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MarshalText returns the payload of the payment, as MarshalJSON.
+func (d Payment) MarshalText() ([]byte, error) {
+	return d.AppendPayload(nil)
+}
+
+// UnmarshalText decodes a payload as ParsePayload, rejecting unknown fields,
+// e.g. of payments given as flags or in configuration.
+func (d *Payment) UnmarshalText(b []byte) error {
+	return d.decode(b, true)
+}
+
+// String returns a summary of the payment for logs, with all but the last
+// four characters of the account and reference redacted.
+func (d Payment) String() string {
+	currency := d.Currency
+	if currency == "" {
+		currency = "SEK"
+	}
+
+	return fmt.Sprintf("%s, %s %s, reference %s, %s %s due %s", d.AccountName, d.PaymentType, redact(d.AccountNumber), redact(d.Reference), d.Due(), strings.ToUpper(currency), d.DueDate)
+}
+
+// redact replaces all but the last four characters of s with asterisks.
+func redact(s string) string {
+	if len(s) <= 4 {
+		return strings.Repeat("*", len(s))
+	}
+
+	return strings.Repeat("*", len(s)-4) + s[len(s)-4:]
+}
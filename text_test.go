@@ -0,0 +1,43 @@
+package payqr
+
+import (
+	"encoding"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ encoding.TextMarshaler   = Payment{}
+	_ encoding.TextUnmarshaler = &Payment{}
+	_ fmt.Stringer             = Payment{}
+)
+
+func TestText(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+	d := New("5402-9681", "Test AB", "5555555555", "52250047157", 1250.5, due, WithCreationDate(due))
+
+	b, err := d.MarshalText()
+	require.NoError(t, err)
+	payload, err := d.Payload()
+	require.NoError(t, err)
+	assert.Equal(t, payload, string(b))
+
+	var decoded Payment
+	require.NoError(t, decoded.UnmarshalText(b))
+	assert.Equal(t, *d, decoded)
+	assert.EqualError(t, decoded.UnmarshalText([]byte(`{"amount":50}`)), `json: unknown field "amount"`)
+}
+
+func TestString(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+	d := New("5402-9681", "Test AB", "5555555555", "52250047157", 1250.5, due)
+	assert.Equal(t, "Test AB, BG *****9681, reference *******7157, 1250.50 SEK due 20220806", d.String())
+	assert.Equal(t, d.String(), fmt.Sprint(d))
+
+	d.Currency, d.DueAmount, d.Reference = "jpy", 1250, "12"
+	assert.Equal(t, "Test AB, BG *****9681, reference **, 1250 JPY due 20220806", fmt.Sprint(*d))
+}
@@ -0,0 +1,224 @@
+//go:build !payqr_payloadonly
+
+// Package tiff renders QR codes as CMYK TIFF images for offset printing.
+// Dark modules are pure black on the K plate only, with no cyan, magenta or
+// yellow, so that the code stays sharp even if the plates are slightly out of
+// register. Light modules are left unprinted.
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"io"
+	"math"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/skip2/go-qrcode"
+)
+
+func init() {
+	payqr.RegisterCapability(payqr.CapabilityTIFF)
+}
+
+// Option is a modifier for the rendering.
+type Option func(*config)
+
+type config struct {
+	dpi  int
+	size float64
+}
+
+// WithResolution sets the resolution in dots per inch. Default is 1200.
+func WithResolution(dpi int) Option {
+	return func(c *config) {
+		c.dpi = dpi
+	}
+}
+
+// WithSize sets the printed width of the code, including its quiet zone, in
+// millimeters. Default is 30.
+func WithSize(mm float64) Option {
+	return func(c *config) {
+		c.size = mm
+	}
+}
+
+// Image returns the QR code as a CMYK image at the resolution and size of the
+// options. Each module is a whole number of pixels, so the size is rounded to
+// the closest size that fits.
+func Image(q *qrcode.QRCode, options ...Option) (*image.CMYK, int, error) {
+	c := &config{dpi: 1200, size: 30}
+	for _, opt := range options {
+		opt(c)
+	}
+	if c.dpi < 1 || c.size <= 0 {
+		return nil, 0, errors.New("tiff: resolution and size must be positive")
+	}
+
+	bitmap := q.Bitmap()
+	n := len(bitmap)
+	module := int(math.Max(1, math.Round(c.size/25.4*float64(c.dpi)/float64(n))))
+
+	img := image.NewCMYK(image.Rect(0, 0, n*module, n*module))
+	black := color.CMYK{K: 0xff}
+	for y := range bitmap {
+		for x, dark := range bitmap[y] {
+			if !dark {
+				continue
+			}
+			for dy := 0; dy < module; dy++ {
+				for dx := 0; dx < module; dx++ {
+					img.SetCMYK(x*module+dx, y*module+dy, black)
+				}
+			}
+		}
+	}
+
+	return img, c.dpi, nil
+}
+
+// Encode writes the QR code as a CMYK TIFF image.
+func Encode(w io.Writer, q *qrcode.QRCode, options ...Option) error {
+	img, dpi, err := Image(q, options...)
+	if err != nil {
+		return err
+	}
+
+	return EncodeImage(w, img, dpi)
+}
+
+// EncodePayment writes the QR code of the payment as a CMYK TIFF image.
+func EncodePayment(w io.Writer, p *payqr.Payment, options ...Option) error {
+	q, err := p.QR()
+	if err != nil {
+		return err
+	}
+
+	return Encode(w, q, options...)
+}
+
+// TIFF tags, types and values used.
+const (
+	tagImageWidth                = 256
+	tagImageLength               = 257
+	tagBitsPerSample             = 258
+	tagCompression               = 259
+	tagPhotometricInterpretation = 262
+	tagStripOffsets              = 273
+	tagSamplesPerPixel           = 277
+	tagRowsPerStrip              = 278
+	tagStripByteCounts           = 279
+	tagXResolution               = 282
+	tagYResolution               = 283
+	tagPlanarConfiguration       = 284
+	tagResolutionUnit            = 296
+	tagInkSet                    = 332
+
+	typeShort    = 3
+	typeLong     = 4
+	typeRational = 5
+
+	compressionPackBits  = 32773
+	photometricSeparated = 5
+	resolutionUnitInch   = 2
+	inkSetCMYK           = 1
+)
+
+// EncodeImage writes the image as a single strip, PackBits compressed, CMYK
+// TIFF with the resolution in dots per inch.
+func EncodeImage(w io.Writer, img *image.CMYK, dpi int) error {
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+
+	var data bytes.Buffer
+	for y := 0; y < height; y++ {
+		start := img.PixOffset(img.Rect.Min.X, img.Rect.Min.Y+y)
+		packBits(&data, img.Pix[start:start+4*width])
+	}
+
+	type entry struct {
+		tag, typ     uint16
+		count, value uint32
+	}
+
+	// The header and directory are followed by the values that do not fit in
+	// an entry, and then the image data.
+	const entries = 14
+	extra := uint32(8 + 2 + entries*12 + 4)
+	bitsPerSample, xRes, yRes := extra, extra+8, extra+16
+	offset := extra + 24
+
+	dir := []entry{
+		{tagImageWidth, typeLong, 1, uint32(width)},
+		{tagImageLength, typeLong, 1, uint32(height)},
+		{tagBitsPerSample, typeShort, 4, bitsPerSample},
+		{tagCompression, typeShort, 1, compressionPackBits},
+		{tagPhotometricInterpretation, typeShort, 1, photometricSeparated},
+		{tagStripOffsets, typeLong, 1, offset},
+		{tagSamplesPerPixel, typeShort, 1, 4},
+		{tagRowsPerStrip, typeLong, 1, uint32(height)},
+		{tagStripByteCounts, typeLong, 1, uint32(data.Len())},
+		{tagXResolution, typeRational, 1, xRes},
+		{tagYResolution, typeRational, 1, yRes},
+		{tagPlanarConfiguration, typeShort, 1, 1},
+		{tagResolutionUnit, typeShort, 1, resolutionUnitInch},
+		{tagInkSet, typeShort, 1, inkSetCMYK},
+	}
+
+	var buf bytes.Buffer
+	le := binary.LittleEndian
+	buf.WriteString("II*\x00")
+	_ = binary.Write(&buf, le, uint32(8))
+	_ = binary.Write(&buf, le, uint16(len(dir)))
+	for _, e := range dir {
+		_ = binary.Write(&buf, le, e.tag)
+		_ = binary.Write(&buf, le, e.typ)
+		_ = binary.Write(&buf, le, e.count)
+		// Short values are left justified in the value field.
+		if e.typ == typeShort && e.count == 1 {
+			_ = binary.Write(&buf, le, [2]uint16{uint16(e.value), 0})
+		} else {
+			_ = binary.Write(&buf, le, e.value)
+		}
+	}
+	_ = binary.Write(&buf, le, uint32(0))
+	_ = binary.Write(&buf, le, [4]uint16{8, 8, 8, 8})
+	_ = binary.Write(&buf, le, [4]uint32{uint32(dpi), 1, uint32(dpi), 1})
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	_, err := w.Write(data.Bytes())
+
+	return err
+}
+
+// packBits writes the row compressed with PackBits: runs of two or more equal
+// bytes are written as a count and the byte, other bytes literally.
+func packBits(buf *bytes.Buffer, row []byte) {
+	for i := 0; i < len(row); {
+		run := 1
+		for i+run < len(row) && run < 128 && row[i+run] == row[i] {
+			run++
+		}
+		if run > 1 {
+			buf.WriteByte(byte(1 - run))
+			buf.WriteByte(row[i])
+			i += run
+			continue
+		}
+
+		// Literal bytes until the next run of at least three.
+		start := i
+		for i < len(row) && i-start < 128 {
+			if i+2 < len(row) && row[i] == row[i+1] && row[i] == row[i+2] {
+				break
+			}
+			i++
+		}
+		buf.WriteByte(byte(i - start - 1))
+		buf.Write(row[start:i])
+	}
+}
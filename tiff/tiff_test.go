@@ -0,0 +1,118 @@
+//go:build !payqr_payloadonly
+
+package tiff
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image/color"
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/skip2/go-qrcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unpackBits decodes PackBits data.
+func unpackBits(data []byte) []byte {
+	var out []byte
+	for i := 0; i < len(data); {
+		n := int(int8(data[i]))
+		i++
+		switch {
+		case n >= 0:
+			out = append(out, data[i:i+n+1]...)
+			i += n + 1
+		case n != -128:
+			out = append(out, bytes.Repeat(data[i:i+1], 1-n)...)
+			i++
+		}
+	}
+
+	return out
+}
+
+func TestPackBits(t *testing.T) {
+	tests := []struct {
+		name string
+		have []byte
+	}{
+		{name: "Empty", have: nil},
+		{name: "Literal", have: []byte{1, 2, 3, 4}},
+		{name: "Run", have: bytes.Repeat([]byte{7}, 300)},
+		{name: "Mixed", have: append(append([]byte{1, 2, 2, 3}, bytes.Repeat([]byte{0}, 10)...), 5, 6, 6, 6, 7)},
+		{name: "Long literal", have: func() []byte {
+			b := make([]byte, 400)
+			for i := range b {
+				b[i] = byte(i)
+			}
+			return b
+		}()},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			packBits(&buf, test.have)
+			assert.Equal(t, test.have, unpackBits(buf.Bytes()))
+		})
+	}
+}
+
+func TestImage(t *testing.T) {
+	q, err := qrcode.New("hello", qrcode.Medium)
+	require.NoError(t, err)
+	n := len(q.Bitmap())
+
+	img, dpi, err := Image(q, WithResolution(300), WithSize(25.4))
+	require.NoError(t, err)
+	assert.Equal(t, 300, dpi)
+	module := img.Rect.Dx() / n
+	assert.Equal(t, n*module, img.Rect.Dx())
+	assert.InDelta(t, 300, img.Rect.Dx(), float64(n))
+
+	// The finder pattern in the top left corner is dark, pure K.
+	assert.Equal(t, color.CMYK{K: 0xff}, img.CMYKAt(4*module, 4*module))
+	assert.Equal(t, color.CMYK{}, img.CMYKAt(0, 0))
+
+	_, _, err = Image(q, WithResolution(0))
+	assert.Error(t, err)
+}
+
+func TestEncode(t *testing.T) {
+	p := payqr.New("5402-9681", "Test AB", "", "52250047157", 50, time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC))
+
+	var buf bytes.Buffer
+	require.NoError(t, EncodePayment(&buf, p, WithResolution(600)))
+	b := buf.Bytes()
+	require.Equal(t, "II*\x00", string(b[:4]))
+
+	le := binary.LittleEndian
+	ifd := le.Uint32(b[4:])
+	tags := map[uint16]uint32{}
+	for i := 0; i < int(le.Uint16(b[ifd:])); i++ {
+		e := b[ifd+2+uint32(i)*12:]
+		value := le.Uint32(e[8:])
+		if le.Uint16(e[2:]) == typeShort && le.Uint32(e[4:]) == 1 {
+			value = uint32(le.Uint16(e[8:]))
+		}
+		tags[le.Uint16(e)] = value
+	}
+
+	assert.Equal(t, uint32(photometricSeparated), tags[tagPhotometricInterpretation])
+	assert.Equal(t, uint32(4), tags[tagSamplesPerPixel])
+	assert.Equal(t, uint32(inkSetCMYK), tags[tagInkSet])
+	assert.Equal(t, uint32(600), le.Uint32(b[tags[tagXResolution]:]))
+
+	width, height := tags[tagImageWidth], tags[tagImageLength]
+	strip := b[tags[tagStripOffsets] : tags[tagStripOffsets]+tags[tagStripByteCounts]]
+	pixels := unpackBits(strip)
+	require.Len(t, pixels, int(width*height*4))
+
+	// Only the K channel is used.
+	for i := 0; i < len(pixels); i += 4 {
+		require.Equal(t, []byte{0, 0, 0}, pixels[i:i+3])
+	}
+}
@@ -0,0 +1,667 @@
+package tinyswish
+
+// QuietZone is the width in modules of the light border to draw around a
+// code, as go-qrcode draws it.
+const QuietZone = 4
+
+// The largest code encoded is version 13 at level H, of 69x69 modules and
+// 244 data codewords in 12 blocks of 24 error correction codewords each.
+const (
+	maxVersion       = 13
+	maxSize          = 17 + 4*maxVersion
+	matrixBytes      = (maxSize*maxSize + 7) / 8
+	maxDataCodewords = 244
+	maxECCodewords   = 288
+	maxBlocks        = 12
+	maxECPerBlock    = 28
+)
+
+// block is a group of blocks of the same size in a version.
+type block struct {
+	n         int // Number of blocks.
+	codewords int // Codewords in each block, data and error correction.
+	data      int // Data codewords in each block.
+}
+
+// version is a QR code version at error correction level H.
+type version struct {
+	blocks    [2]block
+	remainder int // Bits after the last codeword.
+	alignment [3]int
+}
+
+var versions = [maxVersion + 1]version{
+	1:  {blocks: [2]block{{1, 26, 13}}},
+	2:  {blocks: [2]block{{1, 44, 22}}, remainder: 7, alignment: [3]int{6, 18}},
+	3:  {blocks: [2]block{{2, 35, 17}}, remainder: 7, alignment: [3]int{6, 22}},
+	4:  {blocks: [2]block{{2, 50, 24}}, remainder: 7, alignment: [3]int{6, 26}},
+	5:  {blocks: [2]block{{2, 33, 15}, {2, 34, 16}}, remainder: 7, alignment: [3]int{6, 30}},
+	6:  {blocks: [2]block{{4, 43, 19}}, remainder: 7, alignment: [3]int{6, 34}},
+	7:  {blocks: [2]block{{2, 32, 14}, {4, 33, 15}}, alignment: [3]int{6, 22, 38}},
+	8:  {blocks: [2]block{{4, 40, 18}, {2, 41, 19}}, alignment: [3]int{6, 24, 42}},
+	9:  {blocks: [2]block{{4, 36, 16}, {4, 37, 17}}, alignment: [3]int{6, 26, 46}},
+	10: {blocks: [2]block{{6, 43, 19}, {2, 44, 20}}, alignment: [3]int{6, 28, 50}},
+	11: {blocks: [2]block{{4, 50, 22}, {4, 51, 23}}, alignment: [3]int{6, 30, 54}},
+	12: {blocks: [2]block{{4, 46, 20}, {6, 47, 21}}, alignment: [3]int{6, 32, 58}},
+	13: {blocks: [2]block{{8, 44, 20}, {4, 45, 21}}, alignment: [3]int{6, 34, 62}},
+}
+
+func (v *version) dataCodewords() int {
+	return v.blocks[0].n*v.blocks[0].data + v.blocks[1].n*v.blocks[1].data
+}
+
+// formatBits are the format information of level H with each mask, and
+// versionBits the version information of versions 7 and up.
+var (
+	formatBits  = [8]uint32{0x355f, 0x3068, 0x3f31, 0x3a06, 0x24b4, 0x2183, 0x2eda, 0x2bed}
+	versionBits = [maxVersion + 1]uint32{7: 0x07c94, 0x085bc, 0x09a99, 0x0a4d3, 0x0bbf6, 0x0c762, 0x0d847}
+)
+
+// Data modes, ordered so that a higher mode can encode the characters of
+// the lower ones.
+const (
+	modeNumeric uint8 = 1 << iota
+	modeAlphanumeric
+	modeByte
+)
+
+// segment is a run of characters encoded in the same mode.
+type segment struct {
+	mode uint8
+	n    int
+}
+
+// class is the versions sharing the same character count lengths.
+type class struct {
+	min, max  int
+	countBits [3]int // Of the numeric, alphanumeric and byte modes.
+}
+
+var classes = [2]class{
+	{min: 1, max: 9, countBits: [3]int{10, 9, 8}},
+	{min: 10, max: maxVersion, countBits: [3]int{12, 11, 16}},
+}
+
+func modeIndex(mode uint8) int {
+	switch mode {
+	case modeNumeric:
+		return 0
+	case modeAlphanumeric:
+		return 1
+	}
+
+	return 2
+}
+
+// length returns the length in bits of a segment, or -1 if the count does
+// not fit.
+func (cl *class) length(mode uint8, n int) int {
+	bits := cl.countBits[modeIndex(mode)]
+	if n > 1<<bits-1 {
+		return -1
+	}
+
+	length := 4 + bits
+	switch mode {
+	case modeNumeric:
+		length += 10 * (n / 3)
+		if n%3 != 0 {
+			length += 1 + 3*(n%3)
+		}
+	case modeAlphanumeric:
+		length += 11*(n/2) + 6*(n%2)
+	default:
+		length += 8 * n
+	}
+
+	return length
+}
+
+func charMode(b byte) uint8 {
+	switch {
+	case b >= '0' && b <= '9':
+		return modeNumeric
+	case b == ' ' || b == '$' || b == '%' || b == '*' || b == '+' || b == '-' ||
+		b == '.' || b == '/' || b == ':' || (b >= 'A' && b <= 'Z'):
+		return modeAlphanumeric
+	}
+
+	return modeByte
+}
+
+func alphanumeric(b byte) uint32 {
+	switch {
+	case b >= '0' && b <= '9':
+		return uint32(b - '0')
+	case b >= 'A' && b <= 'Z':
+		return uint32(b-'A') + 10
+	}
+
+	switch b {
+	case ' ':
+		return 36
+	case '$':
+		return 37
+	case '%':
+		return 38
+	case '*':
+		return 39
+	case '+':
+		return 40
+	case '-':
+		return 41
+	case '.':
+		return 42
+	case '/':
+		return 43
+	}
+
+	return 44 // ':'
+}
+
+// segment splits the payload in segments for the class, as go-qrcode does:
+// runs of characters of the same mode are merged with the following runs of
+// lower modes while that is shorter, or all is one segment if that is not
+// longer. It returns the number of segments and their length in bits, or -1
+// if they do not fit in the class.
+func (c *Code) segment(cl *class) (int, int) {
+	payload := c.Payload()
+
+	runs, highest := 0, uint8(0)
+	for i, b := range payload {
+		mode := charMode(b)
+		if i == 0 || mode != c.segments[runs-1].mode {
+			c.segments[runs] = segment{mode: mode}
+			runs++
+		}
+		c.segments[runs-1].n++
+		if mode > highest {
+			highest = mode
+		}
+	}
+
+	n, bits := 0, 0
+	for i := 0; i < runs; {
+		s := c.segments[i]
+
+		j := i + 1
+		for ; j < runs && c.segments[j].mode <= s.mode; j++ {
+			next := c.segments[j]
+			merged := cl.length(s.mode, s.n+next.n)
+			first, second := cl.length(s.mode, s.n), cl.length(next.mode, next.n)
+			if merged < 0 || first < 0 || second < 0 {
+				return 0, -1
+			}
+			if merged >= first+second {
+				break
+			}
+			s.n += next.n
+		}
+
+		length := cl.length(s.mode, s.n)
+		if length < 0 {
+			return 0, -1
+		}
+		c.optimised[n] = s
+		n++
+		bits += length
+		i = j
+	}
+
+	single := cl.length(highest, len(payload))
+	if single < 0 {
+		return 0, -1
+	}
+	if single <= bits {
+		c.optimised[0] = segment{mode: highest, n: len(payload)}
+		return 1, single
+	}
+
+	return n, bits
+}
+
+// bitWriter appends bits to a buffer, most significant bit first.
+type bitWriter struct {
+	buf []byte
+	n   int // Bits written.
+}
+
+func (w *bitWriter) write(v uint32, bits int) {
+	for i := bits - 1; i >= 0; i-- {
+		if w.n%8 == 0 {
+			w.buf[w.n/8] = 0
+		}
+		if v>>uint(i)&1 == 1 {
+			w.buf[w.n/8] |= 0x80 >> uint(w.n%8)
+		}
+		w.n++
+	}
+}
+
+// encode encodes the payload in the smallest version it fits in.
+func (c *Code) encode() error {
+	var (
+		cl   *class
+		n    int
+		bits = -1
+	)
+	for i := range classes {
+		cl = &classes[i]
+		if n, bits = c.segment(cl); bits < 0 {
+			continue
+		}
+
+		for v := cl.min; v <= cl.max; v++ {
+			if versions[v].dataCodewords()*8 >= bits {
+				c.version = v
+				break
+			}
+		}
+		if c.version != 0 {
+			break
+		}
+	}
+	if bits < 0 || c.version == 0 {
+		return ErrPayloadTooLong
+	}
+
+	v := &versions[c.version]
+	c.size = 17 + 4*c.version
+	capacity := v.dataCodewords()
+
+	w := bitWriter{buf: c.data[:capacity]}
+	payload := c.Payload()
+	for _, s := range c.optimised[:n] {
+		w.write(1<<uint(modeIndex(s.mode)), 4)
+		w.write(uint32(s.n), cl.countBits[modeIndex(s.mode)])
+
+		data := payload[:s.n]
+		payload = payload[s.n:]
+		switch s.mode {
+		case modeNumeric:
+			for i := 0; i < len(data); i += 3 {
+				value, used := uint32(0), 1
+				for j := i; j < len(data) && j < i+3; j++ {
+					value = value*10 + uint32(data[j]-'0')
+					used += 3
+				}
+				w.write(value, used)
+			}
+		case modeAlphanumeric:
+			for i := 0; i < len(data); i += 2 {
+				if i+1 < len(data) {
+					w.write(alphanumeric(data[i])*45+alphanumeric(data[i+1]), 11)
+				} else {
+					w.write(alphanumeric(data[i]), 6)
+				}
+			}
+		default:
+			for _, b := range data {
+				w.write(uint32(b), 8)
+			}
+		}
+	}
+
+	// Terminator, padding to a whole codeword and pad codewords.
+	terminator := capacity*8 - w.n
+	if terminator > 4 {
+		terminator = 4
+	}
+	w.write(0, terminator)
+	if w.n%8 != 0 {
+		w.write(0, 8-w.n%8)
+	}
+	for pad := uint32(0xec); w.n < capacity*8; pad ^= 0xec ^ 0x11 {
+		w.write(pad, 8)
+	}
+
+	c.interleave(v)
+	c.draw(v)
+
+	return nil
+}
+
+// interleave computes the error correction of each block and interleaves
+// the codewords of the blocks.
+func (c *Code) interleave(v *version) {
+	var (
+		dataStart, ecStart [maxBlocks]int
+		dataLen            [maxBlocks]int
+		blocks             int
+		data, ec           int
+	)
+	ecLen := v.blocks[0].codewords - v.blocks[0].data
+	for _, b := range v.blocks {
+		for i := 0; i < b.n; i++ {
+			dataStart[blocks], dataLen[blocks], ecStart[blocks] = data, b.data, ec
+			reedSolomon(c.data[data:data+b.data], c.ec[ec:ec+ecLen])
+			data += b.data
+			ec += ecLen
+			blocks++
+		}
+	}
+
+	n := 0
+	for i := 0; i < v.blocks[1].data || i < v.blocks[0].data; i++ {
+		for b := 0; b < blocks; b++ {
+			if i < dataLen[b] {
+				c.codewords[n] = c.data[dataStart[b]+i]
+				n++
+			}
+		}
+	}
+	for i := 0; i < ecLen; i++ {
+		for b := 0; b < blocks; b++ {
+			c.codewords[n] = c.ec[ecStart[b]+i]
+			n++
+		}
+	}
+}
+
+func (c *Code) get(x, y int) bool {
+	i := y*maxSize + x
+	return c.modules[i/8]&(1<<uint(i%8)) != 0
+}
+
+func (c *Code) set(x, y int, dark bool) {
+	i := y*maxSize + x
+	if dark {
+		c.modules[i/8] |= 1 << uint(i%8)
+	} else {
+		c.modules[i/8] &^= 1 << uint(i%8)
+	}
+	c.used[i/8] |= 1 << uint(i%8)
+}
+
+func (c *Code) isUsed(x, y int) bool {
+	i := y*maxSize + x
+	return c.used[i/8]&(1<<uint(i%8)) != 0
+}
+
+// draw draws the function patterns and the codewords with the mask of the
+// lowest penalty.
+func (c *Code) draw(v *version) {
+	c.used = [matrixBytes]byte{}
+
+	// Finder patterns with their separators.
+	for _, corner := range [3][2]int{{0, 0}, {c.size - 7, 0}, {0, c.size - 7}} {
+		for dy := -1; dy <= 7; dy++ {
+			for dx := -1; dx <= 7; dx++ {
+				x, y := corner[0]+dx, corner[1]+dy
+				if x < 0 || y < 0 || x >= c.size || y >= c.size {
+					continue
+				}
+				ring := dx
+				if dy < ring {
+					ring = dy
+				}
+				if 6-dx < ring {
+					ring = 6 - dx
+				}
+				if 6-dy < ring {
+					ring = 6 - dy
+				}
+				c.set(x, y, ring == 0 || ring >= 2)
+			}
+		}
+	}
+
+	// Alignment patterns, except where they would overlap the finders.
+	for _, ax := range v.alignment {
+		for _, ay := range v.alignment {
+			if ax == 0 || ay == 0 || c.isUsed(ax, ay) {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					c.set(ax+dx, ay+dy, dx == -2 || dx == 2 || dy == -2 || dy == 2 || dx == 0 && dy == 0)
+				}
+			}
+		}
+	}
+
+	// Timing patterns.
+	for i := 8; i < c.size-8; i++ {
+		c.set(i, 6, i%2 == 0)
+		c.set(6, i, i%2 == 0)
+	}
+
+	// Version information.
+	if bits := versionBits[c.version]; bits != 0 {
+		for i := 0; i < 18; i++ {
+			dark := bits>>uint(i)&1 == 1
+			c.set(i/3, c.size-11+i%3, dark)
+			c.set(c.size-11+i%3, i/3, dark)
+		}
+	}
+
+	best, penalty := 0, 0
+	for mask := 0; mask < 8; mask++ {
+		c.drawFormat(mask)
+		c.drawData(v, mask)
+		if p := c.penalty(); mask == 0 || p < penalty {
+			best, penalty = mask, p
+		}
+	}
+	c.drawFormat(best)
+	c.drawData(v, best)
+}
+
+// drawFormat draws the format information of the mask, and the dark module.
+func (c *Code) drawFormat(mask int) {
+	bits := formatBits[mask]
+	bit := func(i int) bool { return bits>>uint(i)&1 == 1 }
+
+	for i := 0; i <= 7; i++ {
+		c.set(c.size-i-1, 8, bit(i))
+	}
+	for i := 0; i <= 5; i++ {
+		c.set(8, i, bit(i))
+	}
+	c.set(8, 7, bit(6))
+	c.set(8, 8, bit(7))
+	c.set(7, 8, bit(8))
+	for i := 9; i <= 14; i++ {
+		c.set(14-i, 8, bit(i))
+	}
+	for i := 8; i <= 14; i++ {
+		c.set(8, c.size-15+i, bit(i))
+	}
+	c.set(8, c.size-8, true)
+}
+
+// drawData draws the codewords with the mask in the modules left by the
+// function patterns, in two module wide columns from the bottom right.
+func (c *Code) drawData(v *version, mask int) {
+	n := 8*(v.dataCodewords()+v.blocks[0].n*(v.blocks[0].codewords-v.blocks[0].data)+
+		v.blocks[1].n*(v.blocks[1].codewords-v.blocks[1].data)) + v.remainder
+
+	i := 0
+	for right := c.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for k := 0; k < c.size; k++ {
+			y := k
+			if upward {
+				y = c.size - 1 - k
+			}
+			for x := right; x >= right-1; x-- {
+				if c.isUsed(x, y) {
+					continue
+				}
+
+				dark := false
+				if i < n-v.remainder {
+					dark = c.codewords[i/8]&(0x80>>uint(i%8)) != 0
+				}
+				i++
+
+				// The data modules are not marked as used, so that they are
+				// drawn again with the next mask.
+				j := y*maxSize + x
+				if dark != masked(mask, x, y) {
+					c.modules[j/8] |= 1 << uint(j%8)
+				} else {
+					c.modules[j/8] &^= 1 << uint(j%8)
+				}
+			}
+		}
+	}
+}
+
+// masked reports whether the mask inverts the module at column x, row y.
+func masked(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (y+x)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (y+x)%3 == 0
+	case 4:
+		return (y/2+x/3)%2 == 0
+	case 5:
+		return (y*x)%2+(y*x)%3 == 0
+	case 6:
+		return ((y*x)%2+(y*x)%3)%2 == 0
+	}
+
+	return ((y+x)%2+(y*x)%3)%2 == 0
+}
+
+// penalty returns the penalty score of the modules, computed as go-qrcode
+// computes it so that the same mask is chosen.
+func (c *Code) penalty() int {
+	penalty := 0
+
+	// Runs of the same color in rows and columns.
+	for a := 0; a < c.size; a++ {
+		for _, column := range [2]bool{true, false} {
+			last, count := c.at(a, 0, column), 1
+			for b := 1; b < c.size; b++ {
+				v := c.at(a, b, column)
+				if v != last {
+					last, count = v, 1
+					continue
+				}
+				count++
+				if count == 6 {
+					penalty += 3 + 1
+				} else if count > 6 {
+					penalty++
+				}
+			}
+		}
+	}
+
+	// Blocks of 2x2 modules of the same color.
+	for y := 1; y < c.size; y++ {
+		for x := 1; x < c.size; x++ {
+			v := c.get(x, y)
+			if v == c.get(x-1, y) && v == c.get(x, y-1) && v == c.get(x-1, y-1) {
+				penalty += 3
+			}
+		}
+	}
+
+	// Patterns like the finders in rows and columns.
+	for _, column := range [2]bool{false, true} {
+		for a := 0; a < c.size; a++ {
+			var buf int16
+			for b := 0; b < c.size; b++ {
+				buf <<= 1
+				if c.at(b, a, !column) {
+					buf |= 1
+				}
+				if p := buf & 0x7ff; p == 0x05d || p == 0x5d0 || b == c.size-1 && buf&0x7f == 0x5d {
+					penalty += 40
+					buf = 0xff
+				}
+			}
+		}
+	}
+
+	// Balance of dark and light modules.
+	modules, dark := c.size*c.size, 0
+	for y := 0; y < c.size; y++ {
+		for x := 0; x < c.size; x++ {
+			if c.get(x, y) {
+				dark++
+			}
+		}
+	}
+	deviation := modules/2 - dark
+	if deviation < 0 {
+		deviation = -deviation
+	}
+
+	return penalty + 10*(deviation/(modules/20))
+}
+
+// at returns the module at a, b, with a the column and b the row if column
+// is set, and the other way around otherwise.
+func (c *Code) at(a, b int, column bool) bool {
+	if column {
+		return c.get(a, b)
+	}
+
+	return c.get(b, a)
+}
+
+// Arithmetic in GF(256) with the QR code polynomial.
+var gfExp, gfLog = gfTables()
+
+func gfTables() (exp [512]byte, log [256]byte) {
+	x := 1
+	for i := 0; i < 255; i++ {
+		exp[i], exp[i+255] = byte(x), byte(x)
+		log[x] = byte(i)
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11d
+		}
+	}
+
+	return exp, log
+}
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+
+	return gfExp[int(gfLog[a])+int(gfLog[b])]
+}
+
+// reedSolomon computes the error correction codewords of the data into ec.
+func reedSolomon(data, ec []byte) {
+	// The generator polynomial is the product of x - a^i for i below the
+	// number of codewords, with the coefficient of its highest term left out.
+	var generator [maxECPerBlock]byte
+	g := generator[:len(ec)]
+	g[len(g)-1] = 1
+	for i := 0; i < len(g); i++ {
+		for j := 0; j < len(g); j++ {
+			g[j] = gfMul(g[j], gfExp[i])
+			if j+1 < len(g) {
+				g[j] ^= g[j+1]
+			}
+		}
+	}
+
+	for i := range ec {
+		ec[i] = 0
+	}
+	for _, d := range data {
+		factor := d ^ ec[0]
+		copy(ec, ec[1:])
+		ec[len(ec)-1] = 0
+		for j := range ec {
+			ec[j] ^= gfMul(g[j], factor)
+		}
+	}
+}
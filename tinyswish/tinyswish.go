@@ -0,0 +1,181 @@
+// Package tinyswish renders Swish QR codes on constrained devices, such as
+// payment terminals running TinyGo on an ESP32. It does not depend on the
+// payqr package, go-qrcode, encoding/json or fmt, and encodes into fixed
+// buffers of a Code, so that a terminal can keep one Code in static memory
+// and draw each payment without allocating.
+//
+// The output is the matrix of modules only, which the device draws on its
+// display. The codes are the same as those of payqr.SwishPayment.QR: the
+// payload has the same format and is encoded in the same segments, version
+// and mask with error correction level H.
+package tinyswish
+
+import (
+	"errors"
+	"strconv"
+)
+
+const (
+	// MinAmount and MaxAmount are the amounts accepted by Swish, as
+	// payqr.SwishMinAmount and payqr.SwishMaxAmount.
+	MinAmount = 1.00
+	MaxAmount = 999999999999.99
+
+	// MaxPayload is the longest payload in bytes that is encoded.
+	MaxPayload = 241
+)
+
+// Editable fields of a payment, as payqr.SwishEditableField.
+const (
+	PhoneEditable   uint8 = 0b001
+	AmountEditable  uint8 = 0b010
+	MessageEditable uint8 = 0b100
+)
+
+var (
+	ErrEditable       = errors.New("tinyswish: editable fields contains unknown fields")
+	ErrPayeeLocked    = errors.New("tinyswish: phone number is locked but empty")
+	ErrMessageLocked  = errors.New("tinyswish: message is locked but empty")
+	ErrAmountOpen     = errors.New("tinyswish: amount is open but set")
+	ErrAmountLocked   = errors.New("tinyswish: amount is locked but zero")
+	ErrAmountRange    = errors.New("tinyswish: amount is out of range")
+	ErrPayloadTooLong = errors.New("tinyswish: payload is too long")
+)
+
+// Payment is a Swish payment, as payqr.SwishPayment.
+type Payment struct {
+	Payee   string  // Phone or Swish number of the receiving party.
+	Amount  float64 // Amount in SEK.
+	Message string
+
+	// Editable is the fields the user may edit in their app.
+	Editable uint8
+
+	// OpenAmount omits the amount from the payload, leaving it for the
+	// user to fill in. The amount is made editable.
+	OpenAmount bool
+}
+
+// Validate checks the payment as payqr.SwishPayment.Validate does with the
+// default amount limits.
+func (p *Payment) Validate() error {
+	editable := p.editable()
+	if editable&^(PhoneEditable|AmountEditable|MessageEditable) != 0 {
+		return ErrEditable
+	}
+	if p.Payee == "" && editable&PhoneEditable == 0 {
+		return ErrPayeeLocked
+	}
+	if p.Message == "" && editable&MessageEditable == 0 {
+		return ErrMessageLocked
+	}
+	if p.OpenAmount && p.Amount != 0 {
+		return ErrAmountOpen
+	}
+	if p.Amount == 0 {
+		if editable&AmountEditable == 0 {
+			return ErrAmountLocked
+		}
+
+		return nil
+	}
+	if !(p.Amount >= MinAmount && p.Amount <= MaxAmount) {
+		return ErrAmountRange
+	}
+
+	return nil
+}
+
+func (p *Payment) editable() uint8 {
+	if p.OpenAmount {
+		return p.Editable | AmountEditable
+	}
+
+	return p.Editable
+}
+
+// appendPayload appends the payload of the payment, as
+// payqr.SwishPayment.Encode, to dst if it fits in its capacity.
+func (p *Payment) appendPayload(dst []byte) ([]byte, error) {
+	var amount [32]byte
+	a := amount[:0]
+	if !p.OpenAmount {
+		a = strconv.AppendFloat(a, p.Amount, 'f', 2, 64)
+	}
+
+	if len(dst)+len(p.Payee)+len(a)+len(p.Message)+5 > cap(dst) {
+		return dst, ErrPayloadTooLong
+	}
+
+	dst = append(dst, 'C')
+	dst = append(dst, p.Payee...)
+	dst = append(dst, ';')
+	dst = append(dst, a...)
+	dst = append(dst, ';')
+	dst = append(dst, p.Message...)
+	dst = append(dst, ';', '0'+p.editable())
+
+	return dst, nil
+}
+
+// Code is the QR code of a payment, along with the buffers used to encode it.
+// The zero value is ready to be encoded into, and a Code can be reused for
+// any number of payments.
+type Code struct {
+	payload [MaxPayload]byte
+	n       int
+
+	version int
+	size    int
+	modules [matrixBytes]byte
+	used    [matrixBytes]byte
+
+	segments  [MaxPayload]segment
+	optimised [MaxPayload]segment
+	data      [maxDataCodewords]byte
+	ec        [maxECCodewords]byte
+	codewords [maxDataCodewords + maxECCodewords]byte
+}
+
+// Encode validates the payment and encodes its QR code.
+func (c *Code) Encode(p *Payment) error {
+	c.n, c.version, c.size = 0, 0, 0
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	payload, err := p.appendPayload(c.payload[:0])
+	if err != nil {
+		return err
+	}
+	c.n = len(payload)
+
+	return c.encode()
+}
+
+// Payload returns the encoded payload. It is only valid until the next
+// call to Encode.
+func (c *Code) Payload() []byte {
+	return c.payload[:c.n]
+}
+
+// Version returns the QR code version, from 1 to 13.
+func (c *Code) Version() int {
+	return c.version
+}
+
+// Size returns the width and height of the code in modules, without a quiet
+// zone.
+func (c *Code) Size() int {
+	return c.size
+}
+
+// Get reports whether the module at x, y from the top left corner is dark.
+// The caller draws a quiet zone of QuietZone light modules around the code.
+func (c *Code) Get(x, y int) bool {
+	if x < 0 || y < 0 || x >= c.size || y >= c.size {
+		return false
+	}
+
+	return c.get(x, y)
+}
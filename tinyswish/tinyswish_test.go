@@ -0,0 +1,158 @@
+package tinyswish
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/skip2/go-qrcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name string
+		have Payment
+		want *payqr.SwishPayment
+	}{
+		{
+			name: "Locked payment",
+			have: Payment{Payee: "1231111111", Amount: 50, Message: "My message"},
+			want: payqr.NewSwish("1231111111", 50, "My message"),
+		},
+		{
+			name: "Editable message",
+			have: Payment{Payee: "1231111111", Amount: 100.5, Editable: MessageEditable},
+			want: payqr.NewSwish("1231111111", 100.5, "", payqr.WithEditableFields(payqr.SwishMessageEditable)),
+		},
+		{
+			name: "Open amount",
+			have: Payment{Payee: "1231111111", Message: "Tips", OpenAmount: true},
+			want: payqr.NewSwish("1231111111", 0, "Tips", payqr.WithOpenAmount()),
+		},
+		{
+			name: "Numeric message",
+			have: Payment{Payee: "1231111111", Amount: 1250, Message: "52250047157123456789012345"},
+			want: payqr.NewSwish("1231111111", 1250, "52250047157123456789012345"),
+		},
+		{
+			name: "Alphanumeric message",
+			have: Payment{Payee: "1231111111", Amount: 99.99, Message: "ORDER 1234 KASSA 2"},
+			want: payqr.NewSwish("1231111111", 99.99, "ORDER 1234 KASSA 2"),
+		},
+		{
+			name: "Message with UTF-8",
+			have: Payment{Payee: "1231111111", Amount: 10, Message: "Räksmörgås, två st"},
+			want: payqr.NewSwish("1231111111", 10, "Räksmörgås, två st"),
+		},
+		{
+			name: "Long message",
+			have: Payment{Payee: "1231111111", Amount: 999999999999.99, Message: strings.Repeat("Fika och bulle ", 10)},
+			want: payqr.NewSwish("1231111111", 999999999999.99, strings.Repeat("Fika och bulle ", 10)),
+		},
+		{
+			name: "Longest payload",
+			have: Payment{Payee: "1231111111", Amount: 1, Message: strings.Repeat("x", MaxPayload-19)},
+			want: payqr.NewSwish("1231111111", 1, strings.Repeat("x", MaxPayload-19)),
+		},
+	}
+
+	var c Code
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.NoError(t, c.Encode(&test.have))
+			assert.Equal(t, test.want.Encode(), string(c.Payload()))
+
+			q, err := qrcode.New(test.want.Encode(), qrcode.High)
+			require.NoError(t, err)
+			assert.Equal(t, q.VersionNumber, c.Version())
+
+			bitmap := q.Bitmap()
+			require.Len(t, bitmap, c.Size()+2*QuietZone)
+			for y := -QuietZone; y < c.Size()+QuietZone; y++ {
+				for x := -QuietZone; x < c.Size()+QuietZone; x++ {
+					if bitmap[y+QuietZone][x+QuietZone] != c.Get(x, y) {
+						t.Fatalf("module %d, %d differs from go-qrcode", x, y)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestEncodeInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		have Payment
+		want error
+	}{
+		{
+			name: "Unknown editable field",
+			have: Payment{Payee: "1231111111", Amount: 50, Message: "My message", Editable: 0b1000},
+			want: ErrEditable,
+		},
+		{
+			name: "Locked empty payee",
+			have: Payment{Amount: 50, Message: "My message"},
+			want: ErrPayeeLocked,
+		},
+		{
+			name: "Locked empty message",
+			have: Payment{Payee: "1231111111", Amount: 50},
+			want: ErrMessageLocked,
+		},
+		{
+			name: "Open amount with amount",
+			have: Payment{Payee: "1231111111", Amount: 50, Message: "My message", OpenAmount: true},
+			want: ErrAmountOpen,
+		},
+		{
+			name: "Locked zero amount",
+			have: Payment{Payee: "1231111111", Message: "My message"},
+			want: ErrAmountLocked,
+		},
+		{
+			name: "Below minimum",
+			have: Payment{Payee: "1231111111", Amount: 0.5, Message: "My message"},
+			want: ErrAmountRange,
+		},
+		{
+			name: "Too long",
+			have: Payment{Payee: "1231111111", Amount: 1, Message: strings.Repeat("x", MaxPayload-18)},
+			want: ErrPayloadTooLong,
+		},
+	}
+
+	var c Code
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, c.Encode(&test.have))
+			assert.Equal(t, 0, c.Size())
+		})
+	}
+}
+
+func TestEncodeAllocs(t *testing.T) {
+	var c Code
+	p := &Payment{Payee: "1231111111", Amount: 50, Message: "My message"}
+
+	allocs := testing.AllocsPerRun(10, func() {
+		if err := c.Encode(p); err != nil {
+			t.Fatal(err)
+		}
+	})
+	assert.Zero(t, allocs)
+}
+
+func BenchmarkEncode(b *testing.B) {
+	var c Code
+	p := &Payment{Payee: "1231111111", Amount: 50, Message: "My message"}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := c.Encode(p); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !payqr_payloadonly
+
+package twint
+
+import (
+	"github.com/antonlindstrom/payqr"
+	"github.com/skip2/go-qrcode"
+)
+
+// QR returns the Swiss QR Code of the payment. The format mandates error
+// correction level M.
+func QR(p *payqr.Payment, options ...Option) (*qrcode.QRCode, error) {
+	payload, err := Encode(p, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return qrcode.New(payload, qrcode.Medium)
+}
@@ -0,0 +1,27 @@
+//go:build !payqr_payloadonly
+
+package twint
+
+import (
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQR(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+	address := WithCreditorAddress(Address{Street: "Musterstrasse", BuildingNumber: "1", PostalCode: "8000", Town: "Zürich"})
+	p := payqr.New("CH44 3199 9123 0008 8901 2", "Robert Schneider AG", "", "210000000003139471430009017", 1949.75, due)
+
+	q, err := QR(p, address)
+	require.NoError(t, err)
+	want, err := Encode(p, address)
+	require.NoError(t, err)
+	assert.Equal(t, want, q.Content)
+
+	_, err = QR(p)
+	assert.Error(t, err)
+}
@@ -0,0 +1,182 @@
+// Package twint encodes Swiss payments as the Swiss QR Code payload, which the
+// TWINT app and Swiss banking apps scan to pay. Only the code is produced, not
+// the payment part and receipt of a full QR-bill, and the Swiss cross should
+// be drawn over the center of the code when it is printed.
+package twint
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/antonlindstrom/payqr/epc"
+)
+
+// Reference types of the payload.
+const (
+	ReferenceQR       = "QRR"
+	ReferenceCreditor = "SCOR"
+	ReferenceNone     = "NON"
+)
+
+// Address is a structured address.
+type Address struct {
+	Street         string
+	BuildingNumber string
+	PostalCode     string
+	Town           string
+	Country        string // ISO 3166-1 alpha-2, default is the country of the IBAN.
+}
+
+// Option is a modifier for the encoding.
+type Option func(*config)
+
+type config struct {
+	creditor Address
+	message  string
+}
+
+// WithCreditorAddress sets the address of the creditor, the town and postal
+// code are required.
+func WithCreditorAddress(address Address) Option {
+	return func(c *config) {
+		c.creditor = address
+	}
+}
+
+// WithMessage sets the unstructured message shown to the payer. References
+// that are neither QR nor creditor references are used as message by default.
+func WithMessage(message string) Option {
+	return func(c *config) {
+		c.message = message
+	}
+}
+
+// Encode returns the Swiss QR Code payload of the payment to a Swiss or
+// Liechtenstein IBAN, in CHF or EUR. A zero amount lets the payer enter it.
+// A 27 digit QR reference requires a QR-IBAN, an RF creditor reference a
+// regular IBAN.
+func Encode(p *payqr.Payment, options ...Option) (string, error) {
+	c := &config{}
+	for _, opt := range options {
+		opt(c)
+	}
+
+	iban := strings.ToUpper(strings.ReplaceAll(p.AccountNumber, " ", ""))
+	if !strings.HasPrefix(iban, "CH") && !strings.HasPrefix(iban, "LI") {
+		return "", fmt.Errorf("twint: account must be a Swiss or Liechtenstein IBAN")
+	}
+	if err := payqr.ValidateAccount(iban[:2], payqr.PaymentTypeIBAN, iban); err != nil {
+		return "", fmt.Errorf("twint: %w", err)
+	}
+
+	currency := p.Currency
+	if currency == "" {
+		currency = "CHF"
+	}
+	if currency != "CHF" && currency != "EUR" {
+		return "", fmt.Errorf("twint: currency must be CHF or EUR, got %s", currency)
+	}
+
+	var amount string
+	if p.DueAmount != 0 {
+		if p.DueAmount < 0.01 || p.DueAmount > 999999999.99 {
+			return "", fmt.Errorf("twint: amount %.2f must be between 0.01 and 999999999.99", p.DueAmount)
+		}
+		amount = strconv.FormatFloat(p.DueAmount, 'f', 2, 64)
+	}
+
+	refType, ref, message := ReferenceNone, "", c.message
+	qrIBAN := isQRIBAN(iban)
+	switch reference := strings.ReplaceAll(p.Reference, " ", ""); {
+	case epc.IsCreditorReference(reference):
+		refType, ref = ReferenceCreditor, strings.ToUpper(reference)
+	case len(reference) == 27 && isDigits(reference):
+		if QRReferenceCheckDigit(reference[:26]) != reference[26:] {
+			return "", fmt.Errorf("twint: QR reference %s has invalid check digit", reference)
+		}
+		refType, ref = ReferenceQR, reference
+	case message == "":
+		message = p.Reference
+	}
+	if qrIBAN != (refType == ReferenceQR) {
+		return "", fmt.Errorf("twint: QR references must be used with, and only with, a QR-IBAN")
+	}
+
+	creditor := c.creditor
+	if creditor.Country == "" {
+		creditor.Country = iban[:2]
+	}
+
+	fields := []struct {
+		name  string
+		value string
+		max   int
+	}{
+		{"name", p.AccountName, 70},
+		{"street", creditor.Street, 70},
+		{"building number", creditor.BuildingNumber, 16},
+		{"postal code", creditor.PostalCode, 16},
+		{"town", creditor.Town, 35},
+		{"message", message, 140},
+	}
+	for _, f := range fields {
+		if utf8.RuneCountInString(f.value) > f.max {
+			return "", fmt.Errorf("twint: %s is longer than %d characters", f.name, f.max)
+		}
+	}
+	if p.AccountName == "" || creditor.PostalCode == "" || creditor.Town == "" {
+		return "", fmt.Errorf("twint: creditor name, postal code and town are required")
+	}
+
+	lines := []string{
+		"SPC", "0200", "1",
+		iban,
+		"S", p.AccountName, creditor.Street, creditor.BuildingNumber, creditor.PostalCode, creditor.Town, creditor.Country,
+		"", "", "", "", "", "", "", // Ultimate creditor, reserved.
+		amount, currency,
+		"", "", "", "", "", "", "", // Ultimate debtor.
+		refType, ref,
+		message,
+		"EPD",
+	}
+
+	return strings.Join(lines, "\r\n"), nil
+}
+
+// QRReferenceCheckDigit returns the check digit of a QR reference, using
+// recursive mod 10.
+func QRReferenceCheckDigit(digits string) string {
+	table := [10]int{0, 9, 4, 6, 8, 2, 7, 1, 3, 5}
+
+	carry := 0
+	for i := 0; i < len(digits); i++ {
+		carry = table[(carry+int(digits[i]-'0'))%10]
+	}
+
+	return strconv.Itoa((10 - carry) % 10)
+}
+
+// isQRIBAN reports whether the IBAN is a QR-IBAN, with an institution ID
+// between 30000 and 31999.
+func isQRIBAN(iban string) bool {
+	if len(iban) < 9 {
+		return false
+	}
+
+	iid, err := strconv.Atoi(iban[4:9])
+
+	return err == nil && iid >= 30000 && iid <= 31999
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
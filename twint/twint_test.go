@@ -0,0 +1,96 @@
+package twint
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+	address := WithCreditorAddress(Address{Street: "Musterstrasse", BuildingNumber: "1", PostalCode: "8000", Town: "Zürich"})
+
+	tests := []struct {
+		name    string
+		have    *payqr.Payment
+		options []Option
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "QR reference",
+			have:    payqr.New("CH44 3199 9123 0008 8901 2", "Robert Schneider AG", "", "210000000003139471430009017", 1949.75, due),
+			options: []Option{address},
+			want: []string{
+				"SPC", "0200", "1", "CH4431999123000889012",
+				"S", "Robert Schneider AG", "Musterstrasse", "1", "8000", "Zürich", "CH",
+				"", "", "", "", "", "", "",
+				"1949.75", "CHF",
+				"", "", "", "", "", "", "",
+				"QRR", "210000000003139471430009017", "", "EPD",
+			},
+		},
+		{
+			name:    "Message without amount",
+			have:    payqr.New("CH9300762011623852957", "Verein", "", "Spende", 0, due, payqr.WithCurrency("EUR")),
+			options: []Option{address},
+			want: []string{
+				"SPC", "0200", "1", "CH9300762011623852957",
+				"S", "Verein", "Musterstrasse", "1", "8000", "Zürich", "CH",
+				"", "", "", "", "", "", "",
+				"", "EUR",
+				"", "", "", "", "", "", "",
+				"NON", "", "Spende", "EPD",
+			},
+		},
+		{
+			name:    "QR reference with regular IBAN",
+			have:    payqr.New("CH9300762011623852957", "Verein", "", "210000000003139471430009017", 10, due),
+			options: []Option{address},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid QR reference",
+			have:    payqr.New("CH4431999123000889012", "Robert Schneider AG", "", "210000000003139471430009018", 10, due),
+			options: []Option{address},
+			wantErr: true,
+		},
+		{
+			name:    "Missing address",
+			have:    payqr.New("CH9300762011623852957", "Verein", "", "Spende", 10, due),
+			wantErr: true,
+		},
+		{
+			name:    "German IBAN",
+			have:    payqr.New("DE89370400440532013000", "Verein", "", "Spende", 10, due),
+			options: []Option{address},
+			wantErr: true,
+		},
+		{
+			name:    "SEK",
+			have:    payqr.New("CH9300762011623852957", "Verein", "", "Spende", 10, due, payqr.WithCurrency("SEK")),
+			options: []Option{address},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Encode(test.have, test.options...)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, strings.Split(got, "\r\n"))
+		})
+	}
+}
+
+func TestQRReferenceCheckDigit(t *testing.T) {
+	assert.Equal(t, "7", QRReferenceCheckDigit("21000000000313947143000901"))
+}
@@ -0,0 +1,69 @@
+package payqr
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// payloadFields are the fields of a payment, without its methods, to decode
+// with the struct tags.
+type payloadFields Payment
+
+// ParsePayload parses the payload of the QR code of a payment, as given by
+// Payload. Unlike UnmarshalJSON it returns an error for unknown fields and
+// trailing data, which are ignored by bank apps but often are typos or bugs
+// of the producing system.
+func ParsePayload(payload string) (*Payment, error) {
+	d := &Payment{}
+	if err := d.decode([]byte(payload), true); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// UnmarshalJSON decodes a payload, as given by MarshalJSON. Unknown fields are
+// ignored, as by bank apps, but the dates must be as yyyymmdd.
+func (d *Payment) UnmarshalJSON(b []byte) error {
+	return d.decode(b, false)
+}
+
+// decode decodes the payload into d, rejecting unknown fields and trailing
+// data if strict.
+func (d *Payment) decode(b []byte, strict bool) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	p := payloadFields(*d)
+	if err := dec.Decode(&p); err != nil {
+		return err
+	}
+	if strict {
+		if _, err := dec.Token(); err != io.EOF {
+			return fmt.Errorf("unexpected data after the payload")
+		}
+	}
+
+	extra, err := decodeExtraFields(b, strict)
+	if err != nil {
+		return err
+	}
+	if len(extra) > 0 {
+		fields := d.ExtraFields()
+		for k, v := range extra {
+			fields[k] = v
+		}
+		p.extra = fields
+	}
+
+	for _, date := range []struct{ key, value string }{{"idt", p.CreatedDate}, {"ddt", p.DueDate}} {
+		if _, err := time.Parse("20060102", date.value); date.value != "" && err != nil {
+			return invalid(date.key, ErrInvalidDate, "field %s %q is not a date as yyyymmdd", date.key, date.value)
+		}
+	}
+
+	*d = Payment(p)
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package payqr
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePayload(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name    string
+		have    string
+		want    *Payment
+		wantErr string
+	}{
+		{
+			name: "Payload",
+			have: `{"uqr":1,"tp":1,"nme":"Test AB","cid":"5555555555","iref":"52250047157","idt":"20220806","ddt":"20220806","due":1250.5,"pt":"BG","acc":"5402-9681","adr":"Storgatan 1"}`,
+			want: New("5402-9681", "Test AB", "5555555555", "52250047157", 1250.5, due, WithCreationDate(due), WithAddress("Storgatan 1")),
+		},
+		{
+			name:    "Unknown field",
+			have:    `{"uqr":1,"tp":1,"nme":"Test AB","amount":50}`,
+			wantErr: `json: unknown field "amount"`,
+		},
+		{
+			name:    "Trailing data",
+			have:    `{"uqr":1,"tp":1,"nme":"Test AB"} {}`,
+			wantErr: "unexpected data after the payload",
+		},
+		{
+			name:    "Date with dashes",
+			have:    `{"uqr":1,"tp":1,"nme":"Test AB","idt":"20220806","ddt":"2022-08-06"}`,
+			wantErr: `field ddt "2022-08-06" is not a date as yyyymmdd`,
+		},
+		{
+			name:    "Invalid date",
+			have:    `{"uqr":1,"tp":1,"nme":"Test AB","idt":"20221306"}`,
+			wantErr: `field idt "20221306" is not a date as yyyymmdd`,
+		},
+		{
+			name:    "Not JSON",
+			have:    `uqr=1`,
+			wantErr: "invalid character 'u' looking for beginning of value",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			have, err := ParsePayload(test.have)
+			if test.wantErr != "" {
+				assert.EqualError(t, err, test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, have)
+		})
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	var d Payment
+	require.NoError(t, json.Unmarshal([]byte(`{"uqr":1,"tp":1,"nme":"Test AB","ddt":"20220806","amount":50}`), &d))
+	assert.Equal(t, Payment{UsingQRVersion: 1, Type: InvoiceType, AccountName: "Test AB", DueDate: "20220806"}, d)
+
+	d = *New("5402-9681", "Test AB", "5555555555", "52250047157", 50, time.Now(), WithReferenceScheme(ReferenceOCR))
+	require.NoError(t, json.Unmarshal([]byte(`{"iref":"1001"}`), &d))
+	assert.Equal(t, "1001", d.Reference)
+	assert.Equal(t, "Test AB", d.AccountName)
+	assert.Equal(t, ReferenceOCR, d.ReferenceScheme())
+
+	var payments []Payment
+	err := json.Unmarshal([]byte(`[{"ddt":"6 aug 2022"}]`), &payments)
+	assert.EqualError(t, err, `field ddt "6 aug 2022" is not a date as yyyymmdd`)
+}
@@ -0,0 +1,18 @@
+//go:build !payqr_payloadonly
+
+package upi
+
+import (
+	"github.com/antonlindstrom/payqr"
+	"github.com/skip2/go-qrcode"
+)
+
+// QR returns a QR code of the UPI deep link of the payment.
+func QR(p *payqr.Payment, options ...Option) (*qrcode.QRCode, error) {
+	uri, err := URI(p, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return qrcode.New(uri, qrcode.Medium)
+}
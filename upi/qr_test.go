@@ -0,0 +1,26 @@
+//go:build !payqr_payloadonly
+
+package upi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQR(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+	p := payqr.New("acme.shop@okaxis", "Acme & Sons", "", "INV-1001", 1499.5, due, payqr.WithCurrency("INR"))
+
+	q, err := QR(p, WithNote("Invoice 1001"))
+	require.NoError(t, err)
+	want, err := URI(p, WithNote("Invoice 1001"))
+	require.NoError(t, err)
+	assert.Equal(t, want, q.Content)
+
+	_, err = QR(payqr.New("acme.shop@okaxis", "Acme & Sons", "", "INV-1001", 1499.5, due, payqr.WithCurrency("SEK")))
+	assert.Error(t, err)
+}
@@ -0,0 +1,102 @@
+// Package upi encodes payments as Indian UPI deep links, upi://pay URIs that
+// UPI apps open when the QR code is scanned.
+package upi
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/antonlindstrom/payqr"
+)
+
+// Currency is the currency of UPI payments.
+const Currency = "INR"
+
+// Option is a modifier for the URI.
+type Option func(*config)
+
+type config struct {
+	note         string
+	merchantCode string
+}
+
+// WithNote sets the transaction note (tn) shown to the payer.
+func WithNote(note string) Option {
+	return func(c *config) {
+		c.note = note
+	}
+}
+
+// WithMerchantCode sets the merchant category code (mc) of the payee.
+func WithMerchantCode(code string) Option {
+	return func(c *config) {
+		c.merchantCode = code
+	}
+}
+
+var vpa = regexp.MustCompile(`^[A-Za-z0-9._-]{2,256}@[A-Za-z][A-Za-z0-9]{1,63}$`)
+
+// ValidateVPA checks that the virtual payment address has the format
+// name@handle, such as "merchant@okaxis".
+func ValidateVPA(address string) error {
+	if !vpa.MatchString(address) {
+		return fmt.Errorf("upi: %q is not a valid VPA", address)
+	}
+
+	return nil
+}
+
+// URI returns the UPI deep link of the payment. The account number is the VPA
+// of the payee and the reference is the transaction reference (tr). A zero
+// amount lets the payer enter the amount.
+func URI(p *payqr.Payment, options ...Option) (string, error) {
+	c := &config{}
+	for _, opt := range options {
+		opt(c)
+	}
+
+	if p.Currency != "" && !strings.EqualFold(p.Currency, Currency) {
+		return "", fmt.Errorf("upi: currency must be %s, got %s", Currency, p.Currency)
+	}
+	if err := ValidateVPA(p.AccountNumber); err != nil {
+		return "", err
+	}
+	if p.AccountName == "" {
+		return "", fmt.Errorf("upi: name is required")
+	}
+	if p.DueAmount < 0 {
+		return "", fmt.Errorf("upi: amount %.2f is negative", p.DueAmount)
+	}
+
+	// The parameters are kept in the order UPI apps document rather than
+	// sorted as by url.Values.
+	params := []struct{ key, value string }{
+		{"pa", p.AccountNumber},
+		{"pn", p.AccountName},
+		{"mc", c.merchantCode},
+		{"tr", p.Reference},
+		{"tn", c.note},
+		{"am", ""},
+		{"cu", Currency},
+	}
+	if p.DueAmount > 0 {
+		params[5].value = fmt.Sprintf("%.2f", p.DueAmount)
+	}
+
+	query := make([]string, 0, len(params))
+	for _, param := range params {
+		if param.value != "" {
+			query = append(query, param.key+"="+escape(param.value))
+		}
+	}
+
+	return "upi://pay?" + strings.Join(query, "&"), nil
+}
+
+// escape query escapes the value with spaces as %20, as some UPI apps show
+// "+" literally, and keeps the "@" of addresses.
+func escape(s string) string {
+	return strings.NewReplacer("+", "%20", "%40", "@").Replace(url.QueryEscape(s))
+}
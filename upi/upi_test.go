@@ -0,0 +1,61 @@
+package upi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestURI(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		have    *payqr.Payment
+		options []Option
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "Full",
+			have:    payqr.New("acme.shop@okaxis", "Acme & Sons", "", "INV-1001", 1499.5, due, payqr.WithCurrency("INR")),
+			options: []Option{WithNote("Invoice 1001"), WithMerchantCode("5411")},
+			want:    "upi://pay?pa=acme.shop@okaxis&pn=Acme%20%26%20Sons&mc=5411&tr=INV-1001&tn=Invoice%201001&am=1499.50&cu=INR",
+		},
+		{
+			name: "Open amount",
+			have: payqr.New("acme@upi", "Acme", "", "", 0, due),
+			want: "upi://pay?pa=acme@upi&pn=Acme&cu=INR",
+		},
+		{
+			name:    "Invalid VPA",
+			have:    payqr.New("acme", "Acme", "", "", 10, due),
+			wantErr: true,
+		},
+		{
+			name:    "Missing name",
+			have:    payqr.New("acme@upi", "", "", "", 10, due),
+			wantErr: true,
+		},
+		{
+			name:    "SEK",
+			have:    payqr.New("acme@upi", "Acme", "", "", 10, due, payqr.WithCurrency("SEK")),
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := URI(test.have, test.options...)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package payqr
+
+import "math"
+
+// WithVAT sets the total VAT of the payment, the vat field.
+func WithVAT(vat Money) Option {
+	return func(p *Payment) {
+		p.VAT = vat.Float64()
+	}
+}
+
+// WithVATBreakdown sets the VAT by the high, medium and low rates, the vh, vm
+// and vl fields, and the total VAT to their sum. Validate checks that the
+// breakdown sums to the total VAT if it is set otherwise.
+func WithVATBreakdown(high, medium, low Money) Option {
+	return func(p *Payment) {
+		p.HighVAT = high.Float64()
+		p.MediumVAT = medium.Float64()
+		p.LowVAT = low.Float64()
+		p.VAT = Money{Minor: high.Minor + medium.Minor + low.Minor, Currency: high.Currency}.Float64()
+	}
+}
+
+// TotalVAT returns the total VAT of the payment in its currency.
+func (d *Payment) TotalVAT() Money {
+	return MoneyFromFloat(d.VAT, d.Currency)
+}
+
+// VATBreakdown returns the VAT of the payment by the high, medium and low
+// rates in its currency.
+func (d *Payment) VATBreakdown() (high, medium, low Money) {
+	return MoneyFromFloat(d.HighVAT, d.Currency), MoneyFromFloat(d.MediumVAT, d.Currency), MoneyFromFloat(d.LowVAT, d.Currency)
+}
+
+// validateVAT checks that the VAT amounts are numbers and that the breakdown,
+// if any, sums to the total VAT in the minor units of the currency.
+func (d *Payment) validateVAT() error {
+	for _, vat := range []struct {
+		key    string
+		amount float64
+	}{{"vat", d.VAT}, {"vh", d.HighVAT}, {"vm", d.MediumVAT}, {"vl", d.LowVAT}} {
+		if math.IsNaN(vat.amount) || math.IsInf(vat.amount, 0) {
+			return invalid(vat.key, ErrInvalidAmount, "VAT amount %s %v is not a number", vat.key, vat.amount)
+		}
+	}
+
+	high, medium, low := d.VATBreakdown()
+	if high.Minor == 0 && medium.Minor == 0 && low.Minor == 0 {
+		return nil
+	}
+	sum := Money{Minor: high.Minor + medium.Minor + low.Minor, Currency: d.Currency}
+	if total := d.TotalVAT(); sum != total {
+		return invalid("vat", ErrInvalidAmount, "VAT breakdown sums to %s, not the total VAT %s", sum, total)
+	}
+
+	return nil
+}
+
+// VATRate is a rate of VAT in percent.
+type VATRate int64
+
+// The Swedish rates of VAT, of the vh, vm and vl fields.
+const (
+	VATRateHigh   VATRate = 25 // Most goods and services.
+	VATRateMedium VATRate = 12 // Such as food and hotels.
+	VATRateLow    VATRate = 6  // Such as books and passenger transport.
+)
+
+// OfNet returns the VAT of the amount excluding VAT, rounded half away from
+// zero to the minor units of the currency.
+func (r VATRate) OfNet(net Money) Money {
+	return Money{Minor: roundDiv(net.Minor*int64(r), 100), Currency: net.Currency}
+}
+
+// OfGross returns the VAT included in the amount, rounded half away from
+// zero to the minor units of the currency.
+func (r VATRate) OfGross(gross Money) Money {
+	return Money{Minor: roundDiv(gross.Minor*int64(r), 100+int64(r)), Currency: gross.Currency}
+}
+
+// VATFromGross adds the VAT included in the gross amount at the rate to the
+// total VAT of the payment, and to the vh, vm or vl field for the Swedish
+// rates, see VATRate.OfGross. Amounts with different rates are given with an
+// option each.
+func VATFromGross(gross Money, rate VATRate) Option {
+	return withVATAt(rate, rate.OfGross(gross))
+}
+
+// VATFromNet adds the VAT of the net amount at the rate to the total VAT of
+// the payment, and to the vh, vm or vl field for the Swedish rates, see
+// VATRate.OfNet. Amounts with different rates are given with an option each.
+func VATFromNet(net Money, rate VATRate) Option {
+	return withVATAt(rate, rate.OfNet(net))
+}
+
+// withVATAt adds the VAT to the total and to the field of the rate, if any.
+func withVATAt(rate VATRate, vat Money) Option {
+	return func(p *Payment) {
+		add := func(amount *float64) {
+			*amount = Money{Minor: MoneyFromFloat(*amount, vat.Currency).Minor + vat.Minor, Currency: vat.Currency}.Float64()
+		}
+
+		add(&p.VAT)
+		switch rate {
+		case VATRateHigh:
+			add(&p.HighVAT)
+		case VATRateMedium:
+			add(&p.MediumVAT)
+		case VATRateLow:
+			add(&p.LowVAT)
+		}
+	}
+}
+
+// roundDiv returns a/b rounded half away from zero, for b > 0.
+func roundDiv(a, b int64) int64 {
+	if a < 0 {
+		return -((-a + b/2) / b)
+	}
+
+	return (a + b/2) / b
+}
@@ -0,0 +1,123 @@
+package payqr
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVAT(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name    string
+		have    []Option
+		want    string
+		wantErr string
+	}{
+		{
+			name: "Total",
+			have: []Option{WithVAT(NewMoney(1250, "SEK"))},
+			want: `"vat":12.5,`,
+		},
+		{
+			name: "Breakdown",
+			have: []Option{WithVATBreakdown(NewMoney(2500, "SEK"), NewMoney(1250, "SEK"), NewMoney(301, "SEK"))},
+			want: `"vat":40.51,"vh":25,"vm":12.5,"vl":3.01,`,
+		},
+		{
+			name: "Breakdown with the total",
+			have: []Option{WithVATBreakdown(NewMoney(0, "SEK"), NewMoney(1250, "SEK"), NewMoney(0, "SEK")), WithVAT(NewMoney(1250, "SEK"))},
+			want: `"vat":12.5,"vm":12.5,`,
+		},
+		{
+			name:    "Breakdown not summing to the total",
+			have:    []Option{WithVATBreakdown(NewMoney(2500, "SEK"), NewMoney(0, "SEK"), NewMoney(0, "SEK")), WithVAT(NewMoney(2000, "SEK"))},
+			wantErr: "VAT breakdown sums to 25.00, not the total VAT 20.00",
+		},
+		{
+			name:    "Breakdown without the total",
+			have:    []Option{func(p *Payment) { p.LowVAT = 3 }},
+			wantErr: "VAT breakdown sums to 3.00, not the total VAT 0.00",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := New("5402-9681", "Test AB", "5555555555", "52250047157", 250, due, test.have...)
+			err := d.Validate()
+			if test.wantErr != "" {
+				assert.EqualError(t, err, test.wantErr)
+				assert.True(t, errors.Is(err, ErrInvalidAmount))
+				return
+			}
+			require.NoError(t, err)
+
+			payload, err := d.Payload()
+			require.NoError(t, err)
+			assert.Contains(t, payload, `"due":250,`+test.want+`"pt"`)
+
+			parsed, err := ParsePayload(payload)
+			require.NoError(t, err)
+			assert.True(t, d.Equal(parsed))
+			assert.Equal(t, d.TotalVAT(), parsed.TotalVAT())
+		})
+	}
+}
+
+func TestVATBreakdown(t *testing.T) {
+	d := New("5402-9681", "Test AB", "5555555555", "52250047157", 250, time.Now(), WithVATBreakdown(NewMoney(2500, ""), NewMoney(1250, ""), NewMoney(0, "")))
+
+	high, medium, low := d.VATBreakdown()
+	assert.Equal(t, NewMoney(2500, ""), high)
+	assert.Equal(t, NewMoney(1250, ""), medium)
+	assert.Equal(t, NewMoney(0, ""), low)
+	assert.Equal(t, "37.50", d.TotalVAT().String())
+}
+
+func TestVATRate(t *testing.T) {
+	tests := []struct {
+		name  string
+		rate  VATRate
+		have  int64
+		net   int64
+		gross int64
+	}{
+		{name: "High", rate: VATRateHigh, have: 10000, net: 2500, gross: 2000},
+		{name: "Medium", rate: VATRateMedium, have: 11200, net: 1344, gross: 1200},
+		{name: "Low", rate: VATRateLow, have: 1001, net: 60, gross: 57},
+		{name: "Half is rounded up", rate: VATRateLow, have: 25, net: 2, gross: 1},
+		{name: "Negative half is rounded down", rate: VATRateLow, have: -25, net: -2, gross: -1},
+		{name: "Exempt", rate: 0, have: 10000, net: 0, gross: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, NewMoney(test.net, "SEK"), test.rate.OfNet(NewMoney(test.have, "SEK")))
+			assert.Equal(t, NewMoney(test.gross, "SEK"), test.rate.OfGross(NewMoney(test.have, "SEK")))
+		})
+	}
+}
+
+func TestVATFromGross(t *testing.T) {
+	d := New("5402-9681", "Test AB", "5555555555", "52250047157", 1370.10, time.Date(2022, time.August, 6, 0, 0, 0, 0, time.Local),
+		VATFromGross(NewMoney(125000, "SEK"), VATRateHigh),
+		VATFromGross(NewMoney(6720, "SEK"), VATRateMedium),
+		VATFromNet(NewMoney(4500, "SEK"), VATRateLow),
+		VATFromNet(NewMoney(1000, "SEK"), VATRateLow),
+	)
+	require.NoError(t, d.Validate())
+
+	high, medium, low := d.VATBreakdown()
+	assert.Equal(t, NewMoney(25000, ""), high)
+	assert.Equal(t, NewMoney(720, ""), medium)
+	assert.Equal(t, NewMoney(330, ""), low)
+	assert.Equal(t, NewMoney(26050, ""), d.TotalVAT())
+
+	payload, err := d.Payload()
+	require.NoError(t, err)
+	assert.Contains(t, payload, `"vat":260.5,"vh":250,"vm":7.2,"vl":3.3,`)
+}
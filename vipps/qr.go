@@ -0,0 +1,21 @@
+//go:build !payqr_payloadonly
+
+package vipps
+
+import (
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// QR returns a QR code of the deep link or landing page URL of an initiated
+// payment.
+func QR(link string) (*qrcode.QRCode, error) {
+	if !strings.HasPrefix(link, "vipps://") {
+		if err := validateURL(link); err != nil {
+			return nil, err
+		}
+	}
+
+	return qrcode.New(link, qrcode.Medium)
+}
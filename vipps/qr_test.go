@@ -0,0 +1,21 @@
+//go:build !payqr_payloadonly
+
+package vipps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQR(t *testing.T) {
+	q, err := QR(DeepLink("token"))
+	require.NoError(t, err)
+	assert.Equal(t, "vipps://?token=token", q.Content)
+
+	_, err = QR("https://api.vipps.no/dwo-api-application/v1/deeplink/vippsgateway?token=abc")
+	assert.NoError(t, err)
+	_, err = QR("javascript:alert(1)")
+	assert.Error(t, err)
+}
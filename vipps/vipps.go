@@ -0,0 +1,100 @@
+// Package vipps builds Vipps payments for Norwegian customers: the payment
+// initiation sent to the Vipps eCom API, the callback URLs Vipps posts the
+// result to and the vipps:// deep link that opens the payment in the app.
+//
+// Vipps has no prefilled QR format like Swish, a payment is initiated with
+// the API and the QR code holds the deep link or landing page URL returned.
+package vipps
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/antonlindstrom/payqr"
+)
+
+// Currency is the currency of Vipps payments.
+const Currency = "NOK"
+
+var orderID = regexp.MustCompile(`^[A-Za-z0-9-]{1,50}$`)
+
+// MerchantInfo identifies the merchant and where Vipps sends callbacks.
+type MerchantInfo struct {
+	MerchantSerialNumber string `json:"merchantSerialNumber"`
+	CallbackPrefix       string `json:"callbackPrefix"`
+	FallBack             string `json:"fallBack"`
+}
+
+// Transaction is the payment of an initiation.
+type Transaction struct {
+	OrderID         string `json:"orderId"`
+	Amount          int64  `json:"amount"` // In øre.
+	TransactionText string `json:"transactionText"`
+}
+
+// Initiation is the body of a payment initiation with the eCom API.
+type Initiation struct {
+	MerchantInfo MerchantInfo `json:"merchantInfo"`
+	Transaction  Transaction  `json:"transaction"`
+}
+
+// NewInitiation creates a payment initiation for the payment, with the
+// reference as order ID and the account name as transaction text. Callbacks
+// are sent under callbackPrefix and the payer is returned to fallback.
+func NewInitiation(p *payqr.Payment, merchantSerialNumber, callbackPrefix, fallback string) (*Initiation, error) {
+	if p.Currency != "" && !strings.EqualFold(p.Currency, Currency) {
+		return nil, fmt.Errorf("vipps: currency must be %s, got %s", Currency, p.Currency)
+	}
+	if !orderID.MatchString(p.Reference) {
+		return nil, fmt.Errorf("vipps: reference must be 1 to 50 letters, digits and dashes, got %q", p.Reference)
+	}
+	if p.DueAmount < 1 {
+		return nil, fmt.Errorf("vipps: amount %.2f is less than 1.00", p.DueAmount)
+	}
+	for _, u := range []string{callbackPrefix, fallback} {
+		if err := validateURL(u); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Initiation{
+		MerchantInfo: MerchantInfo{
+			MerchantSerialNumber: merchantSerialNumber,
+			CallbackPrefix:       strings.TrimSuffix(callbackPrefix, "/"),
+			FallBack:             fallback,
+		},
+		Transaction: Transaction{
+			OrderID:         p.Reference,
+			Amount:          int64(p.DueAmount*100 + 0.5),
+			TransactionText: p.AccountName,
+		},
+	}, nil
+}
+
+// CallbackURL returns the URL Vipps posts the result of the payment to.
+func (i *Initiation) CallbackURL() string {
+	return CallbackURL(i.MerchantInfo.CallbackPrefix, i.Transaction.OrderID)
+}
+
+// CallbackURL returns the URL Vipps posts the result of the order to, the
+// order ID appended to the callback prefix as {prefix}/v2/payments/{orderId}.
+func CallbackURL(callbackPrefix, orderID string) string {
+	return strings.TrimSuffix(callbackPrefix, "/") + "/v2/payments/" + url.PathEscape(orderID)
+}
+
+// DeepLink returns the vipps:// link that opens the initiated payment with
+// the token in the app.
+func DeepLink(token string) string {
+	return "vipps://?token=" + url.QueryEscape(token)
+}
+
+func validateURL(s string) error {
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("vipps: %q is not an https URL", s)
+	}
+
+	return nil
+}
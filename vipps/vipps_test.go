@@ -0,0 +1,68 @@
+package vipps
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewInitiation(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		have     *payqr.Payment
+		callback string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "Valid",
+			have:     payqr.New("", "Test AS", "", "order-123", 49.9, due, payqr.WithCurrency("NOK")),
+			callback: "https://example.com/vipps/",
+			want:     `{"merchantInfo":{"merchantSerialNumber":"123456","callbackPrefix":"https://example.com/vipps","fallBack":"https://example.com/done"},"transaction":{"orderId":"order-123","amount":4990,"transactionText":"Test AS"}}`,
+		},
+		{
+			name:     "Invalid order ID",
+			have:     payqr.New("", "Test AS", "", "order 123", 49.9, due),
+			callback: "https://example.com/vipps",
+			wantErr:  true,
+		},
+		{
+			name:     "HTTP callback",
+			have:     payqr.New("", "Test AS", "", "order-123", 49.9, due),
+			callback: "http://example.com/vipps",
+			wantErr:  true,
+		},
+		{
+			name:     "SEK",
+			have:     payqr.New("", "Test AS", "", "order-123", 49.9, due, payqr.WithCurrency("SEK")),
+			callback: "https://example.com/vipps",
+			wantErr:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := NewInitiation(test.have, "123456", test.callback, "https://example.com/done")
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			b, err := json.Marshal(got)
+			require.NoError(t, err)
+			assert.JSONEq(t, test.want, string(b))
+			assert.Equal(t, "https://example.com/vipps/v2/payments/order-123", got.CallbackURL())
+		})
+	}
+}
+
+func TestDeepLink(t *testing.T) {
+	assert.Equal(t, "vipps://?token=eyJ0%2Bx", DeepLink("eyJ0+x"))
+}
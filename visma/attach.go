@@ -0,0 +1,52 @@
+//go:build !payqr_payloadonly
+
+package visma
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+
+	"github.com/antonlindstrom/payqr"
+)
+
+// attachmentDocumentType is the document type of customer invoices for
+// attachment links.
+const attachmentDocumentType = "CustomerInvoice"
+
+// AttachQR uploads the QR code of the payment as a PNG image of the size in
+// pixels and links it to the invoice. It returns the ID of the attachment.
+func (c *Client) AttachQR(ctx context.Context, inv Invoice, p *payqr.Payment, size int) (string, error) {
+	q, err := p.QR()
+	if err != nil {
+		return "", err
+	}
+	png, err := q.PNG(size)
+	if err != nil {
+		return "", err
+	}
+
+	var attachment struct {
+		ID string `json:"Id"`
+	}
+	err = c.do(ctx, http.MethodPost, "/attachments", nil, map[string]string{
+		"ContentType": payqr.FormatPNG.ContentType(),
+		"FileName":    payqr.FormatPNG.Filename(fmt.Sprintf("betala-%d", inv.InvoiceNumber)),
+		"Data":        base64.StdEncoding.EncodeToString(png),
+	}, &attachment)
+	if err != nil {
+		return "", err
+	}
+
+	err = c.do(ctx, http.MethodPost, "/attachmentlinks", nil, map[string]interface{}{
+		"DocumentId":    inv.ID,
+		"DocumentType":  attachmentDocumentType,
+		"AttachmentIds": []string{attachment.ID},
+	}, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return attachment.ID, nil
+}
@@ -0,0 +1,52 @@
+//go:build !payqr_payloadonly
+
+package visma
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachQR(t *testing.T) {
+	var link map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+
+		switch r.URL.Path {
+		case "/attachments":
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "image/png", body["ContentType"])
+			assert.Equal(t, "betala-1001.png", body["FileName"])
+			png, err := base64.StdEncoding.DecodeString(body["Data"])
+			require.NoError(t, err)
+			assert.Equal(t, "\x89PNG", string(png[:4]))
+
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"Id":"a1b2c3"}`)
+		case "/attachmentlinks":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&link))
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	defer srv.Close()
+
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+	p := payqr.New("5402-9681", "Test AB", "555555-5555", "52250047157", 1250, due)
+
+	id, err := New("token", WithBaseURL(srv.URL)).AttachQR(context.Background(), Invoice{ID: "5d1f3b0a", InvoiceNumber: 1001}, p, 256)
+	require.NoError(t, err)
+	assert.Equal(t, "a1b2c3", id)
+	assert.Equal(t, "5d1f3b0a", link["DocumentId"])
+	assert.Equal(t, []interface{}{"a1b2c3"}, link["AttachmentIds"])
+}
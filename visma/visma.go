@@ -0,0 +1,283 @@
+// Package visma is a client for the Visma eEkonomi (eAccounting) API, used to
+// fetch the unpaid customer invoices of a company, convert them into payments
+// for payment QR codes and attach the rendered codes to the invoices.
+//
+// Requests are authenticated with an OAuth 2 access token for the company,
+// with the ea:sales scope.
+package visma
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+)
+
+const (
+	// ProductionURL is the base URL of version 2 of the eEkonomi API.
+	ProductionURL = "https://eaccountingapi.vismaonline.com/v2"
+
+	// SandboxURL is the base URL of the sandbox of the API.
+	SandboxURL = "https://eaccountingapi-sandbox.test.vismaonline.com/v2"
+)
+
+// pageSize is the number of invoices fetched per request.
+const pageSize = 100
+
+// Client is a client for the Visma eEkonomi API.
+type Client struct {
+	httpClient  *http.Client
+	baseURL     string
+	accessToken string
+}
+
+// Option is a modifier for the Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithBaseURL sets the base URL of the API. Default is ProductionURL.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// New creates a client authenticating with the access token.
+func New(accessToken string, options ...Option) *Client {
+	c := &Client{
+		httpClient:  http.DefaultClient,
+		baseURL:     ProductionURL,
+		accessToken: accessToken,
+	}
+
+	for _, opt := range options {
+		opt(c)
+	}
+
+	return c
+}
+
+// Error is returned when the API responds with an unexpected status.
+type Error struct {
+	StatusCode int
+	Code       int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("visma: unexpected status %d", e.StatusCode)
+	}
+
+	return fmt.Sprintf("visma: status %d: %s (%d)", e.StatusCode, e.Message, e.Code)
+}
+
+// do sends a request with an optional JSON body and decodes the JSON
+// response into v, if not nil.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, v interface{}) error {
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, r)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var e struct {
+			ErrorCode int    `json:"ErrorCode"`
+			Message   string `json:"DeveloperErrorMessage"`
+		}
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+		_ = json.Unmarshal(b, &e)
+
+		return &Error{StatusCode: resp.StatusCode, Code: e.ErrorCode, Message: e.Message}
+	}
+	if v == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// Company is the company settings, with the accounts invoices are paid to.
+type Company struct {
+	Name                    string `json:"Name"`
+	CorporateIdentityNumber string `json:"CorporateIdentityNumber"`
+	Address1                string `json:"Address1"`
+	PostalCode              string `json:"PostalCode"`
+	City                    string `json:"City"`
+	CountryCode             string `json:"CountryCode"`
+	BankGiro                string `json:"BankGiro"`
+	PlusGiro                string `json:"PlusGiro"`
+	Iban                    string `json:"Iban"`
+	Bic                     string `json:"Bic"`
+}
+
+// Company fetches the company settings.
+func (c *Client) Company(ctx context.Context) (*Company, error) {
+	var company Company
+	if err := c.do(ctx, http.MethodGet, "/companysettings", nil, nil, &company); err != nil {
+		return nil, err
+	}
+
+	return &company, nil
+}
+
+// Invoice is a customer invoice. Dates are as returned by the API, in local
+// time without a zone.
+type Invoice struct {
+	ID                  string  `json:"Id"`
+	InvoiceNumber       int     `json:"InvoiceNumber"`
+	CustomerID          string  `json:"CustomerId"`
+	InvoiceCustomerName string  `json:"InvoiceCustomerName"`
+	InvoiceDate         string  `json:"InvoiceDate"`
+	DueDate             string  `json:"DueDate"`
+	CurrencyCode        string  `json:"CurrencyCode"`
+	TotalAmount         float64 `json:"TotalAmount"`
+	RemainingAmount     float64 `json:"RemainingAmount"`
+	OcrNumber           string  `json:"OcrNumber"`
+}
+
+// UnpaidInvoices fetches the customer invoices with an amount left to pay,
+// fetching all pages.
+func (c *Client) UnpaidInvoices(ctx context.Context) ([]Invoice, error) {
+	var invoices []Invoice
+	for page := 1; ; page++ {
+		var body struct {
+			Meta struct {
+				TotalNumberOfPages int `json:"TotalNumberOfPages"`
+			} `json:"Meta"`
+			Data []Invoice `json:"Data"`
+		}
+		query := url.Values{
+			"$filter":   {"RemainingAmount gt 0"},
+			"$page":     {strconv.Itoa(page)},
+			"$pagesize": {strconv.Itoa(pageSize)},
+		}
+		if err := c.do(ctx, http.MethodGet, "/customerinvoices", query, nil, &body); err != nil {
+			return nil, err
+		}
+
+		invoices = append(invoices, body.Data...)
+		if page >= body.Meta.TotalNumberOfPages {
+			return invoices, nil
+		}
+	}
+}
+
+// parseDate parses a date as returned by the API, such as
+// 2022-08-06T00:00:00.
+func parseDate(s string) (time.Time, error) {
+	if len(s) >= 10 {
+		if t, err := time.Parse("2006-01-02", s[:10]); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
+
+// Payment returns the payment of the remaining amount of the invoice to the
+// Bankgiro number of the company, or else its Plusgiro number or IBAN. The
+// OCR number of the invoice is used as reference, or else the invoice number.
+func (inv Invoice) Payment(company *Company) (*payqr.Payment, error) {
+	due, err := parseDate(inv.DueDate)
+	if err != nil {
+		return nil, fmt.Errorf("visma: invoice %d: %w", inv.InvoiceNumber, err)
+	}
+
+	var options []payqr.Option
+	account := company.BankGiro
+	switch {
+	case company.BankGiro != "":
+		options = append(options, payqr.WithPaymentType(payqr.PaymentTypeBG))
+	case company.PlusGiro != "":
+		account = company.PlusGiro
+		options = append(options, payqr.WithPaymentType(payqr.PaymentTypePG))
+	case company.Iban != "":
+		account = strings.ReplaceAll(company.Iban, " ", "")
+		options = append(options, payqr.WithPaymentType(payqr.PaymentTypeIBAN))
+		if company.Bic != "" {
+			options = append(options, payqr.WithBankCode(company.Bic))
+		}
+	default:
+		return nil, errors.New("visma: company has no Bankgiro, Plusgiro or IBAN account")
+	}
+
+	if inv.CurrencyCode != "" {
+		options = append(options, payqr.WithCurrency(inv.CurrencyCode))
+	}
+	if date, err := parseDate(inv.InvoiceDate); err == nil {
+		options = append(options, payqr.WithCreationDate(date))
+	}
+
+	reference := inv.OcrNumber
+	if reference == "" {
+		reference = strconv.Itoa(inv.InvoiceNumber)
+	}
+
+	return payqr.New(account, company.Name, company.CorporateIdentityNumber, reference, inv.RemainingAmount, due, options...), nil
+}
+
+// Payments fetches the company settings and the unpaid invoices and returns
+// a payment for each invoice, in the same order as the invoices.
+func (c *Client) Payments(ctx context.Context) ([]Invoice, []*payqr.Payment, error) {
+	company, err := c.Company(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	invoices, err := c.UnpaidInvoices(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	payments := make([]*payqr.Payment, 0, len(invoices))
+	for _, inv := range invoices {
+		p, err := inv.Payment(company)
+		if err != nil {
+			return nil, nil, err
+		}
+		payments = append(payments, p)
+	}
+
+	return invoices, payments, nil
+}
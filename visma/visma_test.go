@@ -0,0 +1,64 @@
+package visma
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayments(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+		switch r.URL.Path {
+		case "/companysettings":
+			fmt.Fprint(w, `{"Name":"Test AB","CorporateIdentityNumber":"555555-5555","BankGiro":"5402-9681"}`)
+		case "/customerinvoices":
+			assert.Equal(t, "RemainingAmount gt 0", r.URL.Query().Get("$filter"))
+			fmt.Fprint(w, `{"Meta":{"CurrentPage":1,"TotalNumberOfPages":1},"Data":[`+
+				`{"Id":"5d1f3b0a","InvoiceNumber":1001,"InvoiceDate":"2022-07-07T00:00:00","DueDate":"2022-08-06T00:00:00","CurrencyCode":"SEK","TotalAmount":1250,"RemainingAmount":1250,"OcrNumber":"52250047157"},`+
+				`{"Id":"7a2c9e11","InvoiceNumber":1002,"InvoiceDate":"2022-07-07T00:00:00","DueDate":"2022-08-06T00:00:00","CurrencyCode":"SEK","TotalAmount":100,"RemainingAmount":40}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	c := New("token", WithBaseURL(srv.URL), WithHTTPClient(srv.Client()))
+
+	invoices, payments, err := c.Payments(context.Background())
+	require.NoError(t, err)
+	require.Len(t, invoices, 2)
+
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+	created := time.Date(2022, time.July, 7, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, []*payqr.Payment{
+		payqr.New("5402-9681", "Test AB", "555555-5555", "52250047157", 1250, due,
+			payqr.WithPaymentType(payqr.PaymentTypeBG), payqr.WithCurrency("SEK"), payqr.WithCreationDate(created)),
+		payqr.New("5402-9681", "Test AB", "555555-5555", "1002", 40, due,
+			payqr.WithPaymentType(payqr.PaymentTypeBG), payqr.WithCurrency("SEK"), payqr.WithCreationDate(created)),
+	}, payments)
+}
+
+func TestError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"ErrorCode":4004,"DeveloperErrorMessage":"Missing scope."}`)
+	}))
+	defer srv.Close()
+
+	_, _, err := New("token", WithBaseURL(srv.URL)).Payments(context.Background())
+
+	var apiErr *Error
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+	assert.Equal(t, "visma: status 403: Missing scope. (4004)", err.Error())
+}
@@ -0,0 +1,278 @@
+// Package wallet builds signed Apple Wallet passes (.pkpass) holding the QR
+// code of a payment, with the amount, due date and payee, so an invoice can
+// be kept in Wallet and paid later.
+//
+// Passes are signed with a Pass Type ID certificate from the Apple Developer
+// account, along with the Apple WWDR intermediate certificate.
+package wallet
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"go.mozilla.org/pkcs7"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+func init() {
+	payqr.RegisterCapability(payqr.CapabilityWallet)
+}
+
+// Config holds the identifiers and signing certificates of a pass type.
+type Config struct {
+	PassTypeIdentifier string // Such as "pass.com.example.invoice".
+	TeamIdentifier     string
+	OrganizationName   string
+
+	Certificate  *x509.Certificate
+	Key          crypto.PrivateKey
+	Intermediate *x509.Certificate // The Apple WWDR certificate.
+}
+
+// ConfigFromPKCS12 returns a config with the certificate and key from PKCS#12
+// (.p12) data, as exported from Keychain, and the WWDR certificate in DER.
+func ConfigFromPKCS12(passTypeIdentifier, teamIdentifier, organizationName string, data []byte, password string, wwdr []byte) (*Config, error) {
+	key, cert, _, err := pkcs12.DecodeChain(data, password)
+	if err != nil {
+		return nil, fmt.Errorf("wallet: could not decode pkcs12: %w", err)
+	}
+
+	intermediate, err := x509.ParseCertificate(wwdr)
+	if err != nil {
+		return nil, fmt.Errorf("wallet: could not parse WWDR certificate: %w", err)
+	}
+
+	return &Config{
+		PassTypeIdentifier: passTypeIdentifier,
+		TeamIdentifier:     teamIdentifier,
+		OrganizationName:   organizationName,
+		Certificate:        cert,
+		Key:                key,
+		Intermediate:       intermediate,
+	}, nil
+}
+
+// Option is a modifier for the pass.
+type Option func(*pass)
+
+type pass struct {
+	description string
+	icon        []byte
+	logo        []byte
+}
+
+// WithDescription sets the description of the pass used by accessibility
+// features, default is "Invoice" and the reference.
+func WithDescription(description string) Option {
+	return func(p *pass) {
+		p.description = description
+	}
+}
+
+// WithIcon sets the PNG icon of the pass, shown on the lock screen and in
+// notifications. Default is a plain white icon.
+func WithIcon(png []byte) Option {
+	return func(p *pass) {
+		p.icon = png
+	}
+}
+
+// WithLogo sets the PNG logo shown at the top of the pass.
+func WithLogo(png []byte) Option {
+	return func(p *pass) {
+		p.logo = png
+	}
+}
+
+type barcode struct {
+	Format          string `json:"format"`
+	Message         string `json:"message"`
+	MessageEncoding string `json:"messageEncoding"`
+	AltText         string `json:"altText,omitempty"`
+}
+
+type field struct {
+	Key          string      `json:"key"`
+	Label        string      `json:"label,omitempty"`
+	Value        interface{} `json:"value"`
+	CurrencyCode string      `json:"currencyCode,omitempty"`
+	DateStyle    string      `json:"dateStyle,omitempty"`
+}
+
+type structure struct {
+	PrimaryFields   []field `json:"primaryFields,omitempty"`
+	SecondaryFields []field `json:"secondaryFields,omitempty"`
+	AuxiliaryFields []field `json:"auxiliaryFields,omitempty"`
+	BackFields      []field `json:"backFields,omitempty"`
+}
+
+type passJSON struct {
+	FormatVersion      int       `json:"formatVersion"`
+	PassTypeIdentifier string    `json:"passTypeIdentifier"`
+	SerialNumber       string    `json:"serialNumber"`
+	TeamIdentifier     string    `json:"teamIdentifier"`
+	OrganizationName   string    `json:"organizationName"`
+	Description        string    `json:"description"`
+	RelevantDate       string    `json:"relevantDate,omitempty"`
+	Barcodes           []barcode `json:"barcodes"`
+	Barcode            barcode   `json:"barcode"` // For iOS 8 and earlier.
+	Generic            structure `json:"generic"`
+}
+
+// Build returns the signed .pkpass archive of the payment, identified by the
+// serial number which must be unique for the pass type.
+func (c *Config) Build(p *payqr.Payment, serialNumber string, options ...Option) ([]byte, error) {
+	ps := &pass{description: "Invoice " + p.Reference}
+	for _, opt := range options {
+		opt(ps)
+	}
+
+	if c.PassTypeIdentifier == "" || c.TeamIdentifier == "" || c.OrganizationName == "" {
+		return nil, errors.New("wallet: pass type identifier, team identifier and organization name are required")
+	}
+	if c.Certificate == nil || c.Key == nil {
+		return nil, errors.New("wallet: signing certificate and key are required")
+	}
+	if serialNumber == "" {
+		return nil, errors.New("wallet: serial number is required")
+	}
+
+	// The barcode holds the same payload as Payment.QR.
+	payload, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+
+	currency := p.Currency
+	if currency == "" {
+		currency = "SEK"
+	}
+
+	code := barcode{Format: "PKBarcodeFormatQR", Message: string(payload), MessageEncoding: "utf-8"}
+	pj := passJSON{
+		FormatVersion:      1,
+		PassTypeIdentifier: c.PassTypeIdentifier,
+		SerialNumber:       serialNumber,
+		TeamIdentifier:     c.TeamIdentifier,
+		OrganizationName:   c.OrganizationName,
+		Description:        ps.description,
+		Barcodes:           []barcode{code},
+		Barcode:            code,
+		Generic: structure{
+			PrimaryFields:   []field{{Key: "amount", Label: "Amount", Value: p.DueAmount, CurrencyCode: currency}},
+			AuxiliaryFields: []field{{Key: "payee", Label: "Payee", Value: p.AccountName}},
+			BackFields: []field{
+				{Key: "account", Label: "Account", Value: p.AccountNumber},
+				{Key: "reference", Label: "Reference", Value: p.Reference},
+			},
+		},
+	}
+	if due, err := time.Parse("20060102", p.DueDate); err == nil {
+		date := due.Format(time.RFC3339)
+		pj.RelevantDate = date
+		pj.Generic.SecondaryFields = []field{{Key: "due", Label: "Due", Value: date, DateStyle: "PKDateStyleMedium"}}
+	}
+
+	passData, err := json.MarshalIndent(pj, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	if ps.icon == nil {
+		if ps.icon, err = plainIcon(); err != nil {
+			return nil, err
+		}
+	}
+
+	files := []file{
+		{"pass.json", passData},
+		{"icon.png", ps.icon},
+	}
+	if ps.logo != nil {
+		files = append(files, file{"logo.png", ps.logo})
+	}
+
+	manifest := make(map[string]string, len(files))
+	for _, f := range files {
+		sum := sha1.Sum(f.data)
+		manifest[f.name] = hex.EncodeToString(sum[:])
+	}
+	manifestData, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := c.sign(manifestData)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	files = append(files, file{"manifest.json", manifestData}, file{"signature", signature})
+	for _, f := range files {
+		w, err := zw.Create(f.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(f.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+type file struct {
+	name string
+	data []byte
+}
+
+// sign returns the detached PKCS#7 signature of the manifest.
+func (c *Config) sign(manifest []byte) ([]byte, error) {
+	sd, err := pkcs7.NewSignedData(manifest)
+	if err != nil {
+		return nil, err
+	}
+	sd.SetDigestAlgorithm(pkcs7.OIDDigestAlgorithmSHA256)
+
+	var parents []*x509.Certificate
+	if c.Intermediate != nil {
+		parents = append(parents, c.Intermediate)
+	}
+	if err := sd.AddSignerChain(c.Certificate, c.Key, parents, pkcs7.SignerInfoConfig{}); err != nil {
+		return nil, fmt.Errorf("wallet: could not sign manifest: %w", err)
+	}
+	sd.Detach()
+
+	return sd.Finish()
+}
+
+// plainIcon returns a white 29x29 PNG.
+func plainIcon() ([]byte, error) {
+	img := image.NewGray(image.Rect(0, 0, 29, 29))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
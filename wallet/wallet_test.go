@@ -0,0 +1,115 @@
+package wallet
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/antonlindstrom/payqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mozilla.org/pkcs7"
+)
+
+func testConfig(t *testing.T) *Config {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test WWDR"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "Pass Type ID: pass.com.example.invoice"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, &key.PublicKey, caKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &Config{
+		PassTypeIdentifier: "pass.com.example.invoice",
+		TeamIdentifier:     "ABCDE12345",
+		OrganizationName:   "Test AB",
+		Certificate:        cert,
+		Key:                key,
+		Intermediate:       ca,
+	}
+}
+
+func TestBuild(t *testing.T) {
+	due := time.Date(2022, time.August, 6, 0, 0, 0, 0, time.UTC)
+	p := payqr.New("5402-9681", "Test AB", "555555-5555", "52250047151", 1250, due)
+
+	b, err := testConfig(t).Build(p, "inv-1", WithLogo([]byte("logo")))
+	require.NoError(t, err)
+
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	require.NoError(t, err)
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		files[f.Name], err = io.ReadAll(r)
+		require.NoError(t, err)
+		r.Close()
+	}
+	assert.Len(t, files, 5)
+
+	var manifest map[string]string
+	require.NoError(t, json.Unmarshal(files["manifest.json"], &manifest))
+	for _, name := range []string{"pass.json", "icon.png", "logo.png"} {
+		sum := sha1.Sum(files[name])
+		assert.Equal(t, hex.EncodeToString(sum[:]), manifest[name], name)
+	}
+
+	sig, err := pkcs7.Parse(files["signature"])
+	require.NoError(t, err)
+	sig.Content = files["manifest.json"]
+	assert.NoError(t, sig.Verify())
+
+	var pj passJSON
+	require.NoError(t, json.Unmarshal(files["pass.json"], &pj))
+	assert.Equal(t, "inv-1", pj.SerialNumber)
+	assert.Equal(t, "Invoice 52250047151", pj.Description)
+	assert.Equal(t, "2022-08-06T00:00:00Z", pj.RelevantDate)
+	assert.Equal(t, "SEK", pj.Generic.PrimaryFields[0].CurrencyCode)
+	assert.Equal(t, "PKBarcodeFormatQR", pj.Barcodes[0].Format)
+	assert.Contains(t, pj.Barcodes[0].Message, `"iref":"52250047151"`)
+}
+
+func TestBuildMissingConfig(t *testing.T) {
+	p := payqr.New("5402-9681", "Test AB", "555555-5555", "52250047151", 1250, time.Now())
+
+	_, err := (&Config{PassTypeIdentifier: "pass.com.example.invoice"}).Build(p, "inv-1")
+	assert.Error(t, err)
+
+	_, err = testConfig(t).Build(p, "")
+	assert.Error(t, err)
+}